@@ -0,0 +1,111 @@
+package beaconevents
+
+import (
+	"sync"
+
+	"github.com/ledgerwatch/log/v3"
+)
+
+// Topics of the Beacon API event stream.
+const (
+	TopicHead                = "head"
+	TopicBlock               = "block"
+	TopicAttestation         = "attestation"
+	TopicFinalizedCheckpoint = "finalized_checkpoint"
+	TopicChainReorg          = "chain_reorg"
+)
+
+// IsSupportedTopic reports whether events of the given topic are published.
+func IsSupportedTopic(topic string) bool {
+	switch topic {
+	case TopicHead, TopicBlock, TopicAttestation, TopicFinalizedCheckpoint, TopicChainReorg:
+		return true
+	default:
+		return false
+	}
+}
+
+const subscriptionBufferSize = 128
+
+// Event is a single event of the stream, Data being JSON encoded as is.
+type Event struct {
+	Topic string
+	Data  any
+}
+
+type subscription struct {
+	topics map[string]struct{}
+	ch     chan *Event
+}
+
+// Emitters dispatches events to the subscribers of their topic. A nil
+// *Emitters is valid and drops all events.
+type Emitters struct {
+	mu     sync.RWMutex
+	nextID uint64
+	subs   map[uint64]*subscription
+}
+
+func NewEmitters() *Emitters {
+	return &Emitters{subs: make(map[uint64]*subscription)}
+}
+
+// HasSubscribers reports whether anybody listens to the given topic, so that
+// producers can skip building events nobody will read.
+func (e *Emitters) HasSubscribers(topic string) bool {
+	if e == nil {
+		return false
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	for _, sub := range e.subs {
+		if _, ok := sub.topics[topic]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Publish sends an event to the subscribers of its topic. Subscribers which
+// do not keep up lose events rather than slowing down the publisher.
+func (e *Emitters) Publish(topic string, data any) {
+	if e == nil {
+		return
+	}
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	event := &Event{Topic: topic, Data: data}
+	for _, sub := range e.subs {
+		if _, ok := sub.topics[topic]; !ok {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			log.Debug("[Beacon API] dropping event for slow subscriber", "topic", topic)
+		}
+	}
+}
+
+// Subscribe registers a subscriber to the given topics. The returned function
+// must be called to release the subscription.
+func (e *Emitters) Subscribe(topics []string) (<-chan *Event, func()) {
+	sub := &subscription{
+		topics: make(map[string]struct{}, len(topics)),
+		ch:     make(chan *Event, subscriptionBufferSize),
+	}
+	for _, topic := range topics {
+		sub.topics[topic] = struct{}{}
+	}
+	e.mu.Lock()
+	id := e.nextID
+	e.nextID++
+	e.subs[id] = sub
+	e.mu.Unlock()
+
+	return sub.ch, func() {
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		delete(e.subs, id)
+	}
+}
@@ -0,0 +1,79 @@
+package beaconevents
+
+import (
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/types"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+)
+
+type HeadEvent struct {
+	Slot                      types.Uint64   `json:"slot"`
+	Block                     libcommon.Hash `json:"block"`
+	State                     libcommon.Hash `json:"state"`
+	EpochTransition           bool           `json:"epoch_transition"`
+	PreviousDutyDependentRoot libcommon.Hash `json:"previous_duty_dependent_root"`
+	CurrentDutyDependentRoot  libcommon.Hash `json:"current_duty_dependent_root"`
+	ExecutionOptimistic       bool           `json:"execution_optimistic"`
+}
+
+type BlockEvent struct {
+	Slot                types.Uint64   `json:"slot"`
+	Block               libcommon.Hash `json:"block"`
+	ExecutionOptimistic bool           `json:"execution_optimistic"`
+}
+
+type FinalizedCheckpointEvent struct {
+	Block               libcommon.Hash `json:"block"`
+	State               libcommon.Hash `json:"state"`
+	Epoch               types.Uint64   `json:"epoch"`
+	ExecutionOptimistic bool           `json:"execution_optimistic"`
+}
+
+type ChainReorgEvent struct {
+	Slot                types.Uint64   `json:"slot"`
+	Depth               types.Uint64   `json:"depth"`
+	OldHeadBlock        libcommon.Hash `json:"old_head_block"`
+	NewHeadBlock        libcommon.Hash `json:"new_head_block"`
+	OldHeadState        libcommon.Hash `json:"old_head_state"`
+	NewHeadState        libcommon.Hash `json:"new_head_state"`
+	Epoch               types.Uint64   `json:"epoch"`
+	ExecutionOptimistic bool           `json:"execution_optimistic"`
+}
+
+type CheckpointJSON struct {
+	Epoch types.Uint64   `json:"epoch"`
+	Root  libcommon.Hash `json:"root"`
+}
+
+type AttestationDataJSON struct {
+	Slot            types.Uint64   `json:"slot"`
+	Index           types.Uint64   `json:"index"`
+	BeaconBlockRoot libcommon.Hash `json:"beacon_block_root"`
+	Source          CheckpointJSON `json:"source"`
+	Target          CheckpointJSON `json:"target"`
+}
+
+type AttestationEvent struct {
+	AggregationBits hexutility.Bytes    `json:"aggregation_bits"`
+	Data            AttestationDataJSON `json:"data"`
+	Signature       hexutility.Bytes    `json:"signature"`
+}
+
+func NewAttestationEvent(attestation *solid.Attestation) *AttestationEvent {
+	data := attestation.AttestantionData()
+	source, target := data.Source(), data.Target()
+	signature := attestation.Signature()
+	return &AttestationEvent{
+		AggregationBits: libcommon.Copy(attestation.AggregationBits()),
+		Data: AttestationDataJSON{
+			Slot:            types.Uint64(data.Slot()),
+			Index:           types.Uint64(data.ValidatorIndex()),
+			BeaconBlockRoot: data.BeaconBlockRoot(),
+			Source:          CheckpointJSON{Epoch: types.Uint64(source.Epoch()), Root: source.BlockRoot()},
+			Target:          CheckpointJSON{Epoch: types.Uint64(target.Epoch()), Root: target.BlockRoot()},
+		},
+		Signature: signature[:],
+	}
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// getSpec returns the configuration parameters tagged as part of the spec,
+// keyed by their yaml name, all values being strings.
+func (a *ApiHandler) getSpec(w http.ResponseWriter, _ *http.Request) {
+	spec := make(map[string]string)
+	v := reflect.ValueOf(a.beaconChainCfg).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Tag.Get("spec") != "true" {
+			continue
+		}
+		name := field.Tag.Get("yaml")
+		if name == "" {
+			continue
+		}
+		value := v.Field(i)
+		switch value.Kind() {
+		case reflect.Uint8:
+			// withdrawal prefixes are bytes, flag indices are integers
+			if strings.HasSuffix(name, "_PREFIX") {
+				spec[name] = fmt.Sprintf("0x%02x", value.Uint())
+			} else {
+				spec[name] = strconv.FormatUint(value.Uint(), 10)
+			}
+		case reflect.Uint32:
+			// fork versions
+			spec[name] = fmt.Sprintf("0x%08x", value.Uint())
+		case reflect.Uint64, reflect.Uint:
+			spec[name] = strconv.FormatUint(value.Uint(), 10)
+		case reflect.Int, reflect.Int64:
+			spec[name] = strconv.FormatInt(value.Int(), 10)
+		case reflect.String:
+			spec[name] = value.String()
+		case reflect.Array:
+			if value.Type().Elem().Kind() == reflect.Uint8 {
+				spec[name] = fmt.Sprintf("%#x", value.Slice(0, value.Len()).Bytes())
+			}
+		default:
+			spec[name] = fmt.Sprint(value.Interface())
+		}
+	}
+	writeResponse(w, newBeaconResponse(spec))
+}
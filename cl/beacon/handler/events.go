@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
+)
+
+// getEvents streams the events of the requested topics as server-sent events
+// until the client disconnects.
+func (a *ApiHandler) getEvents(w http.ResponseWriter, r *http.Request) {
+	if a.emitters == nil {
+		writeError(w, http.StatusServiceUnavailable, "events are not enabled")
+		return
+	}
+	topics := splitQuery(r.URL.Query()["topics"])
+	if len(topics) == 0 {
+		writeError(w, http.StatusBadRequest, "missing topics")
+		return
+	}
+	for _, topic := range topics {
+		if !beaconevents.IsSupportedTopic(topic) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported topic %q", topic))
+			return
+		}
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming is not supported")
+		return
+	}
+
+	events, unsubscribe := a.emitters.Subscribe(topics)
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			data, err := json.Marshal(event.Data)
+			if err != nil {
+				log.Debug("[Beacon API] failed to encode event", "topic", event.Topic, "err", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Topic, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/log/v3"
+)
+
+// beaconResponse is the envelope of every Beacon API response.
+type beaconResponse struct {
+	Version             *string `json:"version,omitempty"`
+	Data                any     `json:"data"`
+	ExecutionOptimistic *bool   `json:"execution_optimistic,omitempty"`
+	Finalized           *bool   `json:"finalized,omitempty"`
+}
+
+type apiError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func newBeaconResponse(data any) *beaconResponse {
+	return &beaconResponse{Data: data}
+}
+
+// withFinalized adds the execution_optimistic and finalized metadata of
+// responses about a block or a state.
+func (r *beaconResponse) withFinalized(finalized bool) *beaconResponse {
+	optimistic := false
+	r.ExecutionOptimistic = &optimistic
+	r.Finalized = &finalized
+	return r
+}
+
+// withVersion adds the fork name of versioned responses.
+func (r *beaconResponse) withVersion(version clparams.StateVersion) *beaconResponse {
+	name := clparams.ClVersionToString(version)
+	r.Version = &name
+	return r
+}
+
+func writeResponse(w http.ResponseWriter, resp *beaconResponse) {
+	writeJSON(w, resp)
+}
+
+// writeJSON writes a successful response which is not wrapped in an envelope.
+func writeJSON(w http.ResponseWriter, resp any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Debug("[Beacon API] failed to write response", "err", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(apiError{Code: code, Message: message}); err != nil {
+		log.Debug("[Beacon API] failed to write error", "err", err)
+	}
+}
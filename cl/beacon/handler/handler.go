@@ -5,18 +5,30 @@ import (
 	"sync"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/sentinel"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
 	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
+	"github.com/ledgerwatch/erigon/cl/phase1/light_client"
+	"github.com/ledgerwatch/erigon/cl/phase1/pool"
 )
 
 type ApiHandler struct {
-	o              sync.Once
-	mux            chi.Router
-	genesisCfg     *clparams.GenesisConfig
-	beaconChainCfg *clparams.BeaconChainConfig
+	o               sync.Once
+	mux             chi.Router
+	genesisCfg      *clparams.GenesisConfig
+	beaconChainCfg  *clparams.BeaconChainConfig
+	forkchoiceStore *forkchoice.ForkChoiceStore
+	emitters        *beaconevents.Emitters
+	operationsPool  *pool.OperationsPool
+	sentinel        sentinel.SentinelClient // to publish the submitted operations, may be nil
+	lightClient     *light_client.Store     // may be nil
 }
 
-func NewApiHandler(genesisConfig *clparams.GenesisConfig, beaconChainConfig *clparams.BeaconChainConfig) *ApiHandler {
-	return &ApiHandler{o: sync.Once{}, genesisCfg: genesisConfig, beaconChainCfg: beaconChainConfig}
+func NewApiHandler(genesisConfig *clparams.GenesisConfig, beaconChainConfig *clparams.BeaconChainConfig, forkchoiceStore *forkchoice.ForkChoiceStore, emitters *beaconevents.Emitters,
+	operationsPool *pool.OperationsPool, sentinel sentinel.SentinelClient, lightClient *light_client.Store) *ApiHandler {
+	return &ApiHandler{o: sync.Once{}, genesisCfg: genesisConfig, beaconChainCfg: beaconChainConfig, forkchoiceStore: forkchoiceStore, emitters: emitters,
+		operationsPool: operationsPool, sentinel: sentinel, lightClient: lightClient}
 }
 
 func (a *ApiHandler) init() {
@@ -26,26 +38,40 @@ func (a *ApiHandler) init() {
 	// otterscn specific ones are commented as such
 	r.Route("/eth", func(r chi.Router) {
 		r.Route("/v1", func(r chi.Router) {
-			r.Get("/events", nil)
+			r.Get("/events", a.getEvents)
+			r.Get("/node/syncing", a.getSyncing)
+			r.Get("/config/spec", a.getSpec)
 			r.Route("/beacon", func(r chi.Router) {
-				r.Get("/headers/{tag}", nil)      // otterscan
-				r.Get("/blocks/{slot}/root", nil) //otterscan
+				r.Get("/headers/{block_id}", a.getHeader)        // otterscan
+				r.Get("/blocks/{block_id}/root", a.getBlockRoot) //otterscan
 				r.Get("/genesis", a.getGenesis)
 				r.Post("/binded_blocks", nil)
 				r.Post("/blocks", nil)
+				r.Route("/light_client", func(r chi.Router) {
+					r.Get("/bootstrap/{block_root}", a.getLightClientBootstrap)
+					r.Get("/updates", a.getLightClientUpdates)
+					r.Get("/finality_update", a.getLightClientFinalityUpdate)
+					r.Get("/optimistic_update", a.getLightClientOptimisticUpdate)
+				})
 				r.Route("/pool", func(r chi.Router) {
+					r.Get("/attestations", a.getPoolAttestations)
 					r.Post("/attestations", nil)
 					r.Post("/sync_committees", nil)
+					r.Get("/voluntary_exits", a.getPoolVoluntaryExits)
+					r.Post("/voluntary_exits", a.postPoolVoluntaryExits)
+					r.Get("/proposer_slashings", a.getPoolProposerSlashings)
+					r.Post("/proposer_slashings", a.postPoolProposerSlashings)
+					r.Get("/attester_slashings", a.getPoolAttesterSlashings)
+					r.Post("/attester_slashings", a.postPoolAttesterSlashings)
+					r.Get("/bls_to_execution_changes", a.getPoolBLSToExecutionChanges)
+					r.Post("/bls_to_execution_changes", a.postPoolBLSToExecutionChanges)
 				})
-				r.Get("/node/syncing", nil)
-				r.Get("/config/spec", nil)
 				r.Route("/states", func(r chi.Router) {
-					r.Get("/head/validators/{index}", nil) // otterscan
-					r.Get("/head/committees", nil)         // otterscan
 					r.Route("/{state_id}", func(r chi.Router) {
-						r.Get("/validators", nil)
-						r.Get("/fork", nil)
-						r.Get("/validators/{id}", nil)
+						r.Get("/committees", a.getStateCommittees) // otterscan
+						r.Get("/validators", a.getStateValidators)
+						r.Get("/fork", a.getStateFork)
+						r.Get("/validators/{id}", a.getStateValidator) // otterscan
 					})
 				})
 			})
@@ -57,7 +83,7 @@ func (a *ApiHandler) init() {
 				})
 				r.Get("/blinded_blocks/{slot}", nil)
 				r.Get("/attestation_data", nil)
-				r.Get("/aggregate_attestation", nil)
+				r.Get("/aggregate_attestation", a.getAggregateAttestation)
 				r.Post("/aggregate_and_proofs", nil)
 				r.Post("/beacon_committee_subscriptions", nil)
 				r.Post("/sync_committee_subscriptions", nil)
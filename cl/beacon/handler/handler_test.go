@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
+	"github.com/ledgerwatch/erigon/cl/phase1/light_client"
+	"github.com/ledgerwatch/erigon/cl/phase1/pool"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+const forkchoiceTestData = "../../phase1/forkchoice/test_data/"
+
+func setupTestingHandler(t *testing.T) *ApiHandler {
+	h, block := setupTestingHandlerWithEmitters(t, nil, nil)
+	require.NoError(t, h.forkchoiceStore.OnBlock(block, false, true))
+	return h
+}
+
+// setupTestingHandlerWithEmitters returns a handler over the anchor state only,
+// along with the block to be imported next.
+func setupTestingHandlerWithEmitters(t *testing.T, emitters *beaconevents.Emitters, lightClient *light_client.Store) (*ApiHandler, *cltypes.SignedBeaconBlock) {
+	anchorStateEncoded, err := os.ReadFile(forkchoiceTestData + "anchor_state.ssz_snappy")
+	require.NoError(t, err)
+	blockEncoded, err := os.ReadFile(forkchoiceTestData + "block_0x3af8b5b42ca135c75b32abb32b3d71badb73695d3dc638bacfb6c8b7bcbee1a9.ssz_snappy")
+	require.NoError(t, err)
+
+	anchorState := state.New(&clparams.MainnetBeaconConfig)
+	require.NoError(t, utils.DecodeSSZSnappy(anchorState, anchorStateEncoded, int(clparams.AltairVersion)))
+	block := &cltypes.SignedBeaconBlock{}
+	require.NoError(t, utils.DecodeSSZSnappy(block, blockEncoded, int(clparams.AltairVersion)))
+
+	store, err := forkchoice.NewForkChoiceStore(anchorState, nil, nil, nil, lightClient, emitters, false)
+	require.NoError(t, err)
+	store.OnTick(12)
+
+	genesisCfg := &clparams.GenesisConfig{GenesisTime: anchorState.GenesisTime()}
+	return NewApiHandler(genesisCfg, &clparams.MainnetBeaconConfig, store, emitters, pool.NewOperationsPool(), nil, lightClient), block
+}
+
+func doRequest(t *testing.T, h http.Handler, path string, expectedCode int) map[string]any {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, expectedCode, rec.Code, rec.Body.String())
+	resp := map[string]any{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestBlockEndpoints(t *testing.T) {
+	h := setupTestingHandler(t)
+	headRoot := libcommon.HexToHash("0xc9bd7bcb6dfa49dc4e5a67ca75e89062c36b5c300bc25a1b31db4e1a89306071")
+
+	resp := doRequest(t, h, "/eth/v1/beacon/blocks/1/root", http.StatusOK)
+	require.Equal(t, headRoot.Hex(), resp["data"].(map[string]any)["root"])
+	require.Equal(t, false, resp["execution_optimistic"])
+
+	resp = doRequest(t, h, "/eth/v1/beacon/headers/head", http.StatusOK)
+	data := resp["data"].(map[string]any)
+	require.Equal(t, headRoot.Hex(), data["root"])
+	require.Equal(t, true, data["canonical"])
+	require.Equal(t, "1", data["header"].(map[string]any)["message"].(map[string]any)["slot"])
+
+	resp = doRequest(t, h, "/eth/v1/beacon/headers/"+headRoot.Hex(), http.StatusOK)
+	require.Equal(t, headRoot.Hex(), resp["data"].(map[string]any)["root"])
+
+	resp = doRequest(t, h, "/eth/v1/beacon/blocks/2/root", http.StatusNotFound)
+	require.Equal(t, float64(http.StatusNotFound), resp["code"])
+	doRequest(t, h, "/eth/v1/beacon/blocks/foo/root", http.StatusBadRequest)
+}
+
+func TestStateEndpoints(t *testing.T) {
+	h := setupTestingHandler(t)
+
+	resp := doRequest(t, h, "/eth/v1/beacon/states/head/fork", http.StatusOK)
+	fork := resp["data"].(map[string]any)
+	require.Equal(t, "0x01000000", fork["current_version"])
+
+	resp = doRequest(t, h, "/eth/v1/beacon/states/head/validators", http.StatusOK)
+	validators := resp["data"].([]any)
+	require.NotEmpty(t, validators)
+
+	resp = doRequest(t, h, "/eth/v1/beacon/states/1/validators?id=0,1&status=active", http.StatusOK)
+	validators = resp["data"].([]any)
+	require.Len(t, validators, 2)
+	validator := validators[0].(map[string]any)
+	require.Equal(t, "0", validator["index"])
+	require.Equal(t, "active_ongoing", validator["status"])
+	pubkey := validator["validator"].(map[string]any)["pubkey"].(string)
+
+	resp = doRequest(t, h, "/eth/v1/beacon/states/head/validators/"+pubkey, http.StatusOK)
+	require.Equal(t, "0", resp["data"].(map[string]any)["index"])
+
+	resp = doRequest(t, h, "/eth/v1/beacon/states/head/validators?status=exited", http.StatusOK)
+	require.Empty(t, resp["data"])
+
+	doRequest(t, h, "/eth/v1/beacon/states/head/validators/100000000", http.StatusNotFound)
+	doRequest(t, h, "/eth/v1/beacon/states/2/fork", http.StatusNotFound)
+}
+
+func TestStateCommittees(t *testing.T) {
+	h, block := setupTestingHandlerWithEmitters(t, nil, nil)
+	require.NoError(t, h.forkchoiceStore.OnBlock(block, false, true))
+
+	// the state is found by its root as well
+	stateRoot := libcommon.Hash(block.Block.StateRoot).Hex()
+	resp := doRequest(t, h, "/eth/v1/beacon/states/"+stateRoot+"/committees", http.StatusOK)
+	committees := resp["data"].([]any)
+	require.Len(t, committees, int(clparams.MainnetBeaconConfig.SlotsPerEpoch))
+	committee := committees[1].(map[string]any)
+	require.Equal(t, "0", committee["index"])
+	require.Equal(t, "1", committee["slot"])
+	require.NotEmpty(t, committee["validators"])
+
+	resp = doRequest(t, h, "/eth/v1/beacon/states/head/committees?slot=1&index=0", http.StatusOK)
+	require.Equal(t, []any{committee}, resp["data"])
+	resp = doRequest(t, h, "/eth/v1/beacon/states/head/committees?epoch=1", http.StatusOK)
+	require.Equal(t, "32", resp["data"].([]any)[0].(map[string]any)["slot"])
+
+	doRequest(t, h, "/eth/v1/beacon/states/head/committees?epoch=2", http.StatusBadRequest)
+	doRequest(t, h, "/eth/v1/beacon/states/head/committees?slot=32", http.StatusBadRequest)
+	doRequest(t, h, "/eth/v1/beacon/states/"+libcommon.Hash{1}.Hex()+"/committees", http.StatusNotFound)
+}
+
+func TestNodeEndpoints(t *testing.T) {
+	h := setupTestingHandler(t)
+
+	resp := doRequest(t, h, "/eth/v1/config/spec", http.StatusOK)
+	spec := resp["data"].(map[string]any)
+	require.Equal(t, "12", spec["SECONDS_PER_SLOT"])
+	require.Equal(t, "0x00", spec["BLS_WITHDRAWAL_PREFIX"])
+	require.Equal(t, "0x00000000", spec["GENESIS_FORK_VERSION"])
+
+	resp = doRequest(t, h, "/eth/v1/node/syncing", http.StatusOK)
+	require.Equal(t, "1", resp["data"].(map[string]any)["head_slot"])
+}
+
+func TestEventsEndpoint(t *testing.T) {
+	emitters := beaconevents.NewEmitters()
+	h, block := setupTestingHandlerWithEmitters(t, emitters, nil)
+	server := httptest.NewServer(h)
+	defer server.Close()
+
+	doRequest(t, h, "/eth/v1/events", http.StatusBadRequest)
+	doRequest(t, h, "/eth/v1/events?topics=head,voluntary_exit", http.StatusBadRequest)
+
+	resp, err := http.Get(server.URL + "/eth/v1/events?topics=block,head")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	require.True(t, emitters.HasSubscribers(beaconevents.TopicBlock))
+	require.False(t, emitters.HasSubscribers(beaconevents.TopicAttestation))
+
+	require.NoError(t, h.forkchoiceStore.OnBlock(block, false, true))
+	headRoot := "0xc9bd7bcb6dfa49dc4e5a67ca75e89062c36b5c300bc25a1b31db4e1a89306071"
+
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() (string, map[string]any) {
+		var topic string
+		data := map[string]any{}
+		for {
+			line, err := reader.ReadString('\n')
+			require.NoError(t, err)
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				topic = strings.TrimSpace(strings.TrimPrefix(line, "event: "))
+			case strings.HasPrefix(line, "data: "):
+				require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &data))
+			case line == "\n":
+				return topic, data
+			}
+		}
+	}
+	topic, data := readEvent()
+	require.Equal(t, beaconevents.TopicBlock, topic)
+	require.Equal(t, "1", data["slot"])
+	require.Equal(t, headRoot, data["block"])
+
+	topic, data = readEvent()
+	require.Equal(t, beaconevents.TopicHead, topic)
+	require.Equal(t, "1", data["slot"])
+	require.Equal(t, headRoot, data["block"])
+	require.Equal(t, false, data["epoch_transition"])
+}
+
+func TestPoolEndpoints(t *testing.T) {
+	h := setupTestingHandler(t)
+
+	for _, path := range []string{"voluntary_exits", "proposer_slashings", "attester_slashings", "bls_to_execution_changes", "attestations"} {
+		resp := doRequest(t, h, "/eth/v1/beacon/pool/"+path, http.StatusOK)
+		require.Empty(t, resp["data"], path)
+	}
+	doRequest(t, h, "/eth/v1/beacon/pool/attestations?slot=foo", http.StatusBadRequest)
+	doRequest(t, h, "/eth/v1/validator/aggregate_attestation?attestation_data_root="+libcommon.Hash{}.Hex()+"&slot=1", http.StatusNotFound)
+	doRequest(t, h, "/eth/v1/validator/aggregate_attestation?slot=1", http.StatusBadRequest)
+
+	signature := "0x" + strings.Repeat("00", 96)
+	for path, body := range map[string]string{
+		"voluntary_exits":          `{"message":{"epoch":"0","validator_index":"1"},"signature":"` + signature + `"}`,
+		"proposer_slashings":       `{"signed_header_1":{"message":{"slot":"1","proposer_index":"1"},"signature":"` + signature + `"},"signed_header_2":{"message":{"slot":"1","proposer_index":"1"},"signature":"` + signature + `"}}`,
+		"bls_to_execution_changes": `[{"message":{"validator_index":"1","from_bls_pubkey":"0x` + strings.Repeat("00", 48) + `","to_execution_address":"0x0000000000000000000000000000000000000001"},"signature":"` + signature + `"}]`,
+		"attester_slashings":       `{"attestation_1":{}}`,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/eth/v1/beacon/pool/"+path, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusBadRequest, rec.Code, path)
+	}
+}
+
+func TestLightClientEndpoints(t *testing.T) {
+	lightClient, err := light_client.NewStore(&clparams.MainnetBeaconConfig, nil)
+	require.NoError(t, err)
+	h, block := setupTestingHandlerWithEmitters(t, nil, lightClient)
+
+	doRequest(t, h, "/eth/v1/beacon/light_client/optimistic_update", http.StatusNotFound)
+	doRequest(t, h, "/eth/v1/beacon/light_client/finality_update", http.StatusNotFound)
+	doRequest(t, h, "/eth/v1/beacon/light_client/bootstrap/0x1234", http.StatusBadRequest)
+	doRequest(t, h, "/eth/v1/beacon/light_client/bootstrap/"+libcommon.Hash{}.Hex(), http.StatusNotFound)
+	doRequest(t, h, "/eth/v1/beacon/light_client/updates?start_period=foo&count=1", http.StatusBadRequest)
+
+	// the test blocks are not signed by the sync committee, so only their bootstraps are available
+	require.NoError(t, h.forkchoiceStore.OnBlock(block, false, true))
+	blockRoot, err := block.Block.HashSSZ()
+	require.NoError(t, err)
+	resp := doRequest(t, h, "/eth/v1/beacon/light_client/bootstrap/"+libcommon.Hash(blockRoot).Hex(), http.StatusOK)
+	require.Equal(t, "altair", resp["version"])
+	data := resp["data"].(map[string]any)
+	require.Equal(t, "1", data["header"].(map[string]any)["beacon"].(map[string]any)["slot"])
+	require.Len(t, data["current_sync_committee_branch"], cltypes.SyncCommitteeBranchSize)
+	require.Len(t, data["current_sync_committee"].(map[string]any)["pubkeys"], int(clparams.MainnetBeaconConfig.SyncCommitteeSize))
+
+	doRequest(t, h, "/eth/v1/beacon/light_client/optimistic_update", http.StatusNotFound)
+	req := httptest.NewRequest(http.MethodGet, "/eth/v1/beacon/light_client/updates?start_period=0&count=1", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "[]\n", rec.Body.String())
+}
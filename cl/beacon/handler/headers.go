@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/types"
+)
+
+type headerJSON struct {
+	Slot          types.Uint64   `json:"slot"`
+	ProposerIndex types.Uint64   `json:"proposer_index"`
+	ParentRoot    libcommon.Hash `json:"parent_root"`
+	StateRoot     libcommon.Hash `json:"state_root"`
+	BodyRoot      libcommon.Hash `json:"body_root"`
+}
+
+type signedHeaderJSON struct {
+	Message   headerJSON       `json:"message"`
+	Signature hexutility.Bytes `json:"signature"`
+}
+
+type headerResponse struct {
+	Root      libcommon.Hash   `json:"root"`
+	Canonical bool             `json:"canonical"`
+	Header    signedHeaderJSON `json:"header"`
+}
+
+type rootResponse struct {
+	Root libcommon.Hash `json:"root"`
+}
+
+func (a *ApiHandler) getHeader(w http.ResponseWriter, r *http.Request) {
+	blockID := chi.URLParam(r, "block_id")
+	root, err := a.blockRootFromBlockID(blockID)
+	if err != nil {
+		writeIdError(w, err)
+		return
+	}
+	header, ok := a.forkchoiceStore.GetHeader(root)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Block not found: "+blockID)
+		return
+	}
+	// The anchor block is only known by its header
+	signature, _ := a.forkchoiceStore.GetBlockSignature(root)
+	canonicalRoot, _, err := a.forkchoiceStore.GetCanonicalBlockRoot(header.Slot)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeResponse(w, newBeaconResponse(headerResponse{
+		Root:      root,
+		Canonical: canonicalRoot == root,
+		Header: signedHeaderJSON{
+			Message: headerJSON{
+				Slot:          types.Uint64(header.Slot),
+				ProposerIndex: types.Uint64(header.ProposerIndex),
+				ParentRoot:    header.ParentRoot,
+				StateRoot:     header.Root,
+				BodyRoot:      header.BodyRoot,
+			},
+			Signature: signature[:],
+		},
+	}).withFinalized(a.isFinalized(header.Slot)))
+}
+
+func (a *ApiHandler) getBlockRoot(w http.ResponseWriter, r *http.Request) {
+	blockID := chi.URLParam(r, "block_id")
+	root, err := a.blockRootFromBlockID(blockID)
+	if err != nil {
+		writeIdError(w, err)
+		return
+	}
+	header, ok := a.forkchoiceStore.GetHeader(root)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Block not found: "+blockID)
+		return
+	}
+	writeResponse(w, newBeaconResponse(rootResponse{Root: root}).withFinalized(a.isFinalized(header.Slot)))
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+)
+
+// idError is an error which maps to an HTTP status code.
+type idError struct {
+	code    int
+	message string
+}
+
+func (e *idError) Error() string { return e.message }
+
+func newIdError(code int, format string, args ...any) *idError {
+	return &idError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+func writeIdError(w http.ResponseWriter, err error) {
+	if idErr, ok := err.(*idError); ok {
+		writeError(w, idErr.code, idErr.message)
+		return
+	}
+	writeError(w, http.StatusInternalServerError, err.Error())
+}
+
+// blockRootFromBlockID resolves a block_id (head, genesis, finalized,
+// justified, slot or block root) into the root of a known block.
+func (a *ApiHandler) blockRootFromBlockID(blockID string) (libcommon.Hash, error) {
+	return a.resolveID(blockID, false)
+}
+
+// blockRootFromStateID resolves a state_id (head, genesis, finalized,
+// justified, slot or state root) into the root of the block whose post-state
+// it designates.
+func (a *ApiHandler) blockRootFromStateID(stateID string) (libcommon.Hash, error) {
+	return a.resolveID(stateID, true)
+}
+
+// blockRootFromHex parses a 0x prefixed block root.
+func blockRootFromHex(id string) (libcommon.Hash, error) {
+	if !strings.HasPrefix(id, "0x") || len(id) != 2+2*length.Hash {
+		return libcommon.Hash{}, newIdError(http.StatusBadRequest, "Invalid block root: %s", id)
+	}
+	return libcommon.HexToHash(id), nil
+}
+
+func (a *ApiHandler) resolveID(id string, isState bool) (libcommon.Hash, error) {
+	switch id {
+	case "head":
+		root, _, err := a.forkchoiceStore.GetHead()
+		return root, err
+	case "finalized":
+		return a.forkchoiceStore.FinalizedCheckpoint().BlockRoot(), nil
+	case "justified":
+		return a.forkchoiceStore.JustifiedCheckpoint().BlockRoot(), nil
+	case "genesis":
+		return a.blockRootAtSlot(0)
+	}
+
+	if strings.HasPrefix(id, "0x") {
+		if len(id) != 2+2*length.Hash {
+			return libcommon.Hash{}, newIdError(http.StatusBadRequest, "Invalid ID: %s", id)
+		}
+		root := libcommon.HexToHash(id)
+		if isState {
+			blockRoot, ok := a.forkchoiceStore.GetBlockRootByStateRoot(root)
+			if !ok {
+				return libcommon.Hash{}, newIdError(http.StatusNotFound, "State not found: %s", id)
+			}
+			return blockRoot, nil
+		}
+		if _, ok := a.forkchoiceStore.GetHeader(root); !ok {
+			return libcommon.Hash{}, newIdError(http.StatusNotFound, "Block not found: %s", id)
+		}
+		return root, nil
+	}
+
+	slot, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return libcommon.Hash{}, newIdError(http.StatusBadRequest, "Invalid ID: %s", id)
+	}
+	return a.blockRootAtSlot(slot)
+}
+
+func (a *ApiHandler) blockRootAtSlot(slot uint64) (libcommon.Hash, error) {
+	root, ok, err := a.forkchoiceStore.GetCanonicalBlockRoot(slot)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	if !ok {
+		return libcommon.Hash{}, newIdError(http.StatusNotFound, "No block found at slot %d", slot)
+	}
+	return root, nil
+}
+
+// isFinalized reports whether the block at the given slot is final.
+func (a *ApiHandler) isFinalized(slot uint64) bool {
+	return slot <= a.forkchoiceStore.FinalizedSlot()
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"math/big"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/types"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+)
+
+// maxRequestLightClientUpdates is MAX_REQUEST_LIGHT_CLIENT_UPDATES
+const maxRequestLightClientUpdates = 128
+
+type executionPayloadHeaderJSON struct {
+	ParentHash       libcommon.Hash    `json:"parent_hash"`
+	FeeRecipient     libcommon.Address `json:"fee_recipient"`
+	StateRoot        libcommon.Hash    `json:"state_root"`
+	ReceiptsRoot     libcommon.Hash    `json:"receipts_root"`
+	LogsBloom        hexutility.Bytes  `json:"logs_bloom"`
+	PrevRandao       libcommon.Hash    `json:"prev_randao"`
+	BlockNumber      types.Uint64      `json:"block_number"`
+	GasLimit         types.Uint64      `json:"gas_limit"`
+	GasUsed          types.Uint64      `json:"gas_used"`
+	Timestamp        types.Uint64      `json:"timestamp"`
+	ExtraData        hexutility.Bytes  `json:"extra_data"`
+	BaseFeePerGas    string            `json:"base_fee_per_gas"`
+	BlockHash        libcommon.Hash    `json:"block_hash"`
+	TransactionsRoot libcommon.Hash    `json:"transactions_root"`
+	WithdrawalsRoot  libcommon.Hash    `json:"withdrawals_root"`
+	DataGasUsed      *types.Uint64     `json:"data_gas_used,omitempty"`
+	ExcessDataGas    *types.Uint64     `json:"excess_data_gas,omitempty"`
+}
+
+type lightClientHeaderJSON struct {
+	Beacon          headerJSON                  `json:"beacon"`
+	Execution       *executionPayloadHeaderJSON `json:"execution,omitempty"`
+	ExecutionBranch []libcommon.Hash            `json:"execution_branch,omitempty"`
+}
+
+type syncCommitteeJSON struct {
+	Pubkeys         []hexutility.Bytes `json:"pubkeys"`
+	AggregatePubkey hexutility.Bytes   `json:"aggregate_pubkey"`
+}
+
+type syncAggregateJSON struct {
+	SyncCommitteeBits      hexutility.Bytes `json:"sync_committee_bits"`
+	SyncCommitteeSignature hexutility.Bytes `json:"sync_committee_signature"`
+}
+
+type lightClientBootstrapJSON struct {
+	Header                     lightClientHeaderJSON `json:"header"`
+	CurrentSyncCommittee       syncCommitteeJSON     `json:"current_sync_committee"`
+	CurrentSyncCommitteeBranch []libcommon.Hash      `json:"current_sync_committee_branch"`
+}
+
+type lightClientUpdateJSON struct {
+	AttestedHeader          lightClientHeaderJSON `json:"attested_header"`
+	NextSyncCommittee       syncCommitteeJSON     `json:"next_sync_committee"`
+	NextSyncCommitteeBranch []libcommon.Hash      `json:"next_sync_committee_branch"`
+	FinalizedHeader         lightClientHeaderJSON `json:"finalized_header"`
+	FinalityBranch          []libcommon.Hash      `json:"finality_branch"`
+	SyncAggregate           syncAggregateJSON     `json:"sync_aggregate"`
+	SignatureSlot           types.Uint64          `json:"signature_slot"`
+}
+
+type lightClientFinalityUpdateJSON struct {
+	AttestedHeader  lightClientHeaderJSON `json:"attested_header"`
+	FinalizedHeader lightClientHeaderJSON `json:"finalized_header"`
+	FinalityBranch  []libcommon.Hash      `json:"finality_branch"`
+	SyncAggregate   syncAggregateJSON     `json:"sync_aggregate"`
+	SignatureSlot   types.Uint64          `json:"signature_slot"`
+}
+
+type lightClientOptimisticUpdateJSON struct {
+	AttestedHeader lightClientHeaderJSON `json:"attested_header"`
+	SyncAggregate  syncAggregateJSON     `json:"sync_aggregate"`
+	SignatureSlot  types.Uint64          `json:"signature_slot"`
+}
+
+func branchToJSON(branch solid.HashVectorSSZ) []libcommon.Hash {
+	out := make([]libcommon.Hash, 0, branch.Length())
+	branch.Range(func(_ int, node libcommon.Hash, _ int) bool {
+		out = append(out, node)
+		return true
+	})
+	return out
+}
+
+func executionPayloadHeaderToJSON(h *cltypes.Eth1Header, version clparams.StateVersion) *executionPayloadHeaderJSON {
+	// the base fee is a little endian uint256
+	baseFee := make([]byte, len(h.BaseFeePerGas))
+	for i := range h.BaseFeePerGas {
+		baseFee[len(baseFee)-1-i] = h.BaseFeePerGas[i]
+	}
+	out := &executionPayloadHeaderJSON{
+		ParentHash:       h.ParentHash,
+		FeeRecipient:     h.FeeRecipient,
+		StateRoot:        h.StateRoot,
+		ReceiptsRoot:     h.ReceiptsRoot,
+		LogsBloom:        h.LogsBloom[:],
+		PrevRandao:       h.PrevRandao,
+		BlockNumber:      types.Uint64(h.BlockNumber),
+		GasLimit:         types.Uint64(h.GasLimit),
+		GasUsed:          types.Uint64(h.GasUsed),
+		Timestamp:        types.Uint64(h.Time),
+		ExtraData:        h.Extra.Bytes(),
+		BaseFeePerGas:    new(big.Int).SetBytes(baseFee).String(),
+		BlockHash:        h.BlockHash,
+		TransactionsRoot: h.TransactionsRoot,
+		WithdrawalsRoot:  h.WithdrawalsRoot,
+	}
+	if version >= clparams.DenebVersion {
+		dataGasUsed, excessDataGas := types.Uint64(h.DataGasUsed), types.Uint64(h.ExcessDataGas)
+		out.DataGasUsed, out.ExcessDataGas = &dataGasUsed, &excessDataGas
+	}
+	return out
+}
+
+func lightClientHeaderToJSON(h *cltypes.LightClientHeader) lightClientHeaderJSON {
+	out := lightClientHeaderJSON{
+		Beacon: headerJSON{
+			Slot:          types.Uint64(h.Beacon.Slot),
+			ProposerIndex: types.Uint64(h.Beacon.ProposerIndex),
+			ParentRoot:    h.Beacon.ParentRoot,
+			StateRoot:     h.Beacon.Root,
+			BodyRoot:      h.Beacon.BodyRoot,
+		},
+	}
+	if h.Version() >= clparams.CapellaVersion {
+		out.Execution = executionPayloadHeaderToJSON(h.ExecutionPayloadHeader, h.Version())
+		out.ExecutionBranch = branchToJSON(h.ExecutionBranch)
+	}
+	return out
+}
+
+func syncCommitteeToJSON(s *solid.SyncCommittee) syncCommitteeJSON {
+	out := syncCommitteeJSON{}
+	for _, pk := range s.GetCommittee() {
+		out.Pubkeys = append(out.Pubkeys, libcommon.Copy(pk[:]))
+	}
+	aggregate := s.AggregatePublicKey()
+	out.AggregatePubkey = aggregate[:]
+	return out
+}
+
+func syncAggregateToJSON(s *cltypes.SyncAggregate) syncAggregateJSON {
+	return syncAggregateJSON{
+		SyncCommitteeBits:      libcommon.Copy(s.SyncCommiteeBits[:]),
+		SyncCommitteeSignature: libcommon.Copy(s.SyncCommiteeSignature[:]),
+	}
+}
+
+func lightClientUpdateToJSON(u *cltypes.LightClientUpdate) lightClientUpdateJSON {
+	return lightClientUpdateJSON{
+		AttestedHeader:          lightClientHeaderToJSON(u.AttestedHeader),
+		NextSyncCommittee:       syncCommitteeToJSON(u.NextSyncCommittee),
+		NextSyncCommitteeBranch: branchToJSON(u.NextSyncCommitteeBranch),
+		FinalizedHeader:         lightClientHeaderToJSON(u.FinalizedHeader),
+		FinalityBranch:          branchToJSON(u.FinalityBranch),
+		SyncAggregate:           syncAggregateToJSON(u.SyncAggregate),
+		SignatureSlot:           types.Uint64(u.SignatureSlot),
+	}
+}
+
+func (a *ApiHandler) getLightClientBootstrap(w http.ResponseWriter, r *http.Request) {
+	root, err := blockRootFromHex(chi.URLParam(r, "block_root"))
+	if err != nil {
+		writeIdError(w, err)
+		return
+	}
+	if a.lightClient == nil {
+		writeError(w, http.StatusNotFound, "Light client data is not produced")
+		return
+	}
+	bootstrap, ok := a.lightClient.Bootstrap(root)
+	if !ok {
+		writeError(w, http.StatusNotFound, "Bootstrap not available for block root "+root.Hex())
+		return
+	}
+	writeResponse(w, newBeaconResponse(lightClientBootstrapJSON{
+		Header:                     lightClientHeaderToJSON(bootstrap.Header),
+		CurrentSyncCommittee:       syncCommitteeToJSON(bootstrap.CurrentSyncCommittee),
+		CurrentSyncCommitteeBranch: branchToJSON(bootstrap.CurrentSyncCommitteeBranch),
+	}).withVersion(bootstrap.Version()))
+}
+
+func (a *ApiHandler) getLightClientUpdates(w http.ResponseWriter, r *http.Request) {
+	startPeriod, err := strconv.ParseUint(r.URL.Query().Get("start_period"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid start_period: "+err.Error())
+		return
+	}
+	count, err := strconv.ParseUint(r.URL.Query().Get("count"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid count: "+err.Error())
+		return
+	}
+	if count > maxRequestLightClientUpdates {
+		count = maxRequestLightClientUpdates
+	}
+	responses := []*beaconResponse{}
+	if a.lightClient != nil {
+		for _, update := range a.lightClient.Updates(startPeriod, count) {
+			responses = append(responses, newBeaconResponse(lightClientUpdateToJSON(update)).withVersion(update.Version()))
+		}
+	}
+	writeJSON(w, responses)
+}
+
+func (a *ApiHandler) getLightClientFinalityUpdate(w http.ResponseWriter, r *http.Request) {
+	if a.lightClient == nil {
+		writeError(w, http.StatusNotFound, "Light client data is not produced")
+		return
+	}
+	update, ok := a.lightClient.FinalityUpdate()
+	if !ok {
+		writeError(w, http.StatusNotFound, "No finality update available")
+		return
+	}
+	writeResponse(w, newBeaconResponse(lightClientFinalityUpdateJSON{
+		AttestedHeader:  lightClientHeaderToJSON(update.AttestedHeader),
+		FinalizedHeader: lightClientHeaderToJSON(update.FinalizedHeader),
+		FinalityBranch:  branchToJSON(update.FinalityBranch),
+		SyncAggregate:   syncAggregateToJSON(update.SyncAggregate),
+		SignatureSlot:   types.Uint64(update.SignatureSlot),
+	}).withVersion(update.Version()))
+}
+
+func (a *ApiHandler) getLightClientOptimisticUpdate(w http.ResponseWriter, r *http.Request) {
+	if a.lightClient == nil {
+		writeError(w, http.StatusNotFound, "Light client data is not produced")
+		return
+	}
+	update, ok := a.lightClient.OptimisticUpdate()
+	if !ok {
+		writeError(w, http.StatusNotFound, "No optimistic update available")
+		return
+	}
+	writeResponse(w, newBeaconResponse(lightClientOptimisticUpdateJSON{
+		AttestedHeader: lightClientHeaderToJSON(update.AttestedHeader),
+		SyncAggregate:  syncAggregateToJSON(update.SyncAggregate),
+		SignatureSlot:  types.Uint64(update.SignatureSlot),
+	}).withVersion(update.Version()))
+}
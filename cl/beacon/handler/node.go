@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/types"
+)
+
+type syncingResponse struct {
+	HeadSlot     types.Uint64 `json:"head_slot"`
+	SyncDistance types.Uint64 `json:"sync_distance"`
+	IsSyncing    bool         `json:"is_syncing"`
+	IsOptimistic bool         `json:"is_optimistic"`
+	ElOffline    bool         `json:"el_offline"`
+}
+
+func (a *ApiHandler) getSyncing(w http.ResponseWriter, _ *http.Request) {
+	_, headSlot, err := a.forkchoiceStore.GetHead()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	var currentSlot uint64
+	if now := uint64(time.Now().Unix()); now > a.genesisCfg.GenesisTime {
+		currentSlot = (now - a.genesisCfg.GenesisTime) / a.beaconChainCfg.SecondsPerSlot
+	}
+	var distance uint64
+	if currentSlot > headSlot {
+		distance = currentSlot - headSlot
+	}
+	writeResponse(w, newBeaconResponse(syncingResponse{
+		HeadSlot:     types.Uint64(headSlot),
+		SyncDistance: types.Uint64(distance),
+		// the block of the current slot may not have been received yet
+		IsSyncing: distance > 1,
+	}))
+}
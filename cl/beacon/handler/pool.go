@@ -0,0 +1,431 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/sentinel"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/types"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/phase1/pool"
+	"github.com/ledgerwatch/erigon/cmd/sentinel/sentinel/gossip"
+)
+
+type voluntaryExitJSON struct {
+	Epoch          types.Uint64 `json:"epoch"`
+	ValidatorIndex types.Uint64 `json:"validator_index"`
+}
+
+type signedVoluntaryExitJSON struct {
+	Message   voluntaryExitJSON `json:"message"`
+	Signature hexutility.Bytes  `json:"signature"`
+}
+
+type proposerSlashingJSON struct {
+	SignedHeader1 signedHeaderJSON `json:"signed_header_1"`
+	SignedHeader2 signedHeaderJSON `json:"signed_header_2"`
+}
+
+type checkpointJSON struct {
+	Epoch types.Uint64   `json:"epoch"`
+	Root  libcommon.Hash `json:"root"`
+}
+
+type attestationDataJSON struct {
+	Slot            types.Uint64   `json:"slot"`
+	Index           types.Uint64   `json:"index"`
+	BeaconBlockRoot libcommon.Hash `json:"beacon_block_root"`
+	Source          checkpointJSON `json:"source"`
+	Target          checkpointJSON `json:"target"`
+}
+
+type indexedAttestationJSON struct {
+	AttestingIndices []types.Uint64      `json:"attesting_indices"`
+	Data             attestationDataJSON `json:"data"`
+	Signature        hexutility.Bytes    `json:"signature"`
+}
+
+type attesterSlashingJSON struct {
+	Attestation1 indexedAttestationJSON `json:"attestation_1"`
+	Attestation2 indexedAttestationJSON `json:"attestation_2"`
+}
+
+type blsToExecutionChangeJSON struct {
+	ValidatorIndex     types.Uint64      `json:"validator_index"`
+	FromBLSPubkey      hexutility.Bytes  `json:"from_bls_pubkey"`
+	ToExecutionAddress libcommon.Address `json:"to_execution_address"`
+}
+
+type signedBLSToExecutionChangeJSON struct {
+	Message   blsToExecutionChangeJSON `json:"message"`
+	Signature hexutility.Bytes         `json:"signature"`
+}
+
+type attestationJSON struct {
+	AggregationBits hexutility.Bytes    `json:"aggregation_bits"`
+	Data            attestationDataJSON `json:"data"`
+	Signature       hexutility.Bytes    `json:"signature"`
+}
+
+func signatureFromJSON(b hexutility.Bytes) (signature [96]byte, err error) {
+	if len(b) != len(signature) {
+		return signature, fmt.Errorf("invalid signature length %d", len(b))
+	}
+	copy(signature[:], b)
+	return signature, nil
+}
+
+func signedHeaderToJSON(h *cltypes.SignedBeaconBlockHeader) signedHeaderJSON {
+	return signedHeaderJSON{
+		Message: headerJSON{
+			Slot:          types.Uint64(h.Header.Slot),
+			ProposerIndex: types.Uint64(h.Header.ProposerIndex),
+			ParentRoot:    h.Header.ParentRoot,
+			StateRoot:     h.Header.Root,
+			BodyRoot:      h.Header.BodyRoot,
+		},
+		Signature: h.Signature[:],
+	}
+}
+
+func signedHeaderFromJSON(h signedHeaderJSON) (*cltypes.SignedBeaconBlockHeader, error) {
+	signature, err := signatureFromJSON(h.Signature)
+	if err != nil {
+		return nil, err
+	}
+	return &cltypes.SignedBeaconBlockHeader{
+		Header: &cltypes.BeaconBlockHeader{
+			Slot:          uint64(h.Message.Slot),
+			ProposerIndex: uint64(h.Message.ProposerIndex),
+			ParentRoot:    h.Message.ParentRoot,
+			Root:          h.Message.StateRoot,
+			BodyRoot:      h.Message.BodyRoot,
+		},
+		Signature: signature,
+	}, nil
+}
+
+func attestationDataToJSON(d solid.AttestationData) attestationDataJSON {
+	return attestationDataJSON{
+		Slot:            types.Uint64(d.Slot()),
+		Index:           types.Uint64(d.ValidatorIndex()),
+		BeaconBlockRoot: d.BeaconBlockRoot(),
+		Source:          checkpointJSON{Epoch: types.Uint64(d.Source().Epoch()), Root: d.Source().BlockRoot()},
+		Target:          checkpointJSON{Epoch: types.Uint64(d.Target().Epoch()), Root: d.Target().BlockRoot()},
+	}
+}
+
+func attestationDataFromJSON(d attestationDataJSON) solid.AttestationData {
+	return solid.NewAttestionDataFromParameters(uint64(d.Slot), uint64(d.Index), d.BeaconBlockRoot,
+		solid.NewCheckpointFromParameters(d.Source.Root, uint64(d.Source.Epoch)),
+		solid.NewCheckpointFromParameters(d.Target.Root, uint64(d.Target.Epoch)))
+}
+
+func indexedAttestationToJSON(a *cltypes.IndexedAttestation) indexedAttestationJSON {
+	indices := make([]types.Uint64, 0, a.AttestingIndices.Length())
+	a.AttestingIndices.Range(func(_ int, index uint64, _ int) bool {
+		indices = append(indices, types.Uint64(index))
+		return true
+	})
+	return indexedAttestationJSON{
+		AttestingIndices: indices,
+		Data:             attestationDataToJSON(a.Data),
+		Signature:        a.Signature[:],
+	}
+}
+
+func indexedAttestationFromJSON(a indexedAttestationJSON, maxValidatorsPerCommittee int) (*cltypes.IndexedAttestation, error) {
+	if len(a.AttestingIndices) > maxValidatorsPerCommittee {
+		return nil, fmt.Errorf("too many attesting indices: %d", len(a.AttestingIndices))
+	}
+	signature, err := signatureFromJSON(a.Signature)
+	if err != nil {
+		return nil, err
+	}
+	indices := make([]uint64, len(a.AttestingIndices))
+	for i, index := range a.AttestingIndices {
+		indices[i] = uint64(index)
+	}
+	return &cltypes.IndexedAttestation{
+		AttestingIndices: solid.NewUint64ListSSZFromSlice(maxValidatorsPerCommittee, indices),
+		Data:             attestationDataFromJSON(a.Data),
+		Signature:        signature,
+	}, nil
+}
+
+func (a *ApiHandler) getPoolVoluntaryExits(w http.ResponseWriter, r *http.Request) {
+	exits := a.operationsPool.VoluntaryExits()
+	resp := make([]signedVoluntaryExitJSON, 0, len(exits))
+	for _, exit := range exits {
+		resp = append(resp, signedVoluntaryExitJSON{
+			Message: voluntaryExitJSON{
+				Epoch:          types.Uint64(exit.VolunaryExit.Epoch),
+				ValidatorIndex: types.Uint64(exit.VolunaryExit.ValidatorIndex),
+			},
+			Signature: exit.Signature[:],
+		})
+	}
+	writeResponse(w, newBeaconResponse(resp))
+}
+
+func (a *ApiHandler) getPoolProposerSlashings(w http.ResponseWriter, r *http.Request) {
+	slashings := a.operationsPool.ProposerSlashings()
+	resp := make([]proposerSlashingJSON, 0, len(slashings))
+	for _, slashing := range slashings {
+		resp = append(resp, proposerSlashingJSON{
+			SignedHeader1: signedHeaderToJSON(slashing.Header1),
+			SignedHeader2: signedHeaderToJSON(slashing.Header2),
+		})
+	}
+	writeResponse(w, newBeaconResponse(resp))
+}
+
+func (a *ApiHandler) getPoolAttesterSlashings(w http.ResponseWriter, r *http.Request) {
+	slashings := a.operationsPool.AttesterSlashings()
+	resp := make([]attesterSlashingJSON, 0, len(slashings))
+	for _, slashing := range slashings {
+		resp = append(resp, attesterSlashingJSON{
+			Attestation1: indexedAttestationToJSON(slashing.Attestation_1),
+			Attestation2: indexedAttestationToJSON(slashing.Attestation_2),
+		})
+	}
+	writeResponse(w, newBeaconResponse(resp))
+}
+
+func (a *ApiHandler) getPoolBLSToExecutionChanges(w http.ResponseWriter, r *http.Request) {
+	changes := a.operationsPool.BLSToExecutionChanges()
+	resp := make([]signedBLSToExecutionChangeJSON, 0, len(changes))
+	for _, change := range changes {
+		resp = append(resp, signedBLSToExecutionChangeJSON{
+			Message: blsToExecutionChangeJSON{
+				ValidatorIndex:     types.Uint64(change.Message.ValidatorIndex),
+				FromBLSPubkey:      change.Message.From[:],
+				ToExecutionAddress: change.Message.To,
+			},
+			Signature: change.Signature[:],
+		})
+	}
+	writeResponse(w, newBeaconResponse(resp))
+}
+
+func attestationToJSON(att *solid.Attestation) attestationJSON {
+	signature := att.Signature()
+	return attestationJSON{
+		AggregationBits: att.AggregationBits(),
+		Data:            attestationDataToJSON(att.AttestantionData()),
+		Signature:       signature[:],
+	}
+}
+
+// parseOptionalUint64 parses the query parameter if it is set
+func parseOptionalUint64(r *http.Request, name string) (value uint64, set bool, err error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return 0, false, nil
+	}
+	value, err = strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid %s: %s", name, s)
+	}
+	return value, true, nil
+}
+
+// getPoolAttestations returns the aggregates of the pool, as Caplin does not keep the unaggregated attestations
+func (a *ApiHandler) getPoolAttestations(w http.ResponseWriter, r *http.Request) {
+	slot, filterSlot, err := parseOptionalUint64(r, "slot")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	committeeIndex, filterCommittee, err := parseOptionalUint64(r, "committee_index")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	resp := []attestationJSON{}
+	for _, aggregate := range a.operationsPool.Aggregates() {
+		data := aggregate.AttestantionData()
+		if (filterSlot && data.Slot() != slot) || (filterCommittee && data.ValidatorIndex() != committeeIndex) {
+			continue
+		}
+		resp = append(resp, attestationToJSON(aggregate))
+	}
+	writeResponse(w, newBeaconResponse(resp))
+}
+
+func (a *ApiHandler) getAggregateAttestation(w http.ResponseWriter, r *http.Request) {
+	var dataRoot libcommon.Hash
+	if err := dataRoot.UnmarshalText([]byte(r.URL.Query().Get("attestation_data_root"))); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid attestation_data_root: "+err.Error())
+		return
+	}
+	slot, filterSlot, err := parseOptionalUint64(r, "slot")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if !filterSlot {
+		writeError(w, http.StatusBadRequest, "missing slot")
+		return
+	}
+	aggregate, ok := a.operationsPool.Aggregate(dataRoot)
+	if !ok || aggregate.AttestantionData().Slot() != slot {
+		writeError(w, http.StatusNotFound, "No aggregate found for attestation data root "+dataRoot.String())
+		return
+	}
+	writeResponse(w, newBeaconResponse(attestationToJSON(aggregate)))
+}
+
+// publishOperation publishes the operation on its gossip topic, the failures are only logged as the pool has it anyway
+func (a *ApiHandler) publishOperation(ctx context.Context, gossipType sentinel.GossipType, obj interface {
+	EncodeSSZ([]byte) ([]byte, error)
+}) error {
+	if a.sentinel == nil {
+		return nil
+	}
+	encoded, err := obj.EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	if _, err := a.sentinel.PublishGossip(ctx, &sentinel.GossipData{Data: encoded, Type: gossipType}); err != nil {
+		log.Debug("[Beacon API] failed to publish operation", "err", err)
+	}
+	return nil
+}
+
+// submitOperation validates the operation against the head state, adds it to the pool and publishes it on its gossip
+// topic
+func (a *ApiHandler) submitOperation(w http.ResponseWriter, r *http.Request, add func(s *state.BeaconState) error, gossipType sentinel.GossipType, obj interface {
+	EncodeSSZ([]byte) ([]byte, error)
+}) {
+	s, err := a.forkchoiceStore.HeadState()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if err := add(s); err != nil {
+		if !errors.Is(err, pool.ErrKnown) {
+			writeError(w, http.StatusBadRequest, "Invalid operation: "+err.Error())
+			return
+		}
+	} else if err := a.publishOperation(r.Context(), gossipType, obj); err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (a *ApiHandler) postPoolVoluntaryExits(w http.ResponseWriter, r *http.Request) {
+	var req signedVoluntaryExitJSON
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	signature, err := signatureFromJSON(req.Signature)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	exit := &cltypes.SignedVoluntaryExit{
+		VolunaryExit: &cltypes.VoluntaryExit{Epoch: uint64(req.Message.Epoch), ValidatorIndex: uint64(req.Message.ValidatorIndex)},
+		Signature:    signature,
+	}
+	a.submitOperation(w, r, func(s *state.BeaconState) error { return a.operationsPool.AddVoluntaryExit(s, exit) }, sentinel.GossipType_VoluntaryExitGossipType, exit)
+}
+
+func (a *ApiHandler) postPoolProposerSlashings(w http.ResponseWriter, r *http.Request) {
+	var req proposerSlashingJSON
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	header1, err := signedHeaderFromJSON(req.SignedHeader1)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	header2, err := signedHeaderFromJSON(req.SignedHeader2)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	slashing := &cltypes.ProposerSlashing{Header1: header1, Header2: header2}
+	a.submitOperation(w, r, func(s *state.BeaconState) error { return a.operationsPool.AddProposerSlashing(s, slashing) }, sentinel.GossipType_ProposerSlashingGossipType, slashing)
+}
+
+func (a *ApiHandler) postPoolAttesterSlashings(w http.ResponseWriter, r *http.Request) {
+	var req attesterSlashingJSON
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	maxValidators := int(a.beaconChainCfg.MaxValidatorsPerCommittee)
+	attestation1, err := indexedAttestationFromJSON(req.Attestation1, maxValidators)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	attestation2, err := indexedAttestationFromJSON(req.Attestation2, maxValidators)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	slashing := &cltypes.AttesterSlashing{Attestation_1: attestation1, Attestation_2: attestation2}
+	a.submitOperation(w, r, func(s *state.BeaconState) error { return a.operationsPool.AddAttesterSlashing(s, slashing) }, sentinel.GossipType_AttesterSlashingGossipType, slashing)
+}
+
+// postPoolBLSToExecutionChanges adds the changes to the pool and publishes them, up to the first invalid one
+func (a *ApiHandler) postPoolBLSToExecutionChanges(w http.ResponseWriter, r *http.Request) {
+	var req []signedBLSToExecutionChangeJSON
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	changes := make([]*cltypes.SignedBLSToExecutionChange, 0, len(req))
+	for i, c := range req {
+		signature, err := signatureFromJSON(c.Signature)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("change %d: %s", i, err))
+			return
+		}
+		if len(c.Message.FromBLSPubkey) != 48 {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("change %d: invalid public key length %d", i, len(c.Message.FromBLSPubkey)))
+			return
+		}
+		change := &cltypes.SignedBLSToExecutionChange{
+			Message:   &cltypes.BLSToExecutionChange{ValidatorIndex: uint64(c.Message.ValidatorIndex), To: c.Message.ToExecutionAddress},
+			Signature: signature,
+		}
+		copy(change.Message.From[:], c.Message.FromBLSPubkey)
+		changes = append(changes, change)
+	}
+	s, err := a.forkchoiceStore.HeadState()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	for i, change := range changes {
+		if err := a.operationsPool.AddBLSToExecutionChange(s, change); err != nil {
+			if errors.Is(err, pool.ErrKnown) {
+				continue
+			}
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("Invalid operation: change %d: %s", i, err))
+			return
+		}
+		if err := a.publishOperation(r.Context(), gossip.BlsToExecutionChangeGossipType, change); err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
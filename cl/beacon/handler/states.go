@@ -0,0 +1,341 @@
+package handler
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/types"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+)
+
+type forkResponse struct {
+	PreviousVersion types.Bytes4 `json:"previous_version"`
+	CurrentVersion  types.Bytes4 `json:"current_version"`
+	Epoch           types.Uint64 `json:"epoch"`
+}
+
+type validatorJSON struct {
+	Pubkey                     hexutility.Bytes `json:"pubkey"`
+	WithdrawalCredentials      libcommon.Hash   `json:"withdrawal_credentials"`
+	EffectiveBalance           types.Uint64     `json:"effective_balance"`
+	Slashed                    bool             `json:"slashed"`
+	ActivationEligibilityEpoch types.Uint64     `json:"activation_eligibility_epoch"`
+	ActivationEpoch            types.Uint64     `json:"activation_epoch"`
+	ExitEpoch                  types.Uint64     `json:"exit_epoch"`
+	WithdrawableEpoch          types.Uint64     `json:"withdrawable_epoch"`
+}
+
+type validatorResponse struct {
+	Index     types.Uint64  `json:"index"`
+	Balance   types.Uint64  `json:"balance"`
+	Status    string        `json:"status"`
+	Validator validatorJSON `json:"validator"`
+}
+
+// Validator statuses, see https://hackmd.io/ofFJ5gOmQpu1jjHilHbdQQ
+const (
+	statusPendingInitialized = "pending_initialized"
+	statusPendingQueued      = "pending_queued"
+	statusActiveOngoing      = "active_ongoing"
+	statusActiveExiting      = "active_exiting"
+	statusActiveSlashed      = "active_slashed"
+	statusExitedUnslashed    = "exited_unslashed"
+	statusExitedSlashed      = "exited_slashed"
+	statusWithdrawalPossible = "withdrawal_possible"
+	statusWithdrawalDone     = "withdrawal_done"
+)
+
+func validatorStatus(v solid.Validator, balance, epoch uint64, cfg *clparams.BeaconChainConfig) string {
+	switch {
+	case v.ActivationEpoch() > epoch:
+		if v.ActivationEligibilityEpoch() == cfg.FarFutureEpoch {
+			return statusPendingInitialized
+		}
+		return statusPendingQueued
+	case epoch < v.ExitEpoch():
+		if v.ExitEpoch() == cfg.FarFutureEpoch {
+			return statusActiveOngoing
+		}
+		if v.Slashed() {
+			return statusActiveSlashed
+		}
+		return statusActiveExiting
+	case epoch < v.WithdrawableEpoch():
+		if v.Slashed() {
+			return statusExitedSlashed
+		}
+		return statusExitedUnslashed
+	default:
+		if balance != 0 {
+			return statusWithdrawalPossible
+		}
+		return statusWithdrawalDone
+	}
+}
+
+// statusMatches reports whether a validator status matches a status filter,
+// which is either a status or one of the pending, active, exited and
+// withdrawal groups.
+func statusMatches(status string, filters map[string]struct{}) bool {
+	if len(filters) == 0 {
+		return true
+	}
+	if _, ok := filters[status]; ok {
+		return true
+	}
+	group, _, _ := strings.Cut(status, "_")
+	_, ok := filters[group]
+	return ok
+}
+
+func newValidatorResponse(s *state.BeaconState, index int, epoch uint64) (*validatorResponse, error) {
+	v, err := s.ValidatorForValidatorIndex(index)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := s.ValidatorBalance(index)
+	if err != nil {
+		return nil, err
+	}
+	return &validatorResponse{
+		Index:   types.Uint64(index),
+		Balance: types.Uint64(balance),
+		Status:  validatorStatus(v, balance, epoch, s.BeaconConfig()),
+		Validator: validatorJSON{
+			Pubkey:                     v.PublicKeyBytes(),
+			WithdrawalCredentials:      v.WithdrawalCredentials(),
+			EffectiveBalance:           types.Uint64(v.EffectiveBalance()),
+			Slashed:                    v.Slashed(),
+			ActivationEligibilityEpoch: types.Uint64(v.ActivationEligibilityEpoch()),
+			ActivationEpoch:            types.Uint64(v.ActivationEpoch()),
+			ExitEpoch:                  types.Uint64(v.ExitEpoch()),
+			WithdrawableEpoch:          types.Uint64(v.WithdrawableEpoch()),
+		},
+	}, nil
+}
+
+// validatorIndex resolves a validator id (index or public key) against the state.
+func validatorIndex(s *state.BeaconState, id string) (int, bool, error) {
+	if strings.HasPrefix(id, "0x") {
+		pubkey, err := hex.DecodeString(id[2:])
+		if err != nil || len(pubkey) != 48 {
+			return 0, false, newIdError(http.StatusBadRequest, "Invalid validator ID: %s", id)
+		}
+		var key [48]byte
+		copy(key[:], pubkey)
+		index, ok := s.ValidatorIndexByPubkey(key)
+		return int(index), ok, nil
+	}
+	index, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return 0, false, newIdError(http.StatusBadRequest, "Invalid validator ID: %s", id)
+	}
+	return int(index), index < uint64(s.ValidatorLength()), nil
+}
+
+// stateFromRequest loads the state designated by the state_id path parameter.
+func (a *ApiHandler) stateFromRequest(r *http.Request) (*state.BeaconState, error) {
+	blockRoot, err := a.blockRootFromStateID(chi.URLParam(r, "state_id"))
+	if err != nil {
+		return nil, err
+	}
+	s, err := a.forkchoiceStore.GetFullState(blockRoot)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, newIdError(http.StatusNotFound, "State not found: %s", chi.URLParam(r, "state_id"))
+	}
+	return s, nil
+}
+
+func (a *ApiHandler) getStateFork(w http.ResponseWriter, r *http.Request) {
+	s, err := a.stateFromRequest(r)
+	if err != nil {
+		writeIdError(w, err)
+		return
+	}
+	fork := s.Fork()
+	writeResponse(w, newBeaconResponse(forkResponse{
+		PreviousVersion: fork.PreviousVersion,
+		CurrentVersion:  fork.CurrentVersion,
+		Epoch:           types.Uint64(fork.Epoch),
+	}).withFinalized(a.isFinalized(s.Slot())))
+}
+
+func (a *ApiHandler) getStateValidators(w http.ResponseWriter, r *http.Request) {
+	s, err := a.stateFromRequest(r)
+	if err != nil {
+		writeIdError(w, err)
+		return
+	}
+	query := r.URL.Query()
+	statuses := make(map[string]struct{})
+	for _, status := range splitQuery(query["status"]) {
+		statuses[status] = struct{}{}
+	}
+	epoch := state.Epoch(s.BeaconState)
+
+	var indices []int
+	if ids := splitQuery(query["id"]); len(ids) > 0 {
+		for _, id := range ids {
+			index, ok, err := validatorIndex(s, id)
+			if err != nil {
+				writeIdError(w, err)
+				return
+			}
+			if ok {
+				indices = append(indices, index)
+			}
+		}
+	} else {
+		indices = make([]int, s.ValidatorLength())
+		for i := range indices {
+			indices[i] = i
+		}
+	}
+
+	validators := make([]*validatorResponse, 0, len(indices))
+	for _, index := range indices {
+		validator, err := newValidatorResponse(s, index, epoch)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if statusMatches(validator.Status, statuses) {
+			validators = append(validators, validator)
+		}
+	}
+	writeResponse(w, newBeaconResponse(validators).withFinalized(a.isFinalized(s.Slot())))
+}
+
+func (a *ApiHandler) getStateValidator(w http.ResponseWriter, r *http.Request) {
+	s, err := a.stateFromRequest(r)
+	if err != nil {
+		writeIdError(w, err)
+		return
+	}
+	id := chi.URLParam(r, "id")
+	index, ok, err := validatorIndex(s, id)
+	if err != nil {
+		writeIdError(w, err)
+		return
+	}
+	if !ok {
+		writeError(w, http.StatusNotFound, "Validator not found: "+id)
+		return
+	}
+	validator, err := newValidatorResponse(s, index, state.Epoch(s.BeaconState))
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeResponse(w, newBeaconResponse(validator).withFinalized(a.isFinalized(s.Slot())))
+}
+
+type committeeResponse struct {
+	Index      types.Uint64   `json:"index"`
+	Slot       types.Uint64   `json:"slot"`
+	Validators []types.Uint64 `json:"validators"`
+}
+
+// getStateCommittees returns the beacon committees of an epoch the state can shuffle, the previous, current or next
+// one, optionally restricted to a committee index and a slot.
+func (a *ApiHandler) getStateCommittees(w http.ResponseWriter, r *http.Request) {
+	s, err := a.stateFromRequest(r)
+	if err != nil {
+		writeIdError(w, err)
+		return
+	}
+	query := r.URL.Query()
+	parse := func(name string) (uint64, bool, error) {
+		v := query.Get(name)
+		if v == "" {
+			return 0, false, nil
+		}
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return 0, false, newIdError(http.StatusBadRequest, "Invalid %s: %s", name, v)
+		}
+		return n, true, nil
+	}
+	epoch, hasEpoch, err := parse("epoch")
+	if err != nil {
+		writeIdError(w, err)
+		return
+	}
+	index, hasIndex, err := parse("index")
+	if err != nil {
+		writeIdError(w, err)
+		return
+	}
+	slot, hasSlot, err := parse("slot")
+	if err != nil {
+		writeIdError(w, err)
+		return
+	}
+
+	stateEpoch := state.Epoch(s.BeaconState)
+	if !hasEpoch {
+		epoch = stateEpoch
+	}
+	if epoch+1 < stateEpoch || epoch > stateEpoch+1 {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Epoch %d is out of the range of the state epoch %d", epoch, stateEpoch))
+		return
+	}
+	slotsPerEpoch := s.BeaconConfig().SlotsPerEpoch
+	if hasSlot && slot/slotsPerEpoch != epoch {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("Slot %d is not in epoch %d", slot, epoch))
+		return
+	}
+
+	committeesPerSlot := s.CommitteeCount(epoch)
+	committees := []*committeeResponse{}
+	for committeeSlot := epoch * slotsPerEpoch; committeeSlot < (epoch+1)*slotsPerEpoch; committeeSlot++ {
+		if hasSlot && committeeSlot != slot {
+			continue
+		}
+		for committeeIndex := uint64(0); committeeIndex < committeesPerSlot; committeeIndex++ {
+			if hasIndex && committeeIndex != index {
+				continue
+			}
+			committee, err := s.GetBeaconCommitee(committeeSlot, committeeIndex)
+			if err != nil {
+				writeError(w, http.StatusInternalServerError, err.Error())
+				return
+			}
+			validators := make([]types.Uint64, len(committee))
+			for i, validatorIndex := range committee {
+				validators[i] = types.Uint64(validatorIndex)
+			}
+			committees = append(committees, &committeeResponse{
+				Index:      types.Uint64(committeeIndex),
+				Slot:       types.Uint64(committeeSlot),
+				Validators: validators,
+			})
+		}
+	}
+	writeResponse(w, newBeaconResponse(committees).withFinalized(a.isFinalized(s.Slot())))
+}
+
+// splitQuery flattens repeated and comma separated query values.
+func splitQuery(values []string) []string {
+	var out []string
+	for _, value := range values {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
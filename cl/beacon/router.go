@@ -25,7 +25,7 @@ func ListenAndServe(api *handler.ApiHandler, routerCfg *RouterConfiguration) {
 		Handler:      api,
 		ReadTimeout:  routerCfg.ReadTimeTimeout,
 		IdleTimeout:  routerCfg.IdleTimeout,
-		WriteTimeout: routerCfg.IdleTimeout,
+		WriteTimeout: routerCfg.WriteTimeout,
 	}
 	if err != nil {
 		log.Warn("[Beacon API] Failed to start listening", "addr", routerCfg.Address, "err", err)
@@ -3,6 +3,7 @@ package types
 import (
 	"encoding/hex"
 	"encoding/json"
+	"strconv"
 )
 
 type Bytes4 [4]byte
@@ -10,3 +11,23 @@ type Bytes4 [4]byte
 func (b Bytes4) MarshalJSON() ([]byte, error) {
 	return json.Marshal("0x" + hex.EncodeToString(b[:]))
 }
+
+// Uint64 is encoded as a decimal string, as the Beacon API does for all integers.
+type Uint64 uint64
+
+func (u Uint64) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatUint(uint64(u), 10))
+}
+
+func (u *Uint64) UnmarshalJSON(input []byte) error {
+	var s string
+	if err := json.Unmarshal(input, &s); err != nil {
+		return err
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return err
+	}
+	*u = Uint64(v)
+	return nil
+}
@@ -485,6 +485,23 @@ func (b *BeaconChainConfig) GetCurrentStateVersion(epoch uint64) StateVersion {
 	return stateVersion
 }
 
+// GetForkVersionByVersion returns the fork version of the given state version.
+func (b *BeaconChainConfig) GetForkVersionByVersion(v StateVersion) uint32 {
+	switch v {
+	case Phase0Version:
+		return b.GenesisForkVersion
+	case AltairVersion:
+		return b.AltairForkVersion
+	case BellatrixVersion:
+		return b.BellatrixForkVersion
+	case CapellaVersion:
+		return b.CapellaForkVersion
+	case DenebVersion:
+		return b.DenebForkVersion
+	}
+	panic("invalid version")
+}
+
 // InitializeForkSchedule initializes the schedules forks baked into the config.
 func (b *BeaconChainConfig) InitializeForkSchedule() {
 	b.ForkVersionSchedule = configForkSchedule(b)
@@ -0,0 +1,139 @@
+package cltypes
+
+import (
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/types/clonable"
+
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	ssz2 "github.com/ledgerwatch/erigon/cl/ssz"
+)
+
+// https://github.com/ethereum/consensus-specs/blob/v1.4.0-alpha.3/specs/deneb/p2p-interface.md#configuration
+const (
+	MaxBlobsPerBlock                 = 6
+	MaxRequestBlobSidecars           = 768 // MAX_REQUEST_BLOCKS_DENEB * MAX_BLOBS_PER_BLOCK
+	MinEpochsForBlobSidecarsRequests = 4096
+)
+
+const blobSideCarSize = 32 + 8 + 8 + 32 + 8 + int(BYTES_PER_BLOB) + 48 + 48
+
+// BlobSideCar is a blob of a block with its KZG commitment and proof, gossiped and served next to the block.
+type BlobSideCar struct {
+	BlockRoot       libcommon.Hash
+	Index           uint64
+	Slot            uint64
+	BlockParentRoot libcommon.Hash
+	ProposerIndex   uint64
+	Blob            Blob
+	KzgCommitment   KZGCommitment
+	KzgProof        KZGProof
+}
+
+func (b *BlobSideCar) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, b.BlockRoot[:], b.Index, b.Slot, b.BlockParentRoot[:], b.ProposerIndex, b.Blob[:], b.KzgCommitment[:], b.KzgProof[:])
+}
+
+func (b *BlobSideCar) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, b.BlockRoot[:], &b.Index, &b.Slot, b.BlockParentRoot[:], &b.ProposerIndex, b.Blob[:], b.KzgCommitment[:], b.KzgProof[:])
+}
+
+func (b *BlobSideCar) EncodingSizeSSZ() int {
+	return blobSideCarSize
+}
+
+func (b *BlobSideCar) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(b.BlockRoot[:], b.Index, b.Slot, b.BlockParentRoot[:], b.ProposerIndex, b.Blob[:], b.KzgCommitment[:], b.KzgProof[:])
+}
+
+func (*BlobSideCar) Static() bool {
+	return true
+}
+
+func (*BlobSideCar) Clone() clonable.Clonable {
+	return &BlobSideCar{}
+}
+
+// SignedBlobSideCar is the sidecar signed by the proposer of its block, as it is gossiped.
+type SignedBlobSideCar struct {
+	Message   *BlobSideCar
+	Signature [96]byte
+}
+
+func (s *SignedBlobSideCar) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, s.Message, s.Signature[:])
+}
+
+func (s *SignedBlobSideCar) DecodeSSZ(buf []byte, version int) error {
+	s.Message = new(BlobSideCar)
+	return ssz2.UnmarshalSSZ(buf, version, s.Message, s.Signature[:])
+}
+
+func (s *SignedBlobSideCar) EncodingSizeSSZ() int {
+	return blobSideCarSize + 96
+}
+
+func (s *SignedBlobSideCar) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(s.Message, s.Signature[:])
+}
+
+func (*SignedBlobSideCar) Static() bool {
+	return true
+}
+
+func (*SignedBlobSideCar) Clone() clonable.Clonable {
+	return &SignedBlobSideCar{}
+}
+
+// BlobIdentifier identifies a sidecar in a BlobSidecarsByRoot request.
+type BlobIdentifier struct {
+	BlockRoot libcommon.Hash
+	Index     uint64
+}
+
+func (b *BlobIdentifier) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, b.BlockRoot[:], b.Index)
+}
+
+func (b *BlobIdentifier) DecodeSSZ(buf []byte, version int) error {
+	return ssz2.UnmarshalSSZ(buf, version, b.BlockRoot[:], &b.Index)
+}
+
+func (b *BlobIdentifier) EncodingSizeSSZ() int {
+	return 40
+}
+
+func (b *BlobIdentifier) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(b.BlockRoot[:], b.Index)
+}
+
+func (*BlobIdentifier) Static() bool {
+	return true
+}
+
+func (*BlobIdentifier) Clone() clonable.Clonable {
+	return &BlobIdentifier{}
+}
+
+/*
+ * BlobsByRangeRequest is the request for getting the sidecars of a range of slots.
+ */
+type BlobsByRangeRequest struct {
+	StartSlot uint64
+	Count     uint64
+}
+
+func (b *BlobsByRangeRequest) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, b.StartSlot, b.Count)
+}
+
+func (b *BlobsByRangeRequest) DecodeSSZ(buf []byte, v int) error {
+	return ssz2.UnmarshalSSZ(buf, v, &b.StartSlot, &b.Count)
+}
+
+func (b *BlobsByRangeRequest) EncodingSizeSSZ() int {
+	return 16
+}
+
+func (*BlobsByRangeRequest) Clone() clonable.Clonable {
+	return &BlobsByRangeRequest{}
+}
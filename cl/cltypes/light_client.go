@@ -0,0 +1,326 @@
+package cltypes
+
+import (
+	"github.com/ledgerwatch/erigon-lib/types/clonable"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	ssz2 "github.com/ledgerwatch/erigon/cl/ssz"
+)
+
+// https://github.com/ethereum/consensus-specs/blob/v1.4.0-alpha.3/specs/altair/light-client/sync-protocol.md#constants
+const (
+	ExecutionBranchSize     = 4 // floorlog2(EXECUTION_PAYLOAD_INDEX)
+	SyncCommitteeBranchSize = 5 // floorlog2(CURRENT_SYNC_COMMITTEE_INDEX) and floorlog2(NEXT_SYNC_COMMITTEE_INDEX)
+	FinalityBranchSize      = 6 // floorlog2(FINALIZED_ROOT_INDEX)
+)
+
+// NewBranch converts a merkle branch into its SSZ vector.
+func NewBranch(branch [][32]byte) solid.HashVectorSSZ {
+	v := solid.NewHashVector(len(branch))
+	for i, node := range branch {
+		v.Set(i, node)
+	}
+	return v
+}
+
+// LightClientHeader is the header of a block as seen by light clients. From capella onwards, it also proves
+// the execution payload header of the block.
+type LightClientHeader struct {
+	Beacon                 *BeaconBlockHeader
+	ExecutionPayloadHeader *Eth1Header
+	ExecutionBranch        solid.HashVectorSSZ
+
+	version clparams.StateVersion
+}
+
+// NewLightClientHeader creates an empty header with given version.
+func NewLightClientHeader(version clparams.StateVersion) *LightClientHeader {
+	h := &LightClientHeader{
+		Beacon:          &BeaconBlockHeader{},
+		ExecutionBranch: solid.NewHashVector(ExecutionBranchSize),
+		version:         version,
+	}
+	if version >= clparams.CapellaVersion {
+		h.ExecutionPayloadHeader = NewEth1Header(version)
+	}
+	return h
+}
+
+func (h *LightClientHeader) Version() clparams.StateVersion {
+	return h.version
+}
+
+func (h *LightClientHeader) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, h.getSchema()...)
+}
+
+func (h *LightClientHeader) DecodeSSZ(buf []byte, version int) error {
+	*h = *NewLightClientHeader(clparams.StateVersion(version))
+	return ssz2.UnmarshalSSZ(buf, version, h.getSchema()...)
+}
+
+func (h *LightClientHeader) EncodingSizeSSZ() int {
+	size := h.Beacon.EncodingSizeSSZ()
+	if h.version >= clparams.CapellaVersion {
+		size += h.ExecutionPayloadHeader.EncodingSizeSSZ() + 4 // the header is dynamic
+		size += h.ExecutionBranch.EncodingSizeSSZ()
+	}
+	return size
+}
+
+func (h *LightClientHeader) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(h.getSchema()...)
+}
+
+func (h *LightClientHeader) Static() bool {
+	return h.version < clparams.CapellaVersion
+}
+
+func (h *LightClientHeader) Clone() clonable.Clonable {
+	return NewLightClientHeader(h.version)
+}
+
+func (h *LightClientHeader) getSchema() []interface{} {
+	s := []interface{}{h.Beacon}
+	if h.version >= clparams.CapellaVersion {
+		s = append(s, h.ExecutionPayloadHeader, h.ExecutionBranch)
+	}
+	return s
+}
+
+// LightClientBootstrap lets a light client start following the chain from a trusted block root.
+type LightClientBootstrap struct {
+	Header                     *LightClientHeader
+	CurrentSyncCommittee       *solid.SyncCommittee
+	CurrentSyncCommitteeBranch solid.HashVectorSSZ
+}
+
+func NewLightClientBootstrap(version clparams.StateVersion) *LightClientBootstrap {
+	return &LightClientBootstrap{
+		Header:                     NewLightClientHeader(version),
+		CurrentSyncCommittee:       &solid.SyncCommittee{},
+		CurrentSyncCommitteeBranch: solid.NewHashVector(SyncCommitteeBranchSize),
+	}
+}
+
+func (l *LightClientBootstrap) Version() clparams.StateVersion {
+	return l.Header.version
+}
+
+func (l *LightClientBootstrap) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, l.Header, l.CurrentSyncCommittee, l.CurrentSyncCommitteeBranch)
+}
+
+func (l *LightClientBootstrap) DecodeSSZ(buf []byte, version int) error {
+	*l = *NewLightClientBootstrap(clparams.StateVersion(version))
+	return ssz2.UnmarshalSSZ(buf, version, l.Header, l.CurrentSyncCommittee, l.CurrentSyncCommitteeBranch)
+}
+
+func (l *LightClientBootstrap) EncodingSizeSSZ() int {
+	size := l.Header.EncodingSizeSSZ() + l.CurrentSyncCommittee.EncodingSizeSSZ() + l.CurrentSyncCommitteeBranch.EncodingSizeSSZ()
+	if !l.Header.Static() {
+		size += 4
+	}
+	return size
+}
+
+func (l *LightClientBootstrap) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(l.Header, l.CurrentSyncCommittee, l.CurrentSyncCommitteeBranch)
+}
+
+func (l *LightClientBootstrap) Static() bool {
+	return l.Header.Static()
+}
+
+func (*LightClientBootstrap) Clone() clonable.Clonable {
+	return &LightClientBootstrap{}
+}
+
+// LightClientUpdate proves the next sync committee and the finalized header to a light client, signed by the sync committee.
+type LightClientUpdate struct {
+	AttestedHeader          *LightClientHeader
+	NextSyncCommittee       *solid.SyncCommittee
+	NextSyncCommitteeBranch solid.HashVectorSSZ
+	FinalizedHeader         *LightClientHeader
+	FinalityBranch          solid.HashVectorSSZ
+	SyncAggregate           *SyncAggregate
+	SignatureSlot           uint64
+}
+
+func NewLightClientUpdate(version clparams.StateVersion) *LightClientUpdate {
+	return &LightClientUpdate{
+		AttestedHeader:          NewLightClientHeader(version),
+		NextSyncCommittee:       &solid.SyncCommittee{},
+		NextSyncCommitteeBranch: solid.NewHashVector(SyncCommitteeBranchSize),
+		FinalizedHeader:         NewLightClientHeader(version),
+		FinalityBranch:          solid.NewHashVector(FinalityBranchSize),
+		SyncAggregate:           &SyncAggregate{},
+	}
+}
+
+func (l *LightClientUpdate) Version() clparams.StateVersion {
+	return l.AttestedHeader.version
+}
+
+func (l *LightClientUpdate) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, l.getSchema()...)
+}
+
+func (l *LightClientUpdate) DecodeSSZ(buf []byte, version int) error {
+	*l = *NewLightClientUpdate(clparams.StateVersion(version))
+	return ssz2.UnmarshalSSZ(buf, version, l.getSchema()...)
+}
+
+func (l *LightClientUpdate) EncodingSizeSSZ() int {
+	size := l.AttestedHeader.EncodingSizeSSZ() + l.NextSyncCommittee.EncodingSizeSSZ() + l.NextSyncCommitteeBranch.EncodingSizeSSZ() +
+		l.FinalizedHeader.EncodingSizeSSZ() + l.FinalityBranch.EncodingSizeSSZ() + l.SyncAggregate.EncodingSizeSSZ() + 8
+	if !l.AttestedHeader.Static() {
+		size += 8
+	}
+	return size
+}
+
+func (l *LightClientUpdate) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(l.getSchema()...)
+}
+
+func (l *LightClientUpdate) Static() bool {
+	return l.AttestedHeader.Static()
+}
+
+func (*LightClientUpdate) Clone() clonable.Clonable {
+	return &LightClientUpdate{}
+}
+
+func (l *LightClientUpdate) getSchema() []interface{} {
+	return []interface{}{l.AttestedHeader, l.NextSyncCommittee, l.NextSyncCommitteeBranch, l.FinalizedHeader, l.FinalityBranch, l.SyncAggregate, &l.SignatureSlot}
+}
+
+// LightClientFinalityUpdate is the update without the next sync committee, to follow the finalized header.
+type LightClientFinalityUpdate struct {
+	AttestedHeader  *LightClientHeader
+	FinalizedHeader *LightClientHeader
+	FinalityBranch  solid.HashVectorSSZ
+	SyncAggregate   *SyncAggregate
+	SignatureSlot   uint64
+}
+
+func NewLightClientFinalityUpdate(version clparams.StateVersion) *LightClientFinalityUpdate {
+	return &LightClientFinalityUpdate{
+		AttestedHeader:  NewLightClientHeader(version),
+		FinalizedHeader: NewLightClientHeader(version),
+		FinalityBranch:  solid.NewHashVector(FinalityBranchSize),
+		SyncAggregate:   &SyncAggregate{},
+	}
+}
+
+func (l *LightClientFinalityUpdate) Version() clparams.StateVersion {
+	return l.AttestedHeader.version
+}
+
+func (l *LightClientFinalityUpdate) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, l.getSchema()...)
+}
+
+func (l *LightClientFinalityUpdate) DecodeSSZ(buf []byte, version int) error {
+	*l = *NewLightClientFinalityUpdate(clparams.StateVersion(version))
+	return ssz2.UnmarshalSSZ(buf, version, l.getSchema()...)
+}
+
+func (l *LightClientFinalityUpdate) EncodingSizeSSZ() int {
+	size := l.AttestedHeader.EncodingSizeSSZ() + l.FinalizedHeader.EncodingSizeSSZ() + l.FinalityBranch.EncodingSizeSSZ() +
+		l.SyncAggregate.EncodingSizeSSZ() + 8
+	if !l.AttestedHeader.Static() {
+		size += 8
+	}
+	return size
+}
+
+func (l *LightClientFinalityUpdate) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(l.getSchema()...)
+}
+
+func (l *LightClientFinalityUpdate) Static() bool {
+	return l.AttestedHeader.Static()
+}
+
+func (*LightClientFinalityUpdate) Clone() clonable.Clonable {
+	return &LightClientFinalityUpdate{}
+}
+
+func (l *LightClientFinalityUpdate) getSchema() []interface{} {
+	return []interface{}{l.AttestedHeader, l.FinalizedHeader, l.FinalityBranch, l.SyncAggregate, &l.SignatureSlot}
+}
+
+// LightClientOptimisticUpdate is the latest header signed by the sync committee.
+type LightClientOptimisticUpdate struct {
+	AttestedHeader *LightClientHeader
+	SyncAggregate  *SyncAggregate
+	SignatureSlot  uint64
+}
+
+func NewLightClientOptimisticUpdate(version clparams.StateVersion) *LightClientOptimisticUpdate {
+	return &LightClientOptimisticUpdate{
+		AttestedHeader: NewLightClientHeader(version),
+		SyncAggregate:  &SyncAggregate{},
+	}
+}
+
+func (l *LightClientOptimisticUpdate) Version() clparams.StateVersion {
+	return l.AttestedHeader.version
+}
+
+func (l *LightClientOptimisticUpdate) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, l.AttestedHeader, l.SyncAggregate, &l.SignatureSlot)
+}
+
+func (l *LightClientOptimisticUpdate) DecodeSSZ(buf []byte, version int) error {
+	*l = *NewLightClientOptimisticUpdate(clparams.StateVersion(version))
+	return ssz2.UnmarshalSSZ(buf, version, l.AttestedHeader, l.SyncAggregate, &l.SignatureSlot)
+}
+
+func (l *LightClientOptimisticUpdate) EncodingSizeSSZ() int {
+	size := l.AttestedHeader.EncodingSizeSSZ() + l.SyncAggregate.EncodingSizeSSZ() + 8
+	if !l.AttestedHeader.Static() {
+		size += 4
+	}
+	return size
+}
+
+func (l *LightClientOptimisticUpdate) HashSSZ() ([32]byte, error) {
+	return merkle_tree.HashTreeRoot(l.AttestedHeader, l.SyncAggregate, &l.SignatureSlot)
+}
+
+func (l *LightClientOptimisticUpdate) Static() bool {
+	return l.AttestedHeader.Static()
+}
+
+func (*LightClientOptimisticUpdate) Clone() clonable.Clonable {
+	return &LightClientOptimisticUpdate{}
+}
+
+/*
+ * LightClientUpdatesByRangeRequest is the request for the best updates of a range of sync committee periods.
+ */
+type LightClientUpdatesByRangeRequest struct {
+	StartPeriod uint64
+	Count       uint64
+}
+
+func (l *LightClientUpdatesByRangeRequest) EncodeSSZ(buf []byte) ([]byte, error) {
+	return ssz2.MarshalSSZ(buf, l.StartPeriod, l.Count)
+}
+
+func (l *LightClientUpdatesByRangeRequest) DecodeSSZ(buf []byte, v int) error {
+	return ssz2.UnmarshalSSZ(buf, v, &l.StartPeriod, &l.Count)
+}
+
+func (l *LightClientUpdatesByRangeRequest) EncodingSizeSSZ() int {
+	return 16
+}
+
+func (*LightClientUpdatesByRangeRequest) Clone() clonable.Clonable {
+	return &LightClientUpdatesByRangeRequest{}
+}
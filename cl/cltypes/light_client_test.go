@@ -0,0 +1,64 @@
+package cltypes_test
+
+import (
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/types/ssz"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+)
+
+func newTestLightClientHeader(version clparams.StateVersion) *cltypes.LightClientHeader {
+	h := cltypes.NewLightClientHeader(version)
+	h.Beacon = testHeader.Copy()
+	if version >= clparams.CapellaVersion {
+		h.ExecutionPayloadHeader.BlockNumber = 69
+		h.ExecutionPayloadHeader.BlockHash = libcommon.HexToHash("bb")
+		h.ExecutionBranch.Set(1, libcommon.HexToHash("cc"))
+	}
+	return h
+}
+
+func TestMarshalLightClientTypes(t *testing.T) {
+	for _, version := range []clparams.StateVersion{clparams.AltairVersion, clparams.CapellaVersion, clparams.DenebVersion} {
+		bootstrap := cltypes.NewLightClientBootstrap(version)
+		bootstrap.Header = newTestLightClientHeader(version)
+		bootstrap.CurrentSyncCommitteeBranch.Set(4, libcommon.HexToHash("dd"))
+
+		update := cltypes.NewLightClientUpdate(version)
+		update.AttestedHeader = newTestLightClientHeader(version)
+		update.FinalizedHeader = newTestLightClientHeader(version)
+		update.FinalityBranch.Set(5, libcommon.HexToHash("ee"))
+		update.SyncAggregate.SyncCommiteeBits[0] = 0xff
+		update.SignatureSlot = 3
+
+		finalityUpdate := cltypes.NewLightClientFinalityUpdate(version)
+		finalityUpdate.AttestedHeader = newTestLightClientHeader(version)
+		finalityUpdate.FinalizedHeader = newTestLightClientHeader(version)
+		finalityUpdate.SignatureSlot = 3
+
+		optimisticUpdate := cltypes.NewLightClientOptimisticUpdate(version)
+		optimisticUpdate.AttestedHeader = newTestLightClientHeader(version)
+		optimisticUpdate.SignatureSlot = 3
+
+		cases := []ssz.EncodableSSZ{bootstrap, update, finalityUpdate, optimisticUpdate}
+		unmarshalDestinations := []ssz.EncodableSSZ{
+			&cltypes.LightClientBootstrap{},
+			&cltypes.LightClientUpdate{},
+			&cltypes.LightClientFinalityUpdate{},
+			&cltypes.LightClientOptimisticUpdate{},
+		}
+		for i, tc := range cases {
+			marshalledBytes, err := tc.EncodeSSZ(nil)
+			require.NoError(t, err)
+			require.Equal(t, len(marshalledBytes), tc.EncodingSizeSSZ())
+			require.NoError(t, unmarshalDestinations[i].DecodeSSZ(marshalledBytes, int(version)))
+			require.Equal(t, tc, unmarshalDestinations[i])
+			_, err = tc.(ssz.HashableSSZ).HashSSZ()
+			require.NoError(t, err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package cltypes
 
 import (
+	"github.com/ledgerwatch/erigon-lib/common/length"
 	"github.com/ledgerwatch/erigon-lib/types/clonable"
 	"github.com/ledgerwatch/erigon-lib/types/ssz"
 
@@ -106,3 +107,26 @@ func (s *Status) DecodeSSZ(buf []byte, version int) error {
 func (s *Status) EncodingSizeSSZ() int {
 	return 84
 }
+
+// Root is a block root, as requested by LightClientBootstrap.
+type Root [32]byte
+
+func (r *Root) EncodeSSZ(buf []byte) ([]byte, error) {
+	return append(buf, r[:]...), nil
+}
+
+func (r *Root) DecodeSSZ(buf []byte, _ int) error {
+	if len(buf) < length.Hash {
+		return ssz.ErrLowBufferSize
+	}
+	copy(r[:], buf)
+	return nil
+}
+
+func (r *Root) EncodingSizeSSZ() int {
+	return length.Hash
+}
+
+func (*Root) Clone() clonable.Clonable {
+	return &Root{}
+}
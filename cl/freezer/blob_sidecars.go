@@ -0,0 +1,203 @@
+package freezer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"io/fs"
+	"strconv"
+	"sync"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+const (
+	blobSidecarsNamespace = "blob_sidecars"
+	blobSidecarsSlots     = "slots" // the roots of the blocks of a slot with sidecars, and the imported one as sidecar
+	blobSidecarsMeta      = "meta"
+	blobSidecarsLowest    = "lowest_slot"
+)
+
+// BlobSidecarStore keeps the verified blob sidecars by block root and index, until they are pruned. It also keeps which
+// block of every slot was imported, to serve the sidecars of a range of slots.
+type BlobSidecarStore struct {
+	f  Freezer
+	mu sync.Mutex
+}
+
+func NewBlobSidecarStore(f Freezer) *BlobSidecarStore {
+	return &BlobSidecarStore{f: f}
+}
+
+func (b *BlobSidecarStore) read(object, id string) (data []byte, sidecar []byte, err error) {
+	r, sidecar, err := b.f.Get(blobSidecarsNamespace, object, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer r.Close()
+	data, err = io.ReadAll(r)
+	return data, sidecar, err
+}
+
+// slotRoots returns the roots of the blocks of the slot with sidecars, and the root of the imported one if any
+func (b *BlobSidecarStore) slotRoots(slot uint64) (roots []libcommon.Hash, imported *libcommon.Hash, err error) {
+	data, sidecar, err := b.read(blobSidecarsSlots, strconv.FormatUint(slot, 10))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := 0; i+32 <= len(data); i += 32 {
+		roots = append(roots, libcommon.BytesToHash(data[i:i+32]))
+	}
+	if len(sidecar) == 32 {
+		root := libcommon.BytesToHash(sidecar)
+		imported = &root
+	}
+	return roots, imported, nil
+}
+
+func (b *BlobSidecarStore) lowestSlot() (uint64, bool, error) {
+	data, _, err := b.read(blobSidecarsMeta, blobSidecarsLowest)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, false, nil
+	}
+	if err != nil || len(data) != 8 {
+		return 0, false, err
+	}
+	return binary.BigEndian.Uint64(data), true, nil
+}
+
+func (b *BlobSidecarStore) setLowestSlot(slot uint64) error {
+	return b.f.Put(bytes.NewReader(binary.BigEndian.AppendUint64(nil, slot)), nil, blobSidecarsNamespace, blobSidecarsMeta, blobSidecarsLowest)
+}
+
+// Put stores the sidecar, which must have been verified
+func (b *BlobSidecarStore) Put(sidecar *cltypes.BlobSideCar) error {
+	encoded, err := sidecar.EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.f.Put(bytes.NewReader(utils.CompressSnappy(encoded)), nil, blobSidecarsNamespace, sidecar.BlockRoot.Hex(), strconv.FormatUint(sidecar.Index, 10)); err != nil {
+		return err
+	}
+	roots, _, err := b.slotRoots(sidecar.Slot)
+	if err != nil {
+		return err
+	}
+	for _, root := range roots {
+		if root == sidecar.BlockRoot {
+			return nil
+		}
+	}
+	var data []byte
+	for _, root := range append(roots, sidecar.BlockRoot) {
+		data = append(data, root[:]...)
+	}
+	if err := b.f.Put(bytes.NewReader(data), nil, blobSidecarsNamespace, blobSidecarsSlots, strconv.FormatUint(sidecar.Slot, 10)); err != nil {
+		return err
+	}
+	lowest, ok, err := b.lowestSlot()
+	if err != nil {
+		return err
+	}
+	if !ok || sidecar.Slot < lowest {
+		return b.setLowestSlot(sidecar.Slot)
+	}
+	return nil
+}
+
+// Get returns the sidecar of the block with the given index, or an error wrapping fs.ErrNotExist if it is not stored
+func (b *BlobSidecarStore) Get(blockRoot libcommon.Hash, index uint64) (*cltypes.BlobSideCar, error) {
+	data, _, err := b.read(blockRoot.Hex(), strconv.FormatUint(index, 10))
+	if err != nil {
+		return nil, err
+	}
+	sidecar := &cltypes.BlobSideCar{}
+	if err := utils.DecodeSSZSnappy(sidecar, data, 0); err != nil {
+		return nil, err
+	}
+	return sidecar, nil
+}
+
+// Has tells whether the sidecar of the block with the given index is stored
+func (b *BlobSidecarStore) Has(blockRoot libcommon.Hash, index uint64) bool {
+	r, _, err := b.f.Get(blobSidecarsNamespace, blockRoot.Hex(), strconv.FormatUint(index, 10))
+	if err != nil {
+		return false
+	}
+	r.Close()
+	return true
+}
+
+// SetImported records that the block of the slot was imported, so that its sidecars are the ones served for the slot
+func (b *BlobSidecarStore) SetImported(slot uint64, blockRoot libcommon.Hash) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	roots, _, err := b.slotRoots(slot)
+	if err != nil {
+		return err
+	}
+	var data []byte
+	for _, root := range roots {
+		data = append(data, root[:]...)
+	}
+	return b.f.Put(bytes.NewReader(data), blockRoot[:], blobSidecarsNamespace, blobSidecarsSlots, strconv.FormatUint(slot, 10))
+}
+
+// ImportedSidecars returns the sidecars of the imported block of the slot, by index
+func (b *BlobSidecarStore) ImportedSidecars(slot uint64) ([]*cltypes.BlobSideCar, error) {
+	b.mu.Lock()
+	_, imported, err := b.slotRoots(slot)
+	b.mu.Unlock()
+	if err != nil || imported == nil {
+		return nil, err
+	}
+	var sidecars []*cltypes.BlobSideCar
+	for index := uint64(0); index < cltypes.MaxBlobsPerBlock; index++ {
+		sidecar, err := b.Get(*imported, index)
+		if errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		sidecars = append(sidecars, sidecar)
+	}
+	return sidecars, nil
+}
+
+// Prune removes the sidecars of the slots before the given one
+func (b *BlobSidecarStore) Prune(beforeSlot uint64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	lowest, ok, err := b.lowestSlot()
+	if err != nil || !ok || lowest >= beforeSlot {
+		return err
+	}
+	for slot := lowest; slot < beforeSlot; slot++ {
+		roots, _, err := b.slotRoots(slot)
+		if err != nil {
+			return err
+		}
+		if roots == nil {
+			continue
+		}
+		for _, root := range roots {
+			if err := b.f.Delete(blobSidecarsNamespace, root.Hex(), ""); err != nil {
+				return err
+			}
+		}
+		if err := b.f.Delete(blobSidecarsNamespace, blobSidecarsSlots, strconv.FormatUint(slot, 10)); err != nil {
+			return err
+		}
+	}
+	return b.setLowestSlot(beforeSlot)
+}
@@ -5,6 +5,7 @@ import "io"
 type Freezer interface {
 	Getter
 	Putter
+	Deleter
 }
 
 type Getter interface {
@@ -14,3 +15,8 @@ type Getter interface {
 type Putter interface {
 	Put(data io.Reader, sidecar []byte, namespace, object, id string, extra ...string) error
 }
+
+type Deleter interface {
+	// Delete removes the object with the given id, or all the objects if the id is empty
+	Delete(namespace, object, id string, extra ...string) error
+}
@@ -7,8 +7,11 @@ import (
 	"sync/atomic"
 	"testing"
 
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/freezer"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func runBlobStoreTest(t *testing.T, b *freezer.BlobStore) {
@@ -71,6 +74,45 @@ func runSidecarBlobStoreTest(t *testing.T, b *freezer.SidecarBlobStore) {
 	assert.Nil(t, sidecar)
 }
 
+func runBlobSidecarStoreTest(t *testing.T, b *freezer.BlobSidecarStore) {
+	rootA, rootB := libcommon.Hash{1}, libcommon.Hash{2}
+	for _, sidecar := range []*cltypes.BlobSideCar{
+		{BlockRoot: rootA, Slot: 10, Index: 0},
+		{BlockRoot: rootA, Slot: 10, Index: 1},
+		{BlockRoot: rootB, Slot: 10, Index: 0},
+		{BlockRoot: libcommon.Hash{3}, Slot: 11, Index: 0},
+	} {
+		sidecar.KzgCommitment[0] = byte(sidecar.Index + 1)
+		require.NoError(t, b.Put(sidecar))
+	}
+	sidecar, err := b.Get(rootA, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(10), sidecar.Slot)
+	require.Equal(t, byte(2), sidecar.KzgCommitment[0])
+	assert.True(t, b.Has(rootB, 0))
+	assert.False(t, b.Has(rootB, 1))
+	_, err = b.Get(rootB, 1)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	// Only the sidecars of the imported block of a slot are served by slot
+	sidecars, err := b.ImportedSidecars(10)
+	require.NoError(t, err)
+	assert.Empty(t, sidecars)
+	require.NoError(t, b.SetImported(10, rootA))
+	sidecars, err = b.ImportedSidecars(10)
+	require.NoError(t, err)
+	assert.Len(t, sidecars, 2)
+
+	// Pruning removes the sidecars of all the blocks of the slot
+	require.NoError(t, b.Prune(11))
+	assert.False(t, b.Has(rootA, 0))
+	assert.False(t, b.Has(rootB, 0))
+	assert.True(t, b.Has(libcommon.Hash{3}, 0))
+	sidecars, err = b.ImportedSidecars(10)
+	require.NoError(t, err)
+	assert.Empty(t, sidecars)
+}
+
 func testFreezer(t *testing.T, fn func() (freezer.Freezer, func())) {
 	t.Run("BlobStore", func(t *testing.T) {
 		f, cn := fn()
@@ -82,6 +124,11 @@ func testFreezer(t *testing.T, fn func() (freezer.Freezer, func())) {
 		defer cn()
 		runSidecarBlobStoreTest(t, freezer.NewSidecarBlobStore(f))
 	})
+	t.Run("BlobSidecarStore", func(t *testing.T) {
+		f, cn := fn()
+		defer cn()
+		runBlobSidecarStoreTest(t, freezer.NewBlobSidecarStore(f))
+	})
 }
 
 func TestMemoryStore(t *testing.T) {
@@ -63,3 +63,11 @@ func (f *RootPathOsFs) Put(data io.Reader, sidecar []byte, namespace string, obj
 	}
 	return nil
 }
+
+func (f *RootPathOsFs) Delete(namespace string, object string, id string, extra ...string) error {
+	infoPath, err := f.resolveFileName(namespace, object, id)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(infoPath)
+}
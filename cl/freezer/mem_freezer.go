@@ -56,7 +56,7 @@ func (f *InMemory) Get(namespace string, object string, id string, extra ...stri
 	if err == nil {
 		sidecar = blob.Bytes()
 	}
-	return io.NopCloser(fp), sidecar, nil
+	return io.NopCloser(bytes.NewReader(fp.Bytes())), sidecar, nil
 }
 
 func (f *InMemory) Put(data io.Reader, sidecar []byte, namespace string, object string, id string, extra ...string) error {
@@ -70,3 +70,17 @@ func (f *InMemory) Put(data io.Reader, sidecar []byte, namespace string, object
 	}
 	return nil
 }
+
+func (f *InMemory) Delete(namespace string, object string, id string, extra ...string) error {
+	infoPath, err := f.resolveFileName(namespace, object, id)
+	if err != nil {
+		return err
+	}
+	f.blob.Range(func(key, _ any) bool {
+		if name := key.(string); strings.HasPrefix(name, infoPath+"/") {
+			f.blob.Delete(name)
+		}
+		return true
+	})
+	return nil
+}
@@ -17,6 +17,22 @@ import (
 // IMPORTANT: DATA TYPE MUST IMPLEMENT HASHABLE
 // SUPPORTED PRIMITIVES: uint64, *uint64 and []byte
 func HashTreeRoot(schema ...interface{}) ([32]byte, error) {
+	leaves, err := schemaLeaves(schema...)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	// Calculate the Merkle root from the flat leaves
+	if err := MerkleRootFromFlatLeaves(leaves, leaves); err != nil {
+		return [32]byte{}, err
+	}
+
+	// Convert the bytes of the resulting hash into a [32]byte and return it
+	return common.BytesToHash(leaves[:length.Hash]), nil
+}
+
+// schemaLeaves returns the flat leaves of the schema, padded to a power of two.
+func schemaLeaves(schema ...interface{}) ([]byte, error) {
 	// Calculate the total number of leaves needed based on the schema length
 	leaves := make([]byte, NextPowerOfTwo(uint64(len(schema)*length.Hash)))
 	pos := 0
@@ -39,7 +55,7 @@ func HashTreeRoot(schema ...interface{}) ([32]byte, error) {
 				// If the slice is longer or equal to the length of a hash, calculate the hash of the slice and store it in the leaves
 				root, err := BytesRoot(obj)
 				if err != nil {
-					return [32]byte{}, err
+					return nil, err
 				}
 				copy(leaves[pos:], root[:])
 			}
@@ -47,7 +63,7 @@ func HashTreeRoot(schema ...interface{}) ([32]byte, error) {
 			// If the element implements the HashableSSZ interface, calculate the SSZ hash and store it in the leaves
 			root, err := obj.HashSSZ()
 			if err != nil {
-				return [32]byte{}, err
+				return nil, err
 			}
 			copy(leaves[pos:], root[:])
 		default:
@@ -58,14 +74,7 @@ func HashTreeRoot(schema ...interface{}) ([32]byte, error) {
 		// Move the position pointer to the next leaf
 		pos += length.Hash
 	}
-
-	// Calculate the Merkle root from the flat leaves
-	if err := MerkleRootFromFlatLeaves(leaves, leaves); err != nil {
-		return [32]byte{}, err
-	}
-
-	// Convert the bytes of the resulting hash into a [32]byte and return it
-	return common.BytesToHash(leaves[:length.Hash]), nil
+	return leaves, nil
 }
 
 // HashByteSlice is gohashtree HashBytSlice but using our hopefully safer header converstion
@@ -33,3 +33,40 @@ func TestHashTreeRootTxs(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, common.Hash(root), common.HexToHash("0x987269bc1075122edff32bfc38479757103cee5c1ed6e990de7ffee85b5dd18a"))
 }
+
+func TestMerkleProof(t *testing.T) {
+	bs := state.New(&clparams.MainnetBeaconConfig)
+	require.NoError(t, utils.DecodeSSZSnappy(bs, beaconState, int(clparams.DenebVersion)))
+	root, err := bs.HashSSZ()
+	require.NoError(t, err)
+	toHashes := func(branch [][32]byte) (out []common.Hash) {
+		for _, node := range branch {
+			out = append(out, node)
+		}
+		return
+	}
+
+	branch, err := bs.CurrentSyncCommitteeBranch()
+	require.NoError(t, err)
+	leaf, err := bs.CurrentSyncCommittee().HashSSZ()
+	require.NoError(t, err)
+	require.True(t, utils.IsValidMerkleBranch(leaf, toHashes(branch), 5, 22, root))
+
+	branch, err = bs.NextSyncCommitteeBranch()
+	require.NoError(t, err)
+	leaf, err = bs.NextSyncCommittee().HashSSZ()
+	require.NoError(t, err)
+	require.True(t, utils.IsValidMerkleBranch(leaf, toHashes(branch), 5, 23, root))
+	require.False(t, utils.IsValidMerkleBranch(leaf, toHashes(branch), 5, 22, root))
+
+	branch, err = bs.FinalityRootBranch()
+	require.NoError(t, err)
+	require.True(t, utils.IsValidMerkleBranch(bs.FinalizedCheckpoint().BlockRoot(), toHashes(branch), 6, 41, root))
+
+	// proofs of a schema with fewer fields than leaves
+	branch, err = merkle_tree.MerkleProof(2, 1, uint64(1), uint64(2), uint64(3))
+	require.NoError(t, err)
+	schemaRoot, err := merkle_tree.HashTreeRoot(uint64(1), uint64(2), uint64(3))
+	require.NoError(t, err)
+	require.True(t, utils.IsValidMerkleBranch(merkle_tree.Uint64Root(2), toHashes(branch), 2, 1, schemaRoot))
+}
@@ -0,0 +1,50 @@
+package merkle_tree
+
+import (
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/prysmaticlabs/gohashtree"
+)
+
+// MerkleProof returns the branch proving the field at proofIndex of the given schema, in a tree of the given depth.
+// The branch is ordered from the sibling of the leaf up to the sibling of the root's child.
+func MerkleProof(depth, proofIndex int, schema ...interface{}) ([][32]byte, error) {
+	leaves, err := schemaLeaves(schema...)
+	if err != nil {
+		return nil, err
+	}
+	return MerkleProofFromFlatLeaves(depth, proofIndex, leaves)
+}
+
+// MerkleProofFromFlatLeaves returns the branch proving the leaf at proofIndex of the flat leaves, padded with zero leaves to 2^depth.
+func MerkleProofFromFlatLeaves(depth, proofIndex int, leaves []byte) ([][32]byte, error) {
+	if len(leaves)%length.Hash != 0 {
+		return nil, fmt.Errorf("leaves must be a multiple of %d bytes", length.Hash)
+	}
+	if len(leaves)/length.Hash > 1<<depth || proofIndex >= 1<<depth {
+		return nil, fmt.Errorf("proof index %d out of a tree of depth %d", proofIndex, depth)
+	}
+	layer := make([][32]byte, len(leaves)/length.Hash)
+	for i := range layer {
+		copy(layer[i][:], leaves[i*length.Hash:])
+	}
+	branch := make([][32]byte, depth)
+	for i := 0; i < depth; i++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, ZeroHashes[i])
+		}
+		if sibling := proofIndex ^ 1; sibling < len(layer) {
+			branch[i] = layer[sibling]
+		} else {
+			branch[i] = ZeroHashes[i]
+		}
+		next := make([][32]byte, len(layer)/2)
+		if err := gohashtree.Hash(next, layer); err != nil {
+			return nil, err
+		}
+		layer = next
+		proofIndex /= 2
+	}
+	return branch, nil
+}
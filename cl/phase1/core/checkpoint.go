@@ -2,17 +2,148 @@ package core
 
 import (
 	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
 
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 
 	"github.com/ledgerwatch/erigon/cl/clparams"
-	"github.com/ledgerwatch/erigon/cl/utils"
 	"github.com/ledgerwatch/log/v3"
 )
 
+// CheckpointSyncConfig tells where to get the checkpoint state from and how to verify it
+type CheckpointSyncConfig struct {
+	// Uris serve the finalized state, they must all serve the same one
+	Uris []string
+	// File is an SSZ encoded state to use instead of the URIs
+	File string
+	// WeakSubjectivityCheckpoint is the checkpoint of the state, if it is set
+	WeakSubjectivityCheckpoint solid.Checkpoint
+}
+
+// ParseWeakSubjectivityCheckpoint parses a checkpoint given as root:epoch
+func ParseWeakSubjectivityCheckpoint(s string) (solid.Checkpoint, error) {
+	rootStr, epochStr, ok := strings.Cut(s, ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid weak subjectivity checkpoint %q, expected root:epoch", s)
+	}
+	var root libcommon.Hash
+	if err := root.UnmarshalText([]byte(rootStr)); err != nil {
+		return nil, fmt.Errorf("invalid weak subjectivity checkpoint root %q: %w", rootStr, err)
+	}
+	epoch, err := strconv.ParseUint(epochStr, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid weak subjectivity checkpoint epoch %q: %w", epochStr, err)
+	}
+	return solid.NewCheckpointFromParameters(root, epoch), nil
+}
+
+// RetrieveVerifiedBeaconState reads the checkpoint state from the file or from all the URIs, checks that the URIs agree
+// and that the state is the one of the weak subjectivity checkpoint
+func RetrieveVerifiedBeaconState(ctx context.Context, beaconConfig *clparams.BeaconChainConfig, genesisConfig *clparams.GenesisConfig, cfg CheckpointSyncConfig) (*state.BeaconState, error) {
+	var beaconState *state.BeaconState
+	if cfg.File != "" {
+		log.Info("[Checkpoint Sync] Reading beacon state", "file", cfg.File)
+		marshaled, err := os.ReadFile(cfg.File)
+		if err != nil {
+			return nil, fmt.Errorf("checkpoint sync failed %s", err)
+		}
+		if beaconState, err = decodeCheckpointState(beaconConfig, marshaled); err != nil {
+			return nil, err
+		}
+	} else {
+		if len(cfg.Uris) == 0 {
+			return nil, errors.New("checkpoint sync failed, no checkpoint sync endpoint")
+		}
+		var stateRoot libcommon.Hash
+		for i, uri := range cfg.Uris {
+			s, err := RetrieveBeaconState(ctx, beaconConfig, genesisConfig, uri)
+			if err != nil {
+				return nil, err
+			}
+			root, err := s.HashSSZ()
+			if err != nil {
+				return nil, err
+			}
+			if i > 0 && root != stateRoot {
+				return nil, fmt.Errorf("checkpoint sync failed, %s serves state %x at slot %d, %s serves state %x at slot %d",
+					cfg.Uris[0], stateRoot, beaconState.Slot(), uri, root, s.Slot())
+			}
+			beaconState, stateRoot = s, root
+		}
+	}
+	if gvr := beaconState.GenesisValidatorsRoot(); gvr != genesisConfig.GenesisValidatorRoot {
+		return nil, fmt.Errorf("checkpoint state has genesis validators root %x, expected %x", gvr, genesisConfig.GenesisValidatorRoot)
+	}
+	if cfg.WeakSubjectivityCheckpoint != nil {
+		if err := VerifyWeakSubjectivityCheckpoint(beaconState, cfg.WeakSubjectivityCheckpoint); err != nil {
+			return nil, err
+		}
+	}
+	return beaconState, nil
+}
+
+// VerifyWeakSubjectivityCheckpoint checks that the state is the one of the checkpoint: the state of its epoch whose
+// latest block is the checkpoint block
+func VerifyWeakSubjectivityCheckpoint(s *state.BeaconState, checkpoint solid.Checkpoint) error {
+	if epoch := state.Epoch(s.BeaconState); epoch != checkpoint.Epoch() {
+		return fmt.Errorf("checkpoint state is at epoch %d, weak subjectivity checkpoint is at epoch %d", epoch, checkpoint.Epoch())
+	}
+	blockRoot, err := latestBlockRoot(s)
+	if err != nil {
+		return err
+	}
+	if blockRoot != checkpoint.BlockRoot() {
+		return fmt.Errorf("checkpoint state has block root %x, weak subjectivity checkpoint has block root %x", blockRoot, checkpoint.BlockRoot())
+	}
+	log.Info("[Checkpoint Sync] Verified weak subjectivity checkpoint", "root", blockRoot, "epoch", checkpoint.Epoch())
+	return nil
+}
+
+// latestBlockRoot is the root of the latest block header of the state, whose state root is only set by the next slot
+// processing
+func latestBlockRoot(s *state.BeaconState) (libcommon.Hash, error) {
+	header := s.LatestBlockHeader()
+	if header.Root == (libcommon.Hash{}) {
+		stateRoot, err := s.HashSSZ()
+		if err != nil {
+			return libcommon.Hash{}, err
+		}
+		header.Root = stateRoot
+	}
+	return (&cltypes.BeaconBlockHeader{
+		Slot:          header.Slot,
+		ProposerIndex: header.ProposerIndex,
+		ParentRoot:    header.ParentRoot,
+		Root:          header.Root,
+		BodyRoot:      header.BodyRoot,
+	}).HashSSZ()
+}
+
+// decodeCheckpointState decodes the state with the version of its slot, which follows the genesis time and the
+// genesis validators root
+func decodeCheckpointState(beaconConfig *clparams.BeaconChainConfig, marshaled []byte) (*state.BeaconState, error) {
+	if len(marshaled) < 48 {
+		return nil, fmt.Errorf("checkpoint sync failed, state is too short: %d bytes", len(marshaled))
+	}
+	slot := binary.LittleEndian.Uint64(marshaled[40:48])
+	beaconState := state.New(beaconConfig)
+	if err := beaconState.DecodeSSZ(marshaled, int(beaconConfig.GetCurrentStateVersion(slot/beaconConfig.SlotsPerEpoch))); err != nil {
+		return nil, fmt.Errorf("checkpoint sync failed %s", err)
+	}
+	return beaconState, nil
+}
+
 func RetrieveBeaconState(ctx context.Context, beaconConfig *clparams.BeaconChainConfig, genesisConfig *clparams.GenesisConfig, uri string) (*state.BeaconState, error) {
 	log.Info("[Checkpoint Sync] Requesting beacon state", "uri", uri)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
@@ -39,12 +170,5 @@ func RetrieveBeaconState(ctx context.Context, beaconConfig *clparams.BeaconChain
 		return nil, fmt.Errorf("checkpoint sync failed %s", err)
 	}
 
-	epoch := utils.GetCurrentEpoch(genesisConfig.GenesisTime, beaconConfig.SecondsPerSlot, beaconConfig.SlotsPerEpoch)
-
-	beaconState := state.New(beaconConfig)
-	err = beaconState.DecodeSSZ(marshaled, int(beaconConfig.GetCurrentStateVersion(epoch)))
-	if err != nil {
-		return nil, fmt.Errorf("checkpoint sync failed %s", err)
-	}
-	return beaconState, nil
+	return decodeCheckpointState(beaconConfig, marshaled)
 }
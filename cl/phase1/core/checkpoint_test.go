@@ -0,0 +1,75 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+const anchorRoot = "0x564d76d91f66c1fb2977484a6184efda2e1c26dd01992e048353230e10f83201"
+
+func TestRetrieveVerifiedBeaconState(t *testing.T) {
+	ctx := context.Background()
+	beaconConfig := clparams.MainnetBeaconConfig
+	beaconConfig.AltairForkEpoch = 0
+	encoded, err := os.ReadFile("../forkchoice/test_data/anchor_state.ssz_snappy")
+	require.NoError(t, err)
+	anchorState, err := utils.DecompressSnappy(encoded)
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "state.ssz")
+	require.NoError(t, os.WriteFile(file, anchorState, 0600))
+
+	serve := func(state []byte) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(state) //nolint:errcheck
+		}))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	good, other := serve(anchorState), serve(anchorState)
+	otherState := libcommon.Copy(anchorState)
+	otherState[0]++ // genesis time
+	bad := serve(otherState)
+
+	s, err := decodeCheckpointState(&beaconConfig, anchorState)
+	require.NoError(t, err)
+	genesisConfig := &clparams.GenesisConfig{GenesisValidatorRoot: s.GenesisValidatorsRoot()}
+
+	// The checkpoint must match the state
+	checkpoint, err := ParseWeakSubjectivityCheckpoint(anchorRoot + ":0")
+	require.NoError(t, err)
+	_, err = RetrieveVerifiedBeaconState(ctx, &beaconConfig, genesisConfig, CheckpointSyncConfig{File: file, WeakSubjectivityCheckpoint: checkpoint})
+	require.NoError(t, err)
+	checkpoint, err = ParseWeakSubjectivityCheckpoint(anchorRoot + ":1")
+	require.NoError(t, err)
+	_, err = RetrieveVerifiedBeaconState(ctx, &beaconConfig, genesisConfig, CheckpointSyncConfig{File: file, WeakSubjectivityCheckpoint: checkpoint})
+	require.ErrorContains(t, err, "epoch")
+	checkpoint, err = ParseWeakSubjectivityCheckpoint(libcommon.Hash{1}.Hex() + ":0")
+	require.NoError(t, err)
+	_, err = RetrieveVerifiedBeaconState(ctx, &beaconConfig, genesisConfig, CheckpointSyncConfig{Uris: []string{good.URL}, WeakSubjectivityCheckpoint: checkpoint})
+	require.ErrorContains(t, err, "block root")
+
+	// The endpoints must agree
+	_, err = RetrieveVerifiedBeaconState(ctx, &beaconConfig, genesisConfig, CheckpointSyncConfig{Uris: []string{good.URL, other.URL}})
+	require.NoError(t, err)
+	_, err = RetrieveVerifiedBeaconState(ctx, &beaconConfig, genesisConfig, CheckpointSyncConfig{Uris: []string{good.URL, bad.URL}})
+	require.ErrorContains(t, err, "serves state")
+
+	// The state must be the one of the network
+	_, err = RetrieveVerifiedBeaconState(ctx, &beaconConfig, &clparams.GenesisConfig{GenesisValidatorRoot: libcommon.Hash{1}}, CheckpointSyncConfig{File: file})
+	require.ErrorContains(t, err, "genesis validators root")
+
+	for _, invalid := range []string{"", anchorRoot, anchorRoot + ":x", "0x12:1"} {
+		_, err = ParseWeakSubjectivityCheckpoint(invalid)
+		require.Error(t, err, invalid)
+	}
+}
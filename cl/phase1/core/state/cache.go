@@ -57,6 +57,13 @@ func (b *BeaconState) SetPreviousStateRoot(root libcommon.Hash) {
 }
 
 func (b *BeaconState) _updateProposerIndex() (err error) {
+	b.proposerIndex = new(uint64)
+	*b.proposerIndex, err = b.computeProposerIndex(b.Slot())
+	return
+}
+
+// computeProposerIndex computes the proposer of a slot of the current epoch
+func (b *BeaconState) computeProposerIndex(slot uint64) (uint64, error) {
 	epoch := Epoch(b.BeaconState)
 
 	hash := sha256.New()
@@ -67,7 +74,7 @@ func (b *BeaconState) _updateProposerIndex() (err error) {
 	mix := b.GetRandaoMix(int(mixPosition))
 	input := shuffling2.GetSeed(b.BeaconConfig(), mix, epoch, b.BeaconConfig().DomainBeaconProposer)
 	slotByteArray := make([]byte, 8)
-	binary.LittleEndian.PutUint64(slotByteArray, b.Slot())
+	binary.LittleEndian.PutUint64(slotByteArray, slot)
 
 	// Add slot to the end of the input.
 	inputWithSlot := append(input[:], slotByteArray...)
@@ -81,9 +88,7 @@ func (b *BeaconState) _updateProposerIndex() (err error) {
 	// Write the seed to an array.
 	seedArray := [32]byte{}
 	copy(seedArray[:], seed)
-	b.proposerIndex = new(uint64)
-	*b.proposerIndex, err = shuffling2.ComputeProposerIndex(b.BeaconState, indices, seedArray)
-	return
+	return shuffling2.ComputeProposerIndex(b.BeaconState, indices, seedArray)
 }
 
 // _initializeValidatorsPhase0 initializes the validators matching flags based on previous/current attestations
@@ -74,6 +74,17 @@ func (b *BeaconState) GetBeaconProposerIndex() (uint64, error) {
 	return *b.proposerIndex, nil
 }
 
+// GetBeaconProposerIndexForSlot gets the beacon proposer index of a slot of the current epoch
+func (b *BeaconState) GetBeaconProposerIndexForSlot(slot uint64) (uint64, error) {
+	if epoch := Epoch(b.BeaconState); slot/b.BeaconConfig().SlotsPerEpoch != epoch {
+		return 0, fmt.Errorf("slot %d is not in the current epoch %d", slot, epoch)
+	}
+	if slot == b.Slot() {
+		return b.GetBeaconProposerIndex()
+	}
+	return b.computeProposerIndex(slot)
+}
+
 // BaseRewardPerIncrement return base rewards for processing sync committee and duties.
 func (b *BeaconState) BaseRewardPerIncrement() uint64 {
 	if b.totalActiveBalanceCache == nil {
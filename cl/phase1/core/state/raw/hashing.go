@@ -301,3 +301,33 @@ func (b *BeaconState) markLeaf(idxs ...StateLeafIndex) {
 		b.touchedLeaves[idx] = true
 	}
 }
+
+// stateLeavesDepth is the depth of the tree of the state fields.
+const stateLeavesDepth = 5
+
+func (b *BeaconState) leafBranch(index StateLeafIndex) ([][32]byte, error) {
+	if err := b.computeDirtyLeaves(); err != nil {
+		return nil, err
+	}
+	return merkle_tree.MerkleProofFromFlatLeaves(stateLeavesDepth, int(index), b.leaves)
+}
+
+// CurrentSyncCommitteeBranch returns the branch proving the current sync committee against the state root.
+func (b *BeaconState) CurrentSyncCommitteeBranch() ([][32]byte, error) {
+	return b.leafBranch(CurrentSyncCommitteeLeafIndex)
+}
+
+// NextSyncCommitteeBranch returns the branch proving the next sync committee against the state root.
+func (b *BeaconState) NextSyncCommitteeBranch() ([][32]byte, error) {
+	return b.leafBranch(NextSyncCommitteeLeafIndex)
+}
+
+// FinalityRootBranch returns the branch proving the block root of the finalized checkpoint against the state root.
+func (b *BeaconState) FinalityRootBranch() ([][32]byte, error) {
+	branch, err := b.leafBranch(FinalizedCheckpointLeafIndex)
+	if err != nil {
+		return nil, err
+	}
+	// The root is the second field of the checkpoint, so its sibling is the epoch.
+	return append([][32]byte{merkle_tree.Uint64Root(b.finalizedCheckpoint.Epoch())}, branch...), nil
+}
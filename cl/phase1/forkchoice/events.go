@@ -0,0 +1,123 @@
+package forkchoice
+
+import (
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
+	"github.com/ledgerwatch/erigon/cl/beacon/types"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+)
+
+// emitBlock publishes a block event for a newly imported block.
+func (f *ForkChoiceStore) emitBlock(blockRoot libcommon.Hash, slot uint64) {
+	if f.emitters == nil {
+		return
+	}
+	f.emitters.Publish(beaconevents.TopicBlock, &beaconevents.BlockEvent{
+		Slot:  types.Uint64(slot),
+		Block: blockRoot,
+	})
+}
+
+// onHeadChange follows the head computed by the fork choice. When it has changed
+// since the last call, the light client updates of the new head are produced
+// and the head events are published.
+func (f *ForkChoiceStore) onHeadChange(headRoot libcommon.Hash, headSlot uint64) {
+	if headRoot == f.lastHeadRoot {
+		return
+	}
+	oldHeadRoot, oldHeadSlot := f.lastHeadRoot, f.lastHeadSlot
+	f.lastHeadRoot, f.lastHeadSlot = headRoot, headSlot
+	if f.lightClient != nil {
+		if err := f.lightClient.OnHead(headRoot); err != nil {
+			log.Warn("could not produce light client updates", "slot", headSlot, "err", err)
+		}
+	}
+	f.emitHeadChanges(oldHeadRoot, oldHeadSlot, headRoot, headSlot)
+}
+
+// emitAttestation publishes an attestation event for attestations received
+// from the network.
+func (f *ForkChoiceStore) emitAttestation(attestation *solid.Attestation, fromBlock bool) {
+	if fromBlock || !f.emitters.HasSubscribers(beaconevents.TopicAttestation) {
+		return
+	}
+	f.emitters.Publish(beaconevents.TopicAttestation, beaconevents.NewAttestationEvent(attestation))
+}
+
+func (f *ForkChoiceStore) emitFinalizedCheckpoint(checkpoint solid.Checkpoint) {
+	if f.emitters == nil {
+		return
+	}
+	event := &beaconevents.FinalizedCheckpointEvent{
+		Block: checkpoint.BlockRoot(),
+		Epoch: types.Uint64(checkpoint.Epoch()),
+	}
+	if header, has := f.forkGraph.GetHeader(checkpoint.BlockRoot()); has {
+		event.State = header.Root
+	}
+	f.emitters.Publish(beaconevents.TopicFinalizedCheckpoint, event)
+}
+
+// emitHeadChanges publishes a head event, and a chain_reorg event if the new
+// head does not descend from the previous one.
+func (f *ForkChoiceStore) emitHeadChanges(oldHeadRoot libcommon.Hash, oldHeadSlot uint64, headRoot libcommon.Hash, headSlot uint64) {
+	if f.emitters == nil {
+		return
+	}
+
+	var headState, oldHeadState libcommon.Hash
+	if header, has := f.forkGraph.GetHeader(headRoot); has {
+		headState = header.Root
+	}
+	if header, has := f.forkGraph.GetHeader(oldHeadRoot); has {
+		oldHeadState = header.Root
+	}
+	epoch := f.computeEpochAtSlot(headSlot)
+
+	if f.Ancestor(headRoot, oldHeadSlot) != oldHeadRoot {
+		f.emitters.Publish(beaconevents.TopicChainReorg, &beaconevents.ChainReorgEvent{
+			Slot:         types.Uint64(headSlot),
+			Depth:        types.Uint64(oldHeadSlot - f.commonAncestorSlot(oldHeadRoot, headRoot)),
+			OldHeadBlock: oldHeadRoot,
+			NewHeadBlock: headRoot,
+			OldHeadState: oldHeadState,
+			NewHeadState: headState,
+			Epoch:        types.Uint64(epoch),
+		})
+	}
+
+	f.emitters.Publish(beaconevents.TopicHead, &beaconevents.HeadEvent{
+		Slot:                      types.Uint64(headSlot),
+		Block:                     headRoot,
+		State:                     headState,
+		EpochTransition:           epoch != f.computeEpochAtSlot(oldHeadSlot),
+		PreviousDutyDependentRoot: f.dutyDependentRoot(headRoot, epoch, 1),
+		CurrentDutyDependentRoot:  f.dutyDependentRoot(headRoot, epoch, 0),
+	})
+}
+
+// dutyDependentRoot returns the root of the last block before the epoch which
+// is lookback epochs before the given one, which duties of that epoch depend on.
+func (f *ForkChoiceStore) dutyDependentRoot(headRoot libcommon.Hash, epoch, lookback uint64) libcommon.Hash {
+	if epoch < lookback+1 {
+		return f.Ancestor(headRoot, 0)
+	}
+	return f.Ancestor(headRoot, f.computeStartSlotAtEpoch(epoch-lookback)-1)
+}
+
+// commonAncestorSlot returns the slot of the latest common ancestor of two
+// blocks, or 0 if it is not in the fork graph anymore.
+func (f *ForkChoiceStore) commonAncestorSlot(a, b libcommon.Hash) uint64 {
+	for {
+		header, has := f.forkGraph.GetHeader(a)
+		if !has {
+			return 0
+		}
+		if f.Ancestor(b, header.Slot) == a {
+			return header.Slot
+		}
+		a = header.ParentRoot
+	}
+}
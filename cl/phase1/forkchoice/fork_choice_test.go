@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/freezer"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
 
@@ -46,7 +47,7 @@ func TestForkChoiceBasic(t *testing.T) {
 	// Initialize forkchoice store
 	anchorState := state.New(&clparams.MainnetBeaconConfig)
 	require.NoError(t, utils.DecodeSSZSnappy(anchorState, anchorStateEncoded, int(clparams.AltairVersion)))
-	store, err := forkchoice.NewForkChoiceStore(anchorState, nil, nil, false)
+	store, err := forkchoice.NewForkChoiceStore(anchorState, nil, nil, nil, nil, nil, false)
 	require.NoError(t, err)
 	// first steps
 	store.OnTick(0)
@@ -86,3 +87,62 @@ func TestForkChoiceBasic(t *testing.T) {
 	// lastly do attestation
 	require.NoError(t, store.OnAttestation(testAttestation, false))
 }
+
+func TestOnBlobSidecar(t *testing.T) {
+	block0x3a := &cltypes.SignedBeaconBlock{}
+	require.NoError(t, utils.DecodeSSZSnappy(block0x3a, block3aEncoded, int(clparams.AltairVersion)))
+	anchorState := state.New(&clparams.MainnetBeaconConfig)
+	require.NoError(t, utils.DecodeSSZSnappy(anchorState, anchorStateEncoded, int(clparams.AltairVersion)))
+	blobs := freezer.NewBlobSidecarStore(&freezer.InMemory{})
+	store, err := forkchoice.NewForkChoiceStore(anchorState, nil, nil, blobs, nil, nil, false)
+	require.NoError(t, err)
+	store.OnTick(12)
+
+	blockRoot, err := block0x3a.Block.HashSSZ()
+	require.NoError(t, err)
+	newSidecar := func() *cltypes.SignedBlobSideCar {
+		return &cltypes.SignedBlobSideCar{Message: &cltypes.BlobSideCar{
+			BlockRoot:       blockRoot,
+			Index:           1,
+			Slot:            block0x3a.Block.Slot,
+			BlockParentRoot: block0x3a.Block.ParentRoot,
+			ProposerIndex:   block0x3a.Block.ProposerIndex,
+		}}
+	}
+	sidecar := newSidecar()
+	require.ErrorContains(t, store.OnBlobSidecar(sidecar, 2), "wrong subnet")
+	sidecar.Message.Index = cltypes.MaxBlobsPerBlock
+	require.ErrorContains(t, store.OnBlobSidecar(sidecar, 0), "too big")
+	sidecar = newSidecar()
+	sidecar.Message.Slot = 2
+	require.ErrorContains(t, store.OnBlobSidecar(sidecar, 1), "too early")
+	sidecar = newSidecar()
+	sidecar.Message.BlockParentRoot = libcommon.Hash{1}
+	require.ErrorContains(t, store.OnBlobSidecar(sidecar, 1), "not known")
+	sidecar = newSidecar()
+	sidecar.Message.ProposerIndex++
+	require.ErrorContains(t, store.OnBlobSidecar(sidecar, 1), "expected proposer")
+	// the proposer is right, but the sidecar is not signed
+	require.Error(t, store.OnBlobSidecar(newSidecar(), 1))
+	require.False(t, blobs.Has(blockRoot, 1))
+}
+
+func TestAddBlobSidecars(t *testing.T) {
+	block0x3a := &cltypes.SignedBeaconBlock{}
+	require.NoError(t, utils.DecodeSSZSnappy(block0x3a, block3aEncoded, int(clparams.AltairVersion)))
+	anchorState := state.New(&clparams.MainnetBeaconConfig)
+	require.NoError(t, utils.DecodeSSZSnappy(anchorState, anchorStateEncoded, int(clparams.AltairVersion)))
+	blobs := freezer.NewBlobSidecarStore(&freezer.InMemory{})
+	store, err := forkchoice.NewForkChoiceStore(anchorState, nil, nil, blobs, nil, nil, false)
+	require.NoError(t, err)
+
+	blockRoot, err := block0x3a.Block.HashSSZ()
+	require.NoError(t, err)
+	// pre-deneb blocks have no sidecars to download
+	missing, err := store.MissingBlobSidecars(block0x3a)
+	require.NoError(t, err)
+	require.Empty(t, missing)
+	require.ErrorContains(t, store.AddBlobSidecars(block0x3a, []*cltypes.BlobSideCar{{BlockRoot: libcommon.Hash{1}}}), "received for block")
+	require.ErrorContains(t, store.AddBlobSidecars(block0x3a, []*cltypes.BlobSideCar{{BlockRoot: blockRoot}}), "too big")
+	require.False(t, blobs.Has(blockRoot, 0))
+}
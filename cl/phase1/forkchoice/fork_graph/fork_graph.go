@@ -43,6 +43,7 @@ type ForkGraph struct {
 	nextReferenceState    *state.BeaconState
 	blocks                map[libcommon.Hash]*cltypes.SignedBeaconBlock // set of blocks
 	headers               map[libcommon.Hash]*cltypes.BeaconBlockHeader // set of headers
+	stateRoots            map[libcommon.Hash]libcommon.Hash             // post-state root -> block root
 	badBlocks             map[libcommon.Hash]struct{}                   // blocks that are invalid and that leads to automatic fail of extension.
 	// current state data
 	currentState          *state.BeaconState
@@ -78,6 +79,7 @@ func New(anchorState *state.BeaconState, enabledPruning bool) *ForkGraph {
 		panic(err)
 	}
 	headers[anchorRoot] = &anchorHeader
+	stateRoots := map[libcommon.Hash]libcommon.Hash{anchorHeader.Root: anchorRoot}
 
 	farthestExtendingPath[anchorRoot] = true
 	currentStateReference, err := anchorState.Copy()
@@ -92,9 +94,10 @@ func New(anchorState *state.BeaconState, enabledPruning bool) *ForkGraph {
 		currentReferenceState: currentStateReference,
 		nextReferenceState:    nextStateReference,
 		// storage
-		blocks:    make(map[libcommon.Hash]*cltypes.SignedBeaconBlock),
-		headers:   headers,
-		badBlocks: make(map[libcommon.Hash]struct{}),
+		blocks:     make(map[libcommon.Hash]*cltypes.SignedBeaconBlock),
+		headers:    headers,
+		stateRoots: stateRoots,
+		badBlocks:  make(map[libcommon.Hash]struct{}),
 		// current state data
 		currentState:          anchorState,
 		currentStateBlockRoot: anchorRoot,
@@ -173,6 +176,7 @@ func (f *ForkGraph) AddChainSegment(signedBlock *cltypes.SignedBeaconBlock, full
 		Root:          block.StateRoot,
 		BodyRoot:      bodyRoot,
 	}
+	f.stateRoots[block.StateRoot] = blockRoot
 	// Update the children of the parent
 	f.updateChildren(block.ParentRoot, blockRoot)
 	// Lastly add checkpoints to caches as well.
@@ -209,19 +213,28 @@ func (f *ForkGraph) getBlock(blockRoot libcommon.Hash) (*cltypes.SignedBeaconBlo
 	return obj, has
 }
 
-func (f *ForkGraph) GetState(blockRoot libcommon.Hash, alwaysCopy bool) (*state.BeaconState, bool, error) {
-	// collect all blocks beetwen greatest extending node path and block.
-	blocksInTheWay := []*cltypes.SignedBeaconBlock{}
+func (f *ForkGraph) GetBlock(blockRoot libcommon.Hash) (*cltypes.SignedBeaconBlock, bool) {
+	return f.getBlock(blockRoot)
+}
+
+// GetBlockRootByStateRoot looks up a known block by its post-state root.
+func (f *ForkGraph) GetBlockRootByStateRoot(stateRoot libcommon.Hash) (libcommon.Hash, bool) {
+	blockRoot, has := f.stateRoots[stateRoot]
+	return blockRoot, has
+}
+
+// collectBlocksToReplay collects the blocks between the given block and the reference state it reconnects to, last block first.
+func (f *ForkGraph) collectBlocksToReplay(blockRoot libcommon.Hash) (blocksInTheWay []*cltypes.SignedBeaconBlock, longReconnection, didLongRecconnection, found bool, err error) {
 	// Use the parent root as a reverse iterator.
 	currentIteratorRoot := blockRoot
 	// use the current reference state root as reconnectio
 	reconnectionRootLong, err := f.currentReferenceState.BlockRoot()
 	if err != nil {
-		return nil, false, err
+		return nil, false, false, false, err
 	}
 	reconnectionRootShort, err := f.nextReferenceState.BlockRoot()
 	if err != nil {
-		return nil, false, err
+		return nil, false, false, false, err
 	}
 	// try and find the point of recconection
 	for currentIteratorRoot != reconnectionRootLong && currentIteratorRoot != reconnectionRootShort {
@@ -229,14 +242,23 @@ func (f *ForkGraph) GetState(blockRoot libcommon.Hash, alwaysCopy bool) (*state.
 		if !isSegmentPresent {
 			log.Debug("Could not retrieve state: Missing header", "missing", currentIteratorRoot,
 				"longRecconection", libcommon.Hash(reconnectionRootLong), "shortRecconection", libcommon.Hash(reconnectionRootShort))
-			return nil, false, nil
+			return nil, false, false, false, nil
 		}
 		blocksInTheWay = append(blocksInTheWay, block)
 		currentIteratorRoot = block.Block.ParentRoot
 	}
+	longReconnection = currentIteratorRoot == reconnectionRootLong
+	didLongRecconnection = longReconnection && reconnectionRootLong != reconnectionRootShort
+	return blocksInTheWay, longReconnection, didLongRecconnection, true, nil
+}
+
+func (f *ForkGraph) GetState(blockRoot libcommon.Hash, alwaysCopy bool) (*state.BeaconState, bool, error) {
+	// collect all blocks beetwen greatest extending node path and block.
+	blocksInTheWay, longReconnection, didLongRecconnection, found, err := f.collectBlocksToReplay(blockRoot)
+	if err != nil || !found {
+		return nil, false, err
+	}
 
-	var copyReferencedState *state.BeaconState
-	didLongRecconnection := currentIteratorRoot == reconnectionRootLong && reconnectionRootLong != reconnectionRootShort
 	if f.currentStateBlockRoot == blockRoot {
 		if alwaysCopy {
 			s, err := f.currentState.Copy()
@@ -245,25 +267,52 @@ func (f *ForkGraph) GetState(blockRoot libcommon.Hash, alwaysCopy bool) (*state.
 		return f.currentState, didLongRecconnection, nil
 	}
 	// Take a copy to the reference state.
-	if currentIteratorRoot == reconnectionRootLong {
-		copyReferencedState, err = f.currentReferenceState.Copy()
-		if err != nil {
-			return nil, true, err
-		}
-	} else {
-		copyReferencedState, err = f.nextReferenceState.Copy()
-		if err != nil {
-			return nil, false, err
-		}
+	copyReferencedState, err := f.copyReferenceState(longReconnection)
+	if err != nil {
+		return nil, longReconnection, err
 	}
 
 	// Traverse the blocks from top to bottom.
+	if err := ReplayBlocks(copyReferencedState, blocksInTheWay); err != nil {
+		return nil, didLongRecconnection, err
+	}
+	return copyReferencedState, didLongRecconnection, nil
+}
+
+// GetStateToReplay returns a copy of the state from which the post-state of the given block is reconstructed, and the
+// blocks to apply to it with ReplayBlocks. The state is nil if the block is unknown. Unlike GetState, the graph is
+// only needed to take the copy, so the blocks can be replayed without holding the lock of the graph.
+func (f *ForkGraph) GetStateToReplay(blockRoot libcommon.Hash) (*state.BeaconState, []*cltypes.SignedBeaconBlock, error) {
+	blocksInTheWay, longReconnection, _, found, err := f.collectBlocksToReplay(blockRoot)
+	if err != nil || !found {
+		return nil, nil, err
+	}
+	if f.currentStateBlockRoot == blockRoot {
+		s, err := f.currentState.Copy()
+		return s, nil, err
+	}
+	s, err := f.copyReferenceState(longReconnection)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s, blocksInTheWay, nil
+}
+
+func (f *ForkGraph) copyReferenceState(longReconnection bool) (*state.BeaconState, error) {
+	if longReconnection {
+		return f.currentReferenceState.Copy()
+	}
+	return f.nextReferenceState.Copy()
+}
+
+// ReplayBlocks applies the blocks returned by GetStateToReplay to its state, from top to bottom.
+func ReplayBlocks(s *state.BeaconState, blocksInTheWay []*cltypes.SignedBeaconBlock) error {
 	for i := len(blocksInTheWay) - 1; i >= 0; i-- {
-		if err := transition.TransitionState(copyReferencedState, blocksInTheWay[i], false); err != nil {
-			return nil, didLongRecconnection, err
+		if err := transition.TransitionState(s, blocksInTheWay[i], false); err != nil {
+			return err
 		}
 	}
-	return copyReferencedState, didLongRecconnection, nil
+	return nil
 }
 
 // updateChildren adds a new child to the parent node hash.
@@ -306,6 +355,9 @@ func (f *ForkGraph) removeOldData() (err error) {
 		delete(f.childrens, root)
 		delete(f.currentJustifiedCheckpoints, root)
 		delete(f.finalizedCheckpoints, root)
+		if header, has := f.headers[root]; has {
+			delete(f.stateRoots, header.Root)
+		}
 		delete(f.headers, root)
 	}
 	// Lastly snapshot the state
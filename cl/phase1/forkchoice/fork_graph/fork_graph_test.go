@@ -4,6 +4,7 @@ import (
 	_ "embed"
 	"testing"
 
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 
 	"github.com/ledgerwatch/erigon/cl/clparams"
@@ -45,5 +46,18 @@ func TestForkGraph(t *testing.T) {
 	_, status, err = graph.AddChainSegment(blockB, true)
 	require.NoError(t, err)
 	require.Equal(t, status, PreValidated)
+	// Blocks are indexed by their post-state root
+	blockRootA, err := blockA.Block.HashSSZ()
+	require.NoError(t, err)
+	blockRoot, has := graph.GetBlockRootByStateRoot(blockA.Block.StateRoot)
+	require.True(t, has)
+	require.Equal(t, libcommon.Hash(blockRootA), blockRoot)
+	// The state replayed outside of the graph is the one it computed
+	replayed, blocksInTheWay, err := graph.GetStateToReplay(blockRootA)
+	require.NoError(t, err)
+	require.NoError(t, ReplayBlocks(replayed, blocksInTheWay))
+	stateRoot, err := replayed.HashSSZ()
+	require.NoError(t, err)
+	require.Equal(t, libcommon.Hash(blockA.Block.StateRoot), libcommon.Hash(stateRoot))
 	graph.removeOldData()
 }
@@ -1,13 +1,17 @@
 package forkchoice
 
 import (
+	"fmt"
 	"sync"
 
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/freezer"
 	state2 "github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	"github.com/ledgerwatch/erigon/cl/phase1/execution_client"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice/fork_graph"
+	"github.com/ledgerwatch/erigon/cl/phase1/light_client"
 
 	lru "github.com/hashicorp/golang-lru/v2"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
@@ -18,6 +22,7 @@ type checkpointComparable string
 const (
 	checkpointsPerCache = 1024
 	allowedCachedStates = 4
+	allowedFullStates   = 8
 )
 
 type ForkChoiceStore struct {
@@ -36,11 +41,24 @@ type ForkChoiceStore struct {
 	latestMessages   map[uint64]*LatestMessage
 	// We keep track of them so that we can forkchoice with EL.
 	eth2Roots *lru.Cache[libcommon.Hash, libcommon.Hash] // ETH2 root -> ETH1 hash
-	mu        sync.Mutex
+	// Post-states reconstructed for the Beacon API, never modified once cached.
+	fullStates *lru.Cache[libcommon.Hash, *state2.BeaconState] // block root -> post-state
+	// Copy of the post-state of the head, shared by the readers until the head changes.
+	headState     *state2.BeaconState
+	headStateRoot libcommon.Hash
+	mu            sync.Mutex
 	// EL
 	engine execution_client.ExecutionEngine
 	// freezer
 	recorder freezer.Freezer
+	// blob sidecars, needed for data availability of deneb blocks
+	blobs *freezer.BlobSidecarStore
+	// light client data recorded on block import, its updates produced on head changes
+	lightClient *light_client.Store
+	// Beacon API events, and the last head they and the light client updates were produced for
+	emitters     *beaconevents.Emitters
+	lastHeadRoot libcommon.Hash
+	lastHeadSlot uint64
 }
 
 type LatestMessage struct {
@@ -49,7 +67,7 @@ type LatestMessage struct {
 }
 
 // NewForkChoiceStore initialize a new store from the given anchor state, either genesis or checkpoint sync state.
-func NewForkChoiceStore(anchorState *state2.BeaconState, engine execution_client.ExecutionEngine, recorder freezer.Freezer, enabledPruning bool) (*ForkChoiceStore, error) {
+func NewForkChoiceStore(anchorState *state2.BeaconState, engine execution_client.ExecutionEngine, recorder freezer.Freezer, blobs *freezer.BlobSidecarStore, lightClient *light_client.Store, emitters *beaconevents.Emitters, enabledPruning bool) (*ForkChoiceStore, error) {
 	anchorRoot, err := anchorState.BlockRoot()
 	if err != nil {
 		return nil, err
@@ -66,6 +84,10 @@ func NewForkChoiceStore(anchorState *state2.BeaconState, engine execution_client
 	if err != nil {
 		return nil, err
 	}
+	fullStates, err := lru.New[libcommon.Hash, *state2.BeaconState](allowedFullStates)
+	if err != nil {
+		return nil, err
+	}
 	return &ForkChoiceStore{
 		highestSeen:                   anchorState.Slot(),
 		time:                          anchorState.GenesisTime() + anchorState.BeaconConfig().SecondsPerSlot*anchorState.Slot(),
@@ -78,8 +100,14 @@ func NewForkChoiceStore(anchorState *state2.BeaconState, engine execution_client
 		latestMessages:                map[uint64]*LatestMessage{},
 		checkpointStates:              checkpointStates,
 		eth2Roots:                     eth2Roots,
+		fullStates:                    fullStates,
 		engine:                        engine,
 		recorder:                      recorder,
+		blobs:                         blobs,
+		lightClient:                   lightClient,
+		emitters:                      emitters,
+		lastHeadRoot:                  anchorRoot,
+		lastHeadSlot:                  anchorState.Slot(),
 	}, nil
 }
 
@@ -146,3 +174,93 @@ func (f *ForkChoiceStore) AnchorSlot() uint64 {
 	defer f.mu.Unlock()
 	return f.forkGraph.AnchorSlot()
 }
+
+// GetHeader returns a copy of the header of the block with the given root, if known.
+func (f *ForkChoiceStore) GetHeader(blockRoot libcommon.Hash) (*cltypes.BeaconBlockHeader, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	header, has := f.forkGraph.GetHeader(blockRoot)
+	if !has {
+		return nil, false
+	}
+	return header.Copy(), true
+}
+
+// GetBlockSignature returns the signature of the block with the given root, if the block is still in the fork graph.
+func (f *ForkChoiceStore) GetBlockSignature(blockRoot libcommon.Hash) ([96]byte, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	block, has := f.forkGraph.GetBlock(blockRoot)
+	if !has {
+		return [96]byte{}, false
+	}
+	return block.Signature, true
+}
+
+// GetFullState returns a copy of the post-state of the block with the given root, or nil if it cannot be reconstructed.
+// The blocks leading to the state are replayed without holding the store lock, and the result is cached.
+func (f *ForkChoiceStore) GetFullState(blockRoot libcommon.Hash) (*state2.BeaconState, error) {
+	if s, ok := f.fullStates.Get(blockRoot); ok {
+		return s.Copy()
+	}
+	f.mu.Lock()
+	s, blocksInTheWay, err := f.forkGraph.GetStateToReplay(blockRoot)
+	f.mu.Unlock()
+	if err != nil || s == nil {
+		return nil, err
+	}
+	if err := fork_graph.ReplayBlocks(s, blocksInTheWay); err != nil {
+		return nil, err
+	}
+	f.fullStates.Add(blockRoot, s)
+	return s.Copy()
+}
+
+// HeadState returns the post-state of the head block. The copy is shared by the callers until the head changes, so
+// they must not modify it.
+func (f *ForkChoiceStore) HeadState() (*state2.BeaconState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	headRoot, _, err := f.getHead()
+	if err != nil {
+		return nil, err
+	}
+	if f.headState != nil && f.headStateRoot == headRoot {
+		return f.headState, nil
+	}
+	s, _, err := f.forkGraph.GetState(headRoot, false)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, fmt.Errorf("state of the head %x is not available", headRoot)
+	}
+	if s, err = s.Copy(); err != nil {
+		return nil, err
+	}
+	f.headState, f.headStateRoot = s, headRoot
+	return s, nil
+}
+
+// GetCanonicalBlockRoot returns the root of the canonical block at the given slot, if the slot is not empty.
+func (f *ForkChoiceStore) GetCanonicalBlockRoot(slot uint64) (libcommon.Hash, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	headRoot, _, err := f.getHead()
+	if err != nil {
+		return libcommon.Hash{}, false, err
+	}
+	root := f.Ancestor(headRoot, slot)
+	header, has := f.forkGraph.GetHeader(root)
+	if !has || header.Slot != slot {
+		return libcommon.Hash{}, false, nil
+	}
+	return root, true, nil
+}
+
+// GetBlockRootByStateRoot returns the root of the known block whose post-state has the given root.
+func (f *ForkChoiceStore) GetBlockRootByStateRoot(stateRoot libcommon.Hash) (libcommon.Hash, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.forkGraph.GetBlockRootByStateRoot(stateRoot)
+}
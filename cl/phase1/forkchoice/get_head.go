@@ -13,7 +13,12 @@ import (
 func (f *ForkChoiceStore) GetHead() (libcommon.Hash, uint64, error) {
 	f.mu.Lock()
 	defer f.mu.Unlock()
-	return f.getHead()
+	headRoot, headSlot, err := f.getHead()
+	if err != nil {
+		return libcommon.Hash{}, 0, err
+	}
+	f.onHeadChange(headRoot, headSlot)
+	return headRoot, headSlot, nil
 }
 
 func (f *ForkChoiceStore) getHead() (libcommon.Hash, uint64, error) {
@@ -21,6 +21,7 @@ func (f *ForkChoiceStore) OnAttestation(attestation *solid.Attestation, fromBloc
 	target := data.Target()
 	if cachedIndicies, ok := cache.LoadAttestatingIndicies(&data, attestation.AggregationBits()); ok {
 		f.processAttestingIndicies(attestation, cachedIndicies)
+		f.emitAttestation(attestation, fromBlock)
 		return nil
 	}
 	targetState, err := f.getCheckpointState(target)
@@ -52,6 +53,7 @@ func (f *ForkChoiceStore) OnAttestation(attestation *solid.Attestation, fromBloc
 	}
 	// Lastly update latest messages.
 	f.processAttestingIndicies(attestation, attestationIndicies)
+	f.emitAttestation(attestation, fromBlock)
 	return nil
 }
 
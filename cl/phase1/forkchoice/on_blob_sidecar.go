@@ -0,0 +1,226 @@
+package forkchoice
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/Giulio2002/bls"
+	gokzg4844 "github.com/crate-crypto/go-kzg-4844"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	libkzg "github.com/ledgerwatch/erigon-lib/crypto/kzg"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/fork"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/transition"
+)
+
+// ErrDataNotAvailable is returned when the blob sidecars of a deneb block were not received yet.
+var ErrDataNotAvailable = errors.New("blob sidecars of the block are not available")
+
+// blobSidecarProposer is the proposer expected to sign a blob sidecar
+type blobSidecarProposer struct {
+	index     uint64
+	publicKey [48]byte
+	domain    []byte
+}
+
+// computeBlobSidecarProposer returns the proposer of the slot of the sidecar, the state must be in the epoch of the sidecar
+func computeBlobSidecarProposer(s *state.BeaconState, sidecar *cltypes.BlobSideCar) (*blobSidecarProposer, error) {
+	proposerIndex, err := s.GetBeaconProposerIndexForSlot(sidecar.Slot)
+	if err != nil {
+		return nil, err
+	}
+	proposer, err := s.ValidatorForValidatorIndex(int(proposerIndex))
+	if err != nil {
+		return nil, err
+	}
+	domain, err := s.GetDomain(s.BeaconConfig().DomainBlobSideCar, sidecar.Slot/s.BeaconConfig().SlotsPerEpoch)
+	if err != nil {
+		return nil, err
+	}
+	return &blobSidecarProposer{index: proposerIndex, publicKey: proposer.PublicKey(), domain: domain}, nil
+}
+
+// checkBlobSidecar does the checks of the sidecar against the store. It returns the expected proposer if the parent
+// state is in the epoch of the sidecar, otherwise a copy of the parent state to move to that epoch outside the lock.
+// Both are nil if the sidecar is already stored.
+func (f *ForkChoiceStore) checkBlobSidecar(sidecar *cltypes.BlobSideCar) (*blobSidecarProposer, *state.BeaconState, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Slot() < sidecar.Slot {
+		return nil, nil, fmt.Errorf("blob sidecar is too early compared to current_slot")
+	}
+	if sidecar.Slot <= f.computeStartSlotAtEpoch(f.finalizedCheckpoint.Epoch()) {
+		return nil, nil, fmt.Errorf("blob sidecar is not later than the finalized slot")
+	}
+	if f.blobs.Has(sidecar.BlockRoot, sidecar.Index) {
+		return nil, nil, nil
+	}
+	parentHeader, has := f.forkGraph.GetHeader(sidecar.BlockParentRoot)
+	if !has {
+		return nil, nil, fmt.Errorf("parent block %x of blob sidecar is not known", sidecar.BlockParentRoot)
+	}
+	if parentHeader.Slot >= sidecar.Slot {
+		return nil, nil, fmt.Errorf("blob sidecar is not later than its parent block")
+	}
+	moveEpoch := f.computeEpochAtSlot(parentHeader.Slot) != f.computeEpochAtSlot(sidecar.Slot)
+	s, _, err := f.forkGraph.GetState(sidecar.BlockParentRoot, moveEpoch)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s == nil {
+		return nil, nil, fmt.Errorf("parent state of blob sidecar not accessible")
+	}
+	if moveEpoch {
+		return nil, s, nil
+	}
+	proposer, err := computeBlobSidecarProposer(s, sidecar)
+	return proposer, nil, err
+}
+
+// OnBlobSidecar validates a sidecar received on the blob_sidecar_{subnet} topic and stores it, so that its block can be imported.
+// Only the checks against the store hold its lock, the signature and the KZG proof are verified without it.
+func (f *ForkChoiceStore) OnBlobSidecar(signedSidecar *cltypes.SignedBlobSideCar, subnet uint64) error {
+	if f.blobs == nil {
+		return fmt.Errorf("blob sidecars are not stored")
+	}
+	sidecar := signedSidecar.Message
+	if sidecar.Index >= cltypes.MaxBlobsPerBlock {
+		return fmt.Errorf("blob index %d is too big", sidecar.Index)
+	}
+	if sidecar.Index%cltypes.MaxBlobsPerBlock != subnet {
+		return fmt.Errorf("blob index %d received on wrong subnet %d", sidecar.Index, subnet)
+	}
+	proposer, s, err := f.checkBlobSidecar(sidecar)
+	if err != nil {
+		return err
+	}
+	if s != nil {
+		// the parent state is a copy, moved to the epoch of the sidecar to get its proposer
+		if err := transition.DefaultMachine.ProcessSlots(s, f.computeStartSlotAtEpoch(f.computeEpochAtSlot(sidecar.Slot))); err != nil {
+			return err
+		}
+		if proposer, err = computeBlobSidecarProposer(s, sidecar); err != nil {
+			return err
+		}
+	}
+	if proposer == nil {
+		// already stored
+		return nil
+	}
+	if proposer.index != sidecar.ProposerIndex {
+		return fmt.Errorf("blob sidecar proposer %d does not match the expected proposer %d", sidecar.ProposerIndex, proposer.index)
+	}
+	signingRoot, err := fork.ComputeSigningRoot(sidecar, proposer.domain)
+	if err != nil {
+		return err
+	}
+	valid, err := bls.Verify(signedSidecar.Signature[:], signingRoot[:], proposer.publicKey[:])
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return fmt.Errorf("invalid blob sidecar signature")
+	}
+	if err := verifyBlobKZGProof(sidecar); err != nil {
+		return err
+	}
+	return f.blobs.Put(sidecar)
+}
+
+func verifyBlobKZGProof(sidecar *cltypes.BlobSideCar) error {
+	if err := libkzg.Ctx().VerifyBlobKZGProof(gokzg4844.Blob(sidecar.Blob), gokzg4844.KZGCommitment(sidecar.KzgCommitment), gokzg4844.KZGProof(sidecar.KzgProof)); err != nil {
+		return fmt.Errorf("invalid blob kzg proof: %v", err)
+	}
+	return nil
+}
+
+// MissingBlobSidecars returns the identifiers of the sidecars which OnBlock needs before importing the block, and which
+// are not stored yet.
+func (f *ForkChoiceStore) MissingBlobSidecars(block *cltypes.SignedBeaconBlock) ([]*cltypes.BlobIdentifier, error) {
+	if f.blobs == nil || block.Version() < clparams.DenebVersion {
+		return nil, nil
+	}
+	f.mu.Lock()
+	currentSlot := f.Slot()
+	f.mu.Unlock()
+	if f.computeEpochAtSlot(block.Block.Slot)+cltypes.MinEpochsForBlobSidecarsRequests < f.computeEpochAtSlot(currentSlot) {
+		return nil, nil
+	}
+	blockRoot, err := block.Block.HashSSZ()
+	if err != nil {
+		return nil, err
+	}
+	var missing []*cltypes.BlobIdentifier
+	for index := 0; index < block.Block.Body.BlobKzgCommitments.Len(); index++ {
+		if !f.blobs.Has(blockRoot, uint64(index)) {
+			missing = append(missing, &cltypes.BlobIdentifier{BlockRoot: blockRoot, Index: uint64(index)})
+		}
+	}
+	return missing, nil
+}
+
+// AddBlobSidecars stores the sidecars of the block downloaded with it. They are not signed, so they are checked against
+// the block, whose signature OnBlock verifies, and their KZG proofs are verified.
+func (f *ForkChoiceStore) AddBlobSidecars(block *cltypes.SignedBeaconBlock, sidecars []*cltypes.BlobSideCar) error {
+	if f.blobs == nil {
+		return fmt.Errorf("blob sidecars are not stored")
+	}
+	blockRoot, err := block.Block.HashSSZ()
+	if err != nil {
+		return err
+	}
+	commitments := block.Block.Body.BlobKzgCommitments
+	for _, sidecar := range sidecars {
+		if sidecar.BlockRoot != blockRoot {
+			return fmt.Errorf("blob sidecar of block %x received for block %x", sidecar.BlockRoot, blockRoot)
+		}
+		if sidecar.Index >= uint64(commitments.Len()) {
+			return fmt.Errorf("blob index %d is too big", sidecar.Index)
+		}
+		if sidecar.Slot != block.Block.Slot || sidecar.BlockParentRoot != block.Block.ParentRoot || sidecar.ProposerIndex != block.Block.ProposerIndex {
+			return fmt.Errorf("blob sidecar %d does not match its block", sidecar.Index)
+		}
+		if sidecar.KzgCommitment != *commitments.Get(int(sidecar.Index)) {
+			return fmt.Errorf("blob sidecar %d does not match the block commitment", sidecar.Index)
+		}
+		if err := verifyBlobKZGProof(sidecar); err != nil {
+			return err
+		}
+		if err := f.blobs.Put(sidecar); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isDataAvailable checks that all the blobs committed by the block were received, for the blocks whose sidecars can still be requested.
+func (f *ForkChoiceStore) isDataAvailable(blockRoot libcommon.Hash, block *cltypes.BeaconBlock) error {
+	if f.blobs == nil || block.Version() < clparams.DenebVersion {
+		return nil
+	}
+	if f.computeEpochAtSlot(block.Slot)+cltypes.MinEpochsForBlobSidecarsRequests < f.computeEpochAtSlot(f.Slot()) {
+		return nil
+	}
+	var err error
+	block.Body.BlobKzgCommitments.Range(func(index int, commitment *cltypes.KZGCommitment, _ int) bool {
+		var sidecar *cltypes.BlobSideCar
+		sidecar, err = f.blobs.Get(blockRoot, uint64(index))
+		if errors.Is(err, fs.ErrNotExist) {
+			err = ErrDataNotAvailable
+			return false
+		}
+		if err != nil {
+			return false
+		}
+		if sidecar.KzgCommitment != *commitment {
+			err = fmt.Errorf("blob sidecar %d does not match the block commitment", index)
+			return false
+		}
+		return true
+	})
+	return err
+}
@@ -3,11 +3,15 @@ package forkchoice
 import (
 	"fmt"
 
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/log/v3"
 
+	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/freezer"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice/fork_graph"
+	"github.com/ledgerwatch/erigon/cl/phase1/light_client"
 	"github.com/ledgerwatch/erigon/cl/transition/impl/eth2/statechange"
 )
 
@@ -26,6 +30,10 @@ func (f *ForkChoiceStore) OnBlock(block *cltypes.SignedBeaconBlock, newPayload,
 	if block.Block.Slot <= finalizedSlot {
 		return nil
 	}
+	// Deneb blocks can only be imported once their blobs were received
+	if err := f.isDataAvailable(blockRoot, block.Block); err != nil {
+		return err
+	}
 
 	config := f.forkGraph.Config()
 	lastProcessedState, status, err := f.forkGraph.AddChainSegment(block, fullValidation)
@@ -64,6 +72,11 @@ func (f *ForkChoiceStore) OnBlock(block *cltypes.SignedBeaconBlock, newPayload,
 			return err
 		}
 	}
+	if f.lightClient != nil && block.Version() >= clparams.AltairVersion {
+		if err := f.addLightClientData(blockRoot, block.Block, lastProcessedState); err != nil {
+			log.Warn("could not produce light client data", "slot", block.Block.Slot, "err", err)
+		}
+	}
 	// Update checkpoints
 	f.updateCheckpoints(lastProcessedState.CurrentJustifiedCheckpoint().Copy(), lastProcessedState.FinalizedCheckpoint().Copy())
 	// First thing save previous values of the checkpoints (avoid memory copy of all states and ensure easy revert)
@@ -89,5 +102,53 @@ func (f *ForkChoiceStore) OnBlock(block *cltypes.SignedBeaconBlock, newPayload,
 	if blockEpoch < currentEpoch {
 		f.updateCheckpoints(lastProcessedState.CurrentJustifiedCheckpoint().Copy(), lastProcessedState.FinalizedCheckpoint().Copy())
 	}
+	if f.blobs != nil && block.Version() >= clparams.DenebVersion {
+		if block.Block.Body.BlobKzgCommitments.Len() > 0 {
+			if err := f.blobs.SetImported(block.Block.Slot, blockRoot); err != nil {
+				return err
+			}
+		}
+		if retention := cltypes.MinEpochsForBlobSidecarsRequests * config.SlotsPerEpoch; block.Block.Slot > retention {
+			if err := f.blobs.Prune(block.Block.Slot - retention); err != nil {
+				return err
+			}
+		}
+	}
+	f.emitBlock(blockRoot, block.Block.Slot)
+	if f.emitters != nil || f.lightClient != nil {
+		// follow the head changes caused by the block
+		headRoot, headSlot, err := f.getHead()
+		if err != nil {
+			log.Debug("could not compute head", "slot", block.Block.Slot, "err", err)
+			return nil
+		}
+		f.onHeadChange(headRoot, headSlot)
+	}
+	return nil
+}
+
+// addLightClientData gives the light client store what it needs of the post state of the block.
+func (f *ForkChoiceStore) addLightClientData(blockRoot libcommon.Hash, block *cltypes.BeaconBlock, s *state.BeaconState) error {
+	header, err := light_client.BlockToLightClientHeader(block)
+	if err != nil {
+		return err
+	}
+	data := &light_client.BlockData{
+		Header:               header,
+		CurrentSyncCommittee: s.CurrentSyncCommittee().Copy(),
+		NextSyncCommittee:    s.NextSyncCommittee().Copy(),
+		FinalizedRoot:        s.FinalizedCheckpoint().BlockRoot(),
+		SyncAggregate:        block.Body.SyncAggregate,
+	}
+	if data.CurrentSyncCommitteeBranch, err = s.CurrentSyncCommitteeBranch(); err != nil {
+		return err
+	}
+	if data.NextSyncCommitteeBranch, err = s.NextSyncCommitteeBranch(); err != nil {
+		return err
+	}
+	if data.FinalityBranch, err = s.FinalityRootBranch(); err != nil {
+		return err
+	}
+	f.lightClient.OnBlock(blockRoot, data)
 	return nil
 }
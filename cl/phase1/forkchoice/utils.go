@@ -22,6 +22,7 @@ func (f *ForkChoiceStore) updateCheckpoints(justifiedCheckpoint, finalizedCheckp
 	}
 	if finalizedCheckpoint.Epoch() > f.finalizedCheckpoint.Epoch() {
 		f.finalizedCheckpoint = finalizedCheckpoint
+		f.emitFinalizedCheckpoint(finalizedCheckpoint)
 	}
 }
 
@@ -0,0 +1,387 @@
+package light_client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"sync"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/freezer"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+const (
+	blocksCacheSize     = 256 // a few epochs, enough to reach the finalized block of a block
+	bootstrapsCacheSize = 64
+	// storedPeriods is MIN_EPOCHS_FOR_BLOCK_REQUESTS / EPOCHS_PER_SYNC_COMMITTEE_PERIOD, the periods whose best update is served.
+	storedPeriods = 129
+
+	lightClientNamespace = "light_client"
+	lightClientUpdates   = "updates" // the best update of every period, with its version as sidecar
+)
+
+// BlockData is what is kept of every imported block to produce the light client data of its children.
+type BlockData struct {
+	Header                     *cltypes.LightClientHeader
+	CurrentSyncCommittee       *solid.SyncCommittee
+	CurrentSyncCommitteeBranch [][32]byte
+	NextSyncCommittee          *solid.SyncCommittee
+	NextSyncCommitteeBranch    [][32]byte
+	// Finalized checkpoint of the post state of the block
+	FinalizedRoot  libcommon.Hash
+	FinalityBranch [][32]byte
+	// Sync aggregate of the block, signing its parent
+	SyncAggregate *cltypes.SyncAggregate
+}
+
+// Store produces the light client data from the imported blocks when they become the head, and keeps what is served
+// to light clients. The best updates are persisted in the freezer, if any.
+type Store struct {
+	beaconCfg *clparams.BeaconChainConfig
+	f         freezer.Freezer
+
+	mu               sync.RWMutex
+	blocks           *lru.Cache[libcommon.Hash, *BlockData]
+	bootstraps       *lru.Cache[libcommon.Hash, *cltypes.LightClientBootstrap]
+	bestUpdates      map[uint64]*cltypes.LightClientUpdate // by sync committee period, the recent ones of the freezer
+	finalityUpdate   *cltypes.LightClientFinalityUpdate
+	optimisticUpdate *cltypes.LightClientOptimisticUpdate
+}
+
+func NewStore(beaconCfg *clparams.BeaconChainConfig, f freezer.Freezer) (*Store, error) {
+	blocks, err := lru.New[libcommon.Hash, *BlockData](blocksCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	bootstraps, err := lru.New[libcommon.Hash, *cltypes.LightClientBootstrap](bootstrapsCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{
+		beaconCfg:   beaconCfg,
+		f:           f,
+		blocks:      blocks,
+		bootstraps:  bootstraps,
+		bestUpdates: map[uint64]*cltypes.LightClientUpdate{},
+	}, nil
+}
+
+// SyncCommitteePeriod returns the sync committee period of the slot.
+func (s *Store) SyncCommitteePeriod(slot uint64) uint64 {
+	return slot / s.beaconCfg.SlotsPerEpoch / s.beaconCfg.EpochsPerSyncCommitteePeriod
+}
+
+// OnBlock records the data of an imported block, its updates are produced once it becomes the head.
+func (s *Store) OnBlock(blockRoot libcommon.Hash, data *BlockData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blocks.Add(blockRoot, data)
+}
+
+// OnHead produces the updates signed by the sync aggregate of the new head of the canonical chain for its parent.
+func (s *Store) OnHead(headRoot libcommon.Hash) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	head, ok := s.blocks.Get(headRoot)
+	if !ok {
+		return nil
+	}
+	attested, ok := s.blocks.Get(head.Header.Beacon.ParentRoot)
+	if !ok {
+		return nil
+	}
+	if uint64(head.SyncAggregate.Sum()) < s.beaconCfg.MinSyncCommitteeParticipants {
+		return nil
+	}
+	update := s.createUpdate(head, attested)
+	period := s.SyncCommitteePeriod(attested.Header.Beacon.Slot)
+	best, err := s.bestUpdate(period)
+	if err != nil {
+		return err
+	}
+	if s.isBetterUpdate(update, best) {
+		if err := s.putBestUpdate(period, update); err != nil {
+			return err
+		}
+	}
+
+	if isFinalityUpdate(update) && s.isNewFinalityUpdate(update) {
+		s.finalityUpdate = &cltypes.LightClientFinalityUpdate{
+			AttestedHeader:  update.AttestedHeader,
+			FinalizedHeader: update.FinalizedHeader,
+			FinalityBranch:  update.FinalityBranch,
+			SyncAggregate:   update.SyncAggregate,
+			SignatureSlot:   update.SignatureSlot,
+		}
+		if finalized, ok := s.blocks.Get(attested.FinalizedRoot); ok && !s.bootstraps.Contains(attested.FinalizedRoot) {
+			s.bootstraps.Add(attested.FinalizedRoot, newBootstrap(finalized))
+		}
+	}
+	// the optimistic update follows the head, even if a reorg moved it back
+	s.optimisticUpdate = &cltypes.LightClientOptimisticUpdate{
+		AttestedHeader: update.AttestedHeader,
+		SyncAggregate:  update.SyncAggregate,
+		SignatureSlot:  update.SignatureSlot,
+	}
+	return nil
+}
+
+// bestUpdate returns the best update of the period, read from the freezer if it is not a recent one.
+func (s *Store) bestUpdate(period uint64) (*cltypes.LightClientUpdate, error) {
+	if update, ok := s.bestUpdates[period]; ok || s.f == nil {
+		return update, nil
+	}
+	r, version, err := s.f.Get(lightClientNamespace, lightClientUpdates, strconv.FormatUint(period, 10))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(version) != 1 {
+		return nil, fmt.Errorf("no version for the light client update of period %d", period)
+	}
+	update := &cltypes.LightClientUpdate{}
+	if err := utils.DecodeSSZSnappy(update, data, int(version[0])); err != nil {
+		return nil, err
+	}
+	s.bestUpdates[period] = update
+	return update, nil
+}
+
+// putBestUpdate replaces the best update of the period, the periods which are not served anymore are dropped.
+func (s *Store) putBestUpdate(period uint64, update *cltypes.LightClientUpdate) error {
+	s.bestUpdates[period] = update
+	for stored := range s.bestUpdates {
+		if stored+storedPeriods <= period {
+			delete(s.bestUpdates, stored)
+		}
+	}
+	if s.f == nil {
+		return nil
+	}
+	encoded, err := update.EncodeSSZ(nil)
+	if err != nil {
+		return err
+	}
+	if err := s.f.Put(bytes.NewReader(utils.CompressSnappy(encoded)), []byte{byte(update.Version())}, lightClientNamespace, lightClientUpdates, strconv.FormatUint(period, 10)); err != nil {
+		return err
+	}
+	if period < storedPeriods {
+		return nil
+	}
+	return s.f.Delete(lightClientNamespace, lightClientUpdates, strconv.FormatUint(period-storedPeriods, 10))
+}
+
+// createUpdate is create_light_client_update of the spec, for a block and its parent.
+func (s *Store) createUpdate(block, attested *BlockData) *cltypes.LightClientUpdate {
+	version := attested.Header.Version()
+	update := cltypes.NewLightClientUpdate(version)
+	update.AttestedHeader = attested.Header
+	// The next sync committee is only useful if the message is signed by the current sync committee
+	if s.SyncCommitteePeriod(attested.Header.Beacon.Slot) == s.SyncCommitteePeriod(block.Header.Beacon.Slot) {
+		update.NextSyncCommittee = attested.NextSyncCommittee
+		update.NextSyncCommitteeBranch = cltypes.NewBranch(attested.NextSyncCommitteeBranch)
+	}
+	// Indicate finality whenever possible
+	if attested.FinalizedRoot == (libcommon.Hash{}) {
+		update.FinalityBranch = cltypes.NewBranch(attested.FinalityBranch)
+	} else if finalized, ok := s.blocks.Get(attested.FinalizedRoot); ok {
+		update.FinalizedHeader = upgradeHeader(finalized.Header, version)
+		update.FinalityBranch = cltypes.NewBranch(attested.FinalityBranch)
+	}
+	update.SyncAggregate = block.SyncAggregate
+	update.SignatureSlot = block.Header.Beacon.Slot
+	return update
+}
+
+// BlockToLightClientHeader is block_to_light_client_header of the spec.
+func BlockToLightClientHeader(block *cltypes.BeaconBlock) (*cltypes.LightClientHeader, error) {
+	header := cltypes.NewLightClientHeader(block.Version())
+	bodyRoot, err := block.Body.HashSSZ()
+	if err != nil {
+		return nil, err
+	}
+	header.Beacon = &cltypes.BeaconBlockHeader{
+		Slot:          block.Slot,
+		ProposerIndex: block.ProposerIndex,
+		ParentRoot:    block.ParentRoot,
+		Root:          block.StateRoot,
+		BodyRoot:      bodyRoot,
+	}
+	if block.Version() < clparams.CapellaVersion {
+		return header, nil
+	}
+	if header.ExecutionPayloadHeader, err = block.Body.ExecutionPayload.PayloadHeader(); err != nil {
+		return nil, err
+	}
+	branch, err := block.Body.ExecutionPayloadMerkleProof()
+	if err != nil {
+		return nil, err
+	}
+	header.ExecutionBranch = cltypes.NewBranch(branch)
+	return header, nil
+}
+
+func newBootstrap(data *BlockData) *cltypes.LightClientBootstrap {
+	return &cltypes.LightClientBootstrap{
+		Header:                     data.Header,
+		CurrentSyncCommittee:       data.CurrentSyncCommittee,
+		CurrentSyncCommitteeBranch: cltypes.NewBranch(data.CurrentSyncCommitteeBranch),
+	}
+}
+
+// upgradeHeader converts the header of a block of a previous fork to the version of the object including it.
+func upgradeHeader(header *cltypes.LightClientHeader, version clparams.StateVersion) *cltypes.LightClientHeader {
+	if header.Version() == version {
+		return header
+	}
+	upgraded := cltypes.NewLightClientHeader(version)
+	upgraded.Beacon = header.Beacon
+	if header.Version() >= clparams.CapellaVersion {
+		upgraded.ExecutionPayloadHeader = header.ExecutionPayloadHeader.Copy()
+		if version >= clparams.DenebVersion {
+			upgraded.ExecutionPayloadHeader.Deneb()
+		}
+		upgraded.ExecutionBranch = header.ExecutionBranch
+	}
+	return upgraded
+}
+
+func isZeroBranch(branch solid.HashVectorSSZ) bool {
+	zero := true
+	branch.Range(func(_ int, node libcommon.Hash, _ int) bool {
+		zero = node == (libcommon.Hash{})
+		return zero
+	})
+	return zero
+}
+
+func isSyncCommitteeUpdate(update *cltypes.LightClientUpdate) bool {
+	return !isZeroBranch(update.NextSyncCommitteeBranch)
+}
+
+func isFinalityUpdate(update *cltypes.LightClientUpdate) bool {
+	return !isZeroBranch(update.FinalityBranch)
+}
+
+func hasSupermajority(syncAggregate *cltypes.SyncAggregate) bool {
+	return syncAggregate.Sum()*3 >= len(syncAggregate.SyncCommiteeBits)*8*2
+}
+
+// isBetterUpdate is is_better_update of the spec.
+func (s *Store) isBetterUpdate(newUpdate, oldUpdate *cltypes.LightClientUpdate) bool {
+	if oldUpdate == nil {
+		return true
+	}
+	// Compare supermajority (> 2/3) sync committee participation
+	newParticipants, oldParticipants := newUpdate.SyncAggregate.Sum(), oldUpdate.SyncAggregate.Sum()
+	newSupermajority, oldSupermajority := hasSupermajority(newUpdate.SyncAggregate), hasSupermajority(oldUpdate.SyncAggregate)
+	if newSupermajority != oldSupermajority {
+		return newSupermajority
+	}
+	if !newSupermajority && newParticipants != oldParticipants {
+		return newParticipants > oldParticipants
+	}
+	// Compare presence of relevant sync committee
+	hasRelevantSyncCommittee := func(update *cltypes.LightClientUpdate) bool {
+		return isSyncCommitteeUpdate(update) &&
+			s.SyncCommitteePeriod(update.AttestedHeader.Beacon.Slot) == s.SyncCommitteePeriod(update.SignatureSlot)
+	}
+	if newRelevant, oldRelevant := hasRelevantSyncCommittee(newUpdate), hasRelevantSyncCommittee(oldUpdate); newRelevant != oldRelevant {
+		return newRelevant
+	}
+	// Compare indication of any finality
+	newFinality, oldFinality := isFinalityUpdate(newUpdate), isFinalityUpdate(oldUpdate)
+	if newFinality != oldFinality {
+		return newFinality
+	}
+	// Compare sync committee finality
+	if newFinality {
+		hasSyncCommitteeFinality := func(update *cltypes.LightClientUpdate) bool {
+			return s.SyncCommitteePeriod(update.FinalizedHeader.Beacon.Slot) == s.SyncCommitteePeriod(update.AttestedHeader.Beacon.Slot)
+		}
+		if newSyncCommitteeFinality, oldSyncCommitteeFinality := hasSyncCommitteeFinality(newUpdate), hasSyncCommitteeFinality(oldUpdate); newSyncCommitteeFinality != oldSyncCommitteeFinality {
+			return newSyncCommitteeFinality
+		}
+	}
+	// Tiebreaker 1: Sync committee participation beyond supermajority
+	if newParticipants != oldParticipants {
+		return newParticipants > oldParticipants
+	}
+	// Tiebreaker 2: Prefer older data (fewer changes to best)
+	if newUpdate.AttestedHeader.Beacon.Slot != oldUpdate.AttestedHeader.Beacon.Slot {
+		return newUpdate.AttestedHeader.Beacon.Slot < oldUpdate.AttestedHeader.Beacon.Slot
+	}
+	return newUpdate.SignatureSlot < oldUpdate.SignatureSlot
+}
+
+// isNewFinalityUpdate tells whether the update finalizes a later header than the current finality update, or the same
+// one with a supermajority the current one does not have.
+func (s *Store) isNewFinalityUpdate(update *cltypes.LightClientUpdate) bool {
+	if s.finalityUpdate == nil {
+		return true
+	}
+	slot, currentSlot := update.FinalizedHeader.Beacon.Slot, s.finalityUpdate.FinalizedHeader.Beacon.Slot
+	return slot > currentSlot || (slot == currentSlot && hasSupermajority(update.SyncAggregate) && !hasSupermajority(s.finalityUpdate.SyncAggregate))
+}
+
+// Bootstrap returns the bootstrap of a finalized or recent block.
+func (s *Store) Bootstrap(blockRoot libcommon.Hash) (*cltypes.LightClientBootstrap, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if bootstrap, ok := s.bootstraps.Get(blockRoot); ok {
+		return bootstrap, true
+	}
+	if data, ok := s.blocks.Get(blockRoot); ok {
+		return newBootstrap(data), true
+	}
+	return nil, false
+}
+
+// Updates returns the best updates of the consecutive sync committee periods from startPeriod, stopping at the first unknown one.
+func (s *Store) Updates(startPeriod, count uint64) []*cltypes.LightClientUpdate {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var updates []*cltypes.LightClientUpdate
+	for period := startPeriod; period < startPeriod+count; period++ {
+		update, err := s.bestUpdate(period)
+		if err != nil {
+			log.Debug("could not read light client update", "period", period, "err", err)
+		}
+		if update == nil {
+			break
+		}
+		updates = append(updates, update)
+	}
+	return updates
+}
+
+// FinalityUpdate returns the latest finality update, if any.
+func (s *Store) FinalityUpdate() (*cltypes.LightClientFinalityUpdate, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.finalityUpdate, s.finalityUpdate != nil
+}
+
+// OptimisticUpdate returns the latest optimistic update, if any.
+func (s *Store) OptimisticUpdate() (*cltypes.LightClientOptimisticUpdate, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.optimisticUpdate, s.optimisticUpdate != nil
+}
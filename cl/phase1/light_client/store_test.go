@@ -0,0 +1,111 @@
+package light_client
+
+import (
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/freezer"
+)
+
+func testBranch(size int) [][32]byte {
+	branch := make([][32]byte, size)
+	for i := range branch {
+		branch[i][0] = byte(i + 1)
+	}
+	return branch
+}
+
+// testBlock returns the light client data of an altair block signed by the given number of participants.
+func testBlock(slot uint64, parentRoot, finalizedRoot libcommon.Hash, participants int) *BlockData {
+	syncAggregate := &cltypes.SyncAggregate{}
+	for i := 0; i < participants; i++ {
+		syncAggregate.SyncCommiteeBits[i/8] |= 1 << (i % 8)
+	}
+	header := cltypes.NewLightClientHeader(clparams.AltairVersion)
+	header.Beacon.Slot = slot
+	header.Beacon.ParentRoot = parentRoot
+	return &BlockData{
+		Header:                     header,
+		CurrentSyncCommittee:       &solid.SyncCommittee{},
+		CurrentSyncCommitteeBranch: testBranch(cltypes.SyncCommitteeBranchSize),
+		NextSyncCommittee:          &solid.SyncCommittee{},
+		NextSyncCommitteeBranch:    testBranch(cltypes.SyncCommitteeBranchSize),
+		FinalizedRoot:              finalizedRoot,
+		FinalityBranch:             testBranch(cltypes.FinalityBranchSize),
+		SyncAggregate:              syncAggregate,
+	}
+}
+
+// addHead imports the block and makes it the head.
+func addHead(t *testing.T, store *Store, blockRoot libcommon.Hash, data *BlockData) {
+	store.OnBlock(blockRoot, data)
+	require.NoError(t, store.OnHead(blockRoot))
+}
+
+func TestStoreOnHead(t *testing.T) {
+	cfg := &clparams.MainnetBeaconConfig
+	f := &freezer.InMemory{}
+	store, err := NewStore(cfg, f)
+	require.NoError(t, err)
+	periodSlots := cfg.SlotsPerEpoch * cfg.EpochsPerSyncCommitteePeriod
+
+	finalizedRoot, attestedRoot := libcommon.HexToHash("aa"), libcommon.HexToHash("bb")
+	addHead(t, store, finalizedRoot, testBlock(1, libcommon.Hash{}, libcommon.Hash{}, 0))
+	addHead(t, store, attestedRoot, testBlock(2, finalizedRoot, finalizedRoot, 0))
+
+	// nobody signed the parent so far
+	_, ok := store.OptimisticUpdate()
+	require.False(t, ok)
+	require.Empty(t, store.Updates(0, 1))
+
+	// blocks which do not become the head produce nothing
+	store.OnBlock(libcommon.HexToHash("cc"), testBlock(3, attestedRoot, finalizedRoot, 10))
+	_, ok = store.OptimisticUpdate()
+	require.False(t, ok)
+
+	// a weak aggregate produces all the updates
+	require.NoError(t, store.OnHead(libcommon.HexToHash("cc")))
+	optimisticUpdate, ok := store.OptimisticUpdate()
+	require.True(t, ok)
+	require.Equal(t, uint64(2), optimisticUpdate.AttestedHeader.Beacon.Slot)
+	require.Equal(t, uint64(3), optimisticUpdate.SignatureSlot)
+	finalityUpdate, ok := store.FinalityUpdate()
+	require.True(t, ok)
+	require.Equal(t, uint64(1), finalityUpdate.FinalizedHeader.Beacon.Slot)
+	updates := store.Updates(0, 2)
+	require.Len(t, updates, 1)
+	require.Equal(t, 10, updates[0].SyncAggregate.Sum())
+	bootstrap, ok := store.Bootstrap(finalizedRoot)
+	require.True(t, ok)
+	require.Equal(t, uint64(1), bootstrap.Header.Beacon.Slot)
+
+	// a supermajority of the same period is better
+	addHead(t, store, libcommon.HexToHash("dd"), testBlock(4, attestedRoot, finalizedRoot, 400))
+	require.Equal(t, 400, store.Updates(0, 1)[0].SyncAggregate.Sum())
+	// but not a smaller participation
+	addHead(t, store, libcommon.HexToHash("ee"), testBlock(5, attestedRoot, finalizedRoot, 20))
+	require.Equal(t, 400, store.Updates(0, 1)[0].SyncAggregate.Sum())
+
+	// the next period is only returned after the first one
+	nextRoot := libcommon.HexToHash("ff")
+	addHead(t, store, nextRoot, testBlock(periodSlots+1, attestedRoot, finalizedRoot, 0))
+	addHead(t, store, libcommon.HexToHash("0f"), testBlock(periodSlots+2, nextRoot, finalizedRoot, 10))
+	require.Len(t, store.Updates(0, 2), 2)
+	require.Len(t, store.Updates(1, 2), 1)
+	require.Empty(t, store.Updates(2, 1))
+	optimisticUpdate, _ = store.OptimisticUpdate()
+	require.Equal(t, periodSlots+1, optimisticUpdate.AttestedHeader.Beacon.Slot)
+
+	// the best updates are persisted
+	restarted, err := NewStore(cfg, f)
+	require.NoError(t, err)
+	updates = restarted.Updates(0, 2)
+	require.Len(t, updates, 2)
+	require.Equal(t, 400, updates[0].SyncAggregate.Sum())
+	require.Equal(t, uint64(periodSlots+2), updates[1].SignatureSlot)
+}
@@ -88,6 +88,17 @@ func (f *ForwardBeaconDownloader) RequestMore(ctx context.Context) {
 	f.highestBlockRootProcessed = highestBlockRootProcessed
 }
 
+// RequestBlobSidecars requests the blob sidecars with the given identifiers by root. It doesn't take the lock of the
+// downloader, so that the process function can call it.
+func (f *ForwardBeaconDownloader) RequestBlobSidecars(ids []*cltypes.BlobIdentifier) ([]*cltypes.BlobSideCar, error) {
+	sidecars, pid, err := f.rpc.SendBlobSidecarsByRootReq(f.ctx, ids)
+	if err != nil {
+		f.rpc.BanPeer(pid)
+		return nil, err
+	}
+	return sidecars, nil
+}
+
 // GetHighestProcessedSlot retrieve the highest processed slot we accumulated.
 func (f *ForwardBeaconDownloader) GetHighestProcessedSlot() uint64 {
 	f.mu.Lock()
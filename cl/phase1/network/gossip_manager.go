@@ -2,12 +2,16 @@ package network
 
 import (
 	"context"
+	"errors"
 	"runtime"
 
 	"github.com/VictoriaMetrics/metrics"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/freezer"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
+	"github.com/ledgerwatch/erigon/cl/phase1/pool"
+	"github.com/ledgerwatch/erigon/cmd/sentinel/sentinel/gossip"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/dbg"
@@ -24,23 +28,172 @@ import (
 type GossipManager struct {
 	ctx context.Context
 
-	recorder   freezer.Freezer
-	forkChoice *forkchoice.ForkChoiceStore
-	sentinel   sentinel.SentinelClient
+	recorder       freezer.Freezer
+	forkChoice     *forkchoice.ForkChoiceStore
+	operationsPool *pool.OperationsPool
+	sentinel       gossip.SentinelClient
 	// configs
 	beaconConfig  *clparams.BeaconChainConfig
 	genesisConfig *clparams.GenesisConfig
+	// blocks waiting for their blob sidecars, imported once the last one arrives
+	pendingBlocks map[libcommon.Hash]*cltypes.SignedBeaconBlock
 }
 
-func NewGossipReceiver(ctx context.Context, s sentinel.SentinelClient, forkChoice *forkchoice.ForkChoiceStore,
+func NewGossipReceiver(ctx context.Context, s gossip.SentinelClient, forkChoice *forkchoice.ForkChoiceStore, operationsPool *pool.OperationsPool,
 	beaconConfig *clparams.BeaconChainConfig, genesisConfig *clparams.GenesisConfig, recorder freezer.Freezer) *GossipManager {
 	return &GossipManager{
-		sentinel:      s,
-		forkChoice:    forkChoice,
-		ctx:           ctx,
-		beaconConfig:  beaconConfig,
-		genesisConfig: genesisConfig,
-		recorder:      recorder,
+		sentinel:       s,
+		forkChoice:     forkChoice,
+		operationsPool: operationsPool,
+		ctx:            ctx,
+		beaconConfig:   beaconConfig,
+		genesisConfig:  genesisConfig,
+		recorder:       recorder,
+		pendingBlocks:  map[libcommon.Hash]*cltypes.SignedBeaconBlock{},
+	}
+}
+
+// maxGossipSlotThreshold is how many slots a gossip block can be behind the current slot
+const maxGossipSlotThreshold = 4
+
+// errBlockTooOld is returned for the gossip blocks too far behind the current slot, they are ignored
+var errBlockTooOld = errors.New("block is too old")
+
+// validationError sets the validation result of the gossip messages failing with it, the messages failing with other
+// errors are ignored
+type validationError struct {
+	err    error
+	result gossip.ValidationResult
+}
+
+// reject marks the error of an invalid message, the sentinel penalizes the peer which sent it
+func reject(err error) error {
+	return validationError{err: err, result: gossip.ValidationResult_Reject}
+}
+
+// accept marks an error happening once the message was found valid, it is still forwarded
+func accept(err error) error {
+	return validationError{err: err, result: gossip.ValidationResult_Accept}
+}
+
+func (e validationError) Error() string {
+	return e.err.Error()
+}
+
+func (e validationError) Unwrap() error {
+	return e.err
+}
+
+// validationResult tells the sentinel whether to forward the message which failed with the error
+func validationResult(err error) gossip.ValidationResult {
+	if err == nil {
+		return gossip.ValidationResult_Accept
+	}
+	var validationErr validationError
+	if errors.As(err, &validationErr) {
+		return validationErr.result
+	}
+	return gossip.ValidationResult_Ignore
+}
+
+// addToPool validates the operation against the head state and adds it to the operations pool. The invalid operations
+// are rejected, the known ones ignored.
+func (g *GossipManager) addToPool(add func(s *state.BeaconState) error) error {
+	s, err := g.forkChoice.HeadState()
+	if err != nil {
+		return err
+	}
+	if err := add(s); err != nil {
+		if errors.Is(err, pool.ErrKnown) {
+			return err
+		}
+		return reject(err)
+	}
+	return nil
+}
+
+// importBlock imports the block into the fork choice and updates the head. The errors happening once the block is
+// imported are accepted.
+func (g *GossipManager) importBlock(block *cltypes.SignedBeaconBlock, l log.Ctx) error {
+	if err := g.forkChoice.OnBlock(block, true, true); err != nil {
+		l["at"] = "block process"
+		return err
+	}
+	var err error
+	block.Block.Body.Attestations.Range(func(idx int, a *solid.Attestation, total int) bool {
+		if err = g.forkChoice.OnAttestation(a, true); err != nil {
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		l["at"] = "attestation process"
+		return accept(err)
+	}
+	// Now check the head
+	headRoot, headSlot, err := g.forkChoice.GetHead()
+	if err != nil {
+		l["slot"] = block.Block.Slot
+		l["at"] = "fetch head data"
+		return accept(err)
+	}
+	// Do forkchoice if possible
+	if g.forkChoice.Engine() != nil {
+		finalizedCheckpoint := g.forkChoice.FinalizedCheckpoint()
+		// Run forkchoice
+		if err := g.forkChoice.Engine().ForkChoiceUpdate(
+			g.forkChoice.GetEth1Hash(finalizedCheckpoint.BlockRoot()),
+			g.forkChoice.GetEth1Hash(headRoot),
+		); err != nil {
+			l["at"] = "sending forkchoice"
+			return accept(err)
+		}
+	}
+	// Drop the operations which the new head includes
+	headState, err := g.forkChoice.HeadState()
+	if err != nil {
+		l["at"] = "prune operations pool"
+		return accept(err)
+	}
+	g.operationsPool.Prune(headState)
+	// Log final result
+	log.Debug("New gossip block imported",
+		"slot", block.Block.Slot,
+		"head", headSlot,
+		"headRoot", headRoot,
+	)
+	return nil
+}
+
+// addPendingBlock keeps a block whose blob sidecars did not arrive yet, the blocks too old to be gossiped are dropped
+func (g *GossipManager) addPendingBlock(block *cltypes.SignedBeaconBlock, currentSlot uint64) {
+	for root, pending := range g.pendingBlocks {
+		if pending.Block.Slot+maxGossipSlotThreshold < currentSlot {
+			delete(g.pendingBlocks, root)
+		}
+	}
+	blockRoot, err := block.Block.HashSSZ()
+	if err != nil {
+		return
+	}
+	g.pendingBlocks[blockRoot] = block
+}
+
+// retryPendingBlock imports the pending block of a received blob sidecar, it stays pending until all its sidecars arrived
+func (g *GossipManager) retryPendingBlock(blockRoot libcommon.Hash) {
+	block, ok := g.pendingBlocks[blockRoot]
+	if !ok {
+		return
+	}
+	l := log.Ctx{"slot": block.Block.Slot}
+	err := g.importBlock(block, l)
+	if errors.Is(err, forkchoice.ErrDataNotAvailable) {
+		return
+	}
+	delete(g.pendingBlocks, blockRoot)
+	if err != nil {
+		l["err"] = err
+		log.Debug("[Beacon Gossip] Could not import pending block", l)
 	}
 }
 
@@ -60,20 +213,14 @@ func (g *GossipManager) onRecv(data *sentinel.GossipData, l log.Ctx) error {
 		if err := object.DecodeSSZ(common.CopyBytes(data.Data), int(version)); err != nil {
 			g.sentinel.BanPeer(g.ctx, data.Peer)
 			l["at"] = "decoding block"
-			return err
+			return reject(err)
 		}
 		block := object.(*cltypes.SignedBeaconBlock)
 		l["slot"] = block.Block.Slot
 		currentSlotByTime := utils.GetCurrentSlot(g.genesisConfig.GenesisTime, g.beaconConfig.SecondsPerSlot)
-		maxGossipSlotThreshold := uint64(4)
 		// Skip if slot is too far behind.
 		if block.Block.Slot+maxGossipSlotThreshold < currentSlotByTime {
-			return nil
-		}
-		if block.Block.Slot == currentSlotByTime {
-			if _, err := g.sentinel.PublishGossip(g.ctx, data); err != nil {
-				log.Debug("failed publish gossip", "err", err)
-			}
+			return errBlockTooOld
 		}
 
 		count, err := g.sentinel.GetPeers(g.ctx, &sentinel.EmptyMessage{})
@@ -101,54 +248,45 @@ func (g *GossipManager) onRecv(data *sentinel.GossipData, l log.Ctx) error {
 
 		peers.Get()
 
-		if err := g.forkChoice.OnBlock(block, true, true); err != nil {
+		if err := g.importBlock(block, l); err != nil {
+			if errors.Is(err, forkchoice.ErrDataNotAvailable) {
+				g.addPendingBlock(block, currentSlotByTime)
+				return err
+			}
 			// if we are within a quarter of an epoch within chain tip we ban it
-			if currentSlotByTime < g.forkChoice.HighestSeen()+(g.beaconConfig.SlotsPerEpoch/4) {
+			var validationErr validationError
+			if !errors.As(err, &validationErr) && currentSlotByTime < g.forkChoice.HighestSeen()+(g.beaconConfig.SlotsPerEpoch/4) {
 				g.sentinel.BanPeer(g.ctx, data.Peer)
+				return reject(err)
 			}
-			l["at"] = "block process"
 			return err
 		}
-		block.Block.Body.Attestations.Range(func(idx int, a *solid.Attestation, total int) bool {
-			if err = g.forkChoice.OnAttestation(a, true); err != nil {
-				return false
-			}
-			return true
-		})
-		if err != nil {
-			l["at"] = "attestation process"
-			return err
+	case sentinel.GossipType_BlobSidecarType:
+		object = &cltypes.SignedBlobSideCar{}
+		if err := object.DecodeSSZ(common.CopyBytes(data.Data), int(version)); err != nil {
+			g.sentinel.BanPeer(g.ctx, data.Peer)
+			l["at"] = "decoding blob sidecar"
+			return reject(err)
 		}
-		// Now check the head
-		headRoot, headSlot, err := g.forkChoice.GetHead()
-		if err != nil {
-			l["slot"] = block.Block.Slot
-			l["at"] = "fetch head data"
+		sidecar := object.(*cltypes.SignedBlobSideCar)
+		l["slot"] = sidecar.Message.Slot
+		l["index"] = sidecar.Message.Index
+		if err := g.forkChoice.OnBlobSidecar(sidecar, uint64(data.GetBlobIndex())); err != nil {
+			l["at"] = "blob sidecar process"
 			return err
 		}
-		// Do forkchoice if possible
-		if g.forkChoice.Engine() != nil {
-			finalizedCheckpoint := g.forkChoice.FinalizedCheckpoint()
-			// Run forkchoice
-			if err := g.forkChoice.Engine().ForkChoiceUpdate(
-				g.forkChoice.GetEth1Hash(finalizedCheckpoint.BlockRoot()),
-				g.forkChoice.GetEth1Hash(headRoot),
-			); err != nil {
-				l["at"] = "sending forkchoice"
-				return err
-			}
-		}
-		// Log final result
-		log.Debug("New gossip block imported",
-			"slot", block.Block.Slot,
-			"head", headSlot,
-			"headRoot", headRoot,
-		)
+		g.retryPendingBlock(sidecar.Message.BlockRoot)
 	case sentinel.GossipType_VoluntaryExitGossipType:
 		object = &cltypes.SignedVoluntaryExit{}
 		if err := object.DecodeSSZ(data.Data, int(version)); err != nil {
 			g.sentinel.BanPeer(g.ctx, data.Peer)
 			l["at"] = "decode exit"
+			return reject(err)
+		}
+		if err := g.addToPool(func(s *state.BeaconState) error {
+			return g.operationsPool.AddVoluntaryExit(s, object.(*cltypes.SignedVoluntaryExit))
+		}); err != nil {
+			l["at"] = "validate exit"
 			return err
 		}
 	case sentinel.GossipType_ProposerSlashingGossipType:
@@ -156,6 +294,12 @@ func (g *GossipManager) onRecv(data *sentinel.GossipData, l log.Ctx) error {
 		if err := object.DecodeSSZ(data.Data, int(version)); err != nil {
 			l["at"] = "decode proposer slash"
 			g.sentinel.BanPeer(g.ctx, data.Peer)
+			return reject(err)
+		}
+		if err := g.addToPool(func(s *state.BeaconState) error {
+			return g.operationsPool.AddProposerSlashing(s, object.(*cltypes.ProposerSlashing))
+		}); err != nil {
+			l["at"] = "validate proposer slash"
 			return err
 		}
 	case sentinel.GossipType_AttesterSlashingGossipType:
@@ -163,17 +307,42 @@ func (g *GossipManager) onRecv(data *sentinel.GossipData, l log.Ctx) error {
 		if err := object.DecodeSSZ(data.Data, int(version)); err != nil {
 			l["at"] = "decode attester slash"
 			g.sentinel.BanPeer(g.ctx, data.Peer)
+			return reject(err)
+		}
+		if err := g.addToPool(func(s *state.BeaconState) error {
+			return g.operationsPool.AddAttesterSlashing(s, object.(*cltypes.AttesterSlashing))
+		}); err != nil {
+			l["at"] = "validate attester slash"
 			return err
 		}
 		if err := g.forkChoice.OnAttesterSlashing(object.(*cltypes.AttesterSlashing)); err != nil {
 			l["at"] = "on attester slash"
-			return err
+			return accept(err)
 		}
 	case sentinel.GossipType_AggregateAndProofGossipType:
 		object = &cltypes.SignedAggregateAndProof{}
 		if err := object.DecodeSSZ(data.Data, int(version)); err != nil {
 			l["at"] = "decoding proof"
 			g.sentinel.BanPeer(g.ctx, data.Peer)
+			return reject(err)
+		}
+		if err := g.addToPool(func(s *state.BeaconState) error {
+			return g.operationsPool.AddAggregateAndProof(s, object.(*cltypes.SignedAggregateAndProof))
+		}); err != nil {
+			l["at"] = "validate aggregate"
+			return err
+		}
+	case gossip.BlsToExecutionChangeGossipType:
+		object = &cltypes.SignedBLSToExecutionChange{}
+		if err := object.DecodeSSZ(data.Data, int(version)); err != nil {
+			l["at"] = "decode bls to execution change"
+			g.sentinel.BanPeer(g.ctx, data.Peer)
+			return reject(err)
+		}
+		if err := g.addToPool(func(s *state.BeaconState) error {
+			return g.operationsPool.AddBLSToExecutionChange(s, object.(*cltypes.SignedBLSToExecutionChange))
+		}); err != nil {
+			l["at"] = "validate bls to execution change"
 			return err
 		}
 	}
@@ -196,10 +365,18 @@ func (g *GossipManager) Start() {
 		for k := range l {
 			delete(l, k)
 		}
+		hash := gossip.Hash(data.Data)
 		err = g.onRecv(data, l)
-		if err != nil {
+		if err != nil && !errors.Is(err, pool.ErrKnown) {
 			l["err"] = err
 			log.Debug("[Beacon Gossip] Recoverable Error", l)
 		}
+		if !gossip.IsValidated(data.Type) {
+			continue
+		}
+		// the sentinel forwards the operation to its peers only once it is accepted
+		if _, err := g.sentinel.SetValidationResult(g.ctx, &gossip.GossipValidation{Hash: hash, Result: validationResult(err)}); err != nil {
+			log.Debug("[Beacon Gossip] failed to set the validation result", "err", err)
+		}
 	}
 }
@@ -0,0 +1,260 @@
+// Package pool keeps the operations received from gossip and from the Beacon API until a block includes them.
+package pool
+
+import (
+	"errors"
+	"math/bits"
+	"sort"
+	"sync"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+)
+
+// ErrKnown is returned when the pool already has the operation, or a better one
+var ErrKnown = errors.New("operation is already known")
+
+// OperationsPool keeps the valid voluntary exits, slashings, BLS to execution changes and aggregates, one per
+// validator or attestation data. They are validated against the head state when they are added, and pruned once the
+// head state includes them or they cannot be included anymore.
+type OperationsPool struct {
+	mu                    sync.Mutex
+	voluntaryExits        map[uint64]*cltypes.SignedVoluntaryExit        // by validator index
+	proposerSlashings     map[uint64]*cltypes.ProposerSlashing           // by proposer index
+	attesterSlashings     map[libcommon.Hash]*cltypes.AttesterSlashing   // by root
+	blsToExecutionChanges map[uint64]*cltypes.SignedBLSToExecutionChange // by validator index
+	aggregates            map[libcommon.Hash]*solid.Attestation          // the one with the most attesters, by data root
+}
+
+func NewOperationsPool() *OperationsPool {
+	return &OperationsPool{
+		voluntaryExits:        map[uint64]*cltypes.SignedVoluntaryExit{},
+		proposerSlashings:     map[uint64]*cltypes.ProposerSlashing{},
+		attesterSlashings:     map[libcommon.Hash]*cltypes.AttesterSlashing{},
+		blsToExecutionChanges: map[uint64]*cltypes.SignedBLSToExecutionChange{},
+		aggregates:            map[libcommon.Hash]*solid.Attestation{},
+	}
+}
+
+// AddVoluntaryExit adds the exit if it is valid on top of the state
+func (p *OperationsPool) AddVoluntaryExit(s *state.BeaconState, exit *cltypes.SignedVoluntaryExit) error {
+	p.mu.Lock()
+	_, known := p.voluntaryExits[exit.VolunaryExit.ValidatorIndex]
+	p.mu.Unlock()
+	if known {
+		return ErrKnown
+	}
+	if err := ValidateVoluntaryExit(s, exit); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.voluntaryExits[exit.VolunaryExit.ValidatorIndex] = exit
+	return nil
+}
+
+// AddProposerSlashing adds the slashing if it is valid on top of the state
+func (p *OperationsPool) AddProposerSlashing(s *state.BeaconState, slashing *cltypes.ProposerSlashing) error {
+	index := slashing.Header1.Header.ProposerIndex
+	p.mu.Lock()
+	_, known := p.proposerSlashings[index]
+	p.mu.Unlock()
+	if known {
+		return ErrKnown
+	}
+	if err := ValidateProposerSlashing(s, slashing); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.proposerSlashings[index] = slashing
+	return nil
+}
+
+// AddAttesterSlashing adds the slashing if it is valid on top of the state and slashes a validator which the other
+// slashings of the pool do not slash
+func (p *OperationsPool) AddAttesterSlashing(s *state.BeaconState, slashing *cltypes.AttesterSlashing) error {
+	root, err := slashing.HashSSZ()
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	_, known := p.attesterSlashings[root]
+	p.mu.Unlock()
+	if known {
+		return ErrKnown
+	}
+	if err := ValidateAttesterSlashing(s, slashing); err != nil {
+		return err
+	}
+	slashable, err := slashableIndicies(s, slashing)
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	covered := map[uint64]struct{}{}
+	for _, other := range p.attesterSlashings {
+		for _, index := range solid.IntersectionOfSortedSets(other.Attestation_1.AttestingIndices, other.Attestation_2.AttestingIndices) {
+			covered[index] = struct{}{}
+		}
+	}
+	for _, index := range slashable {
+		if _, ok := covered[index]; !ok {
+			p.attesterSlashings[root] = slashing
+			return nil
+		}
+	}
+	return ErrKnown
+}
+
+// AddBLSToExecutionChange adds the change if it is valid on top of the state
+func (p *OperationsPool) AddBLSToExecutionChange(s *state.BeaconState, change *cltypes.SignedBLSToExecutionChange) error {
+	p.mu.Lock()
+	_, known := p.blsToExecutionChanges[change.Message.ValidatorIndex]
+	p.mu.Unlock()
+	if known {
+		return ErrKnown
+	}
+	if err := ValidateBLSToExecutionChange(s, change); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.blsToExecutionChanges[change.Message.ValidatorIndex] = change
+	return nil
+}
+
+func countBits(aggregationBits []byte) (count int) {
+	for _, b := range aggregationBits {
+		count += bits.OnesCount8(b)
+	}
+	return count - 1 // the length bit of the bitlist
+}
+
+// AddAggregateAndProof adds the aggregate if it is valid on top of the state and has more attesters than the
+// aggregate of the pool with the same data
+func (p *OperationsPool) AddAggregateAndProof(s *state.BeaconState, signedAggregate *cltypes.SignedAggregateAndProof) error {
+	aggregate := signedAggregate.Message.Aggregate
+	dataRoot, err := aggregate.AttestantionData().HashSSZ()
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	best, known := p.aggregates[dataRoot]
+	p.mu.Unlock()
+	if known && countBits(best.AggregationBits()) >= countBits(aggregate.AggregationBits()) {
+		return ErrKnown
+	}
+	if _, err := ValidateAggregateAndProof(s, signedAggregate); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if best, known = p.aggregates[dataRoot]; known && countBits(best.AggregationBits()) >= countBits(aggregate.AggregationBits()) {
+		return ErrKnown
+	}
+	p.aggregates[dataRoot] = aggregate
+	return nil
+}
+
+// Prune removes the operations which the state includes, or which cannot be included on top of it anymore
+func (p *OperationsPool) Prune(s *state.BeaconState) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	beaconConfig := s.BeaconConfig()
+	currentEpoch := state.Epoch(s.BeaconState)
+	for index := range p.voluntaryExits {
+		if v, err := s.ValidatorForValidatorIndex(int(index)); err != nil || v.ExitEpoch() != beaconConfig.FarFutureEpoch {
+			delete(p.voluntaryExits, index)
+		}
+	}
+	for index := range p.proposerSlashings {
+		if v, err := s.ValidatorForValidatorIndex(int(index)); err != nil || !v.IsSlashable(currentEpoch) {
+			delete(p.proposerSlashings, index)
+		}
+	}
+	for root, slashing := range p.attesterSlashings {
+		if slashable, err := slashableIndicies(s, slashing); err != nil || len(slashable) == 0 {
+			delete(p.attesterSlashings, root)
+		}
+	}
+	for index := range p.blsToExecutionChanges {
+		if v, err := s.ValidatorForValidatorIndex(int(index)); err != nil || v.WithdrawalCredentials()[0] != beaconConfig.BLSWithdrawalPrefixByte {
+			delete(p.blsToExecutionChanges, index)
+		}
+	}
+	for root, aggregate := range p.aggregates {
+		if aggregate.AttestantionData().Slot()+beaconConfig.SlotsPerEpoch < s.Slot() {
+			delete(p.aggregates, root)
+		}
+	}
+}
+
+func sortedValues[K comparable, V any](m map[K]V, less func(a, b V) bool) []V {
+	values := make([]V, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	sort.Slice(values, func(i, j int) bool { return less(values[i], values[j]) })
+	return values
+}
+
+// VoluntaryExits returns the exits of the pool, by validator index
+func (p *OperationsPool) VoluntaryExits() []*cltypes.SignedVoluntaryExit {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return sortedValues(p.voluntaryExits, func(a, b *cltypes.SignedVoluntaryExit) bool {
+		return a.VolunaryExit.ValidatorIndex < b.VolunaryExit.ValidatorIndex
+	})
+}
+
+// ProposerSlashings returns the proposer slashings of the pool, by proposer index
+func (p *OperationsPool) ProposerSlashings() []*cltypes.ProposerSlashing {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return sortedValues(p.proposerSlashings, func(a, b *cltypes.ProposerSlashing) bool {
+		return a.Header1.Header.ProposerIndex < b.Header1.Header.ProposerIndex
+	})
+}
+
+// AttesterSlashings returns the attester slashings of the pool, by slot of their first attestation
+func (p *OperationsPool) AttesterSlashings() []*cltypes.AttesterSlashing {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return sortedValues(p.attesterSlashings, func(a, b *cltypes.AttesterSlashing) bool {
+		return a.Attestation_1.Data.Slot() < b.Attestation_1.Data.Slot()
+	})
+}
+
+// BLSToExecutionChanges returns the BLS to execution changes of the pool, by validator index
+func (p *OperationsPool) BLSToExecutionChanges() []*cltypes.SignedBLSToExecutionChange {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return sortedValues(p.blsToExecutionChanges, func(a, b *cltypes.SignedBLSToExecutionChange) bool {
+		return a.Message.ValidatorIndex < b.Message.ValidatorIndex
+	})
+}
+
+// Aggregates returns the best aggregate of every attestation data of the pool, by slot and committee index
+func (p *OperationsPool) Aggregates() []*solid.Attestation {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return sortedValues(p.aggregates, func(a, b *solid.Attestation) bool {
+		if a.AttestantionData().Slot() != b.AttestantionData().Slot() {
+			return a.AttestantionData().Slot() < b.AttestantionData().Slot()
+		}
+		return a.AttestantionData().ValidatorIndex() < b.AttestantionData().ValidatorIndex()
+	})
+}
+
+// Aggregate returns the best aggregate of the attestation data with the given root
+func (p *OperationsPool) Aggregate(dataRoot libcommon.Hash) (*solid.Attestation, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	aggregate, ok := p.aggregates[dataRoot]
+	return aggregate, ok
+}
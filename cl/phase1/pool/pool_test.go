@@ -0,0 +1,107 @@
+package pool
+
+import (
+	"os"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+	blst "github.com/supranational/blst/bindings/go"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/fork"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+const forkchoiceTestData = "../forkchoice/test_data/"
+
+func TestOperationsPool(t *testing.T) {
+	anchorStateEncoded, err := os.ReadFile(forkchoiceTestData + "anchor_state.ssz_snappy")
+	require.NoError(t, err)
+	attestationEncoded, err := os.ReadFile(forkchoiceTestData + "attestation_0xfb924d35b2888d9cd70e6879c1609e6cad7ea3b028a501967747d96e49068cb6.ssz_snappy")
+	require.NoError(t, err)
+	s := state.New(&clparams.MainnetBeaconConfig)
+	require.NoError(t, utils.DecodeSSZSnappy(s, anchorStateEncoded, int(clparams.AltairVersion)))
+	attestation := &solid.Attestation{}
+	require.NoError(t, utils.DecodeSSZSnappy(attestation, attestationEncoded, int(clparams.AltairVersion)))
+	p := NewOperationsPool()
+
+	// The invalid operations are rejected
+	require.Error(t, p.AddVoluntaryExit(s, &cltypes.SignedVoluntaryExit{VolunaryExit: &cltypes.VoluntaryExit{Epoch: 10, ValidatorIndex: 1}}))
+	header := &cltypes.BeaconBlockHeader{Slot: 1, ProposerIndex: 1}
+	require.Error(t, p.AddProposerSlashing(s, &cltypes.ProposerSlashing{
+		Header1: &cltypes.SignedBeaconBlockHeader{Header: header},
+		Header2: &cltypes.SignedBeaconBlockHeader{Header: header},
+	}))
+	require.Error(t, p.AddBLSToExecutionChange(s, &cltypes.SignedBLSToExecutionChange{Message: &cltypes.BLSToExecutionChange{ValidatorIndex: 1}}))
+	require.Error(t, p.AddAggregateAndProof(s, &cltypes.SignedAggregateAndProof{Message: &cltypes.AggregateAndProof{Aggregate: attestation}}))
+	require.Empty(t, p.VoluntaryExits())
+	require.Empty(t, p.ProposerSlashings())
+	require.Empty(t, p.BLSToExecutionChanges())
+	require.Empty(t, p.Aggregates())
+
+	// The known ones are not validated again
+	p.voluntaryExits[1] = &cltypes.SignedVoluntaryExit{VolunaryExit: &cltypes.VoluntaryExit{ValidatorIndex: 1}}
+	require.ErrorIs(t, p.AddVoluntaryExit(s, &cltypes.SignedVoluntaryExit{VolunaryExit: &cltypes.VoluntaryExit{ValidatorIndex: 1}}), ErrKnown)
+	dataRoot, err := attestation.AttestantionData().HashSSZ()
+	require.NoError(t, err)
+	p.aggregates[dataRoot] = attestation
+	require.ErrorIs(t, p.AddAggregateAndProof(s, &cltypes.SignedAggregateAndProof{Message: &cltypes.AggregateAndProof{Aggregate: attestation}}), ErrKnown)
+	aggregate, ok := p.Aggregate(dataRoot)
+	require.True(t, ok)
+	require.Equal(t, attestation, aggregate)
+	_, ok = p.Aggregate(libcommon.Hash{})
+	require.False(t, ok)
+
+	// The operations stay until they cannot be included anymore
+	p.Prune(s)
+	require.Len(t, p.VoluntaryExits(), 1)
+	require.Len(t, p.Aggregates(), 1)
+	s.SetSlot(attestation.AttestantionData().Slot() + s.BeaconConfig().SlotsPerEpoch + 1)
+	s.SetExitEpochForValidatorAtIndex(1, 5)
+	p.Prune(s)
+	require.Empty(t, p.VoluntaryExits())
+	require.Empty(t, p.Aggregates())
+}
+
+func TestOperationsPoolBLSToExecutionChange(t *testing.T) {
+	anchorStateEncoded, err := os.ReadFile(forkchoiceTestData + "anchor_state.ssz_snappy")
+	require.NoError(t, err)
+	s := state.New(&clparams.MainnetBeaconConfig)
+	require.NoError(t, utils.DecodeSSZSnappy(s, anchorStateEncoded, int(clparams.AltairVersion)))
+	beaconConfig := s.BeaconConfig()
+	p := NewOperationsPool()
+
+	// The validator withdraws to the BLS key which signs the change
+	sk := blst.KeyGen(make([]byte, 32))
+	change := &cltypes.SignedBLSToExecutionChange{
+		Message: &cltypes.BLSToExecutionChange{ValidatorIndex: 1, To: libcommon.HexToAddress("0x1234")},
+	}
+	copy(change.Message.From[:], new(blst.P1Affine).From(sk).Compress())
+	credentials := libcommon.Hash(utils.Keccak256(change.Message.From[:]))
+	credentials[0] = beaconConfig.BLSWithdrawalPrefixByte
+	s.SetWithdrawalCredentialForValidatorAtIndex(1, credentials)
+	domain, err := fork.ComputeDomain(beaconConfig.DomainBLSToExecutionChange[:], utils.Uint32ToBytes4(beaconConfig.GenesisForkVersion), s.GenesisValidatorsRoot())
+	require.NoError(t, err)
+	signingRoot, err := fork.ComputeSigningRoot(change.Message, domain)
+	require.NoError(t, err)
+	copy(change.Signature[:], new(blst.P2Affine).Sign(sk, signingRoot[:], []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")).Compress())
+
+	// A change signed for another validator is rejected, the correctly signed one is added once
+	other := &cltypes.SignedBLSToExecutionChange{Message: &cltypes.BLSToExecutionChange{ValidatorIndex: 2, From: change.Message.From, To: change.Message.To}, Signature: change.Signature}
+	require.Error(t, p.AddBLSToExecutionChange(s, other))
+	require.NoError(t, p.AddBLSToExecutionChange(s, change))
+	require.ErrorIs(t, p.AddBLSToExecutionChange(s, change), ErrKnown)
+	require.Equal(t, []*cltypes.SignedBLSToExecutionChange{change}, p.BLSToExecutionChanges())
+
+	// It stays until the validator withdraws to an execution address
+	p.Prune(s)
+	require.Len(t, p.BLSToExecutionChanges(), 1)
+	credentials[0] = beaconConfig.ETH1AddressWithdrawalPrefixByte
+	s.SetWithdrawalCredentialForValidatorAtIndex(1, credentials)
+	p.Prune(s)
+	require.Empty(t, p.BLSToExecutionChanges())
+}
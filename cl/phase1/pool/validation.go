@@ -0,0 +1,247 @@
+package pool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/Giulio2002/bls"
+
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/fork"
+	"github.com/ledgerwatch/erigon/cl/merkle_tree"
+	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
+	"github.com/ledgerwatch/erigon/cl/utils"
+)
+
+// The validations below are the ones of the block processing, without the state changes, and of the gossip topics.
+
+func verifySignature(signature [96]byte, obj interface{ HashSSZ() ([32]byte, error) }, domain []byte, pk []byte) error {
+	signingRoot, err := fork.ComputeSigningRoot(obj, domain)
+	if err != nil {
+		return err
+	}
+	valid, err := bls.Verify(signature[:], signingRoot[:], pk)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+// ValidateVoluntaryExit checks that the exit can be included in a block on top of the state
+func ValidateVoluntaryExit(s *state.BeaconState, signedExit *cltypes.SignedVoluntaryExit) error {
+	exit := signedExit.VolunaryExit
+	currentEpoch := state.Epoch(s.BeaconState)
+	validator, err := s.ValidatorForValidatorIndex(int(exit.ValidatorIndex))
+	if err != nil {
+		return err
+	}
+	if !validator.Active(currentEpoch) {
+		return errors.New("validator is not active")
+	}
+	if validator.ExitEpoch() != s.BeaconConfig().FarFutureEpoch {
+		return errors.New("validator is already exiting")
+	}
+	if currentEpoch < exit.Epoch {
+		return errors.New("exit epoch is in the future")
+	}
+	if currentEpoch < validator.ActivationEpoch()+s.BeaconConfig().ShardCommitteePeriod {
+		return errors.New("validator has not been active long enough")
+	}
+	domain, err := s.GetDomain(s.BeaconConfig().DomainVoluntaryExit, exit.Epoch)
+	if err != nil {
+		return err
+	}
+	pk := validator.PublicKey()
+	return verifySignature(signedExit.Signature, exit, domain, pk[:])
+}
+
+// ValidateProposerSlashing checks that the slashing can be included in a block on top of the state
+func ValidateProposerSlashing(s *state.BeaconState, slashing *cltypes.ProposerSlashing) error {
+	h1, h2 := slashing.Header1.Header, slashing.Header2.Header
+	if h1.Slot != h2.Slot {
+		return fmt.Errorf("non-matching slots: %d != %d", h1.Slot, h2.Slot)
+	}
+	if h1.ProposerIndex != h2.ProposerIndex {
+		return fmt.Errorf("non-matching proposer indices: %d != %d", h1.ProposerIndex, h2.ProposerIndex)
+	}
+	h1Root, err := h1.HashSSZ()
+	if err != nil {
+		return err
+	}
+	h2Root, err := h2.HashSSZ()
+	if err != nil {
+		return err
+	}
+	if h1Root == h2Root {
+		return errors.New("headers are the same")
+	}
+	proposer, err := s.ValidatorForValidatorIndex(int(h1.ProposerIndex))
+	if err != nil {
+		return err
+	}
+	if !proposer.IsSlashable(state.Epoch(s.BeaconState)) {
+		return errors.New("proposer is not slashable")
+	}
+	pk := proposer.PublicKey()
+	for _, signedHeader := range []*cltypes.SignedBeaconBlockHeader{slashing.Header1, slashing.Header2} {
+		domain, err := s.GetDomain(s.BeaconConfig().DomainBeaconProposer, state.GetEpochAtSlot(s.BeaconConfig(), signedHeader.Header.Slot))
+		if err != nil {
+			return err
+		}
+		if err := verifySignature(signedHeader.Signature, signedHeader.Header, domain, pk[:]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// slashableIndicies returns the validators which the attester slashing would slash on top of the state
+func slashableIndicies(s *state.BeaconState, slashing *cltypes.AttesterSlashing) ([]uint64, error) {
+	currentEpoch := state.Epoch(s.BeaconState)
+	var slashable []uint64
+	for _, index := range solid.IntersectionOfSortedSets(slashing.Attestation_1.AttestingIndices, slashing.Attestation_2.AttestingIndices) {
+		validator, err := s.ValidatorForValidatorIndex(int(index))
+		if err != nil {
+			return nil, err
+		}
+		if validator.IsSlashable(currentEpoch) {
+			slashable = append(slashable, index)
+		}
+	}
+	return slashable, nil
+}
+
+// ValidateAttesterSlashing checks that the slashing can be included in a block on top of the state
+func ValidateAttesterSlashing(s *state.BeaconState, slashing *cltypes.AttesterSlashing) error {
+	if !cltypes.IsSlashableAttestationData(slashing.Attestation_1.Data, slashing.Attestation_2.Data) {
+		return errors.New("attestation data is not slashable")
+	}
+	for i, att := range []*cltypes.IndexedAttestation{slashing.Attestation_1, slashing.Attestation_2} {
+		valid, err := state.IsValidIndexedAttestation(s.BeaconState, att)
+		if err != nil {
+			return fmt.Errorf("attestation %d: %w", i+1, err)
+		}
+		if !valid {
+			return fmt.Errorf("invalid indexed attestation %d", i+1)
+		}
+	}
+	slashable, err := slashableIndicies(s, slashing)
+	if err != nil {
+		return err
+	}
+	if len(slashable) == 0 {
+		return errors.New("no validator is slashable")
+	}
+	return nil
+}
+
+// ValidateBLSToExecutionChange checks that the change can be included in a block on top of the state
+func ValidateBLSToExecutionChange(s *state.BeaconState, signedChange *cltypes.SignedBLSToExecutionChange) error {
+	change := signedChange.Message
+	beaconConfig := s.BeaconConfig()
+	validator, err := s.ValidatorForValidatorIndex(int(change.ValidatorIndex))
+	if err != nil {
+		return err
+	}
+	wc := validator.WithdrawalCredentials()
+	if wc[0] != beaconConfig.BLSWithdrawalPrefixByte {
+		return errors.New("withdrawal credentials are not BLS ones")
+	}
+	hashedFrom := utils.Keccak256(change.From[:])
+	if !bytes.Equal(hashedFrom[1:], wc[1:]) {
+		return errors.New("public key does not match the withdrawal credentials")
+	}
+	// the domain of the genesis fork, so that the changes signed before Capella stay valid
+	domain, err := fork.ComputeDomain(beaconConfig.DomainBLSToExecutionChange[:], utils.Uint32ToBytes4(beaconConfig.GenesisForkVersion), s.GenesisValidatorsRoot())
+	if err != nil {
+		return err
+	}
+	return verifySignature(signedChange.Signature, change, domain, change.From[:])
+}
+
+// isAggregator tells whether the selection proof selects its signer as an aggregator of the committee
+func isAggregator(s *state.BeaconState, committeeLength int, selectionProof [96]byte) bool {
+	modulo := utils.Max64(1, uint64(committeeLength)/s.BeaconConfig().TargetAggregatorsPerCommittee)
+	hash := utils.Keccak256(selectionProof[:])
+	return binary.LittleEndian.Uint64(hash[:8])%modulo == 0
+}
+
+// ValidateAggregateAndProof checks the aggregate received from the aggregation gossip topic against the state, and
+// returns the root of its attestation data
+func ValidateAggregateAndProof(s *state.BeaconState, signedAggregate *cltypes.SignedAggregateAndProof) ([32]byte, error) {
+	aggregateAndProof := signedAggregate.Message
+	aggregate := aggregateAndProof.Aggregate
+	data := aggregate.AttestantionData()
+	beaconConfig := s.BeaconConfig()
+
+	currentEpoch := state.Epoch(s.BeaconState)
+	targetEpoch := data.Target().Epoch()
+	if targetEpoch != currentEpoch && targetEpoch != state.PreviousEpoch(s.BeaconState) {
+		return [32]byte{}, fmt.Errorf("target epoch %d is neither the current nor the previous epoch", targetEpoch)
+	}
+	if targetEpoch != state.GetEpochAtSlot(beaconConfig, data.Slot()) {
+		return [32]byte{}, errors.New("target epoch does not match the slot")
+	}
+	committee, err := s.GetBeaconCommitee(data.Slot(), data.ValidatorIndex())
+	if err != nil {
+		return [32]byte{}, err
+	}
+	attesting, err := s.GetAttestingIndicies(data, aggregate.AggregationBits(), true)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if len(attesting) == 0 {
+		return [32]byte{}, errors.New("aggregate has no attester")
+	}
+	inCommittee := false
+	for _, index := range committee {
+		if index == aggregateAndProof.AggregatorIndex {
+			inCommittee = true
+			break
+		}
+	}
+	if !inCommittee {
+		return [32]byte{}, errors.New("aggregator is not in the committee")
+	}
+	if !isAggregator(s, len(committee), aggregateAndProof.SelectionProof) {
+		return [32]byte{}, errors.New("selection proof does not select an aggregator")
+	}
+	aggregator, err := s.ValidatorForValidatorIndex(int(aggregateAndProof.AggregatorIndex))
+	if err != nil {
+		return [32]byte{}, err
+	}
+	pk := aggregator.PublicKey()
+	slotEpoch := state.GetEpochAtSlot(beaconConfig, data.Slot())
+	domain, err := s.GetDomain(beaconConfig.DomainSelectionProof, slotEpoch)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	selectionRoot := utils.Keccak256(merkle_tree.Uint64Root(data.Slot()).Bytes(), domain)
+	valid, err := bls.Verify(aggregateAndProof.SelectionProof[:], selectionRoot[:], pk[:])
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if !valid {
+		return [32]byte{}, errors.New("invalid selection proof")
+	}
+	if domain, err = s.GetDomain(beaconConfig.DomainAggregateAndProof, slotEpoch); err != nil {
+		return [32]byte{}, err
+	}
+	if err := verifySignature(signedAggregate.Signature, aggregateAndProof, domain, pk[:]); err != nil {
+		return [32]byte{}, fmt.Errorf("aggregator: %w", err)
+	}
+	valid, err = state.IsValidIndexedAttestation(s.BeaconState, state.GetIndexedAttestation(aggregate, attesting))
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if !valid {
+		return [32]byte{}, errors.New("invalid aggregate signature")
+	}
+	return data.HashSSZ()
+}
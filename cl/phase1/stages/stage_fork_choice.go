@@ -2,6 +2,7 @@ package stages
 
 import (
 	"context"
+	"errors"
 	"runtime"
 	"time"
 
@@ -100,10 +101,51 @@ func SpawnStageForkChoice(cfg StageForkChoiceCfg, s *stagedsync.StageState, tx k
 	return nil
 }
 
+// downloadBlobSidecars requests the blob sidecars which the blocks need to be imported and stores them. The blocks
+// whose sidecars are still missing fail to be imported with forkchoice.ErrDataNotAvailable.
+func downloadBlobSidecars(cfg StageForkChoiceCfg, blocks []*cltypes.SignedBeaconBlock) {
+	var ids []*cltypes.BlobIdentifier
+	blocksByRoot := map[libcommon.Hash]*cltypes.SignedBeaconBlock{}
+	for _, block := range blocks {
+		missing, err := cfg.forkChoice.MissingBlobSidecars(block)
+		if err != nil {
+			log.Debug("Could not check the blob sidecars of block", "slot", block.Block.Slot, "err", err)
+			continue
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		blocksByRoot[missing[0].BlockRoot] = block
+		ids = append(ids, missing...)
+	}
+	if len(ids) == 0 {
+		return
+	}
+	sidecars, err := cfg.downloader.RequestBlobSidecars(ids)
+	if err != nil {
+		log.Debug("Could not download blob sidecars", "err", err)
+		return
+	}
+	sidecarsByRoot := map[libcommon.Hash][]*cltypes.BlobSideCar{}
+	for _, sidecar := range sidecars {
+		sidecarsByRoot[sidecar.BlockRoot] = append(sidecarsByRoot[sidecar.BlockRoot], sidecar)
+	}
+	for root, blockSidecars := range sidecarsByRoot {
+		block, ok := blocksByRoot[root]
+		if !ok {
+			continue
+		}
+		if err := cfg.forkChoice.AddBlobSidecars(block, blockSidecars); err != nil {
+			log.Debug("Invalid downloaded blob sidecars", "slot", block.Block.Slot, "err", err)
+		}
+	}
+}
+
 func startDownloadService(s *stagedsync.StageState, cfg StageForkChoiceCfg) {
 	cfg.downloader.SetHighestProcessedRoot(libcommon.Hash{})
 	cfg.downloader.SetHighestProcessedSlot(cfg.state.Slot())
 	cfg.downloader.SetProcessFunction(func(highestSlotProcessed uint64, _ libcommon.Hash, newBlocks []*cltypes.SignedBeaconBlock) (uint64, libcommon.Hash, error) {
+		downloadBlobSidecars(cfg, newBlocks)
 		for _, block := range newBlocks {
 			if err := freezer.PutObjectSSZIntoFreezer("signedBeaconBlock", "caplin_core", block.Block.Slot, block, cfg.caplinFreezer); err != nil {
 				return highestSlotProcessed, libcommon.Hash{}, err
@@ -112,6 +154,11 @@ func startDownloadService(s *stagedsync.StageState, cfg StageForkChoiceCfg) {
 			sendForckchoice :=
 				utils.GetCurrentSlot(cfg.genesisCfg.GenesisTime, cfg.beaconCfg.SecondsPerSlot) == block.Block.Slot
 			if err := cfg.forkChoice.OnBlock(block, false, true); err != nil {
+				if errors.Is(err, forkchoice.ErrDataNotAvailable) {
+					// not the fault of the peer, the block is downloaded again with its sidecars
+					log.Debug("Missing blob sidecars of downloaded block", "slot", block.Block.Slot)
+					return highestSlotProcessed, libcommon.Hash{}, nil
+				}
 				log.Warn("Could not download block", "reason", err, "slot", block.Block.Slot)
 				return highestSlotProcessed, libcommon.Hash{}, err
 			}
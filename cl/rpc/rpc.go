@@ -54,21 +54,53 @@ func NewBeaconRpcP2P(ctx context.Context, sentinel sentinel.SentinelClient, beac
 func (b *BeaconRpcP2P) sendBlocksRequest(ctx context.Context, topic string, reqData []byte, count uint64) ([]*cltypes.SignedBeaconBlock, string, error) {
 	// Prepare output slice.
 	responsePacket := []*cltypes.SignedBeaconBlock{}
+	pid, err := b.sendRequest(ctx, topic, reqData, count, count, func(raw []byte, version clparams.StateVersion) error {
+		responseChunk := &cltypes.SignedBeaconBlock{}
+		if err := responseChunk.DecodeSSZ(raw, int(version)); err != nil {
+			return err
+		}
+		responsePacket = append(responsePacket, responseChunk)
+		return nil
+	})
+	if err != nil {
+		return nil, pid, err
+	}
+	return responsePacket, pid, nil
+}
+
+func (b *BeaconRpcP2P) sendBlobSidecarsRequest(ctx context.Context, topic string, reqData []byte, blocks, count uint64) ([]*cltypes.BlobSideCar, string, error) {
+	responsePacket := []*cltypes.BlobSideCar{}
+	pid, err := b.sendRequest(ctx, topic, reqData, blocks, count, func(raw []byte, version clparams.StateVersion) error {
+		responseChunk := &cltypes.BlobSideCar{}
+		if err := responseChunk.DecodeSSZ(raw, int(version)); err != nil {
+			return err
+		}
+		responsePacket = append(responsePacket, responseChunk)
+		return nil
+	})
+	if err != nil {
+		return nil, pid, err
+	}
+	return responsePacket, pid, nil
+}
 
-	ctx, cn := context.WithTimeout(ctx, time.Second*time.Duration(5+10*count))
+// sendRequest sends the request to a peer and decodes at most count response chunks with decodeChunk, the timeout
+// grows with the number of blocks requested.
+func (b *BeaconRpcP2P) sendRequest(ctx context.Context, topic string, reqData []byte, blocks, count uint64, decodeChunk func(raw []byte, version clparams.StateVersion) error) (string, error) {
+	ctx, cn := context.WithTimeout(ctx, time.Second*time.Duration(5+10*blocks))
 	defer cn()
 	message, err := b.sentinel.SendRequest(ctx, &sentinel.RequestData{
 		Data:  reqData,
 		Topic: topic,
 	})
 	if err != nil {
-		return nil, "", err
+		return "", err
 	}
 	if message.Error {
 		rd := snappy.NewReader(bytes.NewBuffer(message.Data))
 		errBytes, _ := io.ReadAll(rd)
 		log.Debug("received range req error", "err", string(errBytes))
-		return nil, message.Peer.Pid, nil
+		return message.Peer.Pid, nil
 	}
 
 	r := bytes.NewReader(message.Data)
@@ -78,17 +110,17 @@ func (b *BeaconRpcP2P) sendBlocksRequest(ctx context.Context, topic string, reqD
 			if err == io.EOF {
 				break
 			}
-			return nil, message.Peer.Pid, err
+			return message.Peer.Pid, err
 		}
 
 		// Read varint for length of message.
 		encodedLn, _, err := ssz_snappy.ReadUvarint(r)
 		if err != nil {
-			return nil, message.Peer.Pid, fmt.Errorf("unable to read varint from message prefix: %v", err)
+			return message.Peer.Pid, fmt.Errorf("unable to read varint from message prefix: %v", err)
 		}
 		// Sanity check for message size.
 		if encodedLn > uint64(maxMessageLength) {
-			return nil, message.Peer.Pid, fmt.Errorf("received message too big")
+			return message.Peer.Pid, fmt.Errorf("received message too big")
 		}
 
 		// Read bytes using snappy into a new raw buffer of side encodedLn.
@@ -98,31 +130,28 @@ func (b *BeaconRpcP2P) sendBlocksRequest(ctx context.Context, topic string, reqD
 		for bytesRead < int(encodedLn) {
 			n, err := sr.Read(raw[bytesRead:])
 			if err != nil {
-				return nil, message.Peer.Pid, fmt.Errorf("read error: %w", err)
+				return message.Peer.Pid, fmt.Errorf("read error: %w", err)
 			}
 			bytesRead += n
 		}
 		// Fork digests
 		respForkDigest := binary.BigEndian.Uint32(forkDigest)
 		if respForkDigest == 0 {
-			return nil, message.Peer.Pid, fmt.Errorf("null fork digest")
+			return message.Peer.Pid, fmt.Errorf("null fork digest")
 		}
 
 		version, err := fork.ForkDigestVersion(utils.Uint32ToBytes4(respForkDigest), b.beaconConfig, b.genesisConfig.GenesisValidatorRoot)
 		if err != nil {
-			return nil, message.Peer.Pid, err
+			return message.Peer.Pid, err
 		}
-		responseChunk := &cltypes.SignedBeaconBlock{}
-
-		if err = responseChunk.DecodeSSZ(raw, int(version)); err != nil {
-			return nil, message.Peer.Pid, err
+		if err := decodeChunk(raw, version); err != nil {
+			return message.Peer.Pid, err
 		}
-		responsePacket = append(responsePacket, responseChunk)
 		// TODO(issues/5884): figure out why there is this extra byte.
 		r.ReadByte()
 	}
 
-	return responsePacket, message.Peer.Pid, nil
+	return message.Peer.Pid, nil
 }
 
 // SendBeaconBlocksByRangeReq retrieves blocks range from beacon chain.
@@ -155,6 +184,31 @@ func (b *BeaconRpcP2P) SendBeaconBlocksByRootReq(ctx context.Context, roots [][3
 	return b.sendBlocksRequest(ctx, communication.BeaconBlocksByRootProtocolV2, data, uint64(len(roots)))
 }
 
+// SendBlobSidecarsByRangeReq retrieves the blob sidecars of a range of slots from beacon chain.
+func (b *BeaconRpcP2P) SendBlobSidecarsByRangeReq(ctx context.Context, start, count uint64) ([]*cltypes.BlobSideCar, string, error) {
+	req := &cltypes.BlobsByRangeRequest{
+		StartSlot: start,
+		Count:     count,
+	}
+	var buffer buffer.Buffer
+	if err := ssz_snappy.EncodeAndWrite(&buffer, req); err != nil {
+		return nil, "", err
+	}
+	data := common.CopyBytes(buffer.Bytes())
+	return b.sendBlobSidecarsRequest(ctx, communication.BlobSidecarByRangeProtocolV1, data, count, count*cltypes.MaxBlobsPerBlock)
+}
+
+// SendBlobSidecarsByRootReq retrieves blob sidecars by block root and index from beacon chain.
+func (b *BeaconRpcP2P) SendBlobSidecarsByRootReq(ctx context.Context, ids []*cltypes.BlobIdentifier) ([]*cltypes.BlobSideCar, string, error) {
+	req := solid.NewStaticListSSZFromList(ids, cltypes.MaxRequestBlobSidecars, 40)
+	var buffer buffer.Buffer
+	if err := ssz_snappy.EncodeAndWrite(&buffer, req); err != nil {
+		return nil, "", err
+	}
+	data := common.CopyBytes(buffer.Bytes())
+	return b.sendBlobSidecarsRequest(ctx, communication.BlobSidecarByRootProtocolV1, data, uint64(len(ids)), uint64(len(ids)))
+}
+
 // Peers retrieves peer count.
 func (b *BeaconRpcP2P) Peers() (uint64, error) {
 	amount, err := b.sentinel.GetPeers(b.ctx, &sentinel.EmptyMessage{})
@@ -3,25 +3,30 @@ package caplin1
 import (
 	"context"
 
+	"github.com/ledgerwatch/erigon/cl/beacon"
+	"github.com/ledgerwatch/erigon/cl/beacon/beaconevents"
+	"github.com/ledgerwatch/erigon/cl/beacon/handler"
 	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
 	"github.com/ledgerwatch/erigon/cl/freezer"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	"github.com/ledgerwatch/erigon/cl/phase1/execution_client"
 	"github.com/ledgerwatch/erigon/cl/phase1/forkchoice"
+	"github.com/ledgerwatch/erigon/cl/phase1/light_client"
 	network2 "github.com/ledgerwatch/erigon/cl/phase1/network"
+	"github.com/ledgerwatch/erigon/cl/phase1/pool"
 	"github.com/ledgerwatch/erigon/cl/phase1/stages"
 
 	"github.com/Giulio2002/bls"
-	"github.com/ledgerwatch/erigon-lib/gointerfaces/sentinel"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/rpc"
+	"github.com/ledgerwatch/erigon/cmd/sentinel/sentinel/gossip"
 	"github.com/ledgerwatch/log/v3"
 
 	"github.com/ledgerwatch/erigon/eth/stagedsync"
 )
 
-func RunCaplinPhase1(ctx context.Context, sentinel sentinel.SentinelClient, beaconConfig *clparams.BeaconChainConfig, genesisConfig *clparams.GenesisConfig,
-	engine execution_client.ExecutionEngine, state *state.BeaconState, caplinFreezer freezer.Freezer) error {
+func RunCaplinPhase1(ctx context.Context, sentinel gossip.SentinelClient, beaconConfig *clparams.BeaconChainConfig, genesisConfig *clparams.GenesisConfig,
+	engine execution_client.ExecutionEngine, state *state.BeaconState, caplinFreezer freezer.Freezer, blobs *freezer.BlobSidecarStore, lightClient *light_client.Store, beaconApiCfg *beacon.RouterConfiguration) error {
 	beaconRpc := rpc.NewBeaconRpcP2P(ctx, sentinel, beaconConfig, genesisConfig)
 	downloader := network2.NewForwardBeaconDownloader(ctx, beaconRpc)
 
@@ -30,11 +35,22 @@ func RunCaplinPhase1(ctx context.Context, sentinel sentinel.SentinelClient, beac
 			return err
 		}
 	}
-	forkChoice, err := forkchoice.NewForkChoiceStore(state, engine, caplinFreezer, true)
+	// events are only produced when somebody can consume them
+	var emitters *beaconevents.Emitters
+	if beaconApiCfg != nil {
+		emitters = beaconevents.NewEmitters()
+	}
+	forkChoice, err := forkchoice.NewForkChoiceStore(state, engine, caplinFreezer, blobs, lightClient, emitters, true)
 	if err != nil {
 		log.Error("Could not create forkchoice", "err", err)
 		return err
 	}
+	operationsPool := pool.NewOperationsPool()
+	if beaconApiCfg != nil {
+		apiHandler := handler.NewApiHandler(genesisConfig, beaconConfig, forkChoice, emitters, operationsPool, sentinel, lightClient)
+		go beacon.ListenAndServe(apiHandler, beaconApiCfg)
+		log.Info("Beacon API started", "addr", beaconApiCfg.Address)
+	}
 	bls.SetEnabledCaching(true)
 	state.ForEachValidator(func(v solid.Validator, idx, total int) bool {
 		pk := v.PublicKey()
@@ -43,6 +59,6 @@ func RunCaplinPhase1(ctx context.Context, sentinel sentinel.SentinelClient, beac
 		}
 		return true
 	})
-	gossipManager := network2.NewGossipReceiver(ctx, sentinel, forkChoice, beaconConfig, genesisConfig, caplinFreezer)
+	gossipManager := network2.NewGossipReceiver(ctx, sentinel, forkChoice, operationsPool, beaconConfig, genesisConfig, caplinFreezer)
 	return stages.SpawnStageForkChoice(stages.StageForkChoice(nil, downloader, genesisConfig, beaconConfig, state, nil, gossipManager, forkChoice, caplinFreezer), &stagedsync.StageState{ID: "Caplin"}, nil, ctx)
 }
@@ -17,13 +17,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/ledgerwatch/erigon/cl/beacon"
-	"github.com/ledgerwatch/erigon/cl/beacon/handler"
 	"github.com/ledgerwatch/erigon/cl/freezer"
 	"github.com/ledgerwatch/erigon/cl/phase1/core"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 	"github.com/ledgerwatch/erigon/cl/phase1/execution_client"
+	"github.com/ledgerwatch/erigon/cl/phase1/light_client"
 
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
 	"github.com/ledgerwatch/log/v3"
@@ -69,7 +70,7 @@ func runCaplinNode(cliCtx *cli.Context) error {
 	if cfg.InitialSync {
 		state = cfg.InitalState
 	} else {
-		state, err = core.RetrieveBeaconState(ctx, cfg.BeaconCfg, cfg.GenesisCfg, cfg.CheckpointUri)
+		state, err = core.RetrieveVerifiedBeaconState(ctx, cfg.BeaconCfg, cfg.GenesisCfg, cfg.CheckpointSync)
 		if err != nil {
 			return err
 		}
@@ -80,6 +81,18 @@ func runCaplinNode(cliCtx *cli.Context) error {
 		return err
 	}
 
+	// blob sidecars and light client updates are kept with the recorded data if any, otherwise in the data directory
+	caplinRoot := filepath.Join(cfg.DataDir, "caplin")
+	if cfg.RecordMode {
+		caplinRoot = cfg.RecordDir
+	}
+	dataFreezer := &freezer.RootPathOsFs{Root: caplinRoot}
+	blobs := freezer.NewBlobSidecarStore(dataFreezer)
+	lightClient, err := light_client.NewStore(cfg.BeaconCfg, dataFreezer)
+	if err != nil {
+		return err
+	}
+
 	sentinel, err := service.StartSentinelService(&sentinel.SentinelConfig{
 		IpAddr:        cfg.Addr,
 		Port:          int(cfg.Port),
@@ -88,6 +101,8 @@ func runCaplinNode(cliCtx *cli.Context) error {
 		NetworkConfig: cfg.NetworkCfg,
 		BeaconConfig:  cfg.BeaconCfg,
 		NoDiscovery:   cfg.NoDiscovery,
+		BlobSidecars:  blobs,
+		LightClient:   lightClient,
 	}, nil, &service.ServerConfig{Network: cfg.ServerProtocol, Addr: cfg.ServerAddr}, nil, &cltypes.Status{
 		ForkDigest:     forkDigest,
 		FinalizedRoot:  state.FinalizedCheckpoint().BlockRoot(),
@@ -115,16 +130,15 @@ func runCaplinNode(cliCtx *cli.Context) error {
 		engine = execution_client.NewExecutionEnginePhase1FromClient(ctx, remote.NewETHBACKENDClient(cc))
 	}
 
+	var beaconApiCfg *beacon.RouterConfiguration
 	if !cfg.NoBeaconApi {
-		apiHandler := handler.NewApiHandler(cfg.GenesisCfg, cfg.BeaconCfg)
-		go beacon.ListenAndServe(apiHandler, &beacon.RouterConfiguration{
+		beaconApiCfg = &beacon.RouterConfiguration{
 			Protocol:        cfg.BeaconProtocol,
 			Address:         cfg.BeaconAddr,
 			ReadTimeTimeout: cfg.BeaconApiReadTimeout,
 			WriteTimeout:    cfg.BeaconApiWriteTimeout,
 			IdleTimeout:     cfg.BeaconApiWriteTimeout,
-		})
-		log.Info("Beacon API started", "addr", cfg.BeaconAddr)
+		}
 	}
 
 	var caplinFreezer freezer.Freezer
@@ -134,5 +148,5 @@ func runCaplinNode(cliCtx *cli.Context) error {
 		}
 	}
 
-	return caplin1.RunCaplinPhase1(ctx, sentinel, cfg.BeaconCfg, cfg.GenesisCfg, engine, state, caplinFreezer)
+	return caplin1.RunCaplinPhase1(ctx, sentinel, cfg.BeaconCfg, cfg.GenesisCfg, engine, state, caplinFreezer, blobs, lightClient, beaconApiCfg)
 }
@@ -38,7 +38,7 @@ func (r *RegressionTester) Run(name string, fn func(*forkchoice.ForkChoiceStore,
 	if err != nil {
 		return err
 	}
-	store, err := forkchoice.NewForkChoiceStore(state, nil, nil, true)
+	store, err := forkchoice.NewForkChoiceStore(state, nil, nil, nil, nil, nil, true)
 	if err != nil {
 		return err
 	}
@@ -38,6 +38,7 @@ type Node struct {
 	MetricsAddr               string `arg:"--metrics.addr" json:"--metrics.addr,omitempty"`
 	StaticPeers               string `arg:"--staticpeers" json:"--staticpeers,omitempty"`
 	WithoutHeimdall           bool   `arg:"--bor.withoutheimdall" flag:"" default:"false" json:"--bor.withoutheimdall,omitempty"`
+	HeimdallCacheMode         string `arg:"--bor.heimdall.cache" json:"--bor.heimdall.cache,omitempty"`
 }
 
 func (node *Node) configure(base Node, nodeNumber int) error {
@@ -49,6 +49,12 @@ var (
 		Usage: "Run without Heimdall service",
 	}
 
+	HeimdallCacheModeFlag = cli.StringFlag{
+		Name:  "bor.heimdall.cache",
+		Usage: "What the nodes keep of the Heimdall responses: off, cache, record or replay",
+		Value: "off",
+	}
+
 	MetricsEnabledFlag = cli.BoolFlag{
 		Name:  "metrics",
 		Usage: "Enable metrics collection and reporting",
@@ -104,6 +110,7 @@ func main() {
 		&DataDirFlag,
 		&ChainFlag,
 		&WithoutHeimdallFlag,
+		&HeimdallCacheModeFlag,
 		&MetricsEnabledFlag,
 		&MetricsNodeFlag,
 		&MetricsPortFlag,
@@ -262,22 +269,25 @@ func selectNetwork(ctx *cli.Context, logger log.Logger) (*devnet.Network, error)
 				Nodes: []devnet.Node{
 					args.Miner{
 						Node: args.Node{
-							ConsoleVerbosity: "0",
-							DirVerbosity:     "5",
+							ConsoleVerbosity:  "0",
+							DirVerbosity:      "5",
+							HeimdallCacheMode: ctx.String(HeimdallCacheModeFlag.Name),
 						},
 						AccountSlots: 200,
 					},
 					args.Miner{
 						Node: args.Node{
-							ConsoleVerbosity: "0",
-							DirVerbosity:     "5",
+							ConsoleVerbosity:  "0",
+							DirVerbosity:      "5",
+							HeimdallCacheMode: ctx.String(HeimdallCacheModeFlag.Name),
 						},
 						AccountSlots: 200,
 					},
 					args.NonMiner{
 						Node: args.Node{
-							ConsoleVerbosity: "0",
-							DirVerbosity:     "5",
+							ConsoleVerbosity:  "0",
+							DirVerbosity:      "5",
+							HeimdallCacheMode: ctx.String(HeimdallCacheModeFlag.Name),
 						},
 					},
 				},
@@ -56,6 +56,8 @@ import (
 	"github.com/ledgerwatch/erigon/common/debug"
 	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/consensus/bor"
+	"github.com/ledgerwatch/erigon/consensus/bor/finality"
+	"github.com/ledgerwatch/erigon/consensus/bor/finality/whitelist"
 	"github.com/ledgerwatch/erigon/consensus/clique"
 	"github.com/ledgerwatch/erigon/consensus/ethash"
 	"github.com/ledgerwatch/erigon/consensus/merge"
@@ -79,6 +81,7 @@ import (
 	"github.com/ledgerwatch/erigon/p2p"
 	"github.com/ledgerwatch/erigon/p2p/dnsdisc"
 	"github.com/ledgerwatch/erigon/p2p/enode"
+	"github.com/ledgerwatch/erigon/p2p/peeradmin"
 	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/engineapi"
@@ -129,6 +132,7 @@ type Ethereum struct {
 	sentryCancel   context.CancelFunc
 	sentriesClient *sentry.MultiClient
 	sentryServers  []*sentry.GrpcServer
+	peerAdmin      *sentry.MultiPeerAdmin
 
 	stagedSync *stagedsync.Sync
 
@@ -148,6 +152,7 @@ type Ethereum struct {
 	txPool2GrpcServer       txpool_proto.TxpoolServer
 	notifyMiningAboutNewTxs chan struct{}
 	forkValidator           *engineapi.ForkValidator
+	whitelist               *whitelist.Service // Heimdall finality of Bor blocks
 	downloader              *downloader3.Downloader
 	blockReader             services.FullBlockReader
 	blockWriter             *blockio.BlockWriter
@@ -281,13 +286,15 @@ func NewBackend(stack *node.Node, config *ethconfig.Config, logger log.Logger) (
 	backend.gasPrice, _ = uint256.FromBig(config.Miner.GasPrice)
 
 	var sentries []direct.SentryClient
+	var peerAdmins []peeradmin.PeerAdminClient
 	if len(stack.Config().P2P.SentryAddr) > 0 {
 		for _, addr := range stack.Config().P2P.SentryAddr {
-			sentryClient, err := sentry.GrpcClient(backend.sentryCtx, addr)
+			sentryClient, peerAdminClient, err := sentry.GrpcClient(backend.sentryCtx, addr)
 			if err != nil {
 				return nil, err
 			}
 			sentries = append(sentries, sentryClient)
+			peerAdmins = append(peerAdmins, peerAdminClient)
 		}
 	} else {
 		var readNodeInfo = func() *eth.NodeInfo {
@@ -344,6 +351,7 @@ func NewBackend(stack *node.Node, config *ethconfig.Config, logger log.Logger) (
 			server := sentry.NewGrpcServer(backend.sentryCtx, discovery, readNodeInfo, &cfg, protocol, logger)
 			backend.sentryServers = append(backend.sentryServers, server)
 			sentries = append(sentries, direct.NewSentryClientDirect(protocol, server))
+			peerAdmins = append(peerAdmins, peeradmin.NewPeerAdminClientDirect(sentry.NewPeerAdminServer(server)))
 		}
 
 		go func() {
@@ -422,13 +430,20 @@ func NewBackend(stack *node.Node, config *ethconfig.Config, logger log.Logger) (
 		consensusConfig = &config.Ethash
 	}
 	backend.engine = ethconsensusconfig.CreateConsensusEngine(chainConfig, consensusConfig, config.Miner.Notify, config.Miner.Noverify,
-		config.HeimdallgRPCAddress, config.HeimdallURL, config.WithoutHeimdall, stack.DataDir(), false /* readonly */, logger)
+		config.HeimdallgRPCAddress, config.HeimdallURL, config.WithoutHeimdall, config.HeimdallCacheMode, stack.DataDir(), false /* readonly */, logger)
+	if casted, ok := backend.engine.(*bor.Bor); ok {
+		casted.SetBorReader(blockReader)
+		if casted.HeimdallClient != nil {
+			backend.whitelist = whitelist.NewService()
+		}
+	}
 	backend.forkValidator = engineapi.NewForkValidator(currentBlockNumber, inMemoryExecution, tmpdir, backend.blockReader)
 
 	if err != nil {
 		return nil, err
 	}
 
+	backend.peerAdmin = sentry.NewMultiPeerAdmin(peerAdmins)
 	backend.sentriesClient, err = sentry.NewMultiClient(
 		chainKv,
 		stack.Config().NodeName(),
@@ -528,6 +543,7 @@ func NewBackend(stack *node.Node, config *ethconfig.Config, logger log.Logger) (
 			ethBackendRPC,
 			backend.txPool2GrpcServer,
 			miningRPC,
+			backend.peerAdmin,
 			stack.Config().PrivateApiAddr,
 			stack.Config().PrivateApiRateLimit,
 			creds,
@@ -615,9 +631,13 @@ func NewBackend(stack *node.Node, config *ethconfig.Config, logger log.Logger) (
 		return nil, err
 	}
 
-	blockRetire := freezeblocks.NewBlockRetire(1, dirs, blockReader, blockWriter, backend.chainDB, backend.notifications.Events, logger)
+	var borDB kv.RwDB
+	if casted, ok := backend.engine.(*bor.Bor); ok {
+		borDB = casted.DB
+	}
+	blockRetire := freezeblocks.NewBlockRetire(1, dirs, blockReader, blockWriter, backend.chainDB, borDB, backend.notifications.Events, logger)
 	backend.stagedSync, err = stages3.NewStagedSync(backend.sentryCtx, backend.chainDB, stack.Config().P2P, config,
-		backend.sentriesClient, backend.notifications, backend.downloaderClient, backend.agg, backend.forkValidator, logger, backend.blockReader, backend.blockWriter, blockRetire)
+		backend.sentriesClient, backend.notifications, backend.downloaderClient, backend.agg, backend.forkValidator, backend.whitelist, logger, backend.blockReader, backend.blockWriter, blockRetire)
 	if err != nil {
 		return nil, err
 	}
@@ -656,7 +676,7 @@ func NewBackend(stack *node.Node, config *ethconfig.Config, logger log.Logger) (
 	}
 	// start HTTP API
 	httpRpcCfg := stack.Config().Http
-	ethRpcClient, txPoolRpcClient, miningRpcClient, stateCache, ff, err := cli.EmbeddedServices(ctx, chainKv, httpRpcCfg.StateCache, backend.blockReader, ethBackendRPC,
+	ethRpcClient, txPoolRpcClient, miningRpcClient, stateCache, ff, err := cli.EmbeddedServices(ctx, chainKv, httpRpcCfg.StateCache, backend.blockReader, ethBackendRPC, backend.peerAdmin,
 		backend.txPool2GrpcServer, miningRPC, stateDiffClient, logger)
 	if err != nil {
 		return nil, err
@@ -900,7 +920,11 @@ func setUpBlockReader(ctx context.Context, db kv.RwDB, dirs datadir.Dirs, snConf
 	if !snConfig.NoDownloader {
 		allSnapshots.OptimisticalyReopenWithDB(db)
 	}
-	blockReader := freezeblocks.NewBlockReader(allSnapshots)
+	allBorSnapshots := freezeblocks.NewBorRoSnapshots(snConfig, dirs.Snap, logger)
+	if !snConfig.NoDownloader {
+		allBorSnapshots.OptimisticalyReopenFolder()
+	}
+	blockReader := freezeblocks.NewBlockReader(allSnapshots, allBorSnapshots)
 	blockWriter := blockio.NewBlockWriter(histV3)
 
 	dir.MustExist(dirs.SnapHistory)
@@ -941,9 +965,13 @@ func (s *Ethereum) Protocols() []p2p.Protocol {
 func (s *Ethereum) Start() error {
 	s.sentriesClient.StartStreamLoops(s.sentryCtx)
 	time.Sleep(10 * time.Millisecond) // just to reduce logs order confusion
+
+	if casted, ok := s.engine.(*bor.Bor); ok && s.whitelist != nil {
+		finality.Whitelist(s.sentryCtx, s.whitelist, casted.HeimdallClient, s.chainDB, s.blockReader, s.logger)
+	}
 	// Execute one iteration
 	go func() {
-		if err := s.stagedSync.Run(s.chainDB, nil, true); err != nil {
+		if err := s.stagedSync.Run(s.sentryCtx, s.chainDB, nil, true); err != nil {
 			panic(err)
 		}
 		lis, err := net.Listen("tcp", "127.0.0.1:8989")
@@ -161,7 +161,7 @@ func (e *Eth1Execution) UpdateForkChoice(ctx context.Context, hash *types2.H256)
 		e.executionPipeline.UnwindTo(currentParentNumber, libcommon.Hash{})
 	}
 	// Run the unwind
-	if err := e.executionPipeline.RunUnwind(e.db, tx); err != nil {
+	if err := e.executionPipeline.RunUnwind(ctx, e.db, tx); err != nil {
 		return nil, err
 	}
 	// Mark all new canonicals as canonicals
@@ -181,7 +181,7 @@ func (e *Eth1Execution) UpdateForkChoice(ctx context.Context, hash *types2.H256)
 		return nil, err
 	}
 	// Run the forkchoice
-	if err := e.executionPipeline.Run(e.db, tx, false); err != nil {
+	if err := e.executionPipeline.Run(ctx, e.db, tx, false); err != nil {
 		return nil, err
 	}
 	// if head hash was set then success otherwise no
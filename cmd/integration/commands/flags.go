@@ -24,6 +24,7 @@ var (
 	file                           string
 	HeimdallgRPCAddress            string
 	HeimdallURL                    string
+	HeimdallCacheMode              string
 	txtrace                        bool // Whether to trace the execution (should only be used together with `block`)
 	pruneFlag                      string
 	pruneH, pruneR, pruneT, pruneC uint64
@@ -40,6 +41,11 @@ var (
 
 	_forceSetHistoryV3    bool
 	workers, reconWorkers uint64
+
+	parallelEVM      bool
+	parallelEVMProcs int
+
+	stdTraceTxHash, stdTraceBadBlock, stdTraceOutput string
 )
 
 func must(err error) {
@@ -147,6 +153,7 @@ func withChain(cmd *cobra.Command) {
 
 func withHeimdall(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&HeimdallURL, "bor.heimdall", "http://localhost:1317", "URL of Heimdall service")
+	cmd.Flags().StringVar(&HeimdallCacheMode, utils.HeimdallCacheModeFlag.Name, utils.HeimdallCacheModeFlag.Value, utils.HeimdallCacheModeFlag.Usage)
 }
 
 func withWorkers(cmd *cobra.Command) {
@@ -154,6 +161,11 @@ func withWorkers(cmd *cobra.Command) {
 	cmd.Flags().Uint64Var(&reconWorkers, "recon.workers", uint64(ethconfig.Defaults.Sync.ReconWorkerCount), "")
 }
 
+func withParallelEVM(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&parallelEVM, "parallel-evm.enable", false, "execute Bor blocks with Block-STM")
+	cmd.Flags().IntVar(&parallelEVMProcs, "parallel-evm.procs", 8, "number of speculative goroutines used by Block-STM")
+}
+
 func withStartTx(cmd *cobra.Command) {
 	cmd.Flags().Uint64Var(&startTxNum, "tx", 0, "start processing from tx")
 }
@@ -162,6 +174,12 @@ func withTraceFromTx(cmd *cobra.Command) {
 	cmd.Flags().Uint64Var(&traceFromTx, "txtrace.from", 0, "start tracing from tx number")
 }
 
+func withStdTrace(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&stdTraceTxHash, "txhash", "", "trace only this transaction of the block")
+	cmd.Flags().StringVar(&stdTraceBadBlock, "bad.block", "", "hash of a rejected block to trace instead of the canonical block at --block")
+	cmd.Flags().StringVar(&stdTraceOutput, "output", "", "directory to write the trace files to, the tmp dir of --datadir by default")
+}
+
 func withCommitment(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&commitmentMode, "commitment.mode", "direct", "defines the way to calculate commitments: 'direct' mode reads from state directly, 'update' accumulate updates before commitment, 'off' actually disables commitment calculation")
 	cmd.Flags().StringVar(&commitmentTrie, "commitment.trie", "hex", "hex - use Hex Patricia Hashed Trie for commitments, bin - use of binary patricia trie")
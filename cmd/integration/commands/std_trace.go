@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	common2 "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcfg"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/spf13/cobra"
+
+	"github.com/ledgerwatch/erigon/cmd/hack/tool/fromdb"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+	"github.com/ledgerwatch/erigon/turbo/transactions"
+)
+
+var cmdStandardTrace = &cobra.Command{
+	Use:     "std_trace",
+	Short:   "Re-execute a block on top of the state of its parent and write the EIP-3155 trace of each transaction into a file",
+	Example: "go run ./cmd/integration std_trace --datadir=... --chain=mainnet --block=17000000 --txhash=0x...",
+	Run: func(cmd *cobra.Command, args []string) {
+		var logger log.Logger
+		var err error
+		if logger, err = debug.SetupCobra(cmd, "integration"); err != nil {
+			logger.Error("Setting up", "error", err)
+			return
+		}
+		ctx, _ := common2.RootContext()
+		db, err := openDB(dbCfg(kv.ChainDB, chaindata), false, logger)
+		if err != nil {
+			logger.Error("Opening DB", "error", err)
+			return
+		}
+		defer db.Close()
+
+		if err := standardTrace(db, ctx, logger); err != nil {
+			if !errors.Is(err, context.Canceled) {
+				logger.Error(err.Error())
+			}
+			return
+		}
+	},
+}
+
+func init() {
+	withDataDir(cmdStandardTrace)
+	withChain(cmdStandardTrace)
+	withHeimdall(cmdStandardTrace)
+	withBlock(cmdStandardTrace)
+	withStdTrace(cmdStandardTrace)
+	rootCmd.AddCommand(cmdStandardTrace)
+}
+
+func standardTrace(db kv.RwDB, ctx context.Context, logger log.Logger) error {
+	dirs := datadir.New(datadirCli)
+	sn, agg := allSnapshots(ctx, db, logger)
+	defer sn.Close()
+	defer agg.Close()
+	br, _ := blocksIO(db, logger)
+	chainConfig, historyV3 := fromdb.ChainConfig(db), kvcfg.HistoryV3.FromDB(db)
+	engine := initConsensusEngine(chainConfig, dirs.DataDir, db, logger)
+
+	tx, err := db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var b *types.Block
+	if stdTraceBadBlock != "" {
+		badBlock, err := rawdb.ReadBadBlock(tx, common2.HexToHash(stdTraceBadBlock))
+		if err != nil {
+			return err
+		}
+		if badBlock == nil {
+			return fmt.Errorf("bad block %s not found", stdTraceBadBlock)
+		}
+		b = badBlock.Block
+	} else {
+		if b, err = br.BlockByNumber(ctx, tx, block); err != nil {
+			return err
+		}
+		if b == nil {
+			return fmt.Errorf("block %d not found", block)
+		}
+	}
+
+	blockCtx, ibs, err := transactions.ComputeBlockEnvOnParent(ctx, engine, b, chainConfig, br, tx, historyV3)
+	if err != nil {
+		return err
+	}
+	outputDir := stdTraceOutput
+	if outputDir == "" {
+		outputDir = dirs.Tmp
+	}
+	config := &tracers.StdTraceConfig{}
+	if stdTraceTxHash != "" {
+		config.TxHash = common2.HexToHash(stdTraceTxHash)
+	}
+	files, err := transactions.StandardTraceBlockToFiles(ctx, engine, b, blockCtx, ibs, chainConfig, config, outputDir)
+	for _, file := range files {
+		logger.Info("Written trace", "block", b.NumberU64(), "file", file)
+	}
+	return err
+}
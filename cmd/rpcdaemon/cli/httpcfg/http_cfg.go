@@ -33,6 +33,7 @@ type HttpCfg struct {
 	WebsocketEnabled         bool
 	WebsocketCompression     bool
 	RpcAllowListFilePath     string
+	RpcRateLimitsFilePath    string
 	RpcBatchConcurrency      uint
 	RpcStreamingDisable      bool
 	DBReadConcurrency        int
@@ -64,4 +65,7 @@ type HttpCfg struct {
 
 	BatchLimit      int // Maximum number of requests in a batch
 	ReturnDataLimit int // Maximum number of bytes returned from calls (like eth_call)
+
+	GetProofMaxRewind   uint64 // Maximum number of blocks eth_getProof may rewind the state
+	LogsReplayMaxBlocks uint64 // Maximum number of past blocks a logs subscription may replay
 }
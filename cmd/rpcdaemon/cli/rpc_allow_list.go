@@ -41,3 +41,22 @@ func parseAllowListForRPC(path string) (rpc.AllowList, error) {
 
 	return allowListFileObj.Allow, nil
 }
+
+// parseRateLimitsForRPC reads the rpc.RateLimits from a JSON file, it returns nil if no file is provided
+func parseRateLimitsForRPC(path string) (*rpc.RateLimits, error) {
+	path = strings.TrimSpace(path)
+	if path == "" { // no file is provided
+		return nil, nil
+	}
+
+	fileContents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rateLimits rpc.RateLimits
+	if err := json.Unmarshal(fileContents, &rateLimits); err != nil {
+		return nil, err
+	}
+	return &rateLimits, nil
+}
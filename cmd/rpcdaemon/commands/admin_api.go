@@ -5,7 +5,11 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/common/debug"
 	"github.com/ledgerwatch/erigon/p2p"
+	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
 )
 
@@ -17,6 +21,24 @@ type AdminAPI interface {
 	// Peers returns information about the connected remote nodes.
 	// https://geth.ethereum.org/docs/rpc/ns-admin#admin_peers
 	Peers(ctx context.Context) ([]*p2p.PeerInfo, error)
+
+	// AddPeer requests connecting to a remote node, and also maintaining the new
+	// connection at all times, even reconnecting if it is lost.
+	AddPeer(ctx context.Context, url string) (bool, error)
+
+	// RemovePeer disconnects from a remote node if the connection exists.
+	RemovePeer(ctx context.Context, url string) (bool, error)
+
+	// AddTrustedPeer allows a remote node to always connect, even if slots are full.
+	AddTrustedPeer(ctx context.Context, url string) (bool, error)
+
+	// RemoveTrustedPeer removes a remote node from the trusted peer set, but it
+	// does not disconnect it automatically.
+	RemoveTrustedPeer(ctx context.Context, url string) (bool, error)
+
+	// PeerEvents creates an RPC subscription which receives peer events from the
+	// node's p2p servers.
+	PeerEvents(ctx context.Context) (*rpc.Subscription, error)
 }
 
 // AdminAPIImpl data structure to store things needed for admin_* commands.
@@ -47,3 +69,49 @@ func (api *AdminAPIImpl) NodeInfo(ctx context.Context) (*p2p.NodeInfo, error) {
 func (api *AdminAPIImpl) Peers(ctx context.Context) ([]*p2p.PeerInfo, error) {
 	return api.ethBackend.Peers(ctx)
 }
+
+func (api *AdminAPIImpl) AddPeer(ctx context.Context, url string) (bool, error) {
+	return api.ethBackend.AddPeer(ctx, url)
+}
+
+func (api *AdminAPIImpl) RemovePeer(ctx context.Context, url string) (bool, error) {
+	return api.ethBackend.RemovePeer(ctx, url)
+}
+
+func (api *AdminAPIImpl) AddTrustedPeer(ctx context.Context, url string) (bool, error) {
+	return api.ethBackend.AddTrustedPeer(ctx, url)
+}
+
+func (api *AdminAPIImpl) RemoveTrustedPeer(ctx context.Context, url string) (bool, error) {
+	return api.ethBackend.RemoveTrustedPeer(ctx, url)
+}
+
+func (api *AdminAPIImpl) PeerEvents(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	// The stream outlives the request, it is closed when the subscription ends
+	streamCtx, cancel := context.WithCancel(context.Background())
+	go func() {
+		defer debug.LogPanic()
+		defer cancel()
+		go func() {
+			defer debug.LogPanic()
+			err := api.ethBackend.PeerEvents(streamCtx, func(event *p2p.PeerEvent) {
+				if err := notifier.Notify(rpcSub.ID, event); err != nil {
+					log.Warn("[rpc] error while notifying subscription", "err", err)
+				}
+			})
+			if err != nil && streamCtx.Err() == nil {
+				log.Warn("[rpc] peer events stream failed", "err", err)
+			}
+		}()
+		<-rpcSub.Err()
+	}()
+
+	return rpcSub, nil
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/ledgerwatch/erigon-lib/chain"
 	"github.com/ledgerwatch/erigon-lib/common"
@@ -118,6 +119,20 @@ func validateHeaderExtraField(extraBytes []byte) error {
 	return nil
 }
 
+// getValidatorBytes returns the validator bytes carried by the extra-data of
+// a header: RLP encoded BlockExtraData from the parallel universe fork block
+// on, raw validator bytes before it.
+func getValidatorBytes(header *types.Header, parallelUniverseBlock *big.Int) ([]byte, error) {
+	if parallelUniverseBlock == nil || header.Number.Cmp(parallelUniverseBlock) < 0 {
+		return header.Extra[extraVanity : len(header.Extra)-extraSeal], nil
+	}
+	data, err := types.DecodeBlockExtraData(header.Extra)
+	if err != nil {
+		return nil, fmt.Errorf("invalid extra-data: %w", err)
+	}
+	return data.ValidatorBytes, nil
+}
+
 // validatorContains checks for a validator in given validator set
 func validatorContains(a []*valset.Validator, x *valset.Validator) (*valset.Validator, bool) {
 	for _, n := range a {
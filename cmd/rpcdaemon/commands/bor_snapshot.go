@@ -20,12 +20,15 @@ import (
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/erigon/rpc"
 )
 
 type Snapshot struct {
 	config *chain.BorConfig // Consensus engine parameters to fine tune behavior
 
+	parallelUniverseBlock *big.Int // Block from which headers carry RLP encoded BlockExtraData
+
 	Number       uint64                    `json:"number"`       // Block number where the snapshot was created
 	Hash         common.Hash               `json:"hash"`         // Block hash where the snapshot was created
 	ValidatorSet *ValidatorSet             `json:"validatorSet"` // Validator set at this moment
@@ -246,11 +249,12 @@ func (api *BorImpl) GetRootHash(start, end uint64) (string, error) {
 // copy creates a deep copy of the snapshot, though not the individual votes.
 func (s *Snapshot) copy() *Snapshot {
 	cpy := &Snapshot{
-		config:       s.config,
-		Number:       s.Number,
-		Hash:         s.Hash,
-		ValidatorSet: s.ValidatorSet.Copy(),
-		Recents:      make(map[uint64]common.Address),
+		config:                s.config,
+		parallelUniverseBlock: s.parallelUniverseBlock,
+		Number:                s.Number,
+		Hash:                  s.Hash,
+		ValidatorSet:          s.ValidatorSet.Copy(),
+		Recents:               make(map[uint64]common.Address),
 	}
 	for block, signer := range s.Recents {
 		cpy.Recents[block] = signer
@@ -341,7 +345,10 @@ func (s *Snapshot) apply(headers []*types.Header) (*Snapshot, error) {
 			if err := validateHeaderExtraField(header.Extra); err != nil {
 				return nil, err
 			}
-			validatorBytes := header.Extra[extraVanity : len(header.Extra)-extraSeal]
+			validatorBytes, err := getValidatorBytes(header, s.parallelUniverseBlock)
+			if err != nil {
+				return nil, err
+			}
 
 			// get validators from headers and use that for new validator set
 			newVals, _ := valset.ParseValidators(validatorBytes)
@@ -417,6 +424,7 @@ func loadSnapshot(api *BorImpl, db kv.Tx, borDb kv.Tx, hash common.Hash) (*Snaps
 	}
 	config, _ := api.chainConfig(db)
 	snap.config = config.Bor
+	snap.parallelUniverseBlock = params.BorParallelUniverseBlock(config.ChainName)
 
 	// update total voting power
 	if err := snap.ValidatorSet.UpdateTotalVotingPower(); err != nil {
@@ -22,6 +22,12 @@ func APIList(db kv.RoDB, borDb kv.RoDB, eth rpchelper.ApiBackend, txPool txpool.
 ) (list []rpc.API) {
 	base := NewBaseApi(filters, stateCache, blockReader, agg, cfg.WithDatadir, cfg.EvmCallTimeout, engine, cfg.Dirs)
 	ethImpl := NewEthAPI(base, db, eth, txPool, mining, cfg.Gascap, cfg.ReturnDataLimit, logger)
+	if cfg.GetProofMaxRewind > 0 {
+		ethImpl.MaxGetProofRewindBlockCount = cfg.GetProofMaxRewind
+	}
+	if cfg.LogsReplayMaxBlocks > 0 {
+		ethImpl.MaxLogsReplayBlockCount = cfg.LogsReplayMaxBlocks
+	}
 	erigonImpl := NewErigonAPI(base, db, eth)
 	txpoolImpl := NewTxPoolAPI(base, db, txPool)
 	netImpl := NewNetAPIImpl(eth)
@@ -33,7 +39,7 @@ func APIList(db kv.RoDB, borDb kv.RoDB, eth rpchelper.ApiBackend, txPool txpool.
 	parityImpl := NewParityAPIImpl(base, db)
 	borImpl := NewBorAPI(base, db, borDb) // bor (consensus) specific
 	otsImpl := NewOtterscanAPI(base, db)
-	gqlImpl := NewGraphQLAPI(base, db)
+	gqlImpl := NewGraphQLAPI(base, db, ethImpl)
 
 	if cfg.GraphQLEnabled {
 		list = append(list, rpc.API{
@@ -147,6 +153,12 @@ func AuthAPIList(db kv.RoDB, eth rpchelper.ApiBackend, txPool txpool.TxpoolClien
 	base := NewBaseApi(filters, stateCache, blockReader, agg, cfg.WithDatadir, cfg.EvmCallTimeout, engine, cfg.Dirs)
 
 	ethImpl := NewEthAPI(base, db, eth, txPool, mining, cfg.Gascap, cfg.ReturnDataLimit, logger)
+	if cfg.GetProofMaxRewind > 0 {
+		ethImpl.MaxGetProofRewindBlockCount = cfg.GetProofMaxRewind
+	}
+	if cfg.LogsReplayMaxBlocks > 0 {
+		ethImpl.MaxLogsReplayBlockCount = cfg.LogsReplayMaxBlocks
+	}
 	engineImpl := NewEngineAPI(base, db, eth, cfg.InternalCL)
 
 	list = append(list, rpc.API{
@@ -2,19 +2,23 @@ package commands
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
+	"math/big"
 	"reflect"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
 	jsoniter "github.com/json-iterator/go"
 	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/iter"
 	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
 	"github.com/ledgerwatch/erigon-lib/kv/order"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
 	common2 "github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/eth/tracers"
 	"github.com/ledgerwatch/erigon/rpc"
@@ -187,6 +191,41 @@ func TestTraceTransactionNoRefund(t *testing.T) {
 	}
 }
 
+func TestTraceCallBlockOverrides(t *testing.T) {
+	m, _, _ := rpcdaemontest.CreateTestSentry(t)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+
+	// NUMBER PUSH1 0 MSTORE TIMESTAMP PUSH1 32 MSTORE COINBASE PUSH1 64 MSTORE PUSH1 96 PUSH1 0 RETURN
+	code := hexutility.Bytes{0x43, 0x60, 0x00, 0x52, 0x42, 0x60, 0x20, 0x52, 0x41, 0x60, 0x40, 0x52, 0x60, 0x60, 0x60, 0x00, 0xf3}
+	to := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	coinbase := common.HexToAddress("0x2000000000000000000000000000000000000002")
+	number := hexutil.Big(*big.NewInt(1000))
+	timestamp := hexutil.Uint64(1_700_000_000)
+
+	var buf bytes.Buffer
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
+	err := api.TraceCall(m.Ctx, ethapi.CallArgs{To: &to}, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber), &tracers.TraceConfig{
+		StateOverrides: &ethapi.StateOverrides{to: {Code: &code}},
+		BlockOverrides: &ethapi.BlockOverrides{
+			Number:   &number,
+			Time:     &timestamp,
+			Coinbase: &coinbase,
+		},
+	}, stream)
+	require.NoError(t, err)
+	require.NoError(t, stream.Flush())
+	var er ethapi.ExecutionResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &er), buf.String())
+	require.False(t, er.Failed)
+
+	ret, err := hex.DecodeString(er.ReturnValue)
+	require.NoError(t, err)
+	require.Len(t, ret, 96)
+	require.Equal(t, uint64(1000), new(big.Int).SetBytes(ret[:32]).Uint64())
+	require.Equal(t, uint64(timestamp), new(big.Int).SetBytes(ret[32:64]).Uint64())
+	require.Equal(t, coinbase, common.BytesToAddress(ret[64:]))
+}
+
 func TestStorageRangeAt(t *testing.T) {
 	m, _, _ := rpcdaemontest.CreateTestSentry(t)
 	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
@@ -0,0 +1,257 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/holiman/uint256"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/ledgerwatch/erigon-lib/chain"
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/log/v3"
+
+	common2 "github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/dbutils"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
+	"github.com/ledgerwatch/erigon/turbo/transactions"
+	"github.com/ledgerwatch/erigon/turbo/trie"
+)
+
+// BadBlockArgs is a block rejected by the node, as returned by debug_getBadBlocks
+type BadBlockArgs struct {
+	Hash   common.Hash            `json:"hash"`
+	Block  map[string]interface{} `json:"block"`
+	RLP    hexutility.Bytes       `json:"rlp"`
+	Reason string                 `json:"reason"`
+}
+
+// GetBadBlocks implements debug_getBadBlocks. Returns the last blocks rejected by the node, the most recent first.
+func (api *PrivateDebugAPIImpl) GetBadBlocks(ctx context.Context) ([]*BadBlockArgs, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	badBlocks, err := rawdb.ReadBadBlocks(tx)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*BadBlockArgs, 0, len(badBlocks))
+	for _, badBlock := range badBlocks {
+		blockRlp, err := rlp.EncodeToBytes(badBlock.Block)
+		if err != nil {
+			return nil, err
+		}
+		fields, err := ethapi.RPCMarshalBlock(badBlock.Block, true, true, nil)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &BadBlockArgs{
+			Hash:   badBlock.Block.Hash(),
+			Block:  fields,
+			RLP:    blockRlp,
+			Reason: badBlock.Reason,
+		})
+	}
+	return results, nil
+}
+
+// TraceBadBlock implements debug_traceBadBlock. Returns Geth style traces of a block rejected by the node,
+// re-executed on top of the state of its parent.
+func (api *PrivateDebugAPIImpl) TraceBadBlock(ctx context.Context, hash common.Hash, config *tracers.TraceConfig, stream *jsoniter.Stream) error {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	defer tx.Rollback()
+
+	badBlock, err := rawdb.ReadBadBlock(tx, hash)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	if badBlock == nil {
+		stream.WriteNil()
+		return fmt.Errorf("bad block %x not found", hash)
+	}
+	chainConfig, err := api.chainConfig(tx)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+	blockCtx, ibs, err := api.blockEnvOnParent(ctx, tx, badBlock.Block, chainConfig)
+	if err != nil {
+		stream.WriteNil()
+		return err
+	}
+
+	if config == nil {
+		config = &tracers.TraceConfig{}
+	}
+	if config.BorTraceEnabled == nil {
+		config.BorTraceEnabled = newBoolPtr(false)
+	}
+	return api.traceBlockTransactions(ctx, tx, badBlock.Block, blockCtx, ibs, chainConfig, config, stream)
+}
+
+// IntermediateRoots implements debug_intermediateRoots. Re-executes a bad or a canonical block on top of the state
+// of its parent and returns the state root after each transaction. The config is accepted for compatibility and ignored.
+func (api *PrivateDebugAPIImpl) IntermediateRoots(ctx context.Context, hash common.Hash, _ *tracers.TraceConfig) ([]common.Hash, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var block *types.Block
+	badBlock, err := rawdb.ReadBadBlock(tx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if badBlock != nil {
+		block = badBlock.Block
+	} else if block, err = api.blockByHashWithSenders(ctx, tx, hash); err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %x not found", hash)
+	}
+	chainConfig, err := api.chainConfig(tx)
+	if err != nil {
+		return nil, err
+	}
+	blockCtx, ibs, err := api.blockEnvOnParent(ctx, tx, block, chainConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// The hashed state is rewound to the parent in a memory batch, and the keys changed by the transactions are
+	// retained so that each root is computed without updating the intermediate hashes
+	trieProgress, err := stages.GetStageProgress(tx, stages.IntermediateHashes)
+	if err != nil {
+		return nil, err
+	}
+	parentNum := block.NumberU64() - 1
+	if parentNum > trieProgress {
+		return nil, fmt.Errorf("state root of parent block %d is not computed yet, trie is at block %d", parentNum, trieProgress)
+	}
+	batch := api.newMemoryBatch(tx)
+	defer batch.Rollback()
+	rl := trie.NewRetainList(0)
+	if parentNum < trieProgress {
+		if _, err := api.unwindTrie(ctx, batch, parentNum, trieProgress, rl, "debug_intermediateRoots", log.Root()); err != nil {
+			return nil, err
+		}
+	}
+	writer := &retainingStateWriter{DbStateWriter: state.NewDbStateWriter(batch, block.NumberU64()), rl: rl}
+
+	engine := api.engine()
+	signer := types.MakeSigner(chainConfig, block.NumberU64(), block.Time())
+	rules := chainConfig.Rules(block.NumberU64(), block.Time())
+	vmenv := vm.NewEVM(blockCtx, evmtypes.TxContext{}, ibs, chainConfig, vm.Config{})
+	roots := make([]common.Hash, 0, block.Transactions().Len())
+	for idx, txn := range block.Transactions() {
+		select {
+		default:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		ibs.SetTxContext(txn.Hash(), block.Hash(), idx)
+		msg, err := txn.AsMessage(*signer, block.BaseFee(), rules)
+		if err != nil {
+			return nil, err
+		}
+		if msg.FeeCap().IsZero() && engine != nil {
+			syscall := func(contract common.Address, data []byte) ([]byte, error) {
+				return core.SysCallContract(contract, data, chainConfig, ibs, block.Header(), engine, true /* constCall */)
+			}
+			msg.SetIsFree(engine.IsServiceTransaction(msg.From(), syscall))
+		}
+		vmenv.Reset(core.NewEVMTxContext(msg), ibs)
+		if _, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(txn.GetGas()).AddDataGas(txn.GetDataGas()), true /* refunds */, false /* gasBailout */); err != nil {
+			return nil, fmt.Errorf("transaction %d (%x) failed: %w", idx, txn.Hash(), err)
+		}
+		if err := ibs.FinalizeTx(rules, writer); err != nil {
+			return nil, err
+		}
+		root, err := trie.NewFlatDBTrieLoader("debug_intermediateRoots", rl, nil, nil, false).CalcTrieRoot(batch, ctx.Done())
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, root)
+	}
+	return roots, nil
+}
+
+// blockEnvOnParent returns the block context and the state the block starts from, which is the state after its
+// parent. Unlike transactions.ComputeTxEnv, it works for blocks which aren't canonical, as long as their parent is.
+func (api *PrivateDebugAPIImpl) blockEnvOnParent(ctx context.Context, tx kv.Tx, block *types.Block, chainConfig *chain.Config) (evmtypes.BlockContext, *state.IntraBlockState, error) {
+	if err := api.BaseAPI.checkPruneHistory(tx, block.NumberU64()); err != nil {
+		return evmtypes.BlockContext{}, nil, err
+	}
+	return transactions.ComputeBlockEnvOnParent(ctx, api.engine(), block, chainConfig, api._blockReader, tx, api.historyV3(tx))
+}
+
+// retainingStateWriter writes the changes to the hashed state and adds the changed keys to the retain list, so that
+// the state root can be recomputed without updating the intermediate hashes
+type retainingStateWriter struct {
+	*state.DbStateWriter
+	rl *trie.RetainList
+}
+
+func (w *retainingStateWriter) UpdateAccountData(address common.Address, original, account *accounts.Account) error {
+	if err := w.DbStateWriter.UpdateAccountData(address, original, account); err != nil {
+		return err
+	}
+	addrHash, err := common2.HashData(address[:])
+	if err != nil {
+		return err
+	}
+	w.rl.AddKeyWithMarker(addrHash[:], false)
+	return nil
+}
+
+func (w *retainingStateWriter) DeleteAccount(address common.Address, original *accounts.Account) error {
+	if err := w.DbStateWriter.DeleteAccount(address, original); err != nil {
+		return err
+	}
+	addrHash, err := common2.HashData(address[:])
+	if err != nil {
+		return err
+	}
+	w.rl.AddKeyWithMarker(addrHash[:], true)
+	return nil
+}
+
+func (w *retainingStateWriter) WriteAccountStorage(address common.Address, incarnation uint64, key *common.Hash, original, value *uint256.Int) error {
+	if err := w.DbStateWriter.WriteAccountStorage(address, incarnation, key, original, value); err != nil {
+		return err
+	}
+	if *original == *value {
+		return nil
+	}
+	addrHash, err := common2.HashData(address[:])
+	if err != nil {
+		return err
+	}
+	seckey, err := common2.HashData(key[:])
+	if err != nil {
+		return err
+	}
+	w.rl.AddKeyWithMarker(dbutils.GenerateCompositeStorageKey(addrHash, incarnation, seckey), value.IsZero())
+	return nil
+}
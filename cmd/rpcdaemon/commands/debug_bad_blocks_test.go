@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	jsoniter "github.com/json-iterator/go"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/consensus/ethash"
+	"github.com/ledgerwatch/erigon/consensus/merge"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rlp"
+	"github.com/ledgerwatch/erigon/turbo/stages"
+)
+
+// proofOfStakeChain returns a post-merge chain, whose blocks pay no rewards, so the root after the last
+// transaction of a block is the block's state root
+func proofOfStakeChain(t *testing.T) (*stages.MockSentry, *core.ChainPack) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		config  = *params.AllProtocolChanges
+		// PUSH1 1 PUSH1 0 SSTORE
+		initCode = []byte{0x60, 0x01, 0x60, 0x00, 0x55}
+	)
+	config.ShanghaiTime = nil // the chain maker doesn't produce withdrawals
+	gspec := &types.Genesis{
+		Config: &config,
+		Alloc:  types.GenesisAlloc{address: {Balance: big.NewInt(params.Ether)}},
+	}
+	m := stages.MockWithGenesisEngine(t, gspec, merge.New(ethash.NewFaker()), true)
+	signer := types.LatestSigner(m.ChainConfig)
+	gasPrice := uint256.NewInt(2 * params.GWei)
+
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 3, func(i int, b *core.BlockGen) {
+		b.SetDifficulty(big.NewInt(0))
+		txn, err := types.SignTx(types.NewContractCreation(b.TxNonce(address), uint256.NewInt(0), 100_000, gasPrice, initCode), *signer, key)
+		require.NoError(t, err)
+		b.AddTx(txn)
+		for j := 0; j < 2; j++ {
+			txn, err := types.SignTx(types.NewTransaction(b.TxNonce(address), libcommon.Address{byte(i + 1), byte(j + 1)}, uint256.NewInt(1000), params.TxGas, gasPrice, nil), *signer, key)
+			require.NoError(t, err)
+			b.AddTx(txn)
+		}
+	})
+	require.NoError(t, err)
+	// Payloads are processed one at a time
+	for i := 0; i < chain.Length(); i++ {
+		require.NoError(t, m.InsertChain(chain.Slice(i, i+1), nil))
+	}
+	return m, chain
+}
+
+func TestIntermediateRoots(t *testing.T) {
+	m, chain := proofOfStakeChain(t)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+
+	for _, block := range chain.Blocks {
+		roots, err := api.IntermediateRoots(m.Ctx, block.Hash(), nil)
+		require.NoError(t, err)
+		require.Len(t, roots, len(block.Transactions()))
+		require.Equal(t, block.Root(), roots[len(roots)-1], "block %d", block.NumberU64())
+		for i := 1; i < len(roots); i++ {
+			require.NotEqual(t, roots[i-1], roots[i])
+		}
+	}
+}
+
+func TestBadBlocks(t *testing.T) {
+	m, chain := proofOfStakeChain(t)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+
+	block := chain.Blocks[1]
+	header := types.CopyHeader(block.Header())
+	header.Root = libcommon.HexToHash("0x01")
+	badBlock := block.WithSeal(header)
+	require.NoError(t, m.DB.Update(m.Ctx, func(tx kv.RwTx) error {
+		return rawdb.WriteBadBlock(tx, badBlock, "invalid merkle root")
+	}))
+
+	badBlocks, err := api.GetBadBlocks(m.Ctx)
+	require.NoError(t, err)
+	require.Len(t, badBlocks, 1)
+	require.Equal(t, badBlock.Hash(), badBlocks[0].Hash)
+	require.Equal(t, "invalid merkle root", badBlocks[0].Reason)
+	require.Equal(t, badBlock.Hash(), badBlocks[0].Block["hash"])
+	decoded := new(types.Block)
+	require.NoError(t, rlp.DecodeBytes(badBlocks[0].RLP, decoded))
+	require.Equal(t, badBlock.Hash(), decoded.Hash())
+
+	// The bad block has the same transactions and parent as the canonical one, so it executes the same way
+	trace := func(f func(stream *jsoniter.Stream) error) string {
+		var buf bytes.Buffer
+		stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
+		require.NoError(t, f(stream))
+		require.NoError(t, stream.Flush())
+		return buf.String()
+	}
+	expected := trace(func(stream *jsoniter.Stream) error {
+		return api.TraceBlockByHash(m.Ctx, block.Hash(), &tracers.TraceConfig{}, stream)
+	})
+	actual := trace(func(stream *jsoniter.Stream) error {
+		return api.TraceBadBlock(m.Ctx, badBlock.Hash(), &tracers.TraceConfig{}, stream)
+	})
+	require.JSONEq(t, expected, actual)
+
+	roots, err := api.IntermediateRoots(m.Ctx, badBlock.Hash(), nil)
+	require.NoError(t, err)
+	require.Len(t, roots, len(badBlock.Transactions()))
+	require.Equal(t, block.Root(), roots[len(roots)-1])
+
+	err = api.TraceBadBlock(context.Background(), block.Hash(), nil, jsoniter.NewStream(jsoniter.ConfigDefault, nil, 4096))
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestBadBlockStoredOnExecutionFailure(t *testing.T) {
+	m, chain := proofOfStakeChain(t)
+
+	// A child of the head whose header claims a wrong amount of gas is rejected by the execution
+	next, err := core.GenerateChain(m.ChainConfig, chain.TopBlock, m.Engine, m.DB, 1, func(i int, b *core.BlockGen) {
+		b.SetDifficulty(big.NewInt(0))
+	})
+	require.NoError(t, err)
+	header := types.CopyHeader(next.TopBlock.Header())
+	header.GasUsed = 1
+	next.Blocks[0] = next.TopBlock.WithSeal(header)
+	next.Headers[0] = header
+	next.TopBlock = next.Blocks[0]
+
+	tx, err := m.DB.BeginRw(m.Ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	require.Error(t, m.InsertChain(next, tx))
+
+	// The bad block is written through the stage transaction and outlives the unwind which follows
+	badBlock, err := rawdb.ReadBadBlock(tx, next.TopBlock.Hash())
+	require.NoError(t, err)
+	require.NotNil(t, badBlock)
+	require.Contains(t, badBlock.Reason, "gas used")
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+	"github.com/ledgerwatch/erigon/turbo/transactions"
+)
+
+// StandardTraceBlockToFile implements debug_standardTraceBlockToFile. Writes the EIP-3155 trace of each transaction
+// of the block into a separate file in the node's temporary directory and returns the names of the files.
+func (api *PrivateDebugAPIImpl) StandardTraceBlockToFile(ctx context.Context, hash common.Hash, config *tracers.StdTraceConfig) ([]string, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	block, err := api.blockByHashWithSenders(ctx, tx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %x not found", hash)
+	}
+	return api.standardTraceBlockToFile(ctx, tx, block, config)
+}
+
+// StandardTraceBadBlockToFile implements debug_standardTraceBadBlockToFile. Same as debug_standardTraceBlockToFile,
+// for a block rejected by the node.
+func (api *PrivateDebugAPIImpl) StandardTraceBadBlockToFile(ctx context.Context, hash common.Hash, config *tracers.StdTraceConfig) ([]string, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	badBlock, err := rawdb.ReadBadBlock(tx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if badBlock == nil {
+		return nil, fmt.Errorf("bad block %x not found", hash)
+	}
+	return api.standardTraceBlockToFile(ctx, tx, badBlock.Block, config)
+}
+
+func (api *PrivateDebugAPIImpl) standardTraceBlockToFile(ctx context.Context, tx kv.Tx, block *types.Block, config *tracers.StdTraceConfig) ([]string, error) {
+	chainConfig, err := api.chainConfig(tx)
+	if err != nil {
+		return nil, err
+	}
+	blockCtx, ibs, err := api.blockEnvOnParent(ctx, tx, block, chainConfig)
+	if err != nil {
+		return nil, err
+	}
+	// A remote rpcdaemon has no datadir
+	dir := api.dirs.Tmp
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return transactions.StandardTraceBlockToFiles(ctx, api.engine(), block, blockCtx, ibs, chainConfig, config, dir)
+}
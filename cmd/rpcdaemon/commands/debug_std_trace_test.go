@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+)
+
+// readTraceFile returns the lines of an EIP-3155 trace file, the last one being the summary of the transaction
+func readTraceFile(t *testing.T, name string) []map[string]interface{} {
+	f, err := os.Open(name)
+	require.NoError(t, err)
+	defer f.Close()
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func TestStandardTraceBlockToFile(t *testing.T) {
+	m, chain := proofOfStakeChain(t)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	block := chain.Blocks[1]
+
+	files, err := api.StandardTraceBlockToFile(m.Ctx, block.Hash(), nil)
+	require.NoError(t, err)
+	require.Len(t, files, len(block.Transactions()))
+	for i, file := range files {
+		require.Equal(t, m.Dirs.Tmp, filepath.Dir(file))
+		lines := readTraceFile(t, file)
+		require.NotEmpty(t, lines)
+		summary := lines[len(lines)-1]
+		require.Contains(t, summary, "gasUsed")
+		require.NotContains(t, summary, "error")
+		if i == 0 {
+			// The contract creation executes PUSH1 PUSH1 SSTORE STOP
+			require.Len(t, lines, 5)
+			require.Equal(t, "SSTORE", lines[2]["opName"])
+		} else {
+			// Plain transfers execute no code
+			require.Len(t, lines, 1)
+		}
+	}
+
+	// Only the requested transaction is traced
+	txn := block.Transactions()[0]
+	files, err = api.StandardTraceBlockToFile(m.Ctx, block.Hash(), &tracers.StdTraceConfig{TxHash: txn.Hash()})
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Len(t, readTraceFile(t, files[0]), 5)
+
+	_, err = api.StandardTraceBlockToFile(m.Ctx, block.Hash(), &tracers.StdTraceConfig{TxHash: libcommon.HexToHash("0x01")})
+	require.ErrorContains(t, err, "not found")
+}
+
+func TestStandardTraceBadBlockToFile(t *testing.T) {
+	m, chain := proofOfStakeChain(t)
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+
+	block := chain.Blocks[2]
+	header := types.CopyHeader(block.Header())
+	header.Root = libcommon.HexToHash("0x01")
+	badBlock := block.WithSeal(header)
+	require.NoError(t, m.DB.Update(m.Ctx, func(tx kv.RwTx) error {
+		return rawdb.WriteBadBlock(tx, badBlock, "invalid merkle root")
+	}))
+
+	files, err := api.StandardTraceBadBlockToFile(m.Ctx, badBlock.Hash(), nil)
+	require.NoError(t, err)
+	require.Len(t, files, len(badBlock.Transactions()))
+
+	_, err = api.StandardTraceBadBlockToFile(m.Ctx, block.Hash(), nil)
+	require.ErrorContains(t, err, "not found")
+}
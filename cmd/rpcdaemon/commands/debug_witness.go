@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+	"github.com/ledgerwatch/erigon/turbo/transactions"
+	"github.com/ledgerwatch/erigon/turbo/trie"
+)
+
+// ExecutionWitness implements debug_executionWitness. Returns the serialized witness of the state after the parent
+// of the block, which is needed to execute the block without the state.
+func (api *PrivateDebugAPIImpl) ExecutionWitness(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (hexutility.Bytes, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blockNr, hash, _, err := rpchelper.GetBlockNumber(blockNrOrHash, tx, api.filters)
+	if err != nil {
+		return nil, err
+	}
+	if blockNr == 0 {
+		return nil, fmt.Errorf("genesis block has no parent")
+	}
+	block, err := api.blockWithSenders(ctx, tx, hash, blockNr)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", blockNr)
+	}
+	chainConfig, err := api.chainConfig(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The state trie of the parent is computed by rewinding the hashed state in a memory batch
+	trieProgress, err := stages.GetStageProgress(tx, stages.IntermediateHashes)
+	if err != nil {
+		return nil, err
+	}
+	parentNum := blockNr - 1
+	if parentNum > trieProgress {
+		return nil, fmt.Errorf("state root of parent block %d is not computed yet, trie is at block %d", parentNum, trieProgress)
+	}
+	loadTrie := func(rl *trie.RetainList) (*trie.FlatDBTrieLoader, kv.Tx, func(), error) {
+		if parentNum == trieProgress {
+			return trie.NewFlatDBTrieLoader("debug_executionWitness", rl, nil, nil, false), tx, func() {}, nil
+		}
+		batch := api.newMemoryBatch(tx)
+		loader, err := api.unwindTrie(ctx, batch, parentNum, trieProgress, rl, "debug_executionWitness", log.Root())
+		if err != nil {
+			batch.Rollback()
+			return nil, nil, nil, err
+		}
+		return loader, batch, batch.Rollback, nil
+	}
+
+	witness, err := transactions.ComputeExecutionWitness(ctx, api.engine(), block, chainConfig, api._blockReader, tx, api.historyV3(tx), loadTrie)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := witness.WriteInto(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
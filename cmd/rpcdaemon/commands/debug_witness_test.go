@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/holiman/uint256"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/consensus/ethash"
+	"github.com/ledgerwatch/erigon/consensus/merge"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/params"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/stages"
+	"github.com/ledgerwatch/erigon/turbo/transactions"
+	"github.com/ledgerwatch/erigon/turbo/trie"
+)
+
+func TestExecutionWitness(t *testing.T) {
+	var (
+		key, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		address = crypto.PubkeyToAddress(key.PublicKey)
+		config  = *params.AllProtocolChanges
+		// The first word of the calldata is the storage key, the second one is the value:
+		// PUSH1 0x20 CALLDATALOAD PUSH1 0 CALLDATALOAD SSTORE
+		setter   = []byte{0x60, 0x20, 0x35, 0x60, 0x00, 0x35, 0x55}
+		contract = libcommon.HexToAddress("0xc0de")
+	)
+	config.ShanghaiTime = nil // the chain maker doesn't produce withdrawals
+	alloc := types.GenesisAlloc{
+		address:  {Balance: big.NewInt(params.Ether)},
+		contract: {Code: setter, Balance: new(big.Int), Storage: map[libcommon.Hash]libcommon.Hash{}},
+	}
+	for i := 1; i <= 32; i++ {
+		alloc[contract].Storage[libcommon.BigToHash(big.NewInt(int64(i)))] = libcommon.BigToHash(big.NewInt(1))
+		// Empty accounts are removed when touched
+		alloc[libcommon.BigToAddress(big.NewInt(int64(0x1000+i)))] = types.GenesisAccount{Balance: new(big.Int)}
+	}
+	gspec := &types.Genesis{Config: &config, Alloc: alloc}
+	m := stages.MockWithGenesisEngine(t, gspec, merge.New(ethash.NewFaker()), true)
+	signer := types.LatestSigner(m.ChainConfig)
+	gasPrice := uint256.NewInt(2 * params.GWei)
+
+	chain, err := core.GenerateChain(m.ChainConfig, m.Genesis, m.Engine, m.DB, 3, func(i int, b *core.BlockGen) {
+		b.SetDifficulty(big.NewInt(0))
+		send := func(to libcommon.Address, value uint64, data []byte) {
+			txn, err := types.SignTx(types.NewTransaction(b.TxNonce(address), to, uint256.NewInt(value), 100_000, gasPrice, data), *signer, key)
+			require.NoError(t, err)
+			b.AddTx(txn)
+		}
+		for j := 0; j < 10; j++ {
+			slot := i*10 + j + 1
+			// Clear a slot, touch an empty account and send to a new one
+			send(contract, 0, append(libcommon.BigToHash(big.NewInt(int64(slot))).Bytes(), make([]byte, 32)...))
+			send(libcommon.BigToAddress(big.NewInt(int64(0x1000+slot))), 0, nil)
+			send(libcommon.Address{byte(i + 1), byte(j + 1)}, 1000, nil)
+		}
+		send(contract, 0, append(libcommon.BigToHash(big.NewInt(int64(100+i))).Bytes(), libcommon.BigToHash(big.NewInt(2)).Bytes()...))
+	})
+	require.NoError(t, err)
+	for i := 0; i < chain.Length(); i++ {
+		require.NoError(t, m.InsertChain(chain.Slice(i, i+1), nil))
+	}
+
+	api := NewPrivateDebugAPI(newBaseApiForTest(m), m.DB, 0)
+	tx, err := m.DB.BeginRo(m.Ctx)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	getHeader := func(hash libcommon.Hash, n uint64) *types.Header {
+		h, _ := m.BlockReader.Header(m.Ctx, tx, hash, n)
+		return h
+	}
+	parentRoot := m.Genesis.Root()
+	for _, block := range chain.Blocks {
+		encoded, err := api.ExecutionWitness(m.Ctx, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(block.NumberU64())))
+		require.NoError(t, err)
+		witness, err := trie.NewWitnessFromReader(bytes.NewReader(encoded), false)
+		require.NoError(t, err)
+
+		root, err := transactions.ExecuteBlockStateless(m.Engine, block, parentRoot, witness, m.ChainConfig, getHeader, stagedsync.NewChainReaderImpl(m.ChainConfig, tx, m.BlockReader))
+		require.NoError(t, err)
+		require.Equal(t, block.Root(), root, "block %d", block.NumberU64())
+
+		// The witness is the state of the parent only
+		_, err = transactions.ExecuteBlockStateless(m.Engine, block, block.Root(), witness, m.ChainConfig, getHeader, stagedsync.NewChainReaderImpl(m.ChainConfig, tx, m.BlockReader))
+		require.ErrorContains(t, err, "witness has state root")
+		parentRoot = block.Root()
+	}
+
+	_, err = api.ExecutionWitness(m.Ctx, rpc.BlockNumberOrHashWithNumber(0))
+	require.ErrorContains(t, err, "genesis")
+}
@@ -89,7 +89,7 @@ type EthAPI interface {
 	GasPrice(_ context.Context) (*hexutil.Big, error)
 
 	// Sending related (see ./eth_call.go)
-	Call(ctx context.Context, args ethapi2.CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *ethapi2.StateOverrides) (hexutility.Bytes, error)
+	Call(ctx context.Context, args ethapi2.CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *ethapi2.StateOverrides, blockOverrides *ethapi2.BlockOverrides) (hexutility.Bytes, error)
 	EstimateGas(ctx context.Context, argsOrNil *ethapi2.CallArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error)
 	SendRawTransaction(ctx context.Context, encodedTx hexutility.Bytes) (common.Hash, error)
 	SendTransaction(_ context.Context, txObject interface{}) (common.Hash, error)
@@ -97,6 +97,7 @@ type EthAPI interface {
 	SignTransaction(_ context.Context, txObject interface{}) (common.Hash, error)
 	GetProof(ctx context.Context, address common.Address, storageKeys []common.Hash, blockNr rpc.BlockNumberOrHash) (*accounts.AccProofResult, error)
 	CreateAccessList(ctx context.Context, args ethapi2.CallArgs, blockNrOrHash *rpc.BlockNumberOrHash, optimizeGas *bool) (*accessListResult, error)
+	SimulateV1(ctx context.Context, opts SimulationOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]map[string]interface{}, error)
 
 	// Mining related (see ./eth_mining.go)
 	Coinbase(ctx context.Context) (common.Address, error)
@@ -324,7 +325,13 @@ type APIImpl struct {
 	db              kv.RoDB
 	GasCap          uint64
 	ReturnDataLimit int
-	logger          log.Logger
+	// MaxGetProofRewindBlockCount limits how far behind the head eth_getProof
+	// may rewind the hashed state to compute proofs, see HttpCfg.GetProofMaxRewind
+	MaxGetProofRewindBlockCount uint64
+	// MaxLogsReplayBlockCount limits how many past blocks a logs subscription
+	// with fromBlock may replay, see HttpCfg.LogsReplayMaxBlocks
+	MaxLogsReplayBlockCount uint64
+	logger                  log.Logger
 }
 
 // NewEthAPI returns APIImpl instance
@@ -342,7 +349,10 @@ func NewEthAPI(base *BaseAPI, db kv.RoDB, eth rpchelper.ApiBackend, txPool txpoo
 		gasCache:        NewGasPriceCache(),
 		GasCap:          gascap,
 		ReturnDataLimit: returnDataLimit,
-		logger:          logger,
+
+		MaxGetProofRewindBlockCount: defaultMaxGetProofRewindBlockCount,
+		MaxLogsReplayBlockCount:     defaultMaxLogsReplayBlockCount,
+		logger:                      logger,
 	}
 }
 
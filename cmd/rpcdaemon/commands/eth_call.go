@@ -15,7 +15,9 @@ import (
 	"github.com/ledgerwatch/erigon-lib/gointerfaces"
 	txpool_proto "github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/iter"
 	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon-lib/kv/order"
 	types2 "github.com/ledgerwatch/erigon-lib/types"
 
 	"github.com/ledgerwatch/erigon/common/hexutil"
@@ -38,7 +40,7 @@ import (
 var latestNumOrHash = rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
 
 // Call implements eth_call. Executes a new message call immediately without creating a transaction on the block chain.
-func (api *APIImpl) Call(ctx context.Context, args ethapi2.CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *ethapi2.StateOverrides) (hexutility.Bytes, error) {
+func (api *APIImpl) Call(ctx context.Context, args ethapi2.CallArgs, blockNrOrHash rpc.BlockNumberOrHash, overrides *ethapi2.StateOverrides, blockOverrides *ethapi2.BlockOverrides) (hexutility.Bytes, error) {
 	tx, err := api.db.BeginRo(ctx)
 	if err != nil {
 		return nil, err
@@ -72,7 +74,7 @@ func (api *APIImpl) Call(ctx context.Context, args ethapi2.CallArgs, blockNrOrHa
 		return nil, err
 	}
 	header := block.HeaderNoCopy()
-	result, err := transactions.DoCall(ctx, engine, args, tx, blockNrOrHash, header, overrides, api.GasCap, chainConfig, stateReader, api._blockReader, api.evmCallTimeout)
+	result, err := transactions.DoCall(ctx, engine, args, tx, blockNrOrHash, header, overrides, blockOverrides, api.GasCap, chainConfig, stateReader, api._blockReader, api.evmCallTimeout)
 	if err != nil {
 		return nil, err
 	}
@@ -306,19 +308,20 @@ func (api *APIImpl) EstimateGas(ctx context.Context, argsOrNil *ethapi2.CallArgs
 	return hexutil.Uint64(hi), nil
 }
 
-// maxGetProofRewindBlockCount limits the number of blocks into the past that
-// GetProof will allow computing proofs.  Because we must rewind the hash state
-// and re-compute the state trie, the further back in time the request, the more
-// computationally intensive the operation becomes.  The staged sync code
-// assumes that if more than 100_000 blocks are skipped, that the entire trie
-// should be re-computed. Re-computing the entire trie will currently take ~15
-// minutes on mainnet.  The current limit has been chosen arbitrarily as
-// 'useful' without likely being overly computationally intense.  This parameter
-// could possibly be made configurable in the future if needed.
-var maxGetProofRewindBlockCount uint64 = 1_000
-
-// GetProof is partially implemented; no Storage proofs, and proofs must be for
-// blocks within maxGetProofRewindBlockCount blocks of the head.
+// defaultMaxGetProofRewindBlockCount is the number of blocks into the past that
+// GetProof allows computing proofs for unless --rpc.getproof.maxrewind is set.
+// It has been chosen arbitrarily as 'useful' without likely being overly
+// computationally intense.
+const defaultMaxGetProofRewindBlockCount uint64 = 1_000
+
+// GetProof implements eth_getProof (EIP-1186), proving the account and the
+// given storage slots against the state root of the requested block. Because
+// the hashed state must be rewound and the trie re-computed for past blocks,
+// the further back in time the request, the more computationally intensive it
+// becomes. The staged sync code assumes that if more than 100_000 blocks are
+// skipped, the entire trie should be re-computed, which takes ~15 minutes on
+// mainnet, so requests are limited to MaxGetProofRewindBlockCount blocks
+// behind the head (see --rpc.getproof.maxrewind).
 func (api *APIImpl) GetProof(ctx context.Context, address libcommon.Address, storageKeys []libcommon.Hash, blockNrOrHash rpc.BlockNumberOrHash) (*accounts.AccProofResult, error) {
 
 	tx, err := api.db.BeginRo(ctx)
@@ -326,9 +329,6 @@ func (api *APIImpl) GetProof(ctx context.Context, address libcommon.Address, sto
 		return nil, err
 	}
 	defer tx.Rollback()
-	if api.historyV3(tx) {
-		return nil, fmt.Errorf("not supported by Erigon3")
-	}
 
 	blockNr, _, _, err := rpchelper.GetBlockNumber(blockNrOrHash, tx, api.filters)
 	if err != nil {
@@ -353,22 +353,12 @@ func (api *APIImpl) GetProof(ctx context.Context, address libcommon.Address, sto
 	rl := trie.NewRetainList(0)
 	var loader *trie.FlatDBTrieLoader
 	if blockNr < latestBlock {
-		if latestBlock-blockNr > maxGetProofRewindBlockCount {
-			return nil, fmt.Errorf("requested block is too old, block must be within %d blocks of the head block number (currently %d)", maxGetProofRewindBlockCount, latestBlock)
+		if latestBlock-blockNr > api.MaxGetProofRewindBlockCount {
+			return nil, fmt.Errorf("requested block is too old, block must be within %d blocks of the head block number (currently %d)", api.MaxGetProofRewindBlockCount, latestBlock)
 		}
-		batch := memdb.NewMemoryBatch(tx, api.dirs.Tmp)
+		batch := api.newMemoryBatch(tx)
 		defer batch.Rollback()
-
-		unwindState := &stagedsync.UnwindState{UnwindPoint: blockNr}
-		stageState := &stagedsync.StageState{BlockNumber: latestBlock}
-
-		hashStageCfg := stagedsync.StageHashStateCfg(nil, api.dirs, api.historyV3(batch))
-		if err := stagedsync.UnwindHashStateStage(unwindState, stageState, batch, hashStageCfg, ctx, api.logger); err != nil {
-			return nil, err
-		}
-
-		interHashStageCfg := stagedsync.StageTrieCfg(nil, false, false, false, api.dirs.Tmp, api._blockReader, nil, api.historyV3(batch), api._agg)
-		loader, err = stagedsync.UnwindIntermediateHashesForTrieLoader("eth_getProof", rl, unwindState, stageState, batch, interHashStageCfg, nil, nil, ctx.Done(), api.logger)
+		loader, err = api.unwindTrie(ctx, batch, blockNr, latestBlock, rl, "eth_getProof", api.logger)
 		if err != nil {
 			return nil, err
 		}
@@ -405,6 +395,63 @@ func (api *APIImpl) GetProof(ctx context.Context, address libcommon.Address, sto
 	return pr.ProofResult()
 }
 
+// newMemoryBatch returns a memory batch over tx. Its writes are discarded on Rollback.
+func (api *BaseAPI) newMemoryBatch(tx kv.Tx) kv.RwTx {
+	memBatch := memdb.NewMemoryBatch(tx, api.dirs.Tmp)
+	if ttx, ok := tx.(kv.TemporalTx); ok {
+		// unwinding on Erigon3 reads the changes from the history
+		return &temporalMemoryBatch{MemoryMutation: memBatch, history: ttx}
+	}
+	return memBatch
+}
+
+// unwindTrie unwinds the hashed state in the memory batch from latestBlock to blockNr. The keys changed in between
+// are added to rl and the returned loader computes the state root of blockNr.
+func (api *BaseAPI) unwindTrie(ctx context.Context, batch kv.RwTx, blockNr, latestBlock uint64, rl *trie.RetainList, logPrefix string, logger log.Logger) (*trie.FlatDBTrieLoader, error) {
+	unwindState := &stagedsync.UnwindState{UnwindPoint: blockNr}
+	stageState := &stagedsync.StageState{BlockNumber: latestBlock}
+
+	hashStageCfg := stagedsync.StageHashStateCfg(nil, api.dirs, api.historyV3(batch))
+	if err := stagedsync.UnwindHashStateStage(unwindState, stageState, batch, hashStageCfg, ctx, logger); err != nil {
+		return nil, err
+	}
+
+	interHashStageCfg := stagedsync.StageTrieCfg(nil, false, false, false, api.dirs.Tmp, api._blockReader, nil, api.historyV3(batch), api._agg)
+	return stagedsync.UnwindIntermediateHashesForTrieLoader(logPrefix, rl, unwindState, stageState, batch, interHashStageCfg, nil, nil, ctx.Done(), logger)
+}
+
+// temporalMemoryBatch is a memory batch over a temporal transaction. Writes go
+// to the batch, while history and domain reads are served by the underlying
+// transaction, which the batch never modifies.
+type temporalMemoryBatch struct {
+	*memdb.MemoryMutation
+	history kv.TemporalTx
+}
+
+func (b *temporalMemoryBatch) DomainGet(name kv.Domain, k, k2 []byte) ([]byte, bool, error) {
+	return b.history.DomainGet(name, k, k2)
+}
+
+func (b *temporalMemoryBatch) DomainGetAsOf(name kv.Domain, k, k2 []byte, ts uint64) ([]byte, bool, error) {
+	return b.history.DomainGetAsOf(name, k, k2, ts)
+}
+
+func (b *temporalMemoryBatch) HistoryGet(name kv.History, k []byte, ts uint64) ([]byte, bool, error) {
+	return b.history.HistoryGet(name, k, ts)
+}
+
+func (b *temporalMemoryBatch) IndexRange(name kv.InvertedIdx, k []byte, fromTs, toTs int, asc order.By, limit int) (iter.U64, error) {
+	return b.history.IndexRange(name, k, fromTs, toTs, asc, limit)
+}
+
+func (b *temporalMemoryBatch) HistoryRange(name kv.History, fromTs, toTs int, asc order.By, limit int) (iter.KV, error) {
+	return b.history.HistoryRange(name, fromTs, toTs, asc, limit)
+}
+
+func (b *temporalMemoryBatch) DomainRange(name kv.Domain, fromKey, toKey []byte, ts uint64, asc order.By, limit int) (iter.KV, error) {
+	return b.history.DomainRange(name, fromKey, toKey, ts, asc, limit)
+}
+
 func (api *APIImpl) tryBlockFromLru(hash libcommon.Hash) *types.Block {
 	var block *types.Block
 	if api.blocksLRU != nil {
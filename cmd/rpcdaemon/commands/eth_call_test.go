@@ -63,7 +63,7 @@ func TestEthCallNonCanonical(t *testing.T) {
 	if _, err := api.Call(context.Background(), ethapi.CallArgs{
 		From: &from,
 		To:   &to,
-	}, rpc.BlockNumberOrHashWithHash(libcommon.HexToHash("0x3fcb7c0d4569fddc89cbea54b42f163e0c789351d98810a513895ab44b47020b"), true), nil); err != nil {
+	}, rpc.BlockNumberOrHashWithHash(libcommon.HexToHash("0x3fcb7c0d4569fddc89cbea54b42f163e0c789351d98810a513895ab44b47020b"), true), nil, nil); err != nil {
 		if fmt.Sprintf("%v", err) != "hash 3fcb7c0d4569fddc89cbea54b42f163e0c789351d98810a513895ab44b47020b is not currently canonical" {
 			t.Errorf("wrong error: %v", err)
 		}
@@ -85,19 +85,48 @@ func TestEthCallToPrunedBlock(t *testing.T) {
 		From: &bankAddress,
 		To:   &contractAddress,
 		Data: &callDataBytes,
-	}, rpc.BlockNumberOrHashWithNumber(ethCallBlockNumber), nil); err != nil {
+	}, rpc.BlockNumberOrHashWithNumber(ethCallBlockNumber), nil, nil); err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
 
-func TestGetProof(t *testing.T) {
-	maxGetProofRewindBlockCount = 1 // Note, this is unsafe for parallel tests, but, this test is the only consumer for now
+func TestEthCallBlockOverrides(t *testing.T) {
+	m, bankAddress, _ := chainWithDeployedContract(t)
+	api := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, 100_000, log.New())
+
+	// NUMBER PUSH1 0 MSTORE TIMESTAMP PUSH1 32 MSTORE COINBASE PUSH1 64 MSTORE PUSH1 96 PUSH1 0 RETURN
+	code := hexutility.Bytes{0x43, 0x60, 0x00, 0x52, 0x42, 0x60, 0x20, 0x52, 0x41, 0x60, 0x40, 0x52, 0x60, 0x60, 0x60, 0x00, 0xf3}
+	to := libcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	coinbase := libcommon.HexToAddress("0x2000000000000000000000000000000000000002")
+	number := hexutil.Big(*big.NewInt(1000))
+	timestamp := hexutil.Uint64(1_700_000_000)
+
+	res, err := api.Call(context.Background(), ethapi.CallArgs{
+		From: &bankAddress,
+		To:   &to,
+	}, latestNumOrHash, &ethapi.StateOverrides{to: {Code: &code}}, &ethapi.BlockOverrides{
+		Number:   &number,
+		Time:     &timestamp,
+		Coinbase: &coinbase,
+	})
+	require.NoError(t, err)
+	require.Len(t, res, 96)
+	assert.Equal(t, uint64(1000), new(big.Int).SetBytes(res[:32]).Uint64())
+	assert.Equal(t, uint64(timestamp), new(big.Int).SetBytes(res[32:64]).Uint64())
+	assert.Equal(t, coinbase, libcommon.BytesToAddress(res[64:]))
 
+	overflow := hexutil.Big(*new(big.Int).Lsh(big.NewInt(1), 64))
+	_, err = api.Call(context.Background(), ethapi.CallArgs{
+		From: &bankAddress,
+		To:   &to,
+	}, latestNumOrHash, nil, &ethapi.BlockOverrides{Number: &overflow})
+	assert.ErrorContains(t, err, "overflows uint64")
+}
+
+func TestGetProof(t *testing.T) {
 	m, bankAddr, contractAddr := chainWithDeployedContract(t)
-	if m.HistoryV3 {
-		t.Skip("not supported by Erigon3")
-	}
 	api := NewEthAPI(newBaseApiForTest(m), m.DB, nil, nil, nil, 5000000, 100_000, log.New())
+	api.MaxGetProofRewindBlockCount = 1
 
 	key := func(b byte) libcommon.Hash {
 		result := libcommon.Hash{}
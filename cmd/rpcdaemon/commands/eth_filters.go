@@ -2,6 +2,8 @@ package commands
 
 import (
 	"context"
+	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/ledgerwatch/log/v3"
@@ -243,7 +245,8 @@ func (api *APIImpl) NewPendingTransactionsWithBody(ctx context.Context) (*rpc.Su
 	return rpcSub, nil
 }
 
-// Logs send a notification each time a new log appears.
+// Logs send a notification each time a new log appears. If the criteria have fromBlock, the logs of the canonical
+// blocks from that block on are sent first, so a subscriber can resume after the last block it has seen.
 func (api *APIImpl) Logs(ctx context.Context, crit filters.FilterCriteria) (*rpc.Subscription, error) {
 	if api.filters == nil {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
@@ -252,27 +255,77 @@ func (api *APIImpl) Logs(ctx context.Context, crit filters.FilterCriteria) (*rpc
 	if !supported {
 		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
 	}
+	replay := crit.BlockHash == nil && crit.FromBlock != nil && crit.FromBlock.Sign() >= 0
+	if replay {
+		if _, err := api.logsReplayEnd(ctx, crit.FromBlock.Uint64()); err != nil {
+			return &rpc.Subscription{}, err
+		}
+	}
 
 	rpcSub := notifier.CreateSubscription()
 
 	go func() {
 		defer debug.LogPanic()
+		// The live logs are subscribed to before the historical ones are read, so that none is missed in between
 		logs, id := api.filters.SubscribeLogs(128, crit)
 		defer api.filters.UnsubscribeLogs(id)
 
+		notify := func(lg *types.Log) {
+			if err := notifier.Notify(rpcSub.ID, lg); err != nil {
+				log.Warn("[rpc] error while notifying subscription", "err", err)
+			}
+		}
+		var pending []*types.Log
+		var replayed chan replayedLogs
+		if replay {
+			replayed = make(chan replayedLogs, 1)
+			go func() {
+				defer debug.LogPanic()
+				historical, to, err := api.historicalLogs(crit)
+				replayed <- replayedLogs{historical, to, err}
+			}()
+		}
+		// The live logs of the replayed blocks are skipped, unless these blocks are unwound
+		var skipTo uint64
 		for {
 			select {
 			case h, ok := <-logs:
 				if h != nil {
-					err := notifier.Notify(rpcSub.ID, h)
-					if err != nil {
-						log.Warn("[rpc] error while notifying subscription", "err", err)
+					switch {
+					case replayed != nil:
+						pending = append(pending, h)
+					case h.Removed:
+						if h.BlockNumber <= skipTo {
+							skipTo = h.BlockNumber - 1
+						}
+						notify(h)
+					case h.BlockNumber > skipTo:
+						notify(h)
 					}
 				}
 				if !ok {
 					log.Warn("[rpc] log channel was closed")
 					return
 				}
+			case r := <-replayed:
+				replayed = nil
+				if r.err != nil {
+					log.Warn("[rpc] error while reading historical logs of subscription", "err", r.err)
+					return
+				}
+				for _, lg := range r.logs {
+					notify(lg)
+				}
+				skipTo = r.to
+				for _, h := range pending {
+					if h.Removed && h.BlockNumber <= skipTo {
+						skipTo = h.BlockNumber - 1
+					}
+					if h.Removed || h.BlockNumber > skipTo {
+						notify(h)
+					}
+				}
+				pending = nil
 			case <-rpcSub.Err():
 				return
 			}
@@ -281,3 +334,51 @@ func (api *APIImpl) Logs(ctx context.Context, crit filters.FilterCriteria) (*rpc
 
 	return rpcSub, nil
 }
+
+type replayedLogs struct {
+	logs []*types.Log
+	to   uint64
+	err  error
+}
+
+// defaultMaxLogsReplayBlockCount is the number of past blocks whose logs a logs
+// subscription may replay unless --rpc.logs.replay.maxblocks is set. The replayed
+// logs are read at once, so the range is bounded like the one of eth_getLogs.
+const defaultMaxLogsReplayBlockCount uint64 = 10_000
+
+// logsReplayEnd returns the latest executed block, up to which the logs from the given block are replayed. It fails
+// if there are more than MaxLogsReplayBlockCount blocks to replay.
+func (api *APIImpl) logsReplayEnd(ctx context.Context, from uint64) (uint64, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	latest, _, _, err := rpchelper.GetBlockNumber(rpc.BlockNumberOrHashWithNumber(rpc.LatestExecutedBlockNumber), tx, nil)
+	if err != nil {
+		return 0, err
+	}
+	if from <= latest && latest-from >= api.MaxLogsReplayBlockCount {
+		return 0, fmt.Errorf("fromBlock is too old, it must be within %d blocks of the latest block (currently %d)", api.MaxLogsReplayBlockCount, latest)
+	}
+	return latest, nil
+}
+
+// historicalLogs returns the logs matching the criteria from fromBlock up to the latest executed block, and the
+// number of that block
+func (api *APIImpl) historicalLogs(crit filters.FilterCriteria) ([]*types.Log, uint64, error) {
+	ctx := context.Background()
+	latest, err := api.logsReplayEnd(ctx, crit.FromBlock.Uint64())
+	if err != nil {
+		return nil, 0, err
+	}
+	if crit.FromBlock.Uint64() > latest {
+		return nil, latest, nil
+	}
+	crit.ToBlock = new(big.Int).SetUint64(latest)
+	logs, err := api.GetLogs(ctx, crit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return logs, latest, nil
+}
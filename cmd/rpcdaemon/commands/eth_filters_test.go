@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"math/big"
 	"math/rand"
 	"sync"
 	"testing"
@@ -8,14 +9,20 @@ import (
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	types2 "github.com/ledgerwatch/erigon-lib/gointerfaces/types"
 
+	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/rpc/rpccfg"
 
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
 	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/eth/filters"
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
 	"github.com/ledgerwatch/erigon/turbo/stages"
@@ -100,3 +107,71 @@ func TestLogsSubscribeAndUnsubscribe_WithoutConcurrentMapIssue(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestLogsSubscriptionFromBlock(t *testing.T) {
+	m, chain, _ := rpcdaemontest.CreateTestSentry(t)
+	ff := rpchelper.New(m.Ctx, nil, nil, nil, func() {}, m.Log)
+	baseApi := NewBaseApi(ff, kvcache.New(kvcache.DefaultCoherentConfig), m.BlockReader, m.HistoryV3Components(), false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
+	api := NewEthAPI(baseApi, m.DB, nil, nil, nil, 5000000, 100_000, log.New())
+
+	server := rpc.NewServer(50, false, true, log.New())
+	require.NoError(t, server.RegisterName("eth", api))
+	client := rpc.DialInProc(server, log.New())
+	defer client.Close()
+
+	expected, err := api.GetLogs(m.Ctx, filters.FilterCriteria{FromBlock: big.NewInt(10)})
+	require.NoError(t, err)
+	require.NotEmpty(t, expected)
+	latest := chain.TopBlock.NumberU64()
+
+	logs := make(chan *types.Log, 128)
+	sub, err := client.EthSubscribe(m.Ctx, logs, "logs", map[string]any{"fromBlock": "0xa"})
+	require.NoError(t, err)
+	defer sub.Unsubscribe()
+	next := func() *types.Log {
+		select {
+		case lg := <-logs:
+			return lg
+		case err := <-sub.Err():
+			t.Fatal(err)
+		case <-time.After(10 * time.Second):
+			t.Fatal("timeout")
+		}
+		return nil
+	}
+	for _, lg := range expected {
+		got := next()
+		require.Equal(t, lg.BlockNumber, got.BlockNumber)
+		require.Equal(t, lg.TxHash, got.TxHash)
+		require.Equal(t, lg.Index, got.Index)
+	}
+
+	// The live logs of the replayed blocks are only sent if these blocks are unwound
+	live := func(blockNum uint64, removed bool) {
+		ff.OnNewLogs(&remote.SubscribeLogsReply{
+			Address:         gointerfaces.ConvertAddressToH160(libcommon.Address{1}),
+			BlockHash:       gointerfaces.ConvertHashToH256(libcommon.Hash{}),
+			BlockNumber:     blockNum,
+			Topics:          []*types2.H256{gointerfaces.ConvertHashToH256(libcommon.Hash{2})},
+			TransactionHash: gointerfaces.ConvertHashToH256(libcommon.Hash{}),
+			Removed:         removed,
+		})
+	}
+	live(latest, false)
+	live(latest+1, false)
+	live(latest, true)
+	live(latest, false)
+	for _, want := range []struct {
+		blockNum uint64
+		removed  bool
+	}{{latest + 1, false}, {latest, true}, {latest, false}} {
+		got := next()
+		require.Equal(t, want.blockNum, got.BlockNumber)
+		require.Equal(t, want.removed, got.Removed)
+	}
+
+	// Replaying more blocks than the limit is refused
+	api.MaxLogsReplayBlockCount = latest - 10
+	_, err = client.EthSubscribe(m.Ctx, make(chan *types.Log), "logs", map[string]any{"fromBlock": "0xa"})
+	require.ErrorContains(t, err, "fromBlock is too old")
+}
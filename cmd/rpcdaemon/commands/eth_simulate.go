@@ -0,0 +1,450 @@
+package commands
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/consensus/misc"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/rpc"
+	ethapi2 "github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+	"github.com/ledgerwatch/erigon/turbo/transactions"
+)
+
+const (
+	// maxSimulateBlocks is the maximum number of blocks (including the gap-filling ones) eth_simulateV1 executes
+	maxSimulateBlocks = 256
+	// simulateTimestampIncrement is the time between simulated blocks when no timestamp is given
+	simulateTimestampIncrement = 12
+)
+
+var (
+	// transferLogAddress is the pseudo-address ERC-7528 assigns to the native currency
+	transferLogAddress = libcommon.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+	// transferLogTopic is keccak256("Transfer(address,address,uint256)")
+	transferLogTopic = libcommon.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+)
+
+// SimulationOpts is the request object of eth_simulateV1
+type SimulationOpts struct {
+	BlockStateCalls        []SimulatedBlock `json:"blockStateCalls"`
+	TraceTransfers         bool             `json:"traceTransfers"`
+	Validation             bool             `json:"validation"`
+	ReturnFullTransactions bool             `json:"returnFullTransactions"`
+}
+
+// SimulatedBlock is a block to be built on top of the previous one out of the given calls
+type SimulatedBlock struct {
+	BlockOverrides *SimBlockOverrides      `json:"blockOverrides"`
+	StateOverrides *ethapi2.StateOverrides `json:"stateOverrides"`
+	Calls          []ethapi2.CallArgs      `json:"calls"`
+}
+
+// SimBlockOverrides are the header fields of a simulated block which may be set by the caller
+type SimBlockOverrides struct {
+	Number        *hexutil.Big       `json:"number"`
+	Difficulty    *hexutil.Big       `json:"difficulty"`
+	Time          *hexutil.Uint64    `json:"time"`
+	GasLimit      *hexutil.Uint64    `json:"gasLimit"`
+	FeeRecipient  *libcommon.Address `json:"feeRecipient"`
+	PrevRandao    *libcommon.Hash    `json:"prevRandao"`
+	BaseFeePerGas *hexutil.Big       `json:"baseFeePerGas"`
+}
+
+type simCallResult struct {
+	ReturnData hexutility.Bytes `json:"returnData"`
+	Logs       []*types.Log     `json:"logs"`
+	GasUsed    hexutil.Uint64   `json:"gasUsed"`
+	Status     hexutil.Uint64   `json:"status"`
+	Error      *simCallError    `json:"error,omitempty"`
+}
+
+type simBlockResult struct {
+	block   *types.Block
+	calls   []simCallResult
+	senders []libcommon.Address
+}
+
+type simCallError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+// SimulateV1 implements eth_simulateV1. Executes a sequence of blocks on top of the given one, each made of
+// message calls with optional block and state overrides. State is chained from one simulated block to the next,
+// so later calls observe the effects of earlier ones.
+//
+// The returned blocks are not sealed: their stateRoot is left empty, as computing it would require committing
+// the simulated state.
+func (api *APIImpl) SimulateV1(ctx context.Context, opts SimulationOpts, blockNrOrHash *rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	if len(opts.BlockStateCalls) == 0 {
+		return nil, errors.New("empty input")
+	}
+	if blockNrOrHash == nil {
+		blockNrOrHash = &latestNumOrHash
+	}
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	chainConfig, err := api.chainConfig(tx)
+	if err != nil {
+		return nil, err
+	}
+	base, err := headerByNumberOrHash(ctx, tx, *blockNrOrHash, api)
+	if err != nil {
+		return nil, err
+	}
+	if base == nil {
+		return nil, fmt.Errorf("block %v not found", blockNrOrHash)
+	}
+	blocks, err := sanitizeSimulatedBlocks(base, opts.BlockStateCalls)
+	if err != nil {
+		return nil, err
+	}
+
+	stateReader, err := rpchelper.CreateStateReader(ctx, tx, *blockNrOrHash, 0, api.filters, api.stateCache, api.historyV3(tx), chainConfig.ChainName)
+	if err != nil {
+		return nil, err
+	}
+	ibs := state.New(stateReader)
+
+	defer func(start time.Time) { log.Trace("Executing EVM simulateV1 finished", "runtime", time.Since(start)) }(time.Now())
+
+	var cancel context.CancelFunc
+	if api.evmCallTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, api.evmCallTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	// BLOCKHASH must see the simulated blocks, everything older is served from the database
+	simulatedHashes := make(map[uint64]libcommon.Hash)
+	canonicalHash := transactions.MakeHeaderGetter(true, tx, api._blockReader)
+	getHash := func(n uint64) libcommon.Hash {
+		if hash, ok := simulatedHashes[n]; ok {
+			return hash
+		}
+		return canonicalHash(n)
+	}
+
+	results := make([]map[string]interface{}, 0, len(blocks))
+	parent := base
+	for _, block := range blocks {
+		res, err := api.simulateBlock(ctx, ibs, chainConfig, parent, block, getHash, &opts)
+		if err != nil {
+			return nil, err
+		}
+		simulatedHashes[res.block.NumberU64()] = res.block.Hash()
+
+		fields, err := ethapi2.RPCMarshalBlock(res.block, true, false, map[string]interface{}{"calls": res.calls})
+		if err != nil {
+			return nil, err
+		}
+		if opts.ReturnFullTransactions {
+			fields["transactions"] = simulatedRPCTransactions(res)
+		}
+		results = append(results, fields)
+		parent = res.block.Header()
+	}
+	return results, nil
+}
+
+// sanitizeSimulatedBlocks checks that block numbers and timestamps are increasing and fills the gaps between
+// requested block numbers with empty blocks.
+func sanitizeSimulatedBlocks(base *types.Header, blocks []SimulatedBlock) ([]SimulatedBlock, error) {
+	res := make([]SimulatedBlock, 0, len(blocks))
+	prevNumber := new(big.Int).Set(base.Number)
+	prevTime := base.Time
+	for _, block := range blocks {
+		if block.BlockOverrides == nil {
+			block.BlockOverrides = &SimBlockOverrides{}
+		}
+		if block.BlockOverrides.Number == nil {
+			block.BlockOverrides.Number = (*hexutil.Big)(new(big.Int).Add(prevNumber, big.NewInt(1)))
+		}
+		number := block.BlockOverrides.Number.ToInt()
+		if number.Cmp(prevNumber) <= 0 {
+			return nil, fmt.Errorf("block numbers must be in order: %d <= %d", number, prevNumber)
+		}
+		if gap := new(big.Int).Sub(number, prevNumber); gap.Cmp(big.NewInt(maxSimulateBlocks)) > 0 || len(res)+int(gap.Int64()) > maxSimulateBlocks {
+			return nil, fmt.Errorf("too many blocks, at most %d can be simulated", maxSimulateBlocks)
+		}
+		for n := new(big.Int).Add(prevNumber, big.NewInt(1)); n.Cmp(number) < 0; n.Add(n, big.NewInt(1)) {
+			prevTime += simulateTimestampIncrement
+			t := hexutil.Uint64(prevTime)
+			res = append(res, SimulatedBlock{BlockOverrides: &SimBlockOverrides{Number: (*hexutil.Big)(new(big.Int).Set(n)), Time: &t}})
+		}
+		if block.BlockOverrides.Time == nil {
+			t := hexutil.Uint64(prevTime + simulateTimestampIncrement)
+			block.BlockOverrides.Time = &t
+		}
+		if uint64(*block.BlockOverrides.Time) <= prevTime {
+			return nil, fmt.Errorf("block timestamps must be in order: %d <= %d", *block.BlockOverrides.Time, prevTime)
+		}
+		prevNumber.Set(number)
+		prevTime = uint64(*block.BlockOverrides.Time)
+		res = append(res, block)
+	}
+	return res, nil
+}
+
+// makeSimulatedHeader builds the header of a simulated block, the sanitized overrides always carry number and time
+func makeSimulatedHeader(chainConfig *chain.Config, parent *types.Header, overrides *SimBlockOverrides, validation bool) *types.Header {
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		UncleHash:  types.EmptyUncleHash,
+		Coinbase:   parent.Coinbase,
+		Difficulty: new(big.Int).Set(parent.Difficulty),
+		Number:     new(big.Int).Set(overrides.Number.ToInt()),
+		GasLimit:   parent.GasLimit,
+		Time:       uint64(*overrides.Time),
+	}
+	if overrides.FeeRecipient != nil {
+		header.Coinbase = *overrides.FeeRecipient
+	}
+	if overrides.Difficulty != nil {
+		header.Difficulty = new(big.Int).Set(overrides.Difficulty.ToInt())
+	}
+	if overrides.GasLimit != nil {
+		header.GasLimit = uint64(*overrides.GasLimit)
+	}
+	if overrides.PrevRandao != nil {
+		header.MixDigest = *overrides.PrevRandao
+	}
+	if chainConfig.IsLondon(header.Number.Uint64()) {
+		switch {
+		case overrides.BaseFeePerGas != nil:
+			header.BaseFee = new(big.Int).Set(overrides.BaseFeePerGas.ToInt())
+		case validation:
+			header.BaseFee = misc.CalcBaseFee(chainConfig, parent)
+		default:
+			// Without validation calls are free unless the caller asks otherwise
+			header.BaseFee = new(big.Int)
+		}
+	}
+	if chainConfig.IsCancun(header.Time) {
+		excessDataGas := misc.CalcExcessDataGas(parent)
+		header.ExcessDataGas = &excessDataGas
+		header.DataGasUsed = new(uint64)
+	}
+	return header
+}
+
+func (api *APIImpl) simulateBlock(ctx context.Context, ibs *state.IntraBlockState, chainConfig *chain.Config, parent *types.Header,
+	block SimulatedBlock, getHash func(uint64) libcommon.Hash, opts *SimulationOpts) (*simBlockResult, error) {
+	header := makeSimulatedHeader(chainConfig, parent, block.BlockOverrides, opts.Validation)
+	blockNum := header.Number.Uint64()
+	rules := chainConfig.Rules(blockNum, header.Time)
+
+	precompiles := vm.ActivePrecompiledContracts(rules)
+	if block.StateOverrides != nil {
+		if err := block.StateOverrides.OverridePrecompiles(precompiles); err != nil {
+			return nil, err
+		}
+		if err := block.StateOverrides.Override(ibs); err != nil {
+			return nil, err
+		}
+	}
+
+	blockCtx := core.NewEVMBlockContext(header, getHash, api.engine(), &header.Coinbase)
+	if opts.TraceTransfers {
+		blockCtx.Transfer = transferWithLog(blockCtx.Transfer)
+	}
+	var baseFee *uint256.Int
+	if header.BaseFee != nil {
+		baseFee = blockCtx.BaseFee
+	}
+	evm := vm.NewEVM(blockCtx, evmtypes.TxContext{}, ibs, chainConfig, vm.Config{NoBaseFee: !opts.Validation})
+	evm.SetPrecompiles(precompiles)
+	go func() {
+		<-ctx.Done()
+		evm.Cancel()
+	}()
+
+	var (
+		gp       = new(core.GasPool).AddGas(header.GasLimit).AddDataGas(chain.MaxDataGasPerBlock)
+		txs      = make(types.Transactions, 0, len(block.Calls))
+		receipts = make(types.Receipts, 0, len(block.Calls))
+		calls    = make([]simCallResult, 0, len(block.Calls))
+		senders  = make([]libcommon.Address, 0, len(block.Calls))
+		gasUsed  uint64
+	)
+	for i, args := range block.Calls {
+		from := libcommon.Address{}
+		if args.From != nil {
+			from = *args.From
+		}
+		nonce := ibs.GetNonce(from)
+		if args.Nonce != nil {
+			nonce = uint64(*args.Nonce)
+		}
+		if args.Gas == nil {
+			remaining := gp.Gas()
+			if api.GasCap != 0 && api.GasCap < remaining {
+				remaining = api.GasCap
+			}
+			args.Gas = (*hexutil.Uint64)(&remaining)
+		}
+		if uint64(*args.Gas) > gp.Gas() {
+			return nil, fmt.Errorf("block %d call %d: gas %d exceeds the remaining block gas %d", blockNum, i, *args.Gas, gp.Gas())
+		}
+		msg, err := args.ToMessage(api.GasCap, baseFee)
+		if err != nil {
+			return nil, fmt.Errorf("block %d call %d: %w", blockNum, i, err)
+		}
+		msg = types.NewMessage(msg.From(), msg.To(), nonce, msg.Value(), msg.Gas(), msg.GasPrice(), msg.FeeCap(), msg.Tip(),
+			msg.Data(), msg.AccessList(), opts.Validation /* checkNonce */, false /* isFree */, msg.MaxFeePerDataGas())
+
+		txn := simulatedTransaction(chainConfig, &args, &msg, header.BaseFee != nil)
+		// Simulated transactions are unsigned, so their hashes may collide: key the logs by position instead
+		var logKey libcommon.Hash
+		binary.BigEndian.PutUint64(logKey[16:], blockNum)
+		binary.BigEndian.PutUint64(logKey[24:], uint64(i))
+		ibs.SetTxContext(logKey, libcommon.Hash{}, i)
+		evm.Reset(core.NewEVMTxContext(msg), ibs)
+		result, err := core.ApplyMessage(evm, msg, gp, true /* refunds */, false /* gasBailout */)
+		if err != nil {
+			return nil, fmt.Errorf("block %d call %d: %w", blockNum, i, err)
+		}
+		if evm.Cancelled() {
+			return nil, fmt.Errorf("execution aborted (timeout = %v)", api.evmCallTimeout)
+		}
+		if err = ibs.FinalizeTx(rules, state.NewNoopWriter()); err != nil {
+			return nil, err
+		}
+		if len(result.ReturnData) > api.ReturnDataLimit {
+			return nil, fmt.Errorf("call returned result on length %d exceeding --rpc.returndata.limit %d", len(result.ReturnData), api.ReturnDataLimit)
+		}
+		gasUsed += result.UsedGas
+
+		receipt := &types.Receipt{
+			Type:              txn.Type(),
+			CumulativeGasUsed: gasUsed,
+			TxHash:            txn.Hash(),
+			GasUsed:           result.UsedGas,
+			Logs:              ibs.GetLogs(logKey),
+			BlockNumber:       header.Number,
+			TransactionIndex:  uint(i),
+			Status:            types.ReceiptStatusSuccessful,
+		}
+		if result.Failed() {
+			receipt.Status = types.ReceiptStatusFailed
+		}
+		if msg.To() == nil {
+			receipt.ContractAddress = crypto.CreateAddress(msg.From(), nonce)
+		}
+		receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+
+		call := simCallResult{ReturnData: result.Return(), GasUsed: hexutil.Uint64(result.UsedGas), Status: hexutil.Uint64(receipt.Status)}
+		if result.Err != nil {
+			call.Error = &simCallError{Code: -32015, Message: result.Err.Error()}
+			if revert := result.Revert(); len(revert) > 0 {
+				call.Error = &simCallError{Code: 3, Message: ethapi2.NewRevertError(result).Error(), Data: hexutility.Encode(revert)}
+			}
+		}
+		txs = append(txs, txn)
+		receipts = append(receipts, receipt)
+		calls = append(calls, call)
+		senders = append(senders, msg.From())
+	}
+	header.GasUsed = gasUsed
+
+	var withdrawals []*types.Withdrawal
+	if chainConfig.IsShanghai(header.Time) {
+		withdrawals = []*types.Withdrawal{}
+	}
+	simulated := types.NewBlock(header, txs, nil, receipts, withdrawals)
+
+	// The block hash is known only now, patch it into the receipts and renumber the logs within the block
+	blockHash := simulated.Hash()
+	var logIndex uint
+	for i, receipt := range receipts {
+		receipt.BlockHash = blockHash
+		for _, l := range receipt.Logs {
+			l.BlockNumber = blockNum
+			l.BlockHash = blockHash
+			l.TxHash = receipt.TxHash
+			l.TxIndex = uint(i)
+			l.Index = logIndex
+			logIndex++
+		}
+		calls[i].Logs = receipt.Logs
+		if calls[i].Logs == nil {
+			calls[i].Logs = []*types.Log{}
+		}
+	}
+	return &simBlockResult{block: simulated, calls: calls, senders: senders}, nil
+}
+
+// simulatedTransaction builds the unsigned transaction equivalent to a simulated call
+func simulatedTransaction(chainConfig *chain.Config, args *ethapi2.CallArgs, msg *types.Message, london bool) types.Transaction {
+	commonTx := types.CommonTx{
+		Nonce: msg.Nonce(),
+		Gas:   msg.Gas(),
+		To:    msg.To(),
+		Value: msg.Value(),
+		Data:  msg.Data(),
+	}
+	chainID, _ := uint256.FromBig(chainConfig.ChainID)
+	switch {
+	case london && args.GasPrice == nil:
+		return &types.DynamicFeeTransaction{CommonTx: commonTx, ChainID: chainID, Tip: msg.Tip(), FeeCap: msg.FeeCap(), AccessList: msg.AccessList()}
+	case args.AccessList != nil:
+		return &types.AccessListTx{LegacyTx: types.LegacyTx{CommonTx: commonTx, GasPrice: msg.GasPrice()}, ChainID: chainID, AccessList: msg.AccessList()}
+	default:
+		return &types.LegacyTx{CommonTx: commonTx, GasPrice: msg.GasPrice()}
+	}
+}
+
+// simulatedRPCTransactions marshals the transactions of a simulated block. They are unsigned,
+// so the sender is taken from the calls rather than recovered from the signature.
+func simulatedRPCTransactions(res *simBlockResult) []*RPCTransaction {
+	txs := res.block.Transactions()
+	rpcTxs := make([]*RPCTransaction, len(txs))
+	for i, txn := range txs {
+		rpcTxs[i] = newRPCTransaction(txn, res.block.Hash(), res.block.NumberU64(), uint64(i), res.block.BaseFee())
+		rpcTxs[i].From = res.senders[i]
+	}
+	return rpcTxs
+}
+
+// transferWithLog wraps a TransferFunc so that every native value transfer also emits an ERC-20 like
+// Transfer log from the ERC-7528 address. As the log is added to the IntraBlockState after the call
+// snapshot is taken, it is discarded together with the rest of a reverted call frame.
+func transferWithLog(transfer evmtypes.TransferFunc) evmtypes.TransferFunc {
+	return func(db evmtypes.IntraBlockState, sender, recipient libcommon.Address, amount *uint256.Int, bailout bool) {
+		transfer(db, sender, recipient, amount, bailout)
+		if amount.IsZero() {
+			return
+		}
+		data := amount.Bytes32()
+		db.AddLog(&types.Log{
+			Address: transferLogAddress,
+			Topics:  []libcommon.Hash{transferLogTopic, libcommon.BytesToHash(sender.Bytes()), libcommon.BytesToHash(recipient.Bytes())},
+			Data:    data[:],
+		})
+	}
+}
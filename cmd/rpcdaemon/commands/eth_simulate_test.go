@@ -0,0 +1,123 @@
+package commands
+
+import (
+	"context"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
+	"github.com/ledgerwatch/log/v3"
+)
+
+func TestSimulateV1(t *testing.T) {
+	m, bankAddress, _ := chainWithDeployedContract(t)
+	agg := m.HistoryV3Components()
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	api := NewEthAPI(NewBaseApi(nil, stateCache, m.BlockReader, agg, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs), m.DB, nil, nil, nil, 5000000, 100_000, log.New())
+
+	var (
+		alice    = libcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+		bob      = libcommon.HexToAddress("0x1000000000000000000000000000000000000002")
+		sha256To = libcommon.HexToAddress("0x1000000000000000000000000000000000000003")
+		reverter = libcommon.HexToAddress("0x1000000000000000000000000000000000000004")
+		revert   = hexutility.Bytes{0x60, 0x00, 0x60, 0x00, 0xfd} // PUSH1 0 PUSH1 0 REVERT
+		sha256At = libcommon.BytesToAddress([]byte{2})
+		input    = hexutility.Bytes("simulate")
+		number   = hexutil.Big(*big.NewInt(7))
+	)
+	opts := SimulationOpts{
+		TraceTransfers:         true,
+		ReturnFullTransactions: true,
+		BlockStateCalls: []SimulatedBlock{
+			{
+				Calls: []ethapi.CallArgs{{From: &bankAddress, To: &alice, Value: (*hexutil.Big)(big.NewInt(1000))}},
+			},
+			{
+				// Numbers 5 and 6 are filled with empty blocks
+				BlockOverrides: &SimBlockOverrides{Number: &number},
+				StateOverrides: &ethapi.StateOverrides{
+					sha256At: {MovePrecompileTo: &sha256To},
+					reverter: {Code: &revert},
+				},
+				Calls: []ethapi.CallArgs{
+					{From: &alice, To: &bob, Value: (*hexutil.Big)(big.NewInt(400))},
+					{From: &alice, To: &sha256To, Data: &input},
+					{From: &alice, To: &reverter, Value: (*hexutil.Big)(big.NewInt(100))},
+				},
+			},
+		},
+	}
+	res, err := api.SimulateV1(context.Background(), opts, nil)
+	require.NoError(t, err)
+	require.Len(t, res, 4)
+
+	for i, block := range res {
+		assert.Equal(t, (*hexutil.Big)(big.NewInt(int64(4+i))), block["number"])
+		if i > 0 {
+			assert.Equal(t, res[i-1]["hash"], block["parentHash"])
+		}
+	}
+
+	calls := res[0]["calls"].([]simCallResult)
+	require.Len(t, calls, 1)
+	assert.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), calls[0].Status)
+	require.Len(t, calls[0].Logs, 1)
+	assert.Equal(t, transferLogAddress, calls[0].Logs[0].Address)
+	assert.Equal(t, libcommon.BytesToHash(alice.Bytes()), calls[0].Logs[0].Topics[2])
+	txs := res[0]["transactions"].([]*RPCTransaction)
+	require.Len(t, txs, 1)
+	assert.Equal(t, bankAddress, txs[0].From)
+
+	calls = res[3]["calls"].([]simCallResult)
+	require.Len(t, calls, 3)
+	// alice spends the funds received in the first block
+	assert.Equal(t, hexutil.Uint64(types.ReceiptStatusSuccessful), calls[0].Status)
+	assert.Len(t, calls[0].Logs, 1)
+	// the moved precompile answers at its new address
+	expected := sha256.Sum256(input)
+	assert.Equal(t, hexutility.Bytes(expected[:]), calls[1].ReturnData)
+	assert.Empty(t, calls[1].Logs)
+	// the transfer log is dropped together with the reverted call
+	assert.Equal(t, hexutil.Uint64(types.ReceiptStatusFailed), calls[2].Status)
+	assert.NotNil(t, calls[2].Error)
+	assert.Empty(t, calls[2].Logs)
+}
+
+func TestSimulateV1Validation(t *testing.T) {
+	m, bankAddress, _ := chainWithDeployedContract(t)
+	agg := m.HistoryV3Components()
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	api := NewEthAPI(NewBaseApi(nil, stateCache, m.BlockReader, agg, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs), m.DB, nil, nil, nil, 5000000, 100_000, log.New())
+
+	to := libcommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	nonce := hexutil.Uint64(100)
+	opts := SimulationOpts{
+		Validation: true,
+		BlockStateCalls: []SimulatedBlock{
+			{Calls: []ethapi.CallArgs{{From: &bankAddress, To: &to, Nonce: &nonce}}},
+		},
+	}
+	_, err := api.SimulateV1(context.Background(), opts, nil)
+	assert.ErrorContains(t, err, "nonce too high")
+
+	opts.Validation = false
+	res, err := api.SimulateV1(context.Background(), opts, nil)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+
+	number := hexutil.Big(*big.NewInt(2))
+	opts.BlockStateCalls[0].BlockOverrides = &SimBlockOverrides{Number: &number}
+	_, err = api.SimulateV1(context.Background(), opts, nil)
+	assert.ErrorContains(t, err, "block numbers must be in order")
+}
@@ -43,7 +43,7 @@ func TestEthSubscribe(t *testing.T) {
 	logger := log.New()
 	backendServer := privateapi.NewEthBackendServer(ctx, nil, m.DB, m.Notifications.Events, m.BlockReader, nil, nil, nil, false, logger)
 	backendClient := direct.NewEthBackendClientDirect(backendServer)
-	backend := rpcservices.NewRemoteBackend(backendClient, m.DB, m.BlockReader)
+	backend := rpcservices.NewRemoteBackend(backendClient, nil, m.DB, m.BlockReader)
 	ff := rpchelper.New(ctx, backend, nil, nil, func() {}, m.Log)
 
 	newHeads, id := ff.SubscribeNewHeads(16)
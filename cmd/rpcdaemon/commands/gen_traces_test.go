@@ -16,6 +16,7 @@ import (
 	"github.com/ledgerwatch/erigon/eth/tracers"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+	"github.com/ledgerwatch/erigon/turbo/stages"
 	// Force-load native and js packages, to trigger registration
 	_ "github.com/ledgerwatch/erigon/eth/tracers/js"
 	_ "github.com/ledgerwatch/erigon/eth/tracers/native"
@@ -112,6 +113,58 @@ func TestGeneratedDebugApi(t *testing.T) {
 	}
 }
 
+func TestGeneratedDebugApiFlatCallTracer(t *testing.T) {
+	checkFlatCallTracerMatchesTraceBlock(t, rpcdaemontest.CreateTestSentryForTraces(t), 1)
+}
+
+func TestGeneratedDebugApiFlatCallTracerSelfdestruct(t *testing.T) {
+	checkFlatCallTracerMatchesTraceBlock(t, rpcdaemontest.CreateTestSentryForTracesCollision(t), 1)
+}
+
+// checkFlatCallTracerMatchesTraceBlock checks that the flat call traces of debug_traceBlockByNumber,
+// reward frames included, are exactly what trace_block returns.
+func checkFlatCallTracerMatchesTraceBlock(t *testing.T, m *stages.MockSentry, blockNum rpc.BlockNumber) {
+	t.Helper()
+	agg := m.HistoryV3Components()
+	stateCache := kvcache.New(kvcache.DefaultCoherentConfig)
+	baseApi := NewBaseApi(nil, stateCache, m.BlockReader, agg, false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
+	api := NewPrivateDebugAPI(baseApi, m.DB, 0)
+	var buf bytes.Buffer
+	stream := jsoniter.NewStream(jsoniter.ConfigDefault, &buf, 4096)
+	flatCallTracer := "flatCallTracer"
+	err := api.TraceBlockByNumber(context.Background(), blockNum, &tracers.TraceConfig{Tracer: &flatCallTracer}, stream)
+	if err != nil {
+		t.Errorf("debug_traceBlock %d: %v", blockNum, err)
+	}
+	if err = stream.Flush(); err != nil {
+		t.Fatalf("error flusing: %v", err)
+	}
+	var result []struct {
+		Result []interface{} `json:"result"`
+	}
+	if err = json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("parsing result: %v", err)
+	}
+	var flat []interface{}
+	for _, txResult := range result {
+		flat = append(flat, txResult.Result...)
+	}
+
+	traces, err := NewTraceAPI(baseApi, m.DB, &httpcfg.HttpCfg{}).Block(context.Background(), blockNum, new(bool))
+	if err != nil {
+		t.Fatalf("trace_block %d: %v", blockNum, err)
+	}
+	traceJson, err := json.Marshal(traces)
+	if err != nil {
+		t.Fatalf("marshall result into JSON: %v", err)
+	}
+	var expected []interface{}
+	if err = json.Unmarshal(traceJson, &expected); err != nil {
+		t.Fatalf("parsing expected: %v", err)
+	}
+	assert.Equal(t, expected, flat)
+}
+
 func TestGeneratedTraceApi(t *testing.T) {
 	m := rpcdaemontest.CreateTestSentryForTraces(t)
 	agg := m.HistoryV3Components()
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"math/big"
 
+	"github.com/ledgerwatch/erigon-lib/chain"
 	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/common/hexutil"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/filters"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
 	"github.com/ledgerwatch/erigon/turbo/rpchelper"
@@ -18,20 +21,168 @@ import (
 type GraphQLAPI interface {
 	GetBlockDetails(ctx context.Context, number rpc.BlockNumber) (map[string]interface{}, error)
 	GetChainID(ctx context.Context) (*big.Int, error)
+	GetLatestBlockNumber(ctx context.Context) (uint64, error)
+	GetTransactionDetails(ctx context.Context, hash common.Hash) (map[string]interface{}, error)
+	GetPendingTransactions(ctx context.Context) ([]*RPCTransaction, error)
+	GetLogs(ctx context.Context, crit filters.FilterCriteria) (types.Logs, error)
+	GetGasPrice(ctx context.Context) (*hexutil.Big, error)
+	GetMaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error)
+	GetSyncing(ctx context.Context) (interface{}, error)
+	GetBalance(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error)
+	GetTransactionCount(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Uint64, error)
+	GetCode(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (hexutility.Bytes, error)
+	GetStorageAt(ctx context.Context, address common.Address, slot string, blockNrOrHash rpc.BlockNumberOrHash) (string, error)
 }
 
 type GraphQLAPIImpl struct {
 	*BaseAPI
-	db kv.RoDB
+	db  kv.RoDB
+	eth *APIImpl // queries sharing their semantics with eth_* are delegated to it
 }
 
-func NewGraphQLAPI(base *BaseAPI, db kv.RoDB) *GraphQLAPIImpl {
+func NewGraphQLAPI(base *BaseAPI, db kv.RoDB, eth *APIImpl) *GraphQLAPIImpl {
 	return &GraphQLAPIImpl{
 		BaseAPI: base,
 		db:      db,
+		eth:     eth,
 	}
 }
 
+func (api *GraphQLAPIImpl) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+	blockNum, err := api.eth.BlockNumber(ctx)
+	return uint64(blockNum), err
+}
+
+// GetTransactionDetails returns the receipt of a mined transaction along with
+// its nonce, value and input, like the receipts of GetBlockDetails but without
+// reading the rest of the block, or nil if it is not known.
+func (api *GraphQLAPIImpl) GetTransactionDetails(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	blockNum, ok, err := api.txnLookup(ctx, tx, hash)
+	if err != nil || !ok {
+		return nil, err
+	}
+	chainConfig, err := api.chainConfig(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The stored receipts are enough to find the transaction and to derive its
+	// receipt, the block is loaded and re-executed only when they are missing
+	if receipts := rawdb.ReadRawReceipts(tx, blockNum); receipts != nil {
+		for i := range receipts {
+			txn, err := api._txnReader.TxnByIdxInBlock(ctx, tx, blockNum, i)
+			if err != nil || txn == nil {
+				return nil, err
+			}
+			if txn.Hash() != hash {
+				continue
+			}
+			header, err := api._blockReader.HeaderByNumber(ctx, tx, blockNum)
+			if err != nil || header == nil {
+				return nil, err
+			}
+			sender, err := txn.Sender(*types.MakeSigner(chainConfig, blockNum, header.Time))
+			if err != nil {
+				return nil, err
+			}
+			receipts.DeriveFieldsAt(i, header.Hash(), blockNum, txn, sender)
+			return marshalGraphQLReceipt(receipts[i], txn, chainConfig, header), nil
+		}
+		return nil, nil
+	}
+
+	block, err := api.blockByNumberWithSenders(ctx, tx, blockNum)
+	if err != nil || block == nil {
+		return nil, err
+	}
+	txnIndex := -1
+	for i, txn := range block.Transactions() {
+		if txn.Hash() == hash {
+			txnIndex = i
+			break
+		}
+	}
+	if txnIndex < 0 {
+		return nil, nil
+	}
+
+	receipts, err := api.getReceipts(ctx, tx, chainConfig, block, block.Body().SendersFromTxs())
+	if err != nil {
+		return nil, fmt.Errorf("getReceipts error: %w", err)
+	}
+	if len(receipts) <= txnIndex {
+		return nil, fmt.Errorf("block has less receipts than expected: %d <= %d, block: %d", len(receipts), txnIndex, blockNum)
+	}
+	return marshalGraphQLReceipt(receipts[txnIndex], block.Transactions()[txnIndex], chainConfig, block.HeaderNoCopy()), nil
+}
+
+// GetPendingTransactions returns the transactions of the pending block, if
+// any is being built.
+func (api *GraphQLAPIImpl) GetPendingTransactions(ctx context.Context) ([]*RPCTransaction, error) {
+	block := api.pendingBlock()
+	if block == nil {
+		return nil, nil
+	}
+
+	tx, err := api.db.BeginRo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	chainConfig, err := api.chainConfig(tx)
+	if err != nil {
+		return nil, err
+	}
+	current := rawdb.ReadCurrentHeader(tx)
+
+	result := make([]*RPCTransaction, 0, block.Transactions().Len())
+	for _, txn := range block.Transactions() {
+		result = append(result, newRPCPendingTransaction(txn, current, chainConfig))
+	}
+	return result, nil
+}
+
+func (api *GraphQLAPIImpl) GetLogs(ctx context.Context, crit filters.FilterCriteria) (types.Logs, error) {
+	return api.eth.GetLogs(ctx, crit)
+}
+
+func (api *GraphQLAPIImpl) GetGasPrice(ctx context.Context) (*hexutil.Big, error) {
+	return api.eth.GasPrice(ctx)
+}
+
+func (api *GraphQLAPIImpl) GetMaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	return api.eth.MaxPriorityFeePerGas(ctx)
+}
+
+func (api *GraphQLAPIImpl) GetBalance(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	return api.eth.GetBalance(ctx, address, blockNrOrHash)
+}
+
+func (api *GraphQLAPIImpl) GetTransactionCount(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Uint64, error) {
+	return api.eth.GetTransactionCount(ctx, address, blockNrOrHash)
+}
+
+func (api *GraphQLAPIImpl) GetCode(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (hexutility.Bytes, error) {
+	return api.eth.GetCode(ctx, address, blockNrOrHash)
+}
+
+func (api *GraphQLAPIImpl) GetStorageAt(ctx context.Context, address common.Address, slot string, blockNrOrHash rpc.BlockNumberOrHash) (string, error) {
+	return api.eth.GetStorageAt(ctx, address, slot, blockNrOrHash)
+}
+
+// GetSyncing returns false when the node is in sync, and the eth_syncing
+// progress map otherwise.
+func (api *GraphQLAPIImpl) GetSyncing(ctx context.Context) (interface{}, error) {
+	return api.eth.Syncing(ctx)
+}
+
 func (api *GraphQLAPIImpl) GetChainID(ctx context.Context) (*big.Int, error) {
 	tx, err := api.db.BeginRo(ctx)
 	if err != nil {
@@ -80,13 +231,7 @@ func (api *GraphQLAPIImpl) GetBlockDetails(ctx context.Context, blockNumber rpc.
 	result := make([]map[string]interface{}, 0, len(receipts))
 	for _, receipt := range receipts {
 		txn := block.Transactions()[receipt.TransactionIndex]
-
-		transaction := marshalReceipt(receipt, txn, chainConfig, block.HeaderNoCopy(), txn.Hash(), true)
-		transaction["nonce"] = txn.GetNonce()
-		transaction["value"] = txn.GetValue()
-		transaction["data"] = txn.GetData()
-		transaction["logs"] = receipt.Logs
-		result = append(result, transaction)
+		result = append(result, marshalGraphQLReceipt(receipt, txn, chainConfig, block.HeaderNoCopy()))
 	}
 
 	response := map[string]interface{}{}
@@ -96,6 +241,15 @@ func (api *GraphQLAPIImpl) GetBlockDetails(ctx context.Context, blockNumber rpc.
 	return response, nil
 }
 
+func marshalGraphQLReceipt(receipt *types.Receipt, txn types.Transaction, chainConfig *chain.Config, header *types.Header) map[string]interface{} {
+	transaction := marshalReceipt(receipt, txn, chainConfig, header, txn.Hash(), true)
+	transaction["nonce"] = txn.GetNonce()
+	transaction["value"] = txn.GetValue()
+	transaction["data"] = txn.GetData()
+	transaction["logs"] = receipt.Logs
+	return transaction
+}
+
 func (api *GraphQLAPIImpl) getBlockWithSenders(ctx context.Context, number rpc.BlockNumber, tx kv.Tx) (*types.Block, []common.Address, error) {
 	if number == rpc.PendingBlockNumber {
 		return api.pendingBlock(), nil, nil
@@ -8,7 +8,9 @@ import (
 
 	"github.com/holiman/uint256"
 	jsoniter "github.com/json-iterator/go"
+	"github.com/ledgerwatch/erigon-lib/chain"
 	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/log/v3"
 
 	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
@@ -99,7 +101,12 @@ func (api *PrivateDebugAPIImpl) traceBlock(ctx context.Context, blockNrOrHash rp
 		stream.WriteNil()
 		return err
 	}
+	return api.traceBlockTransactions(ctx, tx, block, blockCtx, ibs, chainConfig, config, stream)
+}
 
+// traceBlockTransactions traces the transactions of the block one after another, starting from the state in ibs.
+func (api *PrivateDebugAPIImpl) traceBlockTransactions(ctx context.Context, tx kv.Tx, block *types.Block, blockCtx evmtypes.BlockContext, ibs *state.IntraBlockState, chainConfig *chain.Config, config *tracers.TraceConfig, stream *jsoniter.Stream) error {
+	engine := api.engine()
 	signer := types.MakeSigner(chainConfig, block.NumberU64(), block.Time())
 	rules := chainConfig.Rules(block.NumberU64(), block.Time())
 	stream.WriteArrayStart()
@@ -141,7 +148,7 @@ func (api *PrivateDebugAPIImpl) traceBlock(ctx context.Context, blockNrOrHash rp
 			}
 		}
 
-		err = transactions.TraceTx(ctx, msg, blockCtx, txCtx, ibs, config, chainConfig, stream, api.evmCallTimeout)
+		err := transactions.TraceTx(ctx, msg, blockCtx, txCtx, ibs, config, chainConfig, stream, api.evmCallTimeout)
 		if err == nil {
 			err = ibs.FinalizeTx(rules, state.NewNoopWriter())
 		}
@@ -162,11 +169,61 @@ func (api *PrivateDebugAPIImpl) traceBlock(ctx context.Context, blockNrOrHash rp
 		}
 		stream.Flush()
 	}
+	if config.Tracer != nil && *config.Tracer == "flatCallTracer" {
+		if err := api.writeFlatRewards(block, ibs, chainConfig, len(txns) > 0, stream); err != nil {
+			stream.WriteArrayEnd()
+			return err
+		}
+	}
 	stream.WriteArrayEnd()
 	stream.Flush()
 	return nil
 }
 
+// writeFlatRewards appends the block and uncle rewards of the block as one more result of reward frames,
+// so that the flatCallTracer output of debug_traceBlock* matches trace_block.
+func (api *PrivateDebugAPIImpl) writeFlatRewards(block *types.Block, ibs *state.IntraBlockState, chainConfig *chain.Config, more bool, stream *jsoniter.Stream) error {
+	engine := api.engine()
+	if engine == nil {
+		return nil
+	}
+	syscall := func(contract common.Address, data []byte) ([]byte, error) {
+		return core.SysCallContract(contract, data, chainConfig, ibs, block.Header(), engine, true /* constCall */)
+	}
+	rewards, err := engine.CalculateRewards(chainConfig, block.Header(), block.Uncles(), syscall)
+	if err != nil {
+		return err
+	}
+	if len(rewards) == 0 {
+		return nil
+	}
+	blockHash := block.Hash()
+	blockNum := block.NumberU64()
+	frames := make([]ParityTrace, 0, len(rewards))
+	for _, r := range rewards {
+		rewardAction := &RewardTraceAction{}
+		rewardAction.Author = r.Beneficiary
+		rewardAction.RewardType = rewardKindToString(r.Kind)
+		rewardAction.Value.ToInt().Set(r.Amount.ToBig())
+		frames = append(frames, ParityTrace{
+			Action:       rewardAction,
+			BlockHash:    &blockHash,
+			BlockNumber:  &blockNum,
+			TraceAddress: []int{},
+			Type:         "reward", // nolint: goconst
+		})
+	}
+	if more {
+		stream.WriteMore()
+	}
+	stream.WriteObjectStart()
+	stream.WriteObjectField("result")
+	stream.WriteVal(frames)
+	stream.WriteObjectEnd()
+	stream.Flush()
+	return nil
+}
+
 // TraceTransaction implements debug_traceTransaction. Returns Geth style transaction traces.
 func (api *PrivateDebugAPIImpl) TraceTransaction(ctx context.Context, hash common.Hash, config *tracers.TraceConfig, stream *jsoniter.Stream) error {
 	tx, err := api.db.BeginRo(ctx)
@@ -304,12 +361,20 @@ func (api *PrivateDebugAPIImpl) TraceCall(ctx context.Context, args ethapi.CallA
 			return fmt.Errorf("header.BaseFee uint256 overflow")
 		}
 	}
+
+	blockCtx := transactions.NewEVMBlockContext(engine, header, blockNrOrHash.RequireCanonical, dbtx, api._blockReader)
+	if config != nil && config.BlockOverrides != nil {
+		if err := config.BlockOverrides.Override(&blockCtx); err != nil {
+			return fmt.Errorf("override block: %v", err)
+		}
+		if config.BlockOverrides.BaseFee != nil {
+			baseFee = blockCtx.BaseFee
+		}
+	}
 	msg, err := args.ToMessage(api.GasCap, baseFee)
 	if err != nil {
 		return fmt.Errorf("convert args to msg: %v", err)
 	}
-
-	blockCtx := transactions.NewEVMBlockContext(engine, header, blockNrOrHash.RequireCanonical, dbtx, api._blockReader)
 	txCtx := core.NewEVMTxContext(msg)
 	// Trace the transaction and return
 	return transactions.TraceTx(ctx, msg, blockCtx, txCtx, ibs, config, chainConfig, stream, api.evmCallTimeout)
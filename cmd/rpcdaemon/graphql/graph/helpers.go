@@ -5,16 +5,172 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/holiman/uint256"
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/common/hexutility"
 
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/commands"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/graphql/graph/model"
 	"github.com/ledgerwatch/erigon/common/hexutil"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rpc"
 )
 
+// maxBlocksRange limits the number of blocks a single blocks query may return
+const maxBlocksRange = 1_000
+
+// convertBlock builds the block model out of GraphQLAPI.GetBlockDetails
+// results, linking its transactions and logs back to it.
+func convertBlock(res map[string]interface{}) *model.Block {
+	block := &model.Block{}
+	absBlk := res["block"]
+
+	if absBlk != nil {
+		blk := absBlk.(map[string]interface{})
+
+		block.Difficulty = *convertDataToStringP(blk, "difficulty")
+		block.ExtraData = *convertDataToStringP(blk, "extraData")
+		block.GasLimit = uint64(*convertDataToUint64P(blk, "gasLimit"))
+		block.GasUsed = *convertDataToUint64P(blk, "gasUsed")
+		block.Hash = *convertDataToStringP(blk, "hash")
+		block.Number = *convertDataToUint64P(blk, "number")
+		block.Miner = &model.Account{BlockNumber: &block.Number}
+		address := convertDataToStringP(blk, "miner")
+		if address != nil {
+			block.Miner.Address = strings.ToLower(*address)
+		}
+		mixHash := convertDataToStringP(blk, "mixHash")
+		if mixHash != nil {
+			block.MixHash = *mixHash
+		}
+		blockNonce := convertDataToStringP(blk, "nonce")
+		if blockNonce != nil {
+			block.Nonce = *blockNonce
+		}
+		block.Ommers = []*model.Block{}
+		block.Parent = &model.Block{}
+		block.Parent.Hash = *convertDataToStringP(blk, "parentHash")
+		block.ReceiptsRoot = *convertDataToStringP(blk, "receiptsRoot")
+		block.StateRoot = *convertDataToStringP(blk, "stateRoot")
+		block.Timestamp = *convertDataToStringP(blk, "timestamp")
+		block.TransactionCount = convertDataToIntP(blk, "transactionCount")
+		block.TransactionsRoot = *convertDataToStringP(blk, "transactionsRoot")
+		block.TotalDifficulty = *convertDataToStringP(blk, "totalDifficulty")
+		block.Transactions = []*model.Transaction{}
+
+		block.LogsBloom = "0x" + *convertDataToStringP(blk, "logsBloom")
+		block.OmmerHash = *convertDataToStringP(blk, "sha3Uncles")
+
+		absRcp := res["receipts"]
+		rcp := absRcp.([]map[string]interface{})
+		for _, transReceipt := range rcp {
+			block.Transactions = append(block.Transactions, convertTransaction(transReceipt, block))
+		}
+	}
+
+	return block
+}
+
+// convertTransaction builds the transaction model out of a receipt of
+// GraphQLAPI.GetBlockDetails or GraphQLAPI.GetTransactionDetails, linking it
+// and its logs to the block, whose state its accounts are read at.
+func convertTransaction(transReceipt map[string]interface{}, block *model.Block) *model.Transaction {
+	trans := &model.Transaction{}
+	trans.CumulativeGasUsed = convertDataToUint64P(transReceipt, "cumulativeGasUsed")
+	trans.InputData = *convertDataToStringP(transReceipt, "data")
+	trans.EffectiveGasPrice = convertDataToStringP(transReceipt, "effectiveGasPrice")
+	trans.GasPrice = *convertDataToStringP(transReceipt, "effectiveGasPrice")
+	trans.GasUsed = convertDataToUint64P(transReceipt, "gasUsed")
+	trans.Hash = *convertDataToStringP(transReceipt, "transactionHash")
+	trans.Index = convertDataToIntP(transReceipt, "transactionIndex")
+	transNonce := convertDataToStringP(transReceipt, "nonce")
+	if transNonce != nil {
+		trans.Nonce = *transNonce
+	}
+	trans.Status = convertDataToUint64P(transReceipt, "status")
+	trans.Type = convertDataToIntP(transReceipt, "type")
+	trans.Value = *convertDataToStringP(transReceipt, "value")
+
+	trans.Logs = make([]*model.Log, 0)
+	for _, rlog := range transReceipt["logs"].(types.Logs) {
+		tlog := convertLog(rlog, block.Number)
+		tlog.Transaction = trans
+		trans.Logs = append(trans.Logs, tlog)
+	}
+
+	trans.From = &model.Account{BlockNumber: &block.Number}
+	trans.From.Address = strings.ToLower(*convertDataToStringP(transReceipt, "from"))
+
+	trans.To = &model.Account{BlockNumber: &block.Number}
+	address := convertDataToStringP(transReceipt, "to")
+	// To address could be nil in case of contract creation
+	if address != nil {
+		trans.To.Address = strings.ToLower(*convertDataToStringP(transReceipt, "to"))
+	}
+
+	trans.Block = block
+	return trans
+}
+
+// accountBlock returns the block whose state the account is read at.
+func accountBlock(account *model.Account) rpc.BlockNumberOrHash {
+	if account.BlockNumber == nil {
+		return rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	}
+	return rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(*account.BlockNumber))
+}
+
+func convertLog(rlog *types.Log, blockNumber uint64) *model.Log {
+	tlog := &model.Log{
+		Index:   int(rlog.Index),
+		Data:    "0x" + hex.EncodeToString(rlog.Data),
+		Account: &model.Account{Address: strings.ToLower(rlog.Address.String()), BlockNumber: &blockNumber},
+	}
+	for _, rtopic := range rlog.Topics {
+		tlog.Topics = append(tlog.Topics, rtopic.String())
+	}
+	return tlog
+}
+
+// convertRPCTransaction builds the transaction model of a transaction which
+// has not been mined yet.
+func convertRPCTransaction(txn *commands.RPCTransaction) *model.Transaction {
+	trans := &model.Transaction{
+		Hash:      txn.Hash.Hex(),
+		Nonce:     txn.Nonce.String(),
+		From:      &model.Account{Address: strings.ToLower(txn.From.String())},
+		Value:     txn.Value.String(),
+		Gas:       uint64(txn.Gas),
+		InputData: txn.Input.String(),
+		Type:      convertIntP(int(txn.Type)),
+	}
+	if txn.To != nil {
+		trans.To = &model.Account{Address: strings.ToLower(txn.To.String())}
+	}
+	if txn.GasPrice != nil {
+		trans.GasPrice = txn.GasPrice.String()
+	}
+	if txn.FeeCap != nil {
+		maxFeePerGas := txn.FeeCap.String()
+		trans.MaxFeePerGas = &maxFeePerGas
+	}
+	if txn.Tip != nil {
+		maxPriorityFeePerGas := txn.Tip.String()
+		trans.MaxPriorityFeePerGas = &maxPriorityFeePerGas
+	}
+	if txn.R != nil {
+		trans.R, trans.S, trans.V = txn.R.String(), txn.S.String(), txn.V.String()
+	}
+	return trans
+}
+
+func convertIntP(v int) *int {
+	return &v
+}
+
 func convertDataToStringP(abstractMap map[string]interface{}, field string) *string {
 	var result string
 
@@ -0,0 +1,10 @@
+package model
+
+// Account is an account read at the state of a block. Its balance, nonce,
+// code and storage are resolved lazily, only if they are queried.
+type Account struct {
+	Address string `json:"address"`
+	// BlockNumber is the block whose state the account is read at, the
+	// latest one if nil
+	BlockNumber *uint64 `json:"-"`
+}
@@ -6,17 +6,49 @@ package graph
 
 import (
 	"context"
-	"encoding/hex"
 	"fmt"
+	"math/big"
 	"strconv"
-	"strings"
 
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/graphql/graph/model"
 	"github.com/ledgerwatch/erigon/common/hexutil"
-	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/filters"
 	"github.com/ledgerwatch/erigon/rpc"
 )
 
+// Balance is the resolver for the balance field.
+func (r *accountResolver) Balance(ctx context.Context, obj *model.Account) (string, error) {
+	balance, err := r.GraphQLAPI.GetBalance(ctx, libcommon.HexToAddress(obj.Address), accountBlock(obj))
+	if err != nil {
+		return "", err
+	}
+	return balance.String(), nil
+}
+
+// TransactionCount is the resolver for the transactionCount field.
+func (r *accountResolver) TransactionCount(ctx context.Context, obj *model.Account) (uint64, error) {
+	nonce, err := r.GraphQLAPI.GetTransactionCount(ctx, libcommon.HexToAddress(obj.Address), accountBlock(obj))
+	if err != nil {
+		return 0, err
+	}
+	return uint64(*nonce), nil
+}
+
+// Code is the resolver for the code field.
+func (r *accountResolver) Code(ctx context.Context, obj *model.Account) (string, error) {
+	code, err := r.GraphQLAPI.GetCode(ctx, libcommon.HexToAddress(obj.Address), accountBlock(obj))
+	if err != nil {
+		return "", err
+	}
+	return code.String(), nil
+}
+
+// Storage is the resolver for the storage field.
+func (r *accountResolver) Storage(ctx context.Context, obj *model.Account, slot string) (string, error) {
+	return r.GraphQLAPI.GetStorageAt(ctx, libcommon.HexToAddress(obj.Address), slot, accountBlock(obj))
+}
+
 // SendRawTransaction is the resolver for the sendRawTransaction field.
 func (r *mutationResolver) SendRawTransaction(ctx context.Context, data string) (string, error) {
 	panic(fmt.Errorf("not implemented: SendRawTransaction - sendRawTransaction"))
@@ -68,130 +100,180 @@ func (r *queryResolver) Block(ctx context.Context, number *string, hash *string)
 		return nil, err
 	}
 
-	block := &model.Block{}
-	absBlk := res["block"]
+	block := convertBlock(res)
 
-	if absBlk != nil {
-		blk := absBlk.(map[string]interface{})
+	return block, ctx.Err()
+}
 
-		block.Difficulty = *convertDataToStringP(blk, "difficulty")
-		block.ExtraData = *convertDataToStringP(blk, "extraData")
-		block.GasLimit = uint64(*convertDataToUint64P(blk, "gasLimit"))
-		block.GasUsed = *convertDataToUint64P(blk, "gasUsed")
-		block.Hash = *convertDataToStringP(blk, "hash")
-		block.Miner = &model.Account{}
-		address := convertDataToStringP(blk, "miner")
-		if address != nil {
-			block.Miner.Address = strings.ToLower(*address)
-		}
-		mixHash := convertDataToStringP(blk, "mixHash")
-		if mixHash != nil {
-			block.MixHash = *mixHash
-		}
-		blockNonce := convertDataToStringP(blk, "nonce")
-		if blockNonce != nil {
-			block.Nonce = *blockNonce
-		}
-		block.Number = *convertDataToUint64P(blk, "number")
-		block.Ommers = []*model.Block{}
-		block.Parent = &model.Block{}
-		block.Parent.Hash = *convertDataToStringP(blk, "parentHash")
-		block.ReceiptsRoot = *convertDataToStringP(blk, "receiptsRoot")
-		block.StateRoot = *convertDataToStringP(blk, "stateRoot")
-		block.Timestamp = *convertDataToStringP(blk, "timestamp")
-		block.TransactionCount = convertDataToIntP(blk, "transactionCount")
-		block.TransactionsRoot = *convertDataToStringP(blk, "transactionsRoot")
-		block.TotalDifficulty = *convertDataToStringP(blk, "totalDifficulty")
-		block.Transactions = []*model.Transaction{}
-
-		block.LogsBloom = "0x" + *convertDataToStringP(blk, "logsBloom")
-		block.OmmerHash = *convertDataToStringP(blk, "sha3Uncles")
-
-		absRcp := res["receipts"]
-		rcp := absRcp.([]map[string]interface{})
-		for _, transReceipt := range rcp {
-			trans := &model.Transaction{}
-			trans.CumulativeGasUsed = convertDataToUint64P(transReceipt, "cumulativeGasUsed")
-			trans.InputData = *convertDataToStringP(transReceipt, "data")
-			trans.EffectiveGasPrice = convertDataToStringP(transReceipt, "effectiveGasPrice")
-			trans.GasPrice = *convertDataToStringP(transReceipt, "effectiveGasPrice")
-			trans.GasUsed = convertDataToUint64P(transReceipt, "gasUsed")
-			trans.Hash = *convertDataToStringP(transReceipt, "transactionHash")
-			trans.Index = convertDataToIntP(transReceipt, "transactionIndex")
-			transNonce := convertDataToStringP(transReceipt, "nonce")
-			if transNonce != nil {
-				trans.Nonce = *transNonce
-			}
-			trans.Status = convertDataToUint64P(transReceipt, "status")
-			trans.Type = convertDataToIntP(transReceipt, "type")
-			trans.Value = *convertDataToStringP(transReceipt, "value")
-
-			trans.Logs = make([]*model.Log, 0)
-			for _, rlog := range transReceipt["logs"].(types.Logs) {
-				tlog := model.Log{
-					Index: int(rlog.Index),
-					Data:  "0x" + hex.EncodeToString(rlog.Data),
-				}
-				tlog.Account = &model.Account{}
-				tlog.Account.Address = strings.ToLower(rlog.Address.String())
-
-				for _, rtopic := range rlog.Topics {
-					tlog.Topics = append(tlog.Topics, rtopic.String())
-				}
-
-				trans.Logs = append(trans.Logs, &tlog)
-			}
+// Blocks is the resolver for the blocks field.
+func (r *queryResolver) Blocks(ctx context.Context, from *uint64, to *uint64) ([]*model.Block, error) {
+	if from == nil {
+		return nil, fmt.Errorf("from block number must be specified")
+	}
 
-			trans.From = &model.Account{}
-			trans.From.Address = strings.ToLower(*convertDataToStringP(transReceipt, "from"))
+	var toNumber uint64
+	if to != nil {
+		toNumber = *to
+	} else {
+		latest, err := r.GraphQLAPI.GetLatestBlockNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+		toNumber = latest
+	}
 
-			trans.To = &model.Account{}
-			address := convertDataToStringP(transReceipt, "to")
-			// To address could be nil in case of contract creation
-			if address != nil {
-				trans.To.Address = strings.ToLower(*convertDataToStringP(transReceipt, "to"))
-			}
+	blocks := []*model.Block{}
+	if toNumber < *from {
+		return blocks, nil
+	}
+	if toNumber-*from >= maxBlocksRange {
+		return nil, fmt.Errorf("block range %d-%d exceeds the limit of %d blocks", *from, toNumber, maxBlocksRange)
+	}
 
-			block.Transactions = append(block.Transactions, trans)
+	for number := *from; number <= toNumber; number++ {
+		res, err := r.GraphQLAPI.GetBlockDetails(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if res["block"] == nil {
+			// past the head of the chain
+			break
 		}
+		blocks = append(blocks, convertBlock(res))
 	}
 
-	return block, ctx.Err()
-}
-
-// Blocks is the resolver for the blocks field.
-func (r *queryResolver) Blocks(ctx context.Context, from *uint64, to *uint64) ([]*model.Block, error) {
-	panic(fmt.Errorf("not implemented: Blocks - blocks"))
+	return blocks, ctx.Err()
 }
 
 // Pending is the resolver for the pending field.
 func (r *queryResolver) Pending(ctx context.Context) (*model.Pending, error) {
-	panic(fmt.Errorf("not implemented: Pending - pending"))
+	txs, err := r.GraphQLAPI.GetPendingTransactions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := &model.Pending{
+		TransactionCount: len(txs),
+		Transactions:     make([]*model.Transaction, 0, len(txs)),
+	}
+	for _, txn := range txs {
+		pending.Transactions = append(pending.Transactions, convertRPCTransaction(txn))
+	}
+
+	return pending, ctx.Err()
 }
 
 // Transaction is the resolver for the transaction field.
 func (r *queryResolver) Transaction(ctx context.Context, hash string) (*model.Transaction, error) {
-	panic(fmt.Errorf("not implemented: Transaction - transaction"))
+	txnHash := libcommon.HexToHash(hash)
+
+	res, err := r.GraphQLAPI.GetTransactionDetails(ctx, txnHash)
+	if err != nil {
+		return nil, err
+	}
+	if res != nil {
+		return convertTransaction(res, &model.Block{
+			Number: *convertDataToUint64P(res, "blockNumber"),
+			Hash:   *convertDataToStringP(res, "blockHash"),
+		}), ctx.Err()
+	}
+
+	// Not mined yet, it may still be in the pending block
+	txs, err := r.GraphQLAPI.GetPendingTransactions(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, txn := range txs {
+		if txn.Hash == txnHash {
+			return convertRPCTransaction(txn), nil
+		}
+	}
+
+	return nil, ctx.Err()
 }
 
 // Logs is the resolver for the logs field.
 func (r *queryResolver) Logs(ctx context.Context, filter model.FilterCriteria) ([]*model.Log, error) {
-	panic(fmt.Errorf("not implemented: Logs - logs"))
+	crit := filters.FilterCriteria{
+		Addresses: make([]libcommon.Address, 0, len(filter.Addresses)),
+		Topics:    make([][]libcommon.Hash, 0, len(filter.Topics)),
+	}
+	if filter.FromBlock != nil {
+		crit.FromBlock = new(big.Int).SetUint64(*filter.FromBlock)
+	}
+	if filter.ToBlock != nil {
+		crit.ToBlock = new(big.Int).SetUint64(*filter.ToBlock)
+	}
+	for _, address := range filter.Addresses {
+		crit.Addresses = append(crit.Addresses, libcommon.HexToAddress(address))
+	}
+	for _, topics := range filter.Topics {
+		position := make([]libcommon.Hash, 0, len(topics))
+		for _, topic := range topics {
+			position = append(position, libcommon.HexToHash(topic))
+		}
+		crit.Topics = append(crit.Topics, position)
+	}
+
+	logs, err := r.GraphQLAPI.GetLogs(ctx, crit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Log, 0, len(logs))
+	for _, rlog := range logs {
+		tlog := convertLog(rlog, rlog.BlockNumber)
+		tlog.Transaction = &model.Transaction{
+			Hash:  rlog.TxHash.Hex(),
+			Index: convertIntP(int(rlog.TxIndex)),
+			Block: &model.Block{
+				Number: rlog.BlockNumber,
+				Hash:   rlog.BlockHash.Hex(),
+			},
+		}
+		result = append(result, tlog)
+	}
+
+	return result, ctx.Err()
 }
 
 // GasPrice is the resolver for the gasPrice field.
 func (r *queryResolver) GasPrice(ctx context.Context) (string, error) {
-	panic(fmt.Errorf("not implemented: GasPrice - gasPrice"))
+	gasPrice, err := r.GraphQLAPI.GetGasPrice(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return gasPrice.String(), nil
 }
 
 // MaxPriorityFeePerGas is the resolver for the maxPriorityFeePerGas field.
 func (r *queryResolver) MaxPriorityFeePerGas(ctx context.Context) (string, error) {
-	panic(fmt.Errorf("not implemented: MaxPriorityFeePerGas - maxPriorityFeePerGas"))
+	tipCap, err := r.GraphQLAPI.GetMaxPriorityFeePerGas(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return tipCap.String(), nil
 }
 
 // Syncing is the resolver for the syncing field.
 func (r *queryResolver) Syncing(ctx context.Context) (*model.SyncState, error) {
-	panic(fmt.Errorf("not implemented: Syncing - syncing"))
+	res, err := r.GraphQLAPI.GetSyncing(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, ok := res.(map[string]interface{})
+	if !ok {
+		// Not syncing
+		return nil, nil
+	}
+
+	return &model.SyncState{
+		CurrentBlock: *convertDataToUint64P(progress, "currentBlock"),
+		HighestBlock: *convertDataToUint64P(progress, "highestBlock"),
+	}, nil
 }
 
 // ChainID is the resolver for the chainID field.
@@ -201,11 +283,15 @@ func (r *queryResolver) ChainID(ctx context.Context) (string, error) {
 	return "0x" + strconv.FormatUint(chainID.Uint64(), 16), err
 }
 
+// Account returns AccountResolver implementation.
+func (r *Resolver) Account() AccountResolver { return &accountResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+type accountResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
@@ -1,11 +1,29 @@
 package graphql
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
 	"io"
+	"math/big"
 	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"strings"
 	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/kvcache"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/commands"
+	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/rpcdaemontest"
+	"github.com/ledgerwatch/erigon/eth/filters"
+	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/rpc/rpccfg"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
 )
 
 func TestGraphQLQueryBlock(t *testing.T) {
@@ -126,3 +144,78 @@ func TestGraphQLQueryBlock(t *testing.T) {
 		}
 	}
 }
+
+func TestGraphQLResolvers(t *testing.T) {
+	m, chain, _ := rpcdaemontest.CreateTestSentry(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ff := rpchelper.New(ctx, nil, nil, nil, func() {}, m.Log)
+	base := commands.NewBaseApi(ff, kvcache.New(kvcache.DefaultCoherentConfig), m.BlockReader, m.HistoryV3Components(), false, rpccfg.DefaultEvmCallTimeout, m.Engine, m.Dirs)
+	ethImpl := commands.NewEthAPI(base, m.DB, nil, nil, nil, 5000000, 100_000, log.New())
+	handler := CreateHandler([]rpc.API{{Namespace: "graphql", Service: commands.GraphQLAPI(commands.NewGraphQLAPI(base, m.DB, ethImpl))}})
+
+	query := func(q string) map[string]interface{} {
+		body, err := json.Marshal(map[string]string{"query": q})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var resp struct {
+			Data   map[string]interface{} `json:"data"`
+			Errors []interface{}          `json:"errors"`
+		}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		require.Empty(t, resp.Errors, rec.Body.String())
+		return resp.Data
+	}
+
+	blocks := query(`{blocks(from:1,to:3){number}}`)["blocks"].([]interface{})
+	require.Len(t, blocks, 3)
+	require.Equal(t, float64(3), blocks[2].(map[string]interface{})["number"])
+	require.Len(t, query(`{blocks(from:3,to:1){number}}`)["blocks"], 0)
+
+	txn := chain.Blocks[0].Transactions()[0]
+	res := query(fmt.Sprintf(`{transaction(hash:"%s"){hash from{address} block{number hash}}}`, txn.Hash().Hex()))["transaction"].(map[string]interface{})
+	require.Equal(t, txn.Hash().Hex(), res["hash"])
+	require.Equal(t, float64(1), res["block"].(map[string]interface{})["number"])
+	require.Equal(t, chain.Blocks[0].Hash().Hex(), res["block"].(map[string]interface{})["hash"])
+	require.Nil(t, query(`{transaction(hash:"0x0000000000000000000000000000000000000000000000000000000000000001"){hash}}`)["transaction"])
+
+	// the accounts of a transaction are read at the state of its block
+	res = query(fmt.Sprintf(`{transaction(hash:"%s"){from{address balance transactionCount}}}`, txn.Hash().Hex()))["transaction"].(map[string]interface{})
+	from := res["from"].(map[string]interface{})
+	sender := libcommon.HexToAddress(from["address"].(string))
+	balance, err := ethImpl.GetBalance(ctx, sender, rpc.BlockNumberOrHashWithNumber(1))
+	require.NoError(t, err)
+	require.Equal(t, balance.String(), from["balance"])
+	nonce, err := ethImpl.GetTransactionCount(ctx, sender, rpc.BlockNumberOrHashWithNumber(1))
+	require.NoError(t, err)
+	require.Equal(t, float64(*nonce), from["transactionCount"])
+	latestNonce, err := ethImpl.GetTransactionCount(ctx, sender, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+	require.NoError(t, err)
+	require.NotEqual(t, *nonce, *latestNonce)
+
+	logs, err := ethImpl.GetLogs(context.Background(), filters.FilterCriteria{FromBlock: big.NewInt(0)})
+	require.NoError(t, err)
+	require.NotEmpty(t, logs)
+	res2 := query(`{logs(filter:{fromBlock:0}){index account{address} transaction{hash block{number}}}}`)["logs"].([]interface{})
+	require.Len(t, res2, len(logs))
+	first := res2[0].(map[string]interface{})
+	require.Equal(t, logs[0].TxHash.Hex(), first["transaction"].(map[string]interface{})["hash"])
+	require.Equal(t, strings.ToLower(logs[0].Address.Hex()), first["account"].(map[string]interface{})["address"])
+
+	res = query(fmt.Sprintf(`{transaction(hash:"%s"){logs{account{code storage(slot:"0x0000000000000000000000000000000000000000000000000000000000000000")}}}}`, logs[0].TxHash.Hex()))["transaction"].(map[string]interface{})
+	emitter := res["logs"].([]interface{})[0].(map[string]interface{})["account"].(map[string]interface{})
+	code, err := ethImpl.GetCode(ctx, logs[0].Address, rpc.BlockNumberOrHashWithNumber(rpc.BlockNumber(logs[0].BlockNumber)))
+	require.NoError(t, err)
+	require.NotEmpty(t, code)
+	require.Equal(t, code.String(), emitter["code"])
+	require.Regexp(t, "^0x[0-9a-f]{64}$", emitter["storage"])
+
+	require.Nil(t, query(`{syncing{currentBlock}}`)["syncing"])
+	require.Equal(t, float64(0), query(`{pending{transactionCount}}`)["pending"].(map[string]interface{})["transactionCount"])
+	require.Regexp(t, "^0x[0-9a-f]+$", query(`{gasPrice}`)["gasPrice"])
+	require.Regexp(t, "^0x[0-9a-f]+$", query(`{maxPriorityFeePerGas}`)["maxPriorityFeePerGas"])
+}
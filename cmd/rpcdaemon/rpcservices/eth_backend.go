@@ -7,11 +7,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"sync/atomic"
 
 	"github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	proto_sentry "github.com/ledgerwatch/erigon-lib/gointerfaces/sentry"
 	types2 "github.com/ledgerwatch/erigon-lib/gointerfaces/types"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/eth/ethconfig"
@@ -22,23 +24,28 @@ import (
 
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
 	"github.com/ledgerwatch/erigon/ethdb/privateapi"
 	"github.com/ledgerwatch/erigon/p2p"
+	"github.com/ledgerwatch/erigon/p2p/enode"
+	"github.com/ledgerwatch/erigon/p2p/peeradmin"
 	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/turbo/services"
 )
 
 type RemoteBackend struct {
 	remoteEthBackend remote.ETHBACKENDClient
+	peerAdmin        peeradmin.PeerAdminClient
 	log              log.Logger
 	version          gointerfaces.Version
 	db               kv.RoDB
 	blockReader      services.FullBlockReader
 }
 
-func NewRemoteBackend(client remote.ETHBACKENDClient, db kv.RoDB, blockReader services.FullBlockReader) *RemoteBackend {
+func NewRemoteBackend(client remote.ETHBACKENDClient, peerAdmin peeradmin.PeerAdminClient, db kv.RoDB, blockReader services.FullBlockReader) *RemoteBackend {
 	return &RemoteBackend{
 		remoteEthBackend: client,
+		peerAdmin:        peerAdmin,
 		version:          gointerfaces.VersionFromProto(privateapi.EthBackendAPIVersion),
 		log:              log.New("remote_service", "eth_backend"),
 		db:               db,
@@ -80,10 +87,11 @@ func (back *RemoteBackend) BlockByHash(ctx context.Context, db kv.Tx, hash commo
 	block, _, err := back.BlockWithSenders(ctx, db, hash, *number)
 	return block, err
 }
-func (back *RemoteBackend) TxsV3Enabled() bool                 { panic("not implemented") }
-func (back *RemoteBackend) Snapshots() services.BlockSnapshots { panic("not implemented") }
-func (back *RemoteBackend) FrozenBlocks() uint64               { return back.blockReader.FrozenBlocks() }
-func (back *RemoteBackend) FrozenFiles() (list []string)       { return back.blockReader.FrozenFiles() }
+func (back *RemoteBackend) TxsV3Enabled() bool                    { panic("not implemented") }
+func (back *RemoteBackend) Snapshots() services.BlockSnapshots    { panic("not implemented") }
+func (back *RemoteBackend) BorSnapshots() services.BlockSnapshots { panic("not implemented") }
+func (back *RemoteBackend) FrozenBlocks() uint64                  { return back.blockReader.FrozenBlocks() }
+func (back *RemoteBackend) FrozenFiles() (list []string)          { return back.blockReader.FrozenFiles() }
 func (back *RemoteBackend) FreezingCfg() ethconfig.BlocksFreezing {
 	return back.blockReader.FreezingCfg()
 }
@@ -243,6 +251,12 @@ func (back *RemoteBackend) HeaderByHash(ctx context.Context, tx kv.Getter, hash
 func (back *RemoteBackend) CanonicalHash(ctx context.Context, tx kv.Getter, blockNum uint64) (common.Hash, error) {
 	return back.blockReader.CanonicalHash(ctx, tx, blockNum)
 }
+func (back *RemoteBackend) EventsByBlock(ctx context.Context, tx kv.Getter, hash common.Hash, blockNum uint64) ([]byte, bool, error) {
+	return back.blockReader.EventsByBlock(ctx, tx, hash, blockNum)
+}
+func (back *RemoteBackend) Span(ctx context.Context, tx kv.Getter, spanID uint64) ([]byte, error) {
+	return back.blockReader.Span(ctx, tx, spanID)
+}
 func (back *RemoteBackend) TxnByIdxInBlock(ctx context.Context, tx kv.Getter, blockNum uint64, i int) (types.Transaction, error) {
 	return back.blockReader.TxnByIdxInBlock(ctx, tx, blockNum, i)
 }
@@ -366,3 +380,84 @@ func (back *RemoteBackend) PendingBlock(ctx context.Context) (*types.Block, erro
 
 	return &block, nil
 }
+
+// peerAdminOp returns an error listing the sentries on which the operation failed, if any
+func (back *RemoteBackend) peerAdminOp(ctx context.Context, url string, call func(peeradmin.PeerAdminClient, context.Context, *peeradmin.PeerRequest, ...grpc.CallOption) (*peeradmin.PeerReply, error)) (bool, error) {
+	if back.peerAdmin == nil {
+		return false, errors.New("peer administration is not available")
+	}
+	res, err := call(back.peerAdmin, ctx, &peeradmin.PeerRequest{Url: url})
+	if err != nil {
+		if s, ok := status.FromError(err); ok {
+			return false, errors.New(s.Message())
+		}
+		return false, err
+	}
+	if res.Success {
+		return true, nil
+	}
+	var failures []string
+	for i, sentry := range res.Sentries {
+		if !sentry.Success {
+			failures = append(failures, fmt.Sprintf("sentry %d: %s", i, sentry.Error))
+		}
+	}
+	if len(failures) == 0 {
+		return false, nil
+	}
+	return false, errors.New(strings.Join(failures, "; "))
+}
+
+func (back *RemoteBackend) AddPeer(ctx context.Context, url string) (bool, error) {
+	return back.peerAdminOp(ctx, url, peeradmin.PeerAdminClient.AddPeer)
+}
+
+func (back *RemoteBackend) RemovePeer(ctx context.Context, url string) (bool, error) {
+	return back.peerAdminOp(ctx, url, peeradmin.PeerAdminClient.RemovePeer)
+}
+
+func (back *RemoteBackend) AddTrustedPeer(ctx context.Context, url string) (bool, error) {
+	return back.peerAdminOp(ctx, url, peeradmin.PeerAdminClient.AddTrustedPeer)
+}
+
+func (back *RemoteBackend) RemoveTrustedPeer(ctx context.Context, url string) (bool, error) {
+	return back.peerAdminOp(ctx, url, peeradmin.PeerAdminClient.RemoveTrustedPeer)
+}
+
+func (back *RemoteBackend) PeerEvents(ctx context.Context, onEvent func(*p2p.PeerEvent)) error {
+	if back.peerAdmin == nil {
+		return errors.New("peer administration is not available")
+	}
+	subscription, err := back.peerAdmin.PeerEvents(ctx, &proto_sentry.PeerEventsRequest{}, grpc.WaitForReady(true))
+	if err != nil {
+		if s, ok := status.FromError(err); ok {
+			return errors.New(s.Message())
+		}
+		return err
+	}
+	for {
+		event, err := subscription.Recv()
+		if errors.Is(err, io.EOF) {
+			log.Debug("rpcdaemon: the peer events channel was closed")
+			break
+		}
+		if err != nil {
+			return err
+		}
+		onEvent(convertPeerEvent(event))
+	}
+	return nil
+}
+
+// convertPeerEvent identifies the peer by its node ID, the sentry reports its public key
+func convertPeerEvent(event *proto_sentry.PeerEvent) *p2p.PeerEvent {
+	pubkey := gointerfaces.ConvertH512ToHash(event.PeerId)
+	ev := &p2p.PeerEvent{Peer: enode.ID(crypto.Keccak256Hash(pubkey[:]))}
+	switch event.EventId {
+	case proto_sentry.PeerEvent_Connect:
+		ev.Type = p2p.PeerEventTypeAdd
+	case proto_sentry.PeerEvent_Disconnect:
+		ev.Type = p2p.PeerEventTypeDrop
+	}
+	return ev
+}
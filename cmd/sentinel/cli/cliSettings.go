@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ledgerwatch/erigon/cl/phase1/core"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/rawdb"
 	"github.com/ledgerwatch/erigon/cl/phase1/core/state"
 
@@ -29,8 +30,9 @@ type ConsensusClientCliCfg struct {
 	ServerTcpPort         uint                        `json:"serverTcpPort"`
 	LogLvl                uint                        `json:"logLevel"`
 	NoDiscovery           bool                        `json:"noDiscovery"`
-	CheckpointUri         string                      `json:"checkpointUri"`
+	CheckpointSync        core.CheckpointSyncConfig   `json:"checkpointSync"`
 	Chaindata             string                      `json:"chaindata"`
+	DataDir               string                      `json:"dataDir"`
 	ErigonPrivateApi      string                      `json:"erigonPrivateApi"`
 	TransitionChain       bool                        `json:"transitionChain"`
 	NetworkType           clparams.NetworkType        `json:"networkType"`
@@ -97,12 +99,19 @@ func SetupConsensusClientCfg(ctx *cli.Context) (*ConsensusClientCliCfg, error) {
 	}
 	cfg.NoDiscovery = ctx.Bool(flags.NoDiscovery.Name)
 	if ctx.String(flags.CheckpointSyncUrlFlag.Name) != "" {
-		cfg.CheckpointUri = ctx.String(flags.CheckpointSyncUrlFlag.Name)
+		cfg.CheckpointSync.Uris = utils.SplitAndTrim(ctx.String(flags.CheckpointSyncUrlFlag.Name))
 	} else {
-		cfg.CheckpointUri = clparams.GetCheckpointSyncEndpoint(cfg.NetworkType)
-		fmt.Println(cfg.CheckpointUri)
+		cfg.CheckpointSync.Uris = []string{clparams.GetCheckpointSyncEndpoint(cfg.NetworkType)}
+		fmt.Println(cfg.CheckpointSync.Uris)
+	}
+	cfg.CheckpointSync.File = ctx.String(flags.CheckpointSyncFileFlag.Name)
+	if ctx.String(flags.WeakSubjectivityCheckpointFlag.Name) != "" {
+		if cfg.CheckpointSync.WeakSubjectivityCheckpoint, err = core.ParseWeakSubjectivityCheckpoint(ctx.String(flags.WeakSubjectivityCheckpointFlag.Name)); err != nil {
+			return nil, err
+		}
 	}
 	cfg.Chaindata = ctx.String(flags.ChaindataFlag.Name)
+	cfg.DataDir = ctx.String(flags.DataDirFlag.Name)
 	cfg.BeaconDataCfg = rawdb.BeaconDataConfigurations[ctx.String(flags.BeaconDBModeFlag.Name)]
 	// Process bootnodes
 	if ctx.String(flags.BootnodesFlag.Name) != "" {
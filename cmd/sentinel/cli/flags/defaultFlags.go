@@ -16,11 +16,14 @@ var CLDefaultFlags = []cli.Flag{
 	&SentinelTcpPort,
 	&NoDiscovery,
 	&ChaindataFlag,
+	&DataDirFlag,
 	&BeaconDBModeFlag,
 	&BootnodesFlag,
 	&BeaconConfigFlag,
 	&GenesisSSZFlag,
 	&CheckpointSyncUrlFlag,
+	&CheckpointSyncFileFlag,
+	&WeakSubjectivityCheckpointFlag,
 	&SentinelStaticPeersFlag,
 	&TransitionChainFlag,
 	&InitSyncFlag,
@@ -2,6 +2,8 @@ package flags
 
 import (
 	"github.com/urfave/cli/v2"
+
+	"github.com/ledgerwatch/erigon/common/paths"
 )
 
 var (
@@ -85,6 +87,11 @@ var (
 		Usage: "chaindata of database",
 		Value: "",
 	}
+	DataDirFlag = cli.StringFlag{
+		Name:  "datadir",
+		Usage: "data directory, the blob sidecars and light client updates are kept in its caplin subdirectory",
+		Value: paths.DefaultDataDir(),
+	}
 	BeaconDBModeFlag = cli.StringFlag{
 		Name:  "beacon-db-mode",
 		Usage: "level of storing on beacon chain, minimal(only 500k blocks stored), full (all blocks stored), light (no blocks stored)",
@@ -92,7 +99,17 @@ var (
 	}
 	CheckpointSyncUrlFlag = cli.StringFlag{
 		Name:  "checkpoint-sync-url",
-		Usage: "checkpoint sync endpoint",
+		Usage: "comma-separated checkpoint sync endpoints, which must all serve the same state",
+		Value: "",
+	}
+	CheckpointSyncFileFlag = cli.StringFlag{
+		Name:  "checkpoint-sync-file",
+		Usage: "SSZ encoded checkpoint state to start from, instead of the checkpoint sync endpoints",
+		Value: "",
+	}
+	WeakSubjectivityCheckpointFlag = cli.StringFlag{
+		Name:  "weak-subjectivity-checkpoint",
+		Usage: "trusted checkpoint, as block_root:epoch, which the checkpoint state must match",
 		Value: "",
 	}
 	ErigonPrivateApiFlag = cli.StringFlag{
@@ -19,6 +19,8 @@ import (
 	"net"
 
 	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/freezer"
+	"github.com/ledgerwatch/erigon/cl/phase1/light_client"
 	"github.com/ledgerwatch/log/v3"
 	"github.com/libp2p/go-libp2p"
 	"github.com/libp2p/go-libp2p/core/crypto"
@@ -43,6 +45,8 @@ type SentinelConfig struct {
 	HostDNS       string
 	NoDiscovery   bool
 	TmpDir        string
+	BlobSidecars  *freezer.BlobSidecarStore // Served to the peers requesting blob sidecars
+	LightClient   *light_client.Store       // Served to the peers requesting light client data
 }
 
 func convertToCryptoPrivkey(privkey *ecdsa.PrivateKey) (crypto.PrivKey, error) {
@@ -0,0 +1,61 @@
+// Package gossip extends the sentinel API of erigon-lib with the validation of the gossip messages by the consensus
+// client, and with the gossip types which the erigon-lib version this module is pinned to does not have.
+package gossip
+
+import (
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/sentinel"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"google.golang.org/grpc"
+)
+
+// The generated code is produced from gossip.proto, its p2psentinel/sentinel.proto import is the one of erigon-lib:
+//
+//	protoc --proto_path=. --proto_path=<erigon-lib>/interfaces \
+//		--go_out=paths=source_relative:. --go-grpc_out=paths=source_relative:. \
+//		--go_opt=Mp2psentinel/sentinel.proto=github.com/ledgerwatch/erigon-lib/gointerfaces/sentinel \
+//		--go-grpc_opt=Mp2psentinel/sentinel.proto=github.com/ledgerwatch/erigon-lib/gointerfaces/sentinel \
+//		gossip/gossip.proto
+
+// BlsToExecutionChangeGossipType is the type of the messages of the bls_to_execution_change topic, next to the ones of
+// sentinel.GossipType.
+const BlsToExecutionChangeGossipType sentinel.GossipType = 6
+
+// IsValidated tells whether the messages of the gossip type wait for the validation result of the consensus client
+// before they are forwarded. These are the operations of the pool, the blocks and the blob sidecars are forwarded
+// after the checks of the sentinel so that their propagation doesn't wait for their import.
+func IsValidated(t sentinel.GossipType) bool {
+	switch t {
+	case sentinel.GossipType_AggregateAndProofGossipType,
+		sentinel.GossipType_VoluntaryExitGossipType,
+		sentinel.GossipType_ProposerSlashingGossipType,
+		sentinel.GossipType_AttesterSlashingGossipType,
+		BlsToExecutionChangeGossipType:
+		return true
+	}
+	return false
+}
+
+// Hash identifies a gossip message by its uncompressed data, the sentinel and the consensus client use it to match the
+// validation results with the messages.
+func Hash(data []byte) []byte {
+	h := utils.Keccak256(data)
+	return h[:]
+}
+
+// SentinelClient is the client of both the sentinel API and the gossip validation of a sentinel.
+type SentinelClient interface {
+	sentinel.SentinelClient
+	GossipValidatorClient
+}
+
+type sentinelClient struct {
+	sentinel.SentinelClient
+	GossipValidatorClient
+}
+
+func NewSentinelClient(cc grpc.ClientConnInterface) SentinelClient {
+	return &sentinelClient{
+		SentinelClient:        sentinel.NewSentinelClient(cc),
+		GossipValidatorClient: NewGossipValidatorClient(cc),
+	}
+}
@@ -0,0 +1,95 @@
+/*
+   Copyright 2022 Erigon-Lightclient contributors
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package handlers
+
+import (
+	"errors"
+	"io/fs"
+
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/cltypes/solid"
+	"github.com/ledgerwatch/erigon/cl/fork"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/cmd/sentinel/sentinel/communication/ssz_snappy"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/libp2p/go-libp2p/core/network"
+)
+
+// blobSidecarResponsePrefix is the prefix of every response chunk: the result and the context bytes of the deneb fork.
+func (c *ConsensusHandlers) blobSidecarResponsePrefix() ([]byte, error) {
+	digest, err := fork.ComputeForkDigestForVersion(utils.Uint32ToBytes4(c.beaconConfig.DenebForkVersion), c.genesisConfig.GenesisValidatorRoot)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{SuccessfulResponsePrefix}, digest[:]...), nil
+}
+
+func (c *ConsensusHandlers) blobSidecarsByRangeHandler(stream network.Stream) error {
+	log.Trace("Got blob sidecars by range handler call")
+	req := &cltypes.BlobsByRangeRequest{}
+	if err := ssz_snappy.DecodeAndReadNoForkDigest(stream, req, clparams.DenebVersion); err != nil {
+		return err
+	}
+	if c.blobs == nil {
+		return ssz_snappy.EncodeAndWrite(stream, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	prefix, err := c.blobSidecarResponsePrefix()
+	if err != nil {
+		return err
+	}
+	count := utils.Min64(req.Count, cltypes.MaxRequestBlobSidecars/cltypes.MaxBlobsPerBlock)
+	for slot := req.StartSlot; slot < req.StartSlot+count; slot++ {
+		sidecars, err := c.blobs.ImportedSidecars(slot)
+		if err != nil {
+			return err
+		}
+		for _, sidecar := range sidecars {
+			if err := ssz_snappy.EncodeAndWrite(stream, sidecar, prefix...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *ConsensusHandlers) blobSidecarsByRootHandler(stream network.Stream) error {
+	log.Trace("Got blob sidecars by root handler call")
+	req := solid.NewStaticListSSZ[*cltypes.BlobIdentifier](cltypes.MaxRequestBlobSidecars, 40)
+	if err := ssz_snappy.DecodeAndReadNoForkDigest(stream, req, clparams.DenebVersion); err != nil {
+		return err
+	}
+	if c.blobs == nil {
+		return ssz_snappy.EncodeAndWrite(stream, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	prefix, err := c.blobSidecarResponsePrefix()
+	if err != nil {
+		return err
+	}
+	req.Range(func(_ int, id *cltypes.BlobIdentifier, _ int) bool {
+		var sidecar *cltypes.BlobSideCar
+		sidecar, err = c.blobs.Get(id.BlockRoot, id.Index)
+		if errors.Is(err, fs.ErrNotExist) {
+			// sidecars which are not known are skipped
+			err = nil
+			return true
+		}
+		if err != nil {
+			return false
+		}
+		err = ssz_snappy.EncodeAndWrite(stream, sidecar, prefix...)
+		return err == nil
+	})
+	return err
+}
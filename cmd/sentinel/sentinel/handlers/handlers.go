@@ -20,6 +20,8 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/freezer"
+	"github.com/ledgerwatch/erigon/cl/phase1/light_client"
 	"github.com/ledgerwatch/erigon/cmd/sentinel/sentinel/communication"
 	"github.com/ledgerwatch/erigon/cmd/sentinel/sentinel/peers"
 	"github.com/ledgerwatch/log/v3"
@@ -37,7 +39,9 @@ type ConsensusHandlers struct {
 	genesisConfig *clparams.GenesisConfig
 	ctx           context.Context
 
-	db kv.RoDB // Read stuff from database to answer
+	db          kv.RoDB                   // Read stuff from database to answer
+	blobs       *freezer.BlobSidecarStore // Blob sidecars to answer, if any
+	lightClient *light_client.Store       // Light client data to answer, if any
 }
 
 const (
@@ -46,12 +50,14 @@ const (
 )
 
 func NewConsensusHandlers(ctx context.Context, db kv.RoDB, host host.Host,
-	peers *peers.Manager, beaconConfig *clparams.BeaconChainConfig, genesisConfig *clparams.GenesisConfig, metadata *cltypes.Metadata) *ConsensusHandlers {
+	peers *peers.Manager, beaconConfig *clparams.BeaconChainConfig, genesisConfig *clparams.GenesisConfig, metadata *cltypes.Metadata, blobs *freezer.BlobSidecarStore, lightClient *light_client.Store) *ConsensusHandlers {
 	c := &ConsensusHandlers{
 		peers:         peers,
 		host:          host,
 		metadata:      metadata,
 		db:            db,
+		blobs:         blobs,
+		lightClient:   lightClient,
 		genesisConfig: genesisConfig,
 		beaconConfig:  beaconConfig,
 		ctx:           ctx,
@@ -65,6 +71,13 @@ func NewConsensusHandlers(ctx context.Context, db kv.RoDB, host host.Host,
 		communication.MetadataProtocolV2:            c.metadataV2Handler,
 		communication.BeaconBlocksByRangeProtocolV1: c.blocksByRangeHandler,
 		communication.BeaconBlocksByRootProtocolV1:  c.beaconBlocksByRootHandler,
+		communication.BlobSidecarByRangeProtocolV1:  c.blobSidecarsByRangeHandler,
+		communication.BlobSidecarByRootProtocolV1:   c.blobSidecarsByRootHandler,
+
+		communication.LightClientBootstrapProtocolV1:        c.lightClientBootstrapHandler,
+		communication.LightClientUpdatesByRangeProtocolV1:   c.lightClientUpdatesByRangeHandler,
+		communication.LightClientFinalityUpdateProtocolV1:   c.lightClientFinalityUpdateHandler,
+		communication.LightClientOptimisticUpdateProtocolV1: c.lightClientOptimisticUpdateHandler,
 	}
 
 	c.handlers = map[protocol.ID]network.StreamHandler{}
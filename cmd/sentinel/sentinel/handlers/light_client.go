@@ -0,0 +1,93 @@
+/*
+   Copyright 2022 Erigon-Lightclient contributors
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+       http://www.apache.org/licenses/LICENSE-2.0
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package handlers
+
+import (
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/types/ssz"
+	"github.com/ledgerwatch/erigon/cl/clparams"
+	"github.com/ledgerwatch/erigon/cl/cltypes"
+	"github.com/ledgerwatch/erigon/cl/fork"
+	"github.com/ledgerwatch/erigon/cl/utils"
+	"github.com/ledgerwatch/erigon/cmd/sentinel/sentinel/communication"
+	"github.com/ledgerwatch/erigon/cmd/sentinel/sentinel/communication/ssz_snappy"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/libp2p/go-libp2p/core/network"
+)
+
+// writeLightClientObject writes a response chunk, whose context bytes are the fork digest of the version of the object.
+func (c *ConsensusHandlers) writeLightClientObject(stream network.Stream, obj ssz.Marshaler, version clparams.StateVersion) error {
+	digest, err := fork.ComputeForkDigestForVersion(utils.Uint32ToBytes4(c.beaconConfig.GetForkVersionByVersion(version)), c.genesisConfig.GenesisValidatorRoot)
+	if err != nil {
+		return err
+	}
+	return ssz_snappy.EncodeAndWrite(stream, obj, append([]byte{SuccessfulResponsePrefix}, digest[:]...)...)
+}
+
+func (c *ConsensusHandlers) lightClientBootstrapHandler(stream network.Stream) error {
+	log.Trace("Got light client bootstrap handler call")
+	root := &cltypes.Root{}
+	if err := ssz_snappy.DecodeAndReadNoForkDigest(stream, root, clparams.AltairVersion); err != nil {
+		return err
+	}
+	if c.lightClient == nil {
+		return ssz_snappy.EncodeAndWrite(stream, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	bootstrap, ok := c.lightClient.Bootstrap(libcommon.Hash(*root))
+	if !ok {
+		return ssz_snappy.EncodeAndWrite(stream, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	return c.writeLightClientObject(stream, bootstrap, bootstrap.Version())
+}
+
+func (c *ConsensusHandlers) lightClientUpdatesByRangeHandler(stream network.Stream) error {
+	log.Trace("Got light client updates by range handler call")
+	req := &cltypes.LightClientUpdatesByRangeRequest{}
+	if err := ssz_snappy.DecodeAndReadNoForkDigest(stream, req, clparams.AltairVersion); err != nil {
+		return err
+	}
+	if c.lightClient == nil {
+		return ssz_snappy.EncodeAndWrite(stream, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	for _, update := range c.lightClient.Updates(req.StartPeriod, utils.Min64(req.Count, communication.MaximumRequestClientUpdates)) {
+		if err := c.writeLightClientObject(stream, update, update.Version()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ConsensusHandlers) lightClientFinalityUpdateHandler(stream network.Stream) error {
+	log.Trace("Got light client finality update handler call")
+	if c.lightClient == nil {
+		return ssz_snappy.EncodeAndWrite(stream, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	update, ok := c.lightClient.FinalityUpdate()
+	if !ok {
+		return ssz_snappy.EncodeAndWrite(stream, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	return c.writeLightClientObject(stream, update, update.Version())
+}
+
+func (c *ConsensusHandlers) lightClientOptimisticUpdateHandler(stream network.Stream) error {
+	log.Trace("Got light client optimistic update handler call")
+	if c.lightClient == nil {
+		return ssz_snappy.EncodeAndWrite(stream, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	update, ok := c.lightClient.OptimisticUpdate()
+	if !ok {
+		return ssz_snappy.EncodeAndWrite(stream, &emptyString{}, ResourceUnavaiablePrefix)
+	}
+	return c.writeLightClientObject(stream, update, update.Version())
+}
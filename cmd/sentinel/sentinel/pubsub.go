@@ -43,6 +43,7 @@ const (
 	VoluntaryExitTopic           TopicName = "voluntary_exit"
 	ProposerSlashingTopic        TopicName = "proposer_slashing"
 	AttesterSlashingTopic        TopicName = "attester_slashing"
+	BlsToExecutionChangeTopic    TopicName = "bls_to_execution_change"
 	BlobSidecarTopic             TopicName = "blob_sidecar_%d" // This topic needs an index
 )
 
@@ -71,6 +72,10 @@ var AttesterSlashingSsz = GossipTopic{
 	Name:     AttesterSlashingTopic,
 	CodecStr: SSZSnappyCodec,
 }
+var BlsToExecutionChangeSsz = GossipTopic{
+	Name:     BlsToExecutionChangeTopic,
+	CodecStr: SSZSnappyCodec,
+}
 
 type GossipManager struct {
 	ch            chan *pubsub.Message
@@ -156,6 +161,16 @@ func (s *Sentinel) SubscribeGossip(topic GossipTopic, opts ...pubsub.TopicOpt) (
 	return sub, nil
 }
 
+// RegisterGossipValidator makes the messages of the topic go through the validator before they are delivered to the
+// subscription and forwarded to the other peers.
+func (s *Sentinel) RegisterGossipValidator(topic GossipTopic, validator pubsub.ValidatorEx) error {
+	digest, err := fork.ComputeForkDigest(s.cfg.BeaconConfig, s.cfg.GenesisConfig)
+	if err != nil {
+		return err
+	}
+	return s.pubsub.RegisterTopicValidator(fmt.Sprintf("/eth2/%x/%s/%s", digest, topic.Name, topic.CodecStr), validator)
+}
+
 func (s *Sentinel) Unsubscribe(topic GossipTopic, opts ...pubsub.TopicOpt) (err error) {
 	digest, err := fork.ComputeForkDigest(s.cfg.BeaconConfig, s.cfg.GenesisConfig)
 	if err != nil {
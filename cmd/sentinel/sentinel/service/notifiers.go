@@ -82,3 +82,10 @@ func (g *gossipNotifier) removeSubscriber(id int) error {
 	g.notifiers = append(g.notifiers[:id], g.notifiers[id+1:]...)
 	return nil
 }
+
+func (g *gossipNotifier) hasSubscribers() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return len(g.notifiers) > 0
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/ledgerwatch/erigon/cl/utils"
 	"github.com/ledgerwatch/erigon/cmd/sentinel/sentinel"
 	"github.com/ledgerwatch/erigon/cmd/sentinel/sentinel/communication"
+	"github.com/ledgerwatch/erigon/cmd/sentinel/sentinel/gossip"
 	"github.com/ledgerwatch/erigon/cmd/sentinel/sentinel/peers"
 	"github.com/ledgerwatch/log/v3"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
@@ -23,10 +24,12 @@ import (
 
 type SentinelServer struct {
 	sentinelrpc.UnimplementedSentinelServer
+	gossip.UnimplementedGossipValidatorServer
 
 	ctx            context.Context
 	sentinel       *sentinel.Sentinel
 	gossipNotifier *gossipNotifier
+	validations    *gossipValidations
 
 	mu     sync.RWMutex
 	logger log.Logger
@@ -37,16 +40,23 @@ func NewSentinelServer(ctx context.Context, sentinel *sentinel.Sentinel, logger
 		sentinel:       sentinel,
 		ctx:            ctx,
 		gossipNotifier: newGossipNotifier(),
+		validations:    newGossipValidations(),
 		logger:         logger,
 	}
 }
 
+// blobSideCarTopicPrefix is the name of the blob sidecar topics without their index
+var blobSideCarTopicPrefix = strings.TrimSuffix(string(sentinel.BlobSidecarTopic), "%d")
+
 // extractBlobSideCarIndex takes a topic and extract the blob sidecar
 func extractBlobSideCarIndex(topic string) int {
 	// compute the index prefixless
-	startIndex := strings.Index(topic, string(sentinel.BlobSidecarTopic)) + len(sentinel.BlobSidecarTopic)
-	endIndex := strings.Index(topic[:startIndex], "/")
-	blobIndex, err := strconv.Atoi(topic[startIndex:endIndex])
+	startIndex := strings.Index(topic, blobSideCarTopicPrefix) + len(blobSideCarTopicPrefix)
+	endIndex := strings.Index(topic[startIndex:], "/")
+	if endIndex < 0 {
+		endIndex = len(topic) - startIndex
+	}
+	blobIndex, err := strconv.Atoi(topic[startIndex : startIndex+endIndex])
 	if err != nil {
 		panic(fmt.Sprintf("should not be substribed to %s", topic))
 	}
@@ -87,6 +97,8 @@ func (s *SentinelServer) PublishGossip(_ context.Context, msg *sentinelrpc.Gossi
 		subscription = manager.GetMatchingSubscription(string(sentinel.ProposerSlashingTopic))
 	case sentinelrpc.GossipType_AttesterSlashingGossipType:
 		subscription = manager.GetMatchingSubscription(string(sentinel.AttesterSlashingTopic))
+	case gossip.BlsToExecutionChangeGossipType:
+		subscription = manager.GetMatchingSubscription(string(sentinel.BlsToExecutionChangeTopic))
 	case sentinelrpc.GossipType_BlobSidecarType:
 		if msg.BlobIndex == nil {
 			return &sentinelrpc.EmptyMessage{}, errors.New("cannot publish sidecar blob with no index")
@@ -237,31 +249,37 @@ func (s *SentinelServer) GetPeers(_ context.Context, _ *sentinelrpc.EmptyMessage
 	}, nil
 }
 
+func (s *SentinelServer) SetValidationResult(_ context.Context, in *gossip.GossipValidation) (*sentinelrpc.EmptyMessage, error) {
+	s.validations.set(in.Hash, in.Result)
+	return &sentinelrpc.EmptyMessage{}, nil
+}
+
+// ListenToGossip relays the messages of the subscriptions to the subscribers, except for the ones validateGossip
+// already relayed.
 func (s *SentinelServer) ListenToGossip() {
-	refreshTicker := time.NewTicker(100 * time.Millisecond)
-	defer refreshTicker.Stop()
 	for {
-		s.mu.RLock()
 		select {
 		case pkt := <-s.sentinel.RecvGossip():
-			s.handleGossipPacket(pkt)
+			if err := s.handleGossipPacket(pkt); err != nil {
+				s.logger.Debug("[Sentinel Gossip] failed to handle packet", "topic", *pkt.Topic, "err", err)
+			}
 		case <-s.ctx.Done():
 			return
-		case <-refreshTicker.C:
 		}
-		s.mu.RUnlock()
 	}
 }
 
 func (s *SentinelServer) handleGossipPacket(pkt *pubsub.Message) error {
-	var err error
-	s.logger.Trace("[Sentinel Gossip] Received Packet", "topic", pkt.Topic)
+	t, blobIndex, ok := gossipType(*pkt.Topic)
+	if !ok || gossip.IsValidated(t) {
+		return nil
+	}
+	s.logger.Trace("[Sentinel Gossip] Received Packet", "topic", *pkt.Topic)
 	data := pkt.GetData()
-
 	// If we use snappy codec then decompress it accordingly.
 	if strings.Contains(*pkt.Topic, sentinel.SSZSnappyCodec) {
-		data, err = utils.DecompressSnappy(data)
-		if err != nil {
+		var err error
+		if data, err = utils.DecompressSnappy(data); err != nil {
 			return err
 		}
 	}
@@ -269,21 +287,86 @@ func (s *SentinelServer) handleGossipPacket(pkt *pubsub.Message) error {
 	if err != nil {
 		return err
 	}
-	// Check to which gossip it belongs to.
-	if strings.Contains(*pkt.Topic, string(sentinel.BeaconBlockTopic)) {
-		s.gossipNotifier.notify(sentinelrpc.GossipType_BeaconBlockGossipType, data, string(textPid))
-	} else if strings.Contains(*pkt.Topic, string(sentinel.BeaconAggregateAndProofTopic)) {
-		s.gossipNotifier.notify(sentinelrpc.GossipType_AggregateAndProofGossipType, data, string(textPid))
-	} else if strings.Contains(*pkt.Topic, string(sentinel.VoluntaryExitTopic)) {
-		s.gossipNotifier.notify(sentinelrpc.GossipType_VoluntaryExitGossipType, data, string(textPid))
-	} else if strings.Contains(*pkt.Topic, string(sentinel.ProposerSlashingTopic)) {
-		s.gossipNotifier.notify(sentinelrpc.GossipType_ProposerSlashingGossipType, data, string(textPid))
-	} else if strings.Contains(*pkt.Topic, string(sentinel.AttesterSlashingTopic)) {
-		s.gossipNotifier.notify(sentinelrpc.GossipType_AttesterSlashingGossipType, data, string(textPid))
-	} else if strings.Contains(*pkt.Topic, string(sentinel.BlobSidecarTopic)) {
-		// extract the index
-
-		s.gossipNotifier.notifyBlob(sentinelrpc.GossipType_BlobSidecarType, data, string(textPid), extractBlobSideCarIndex(*pkt.Topic))
+	if t == sentinelrpc.GossipType_BlobSidecarType {
+		s.gossipNotifier.notifyBlob(t, data, string(textPid), blobIndex)
+	} else {
+		s.gossipNotifier.notify(t, data, string(textPid))
 	}
 	return nil
 }
+
+// gossipType tells which gossip message the topic carries, and the index of the blob sidecar topics
+func gossipType(topic string) (t sentinelrpc.GossipType, blobIndex int, ok bool) {
+	switch {
+	case strings.Contains(topic, string(sentinel.BeaconBlockTopic)):
+		return sentinelrpc.GossipType_BeaconBlockGossipType, 0, true
+	case strings.Contains(topic, string(sentinel.BeaconAggregateAndProofTopic)):
+		return sentinelrpc.GossipType_AggregateAndProofGossipType, 0, true
+	case strings.Contains(topic, string(sentinel.VoluntaryExitTopic)):
+		return sentinelrpc.GossipType_VoluntaryExitGossipType, 0, true
+	case strings.Contains(topic, string(sentinel.ProposerSlashingTopic)):
+		return sentinelrpc.GossipType_ProposerSlashingGossipType, 0, true
+	case strings.Contains(topic, string(sentinel.AttesterSlashingTopic)):
+		return sentinelrpc.GossipType_AttesterSlashingGossipType, 0, true
+	case strings.Contains(topic, string(sentinel.BlsToExecutionChangeTopic)):
+		return gossip.BlsToExecutionChangeGossipType, 0, true
+	case strings.Contains(topic, blobSideCarTopicPrefix):
+		return sentinelrpc.GossipType_BlobSidecarType, extractBlobSideCarIndex(topic), true
+	}
+	return 0, 0, false
+}
+
+func (s *SentinelServer) penalizePeer(pid peer.ID) {
+	s.sentinel.Peers().WithPeer(pid, func(peer *peers.Peer) {
+		peer.Penalize()
+	})
+}
+
+// validateGossip relays the operation to the subscribers and waits for their validation result, only the accepted
+// operations are forwarded to the other peers. Our own messages and the ones nobody consumes are accepted as they are.
+func (s *SentinelServer) validateGossip(ctx context.Context, pid peer.ID, msg *pubsub.Message) pubsub.ValidationResult {
+	if pid == s.sentinel.Host().ID() {
+		return pubsub.ValidationAccept
+	}
+	t, blobIndex, ok := gossipType(*msg.Topic)
+	if !ok || !s.gossipNotifier.hasSubscribers() {
+		return pubsub.ValidationAccept
+	}
+	s.logger.Trace("[Sentinel Gossip] Received Packet", "topic", *msg.Topic)
+	// If we use snappy codec then decompress it accordingly.
+	data := msg.GetData()
+	if strings.Contains(*msg.Topic, sentinel.SSZSnappyCodec) {
+		var err error
+		if data, err = utils.DecompressSnappy(data); err != nil {
+			s.penalizePeer(pid)
+			return pubsub.ValidationReject
+		}
+	}
+	hash := gossip.Hash(data)
+	result, ok := s.validations.add(hash)
+	if !ok {
+		return pubsub.ValidationIgnore
+	}
+	defer s.validations.remove(hash)
+	if t == sentinelrpc.GossipType_BlobSidecarType {
+		s.gossipNotifier.notifyBlob(t, data, pid.String(), blobIndex)
+	} else {
+		s.gossipNotifier.notify(t, data, pid.String())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, gossipValidationTimeout)
+	defer cancel()
+	select {
+	case r := <-result:
+		switch r {
+		case gossip.ValidationResult_Accept:
+			return pubsub.ValidationAccept
+		case gossip.ValidationResult_Reject:
+			s.penalizePeer(pid)
+			return pubsub.ValidationReject
+		}
+		return pubsub.ValidationIgnore
+	case <-ctx.Done():
+		return pubsub.ValidationIgnore
+	}
+}
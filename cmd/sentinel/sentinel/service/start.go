@@ -10,7 +10,9 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cmd/sentinel/sentinel"
+	"github.com/ledgerwatch/erigon/cmd/sentinel/sentinel/gossip"
 	"github.com/ledgerwatch/log/v3"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	rcmgrObs "github.com/libp2p/go-libp2p/p2p/host/resource-manager/obs"
 	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
@@ -33,24 +35,38 @@ func createSentinel(cfg *sentinel.SentinelConfig, db kv.RoDB, logger log.Logger)
 	if err := sent.Start(); err != nil {
 		return nil, err
 	}
+	return sent, nil
+}
+
+// subscribeGossip joins the gossip topics, the messages of the operations go through the validator before they are
+// forwarded
+func subscribeGossip(sent *sentinel.Sentinel, validator pubsub.ValidatorEx, logger log.Logger) {
 	gossipTopics := []sentinel.GossipTopic{
 		sentinel.BeaconBlockSsz,
-		//sentinel.BeaconAggregateAndProofSsz,
-		//sentinel.VoluntaryExitSsz,
-		//sentinel.ProposerSlashingSsz,
-		//sentinel.AttesterSlashingSsz,
+		sentinel.BeaconAggregateAndProofSsz,
+		sentinel.VoluntaryExitSsz,
+		sentinel.ProposerSlashingSsz,
+		sentinel.AttesterSlashingSsz,
+		sentinel.BlsToExecutionChangeSsz,
 	}
-	// gossipTopics = append(gossipTopics, sentinel.GossipSidecarTopics(params.MaxBlobsPerBlock)...)
+	gossipTopics = append(gossipTopics, sentinel.GossipSidecarTopics(cltypes.MaxBlobsPerBlock)...)
 
 	for _, v := range gossipTopics {
 		if err := sent.Unsubscribe(v); err != nil {
 			logger.Error("[Sentinel] failed to start sentinel", "err", err)
 			continue
 		}
+		if t, _, _ := gossipType(string(v.Name)); gossip.IsValidated(t) {
+			if err := sent.RegisterGossipValidator(v, validator); err != nil {
+				logger.Error("[Sentinel] failed to start sentinel", "err", err)
+				continue
+			}
+		}
 		// now lets separately connect to the gossip topics. this joins the room
 		subscriber, err := sent.SubscribeGossip(v)
 		if err != nil {
 			logger.Error("[Sentinel] failed to start sentinel", "err", err)
+			continue
 		}
 		// actually start the subscription, aka listening and sending packets to the sentinel recv channel
 		err = subscriber.Listen()
@@ -58,10 +74,9 @@ func createSentinel(cfg *sentinel.SentinelConfig, db kv.RoDB, logger log.Logger)
 			logger.Error("[Sentinel] failed to start sentinel", "err", err)
 		}
 	}
-	return sent, nil
 }
 
-func StartSentinelService(cfg *sentinel.SentinelConfig, db kv.RoDB, srvCfg *ServerConfig, creds credentials.TransportCredentials, initialStatus *cltypes.Status, logger log.Logger) (sentinelrpc.SentinelClient, error) {
+func StartSentinelService(cfg *sentinel.SentinelConfig, db kv.RoDB, srvCfg *ServerConfig, creds credentials.TransportCredentials, initialStatus *cltypes.Status, logger log.Logger) (gossip.SentinelClient, error) {
 	ctx := context.Background()
 	sent, err := createSentinel(cfg, db, logger)
 	if err != nil {
@@ -73,6 +88,7 @@ func StartSentinelService(cfg *sentinel.SentinelConfig, db kv.RoDB, srvCfg *Serv
 		sent.SetStatus(initialStatus)
 	}
 	server := NewSentinelServer(ctx, sent, logger)
+	subscribeGossip(sent, server.validateGossip, logger)
 	if creds == nil {
 		creds = insecure.NewCredentials()
 	}
@@ -100,7 +116,7 @@ WaitingLoop:
 		return nil, err
 	}
 
-	return sentinelrpc.NewSentinelClient(conn), nil
+	return gossip.NewSentinelClient(conn), nil
 }
 
 func StartServe(server *SentinelServer, srvCfg *ServerConfig, creds credentials.TransportCredentials) {
@@ -113,6 +129,7 @@ func StartServe(server *SentinelServer, srvCfg *ServerConfig, creds credentials.
 	go server.ListenToGossip()
 	// Regiser our server as a gRPC server
 	sentinelrpc.RegisterSentinelServer(gRPCserver, server)
+	gossip.RegisterGossipValidatorServer(gRPCserver, server)
 	if err := gRPCserver.Serve(lis); err != nil {
 		log.Warn("[Sentinel] could not serve service", "reason", err)
 	}
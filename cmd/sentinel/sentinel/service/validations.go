@@ -0,0 +1,59 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/erigon/cmd/sentinel/sentinel/gossip"
+)
+
+// gossipValidationTimeout bounds the wait for the validation result of the consumers, the message is ignored afterwards
+const gossipValidationTimeout = 4 * time.Second
+
+// gossipValidations are the messages waiting for the validation result of the consumers, by hash of their data
+type gossipValidations struct {
+	pending map[string]chan gossip.ValidationResult
+
+	mu sync.Mutex
+}
+
+func newGossipValidations() *gossipValidations {
+	return &gossipValidations{
+		pending: map[string]chan gossip.ValidationResult{},
+	}
+}
+
+// add starts waiting for the result of the message, unless the same data is already waiting for it
+func (v *gossipValidations) add(hash []byte) (chan gossip.ValidationResult, bool) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if _, ok := v.pending[string(hash)]; ok {
+		return nil, false
+	}
+	ch := make(chan gossip.ValidationResult, 1)
+	v.pending[string(hash)] = ch
+	return ch, true
+}
+
+func (v *gossipValidations) remove(hash []byte) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	delete(v.pending, string(hash))
+}
+
+// set gives the result to the message waiting for it, the results of unknown messages are dropped
+func (v *gossipValidations) set(hash []byte, result gossip.ValidationResult) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	ch, ok := v.pending[string(hash)]
+	if !ok {
+		return
+	}
+	select {
+	case ch <- result:
+	default: // the first result counts
+	}
+}
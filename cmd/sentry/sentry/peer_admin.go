@@ -0,0 +1,141 @@
+package sentry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	proto_sentry "github.com/ledgerwatch/erigon-lib/gointerfaces/sentry"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+
+	"github.com/ledgerwatch/erigon/p2p/enode"
+	"github.com/ledgerwatch/erigon/p2p/peeradmin"
+)
+
+// MultiPeerAdmin serves the PeerAdmin service for all the sentries of the node: the peers are added to and removed
+// from every sentry, the reply has the result on each of them, and the peer events of all of them are merged.
+type MultiPeerAdmin struct {
+	peeradmin.UnimplementedPeerAdminServer
+	sentries []peeradmin.PeerAdminClient
+}
+
+func NewMultiPeerAdmin(sentries []peeradmin.PeerAdminClient) *MultiPeerAdmin {
+	return &MultiPeerAdmin{sentries: sentries}
+}
+
+func (m *MultiPeerAdmin) forEach(ctx context.Context, in *peeradmin.PeerRequest, call func(peeradmin.PeerAdminClient, context.Context, *peeradmin.PeerRequest, ...grpc.CallOption) (*peeradmin.PeerReply, error)) (*peeradmin.PeerReply, error) {
+	if len(m.sentries) == 0 {
+		return nil, errors.New("no sentries")
+	}
+	reply := &peeradmin.PeerReply{Success: true, Sentries: make([]*peeradmin.SentryResult, len(m.sentries))}
+	for i, sentry := range m.sentries {
+		result := &peeradmin.SentryResult{}
+		if sentryReply, err := call(sentry, ctx, in); err != nil {
+			result.Error = err.Error()
+		} else {
+			result.Success = sentryReply.Success
+		}
+		reply.Success = reply.Success && result.Success
+		reply.Sentries[i] = result
+	}
+	return reply, nil
+}
+
+func (m *MultiPeerAdmin) AddPeer(ctx context.Context, in *peeradmin.PeerRequest) (*peeradmin.PeerReply, error) {
+	return m.forEach(ctx, in, peeradmin.PeerAdminClient.AddPeer)
+}
+
+func (m *MultiPeerAdmin) RemovePeer(ctx context.Context, in *peeradmin.PeerRequest) (*peeradmin.PeerReply, error) {
+	return m.forEach(ctx, in, peeradmin.PeerAdminClient.RemovePeer)
+}
+
+func (m *MultiPeerAdmin) AddTrustedPeer(ctx context.Context, in *peeradmin.PeerRequest) (*peeradmin.PeerReply, error) {
+	return m.forEach(ctx, in, peeradmin.PeerAdminClient.AddTrustedPeer)
+}
+
+func (m *MultiPeerAdmin) RemoveTrustedPeer(ctx context.Context, in *peeradmin.PeerRequest) (*peeradmin.PeerReply, error) {
+	return m.forEach(ctx, in, peeradmin.PeerAdminClient.RemoveTrustedPeer)
+}
+
+func (m *MultiPeerAdmin) PeerEvents(req *proto_sentry.PeerEventsRequest, server peeradmin.PeerAdmin_PeerEventsServer) error {
+	g, ctx := errgroup.WithContext(server.Context())
+	var sendLock sync.Mutex
+	for _, sentry := range m.sentries {
+		sentry := sentry
+		g.Go(func() error {
+			stream, err := sentry.PeerEvents(ctx, req, grpc.WaitForReady(true))
+			if err != nil {
+				return err
+			}
+			for {
+				event, err := stream.Recv()
+				if errors.Is(err, io.EOF) {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				sendLock.Lock()
+				err = server.Send(event)
+				sendLock.Unlock()
+				if err != nil {
+					return err
+				}
+			}
+		})
+	}
+	if len(m.sentries) == 0 {
+		<-ctx.Done()
+	}
+	return g.Wait()
+}
+
+// PeerAdminServer serves the PeerAdmin service of a sentry for its own p2p server
+type PeerAdminServer struct {
+	peeradmin.UnimplementedPeerAdminServer
+	ss *GrpcServer
+}
+
+func NewPeerAdminServer(ss *GrpcServer) *PeerAdminServer {
+	return &PeerAdminServer{ss: ss}
+}
+
+func (s *PeerAdminServer) op(url string, op func(*enode.Node)) (*peeradmin.PeerReply, error) {
+	if s.ss.P2pServer == nil {
+		return nil, errors.New("p2p server was not started")
+	}
+	node, err := enode.Parse(enode.ValidSchemes, url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid enode: %w", err)
+	}
+	op(node)
+	return &peeradmin.PeerReply{Success: true}, nil
+}
+
+// AddPeer adds the node to the static peers, the sentry keeps connecting to it.
+func (s *PeerAdminServer) AddPeer(_ context.Context, in *peeradmin.PeerRequest) (*peeradmin.PeerReply, error) {
+	return s.op(in.GetUrl(), func(node *enode.Node) { s.ss.P2pServer.AddPeer(node) })
+}
+
+// RemovePeer removes the node from the static peers and disconnects from it.
+func (s *PeerAdminServer) RemovePeer(_ context.Context, in *peeradmin.PeerRequest) (*peeradmin.PeerReply, error) {
+	return s.op(in.GetUrl(), func(node *enode.Node) { s.ss.P2pServer.RemovePeer(node) })
+}
+
+// AddTrustedPeer allows the node to connect even if the peer slots are full.
+func (s *PeerAdminServer) AddTrustedPeer(_ context.Context, in *peeradmin.PeerRequest) (*peeradmin.PeerReply, error) {
+	return s.op(in.GetUrl(), func(node *enode.Node) { s.ss.P2pServer.AddTrustedPeer(node) })
+}
+
+// RemoveTrustedPeer removes the node from the trusted peers, it isn't disconnected.
+func (s *PeerAdminServer) RemoveTrustedPeer(_ context.Context, in *peeradmin.PeerRequest) (*peeradmin.PeerReply, error) {
+	return s.op(in.GetUrl(), func(node *enode.Node) { s.ss.P2pServer.RemoveTrustedPeer(node) })
+}
+
+// PeerEvents subscribes to notifications about connected or lost peers.
+func (s *PeerAdminServer) PeerEvents(req *proto_sentry.PeerEventsRequest, server peeradmin.PeerAdmin_PeerEventsServer) error {
+	return s.ss.PeerEvents(req, server)
+}
@@ -0,0 +1,149 @@
+package sentry
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/gointerfaces"
+	proto_sentry "github.com/ledgerwatch/erigon-lib/gointerfaces/sentry"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/p2p"
+	"github.com/ledgerwatch/erigon/p2p/peeradmin"
+)
+
+const testEnode = "enode://a979fb575495b8d6db44f750317d0f4622bf4c2aa3365d6af7c284339968eef29b69ad0dce72a4d8db5ebb4968de0e3bec910127f134779fbcb0cb6d3331163c@52.16.188.185:30303"
+
+// fakePeerAdmin records the calls and sends an event for every peer id of the request
+type fakePeerAdmin struct {
+	peeradmin.UnimplementedPeerAdminServer
+	lock    sync.Mutex
+	calls   []string
+	reply   bool
+	err     error
+	peerIds [][64]byte
+}
+
+func (f *fakePeerAdmin) record(call string, in *peeradmin.PeerRequest) (*peeradmin.PeerReply, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.calls = append(f.calls, call+" "+in.GetUrl())
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &peeradmin.PeerReply{Success: f.reply}, nil
+}
+
+func (f *fakePeerAdmin) AddPeer(_ context.Context, in *peeradmin.PeerRequest) (*peeradmin.PeerReply, error) {
+	return f.record("add", in)
+}
+
+func (f *fakePeerAdmin) RemovePeer(_ context.Context, in *peeradmin.PeerRequest) (*peeradmin.PeerReply, error) {
+	return f.record("remove", in)
+}
+
+func (f *fakePeerAdmin) AddTrustedPeer(_ context.Context, in *peeradmin.PeerRequest) (*peeradmin.PeerReply, error) {
+	return f.record("addTrusted", in)
+}
+
+func (f *fakePeerAdmin) RemoveTrustedPeer(_ context.Context, in *peeradmin.PeerRequest) (*peeradmin.PeerReply, error) {
+	return f.record("removeTrusted", in)
+}
+
+func (f *fakePeerAdmin) PeerEvents(_ *proto_sentry.PeerEventsRequest, server peeradmin.PeerAdmin_PeerEventsServer) error {
+	for _, id := range f.peerIds {
+		if err := server.Send(&proto_sentry.PeerEvent{PeerId: gointerfaces.ConvertHashToH512(id), EventId: proto_sentry.PeerEvent_Connect}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collectPeerEvents drains the stream of the client
+func collectPeerEvents(t *testing.T, client peeradmin.PeerAdminClient) map[[64]byte]bool {
+	stream, err := client.PeerEvents(context.Background(), &proto_sentry.PeerEventsRequest{})
+	require.NoError(t, err)
+	ids := map[[64]byte]bool{}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			break
+		}
+		ids[gointerfaces.ConvertH512ToHash(event.PeerId)] = true
+	}
+	return ids
+}
+
+func TestMultiPeerAdmin(t *testing.T) {
+	s1 := &fakePeerAdmin{reply: true, peerIds: [][64]byte{{1}, {2}}}
+	s2 := &fakePeerAdmin{reply: true, peerIds: [][64]byte{{3}}}
+	multi := NewMultiPeerAdmin([]peeradmin.PeerAdminClient{peeradmin.NewPeerAdminClientDirect(s1), peeradmin.NewPeerAdminClientDirect(s2)})
+	client := peeradmin.NewPeerAdminClientDirect(multi)
+	ctx := context.Background()
+
+	reply, err := client.AddPeer(ctx, &peeradmin.PeerRequest{Url: testEnode})
+	require.NoError(t, err)
+	require.True(t, reply.Success)
+	require.Len(t, reply.Sentries, 2)
+	_, err = client.RemoveTrustedPeer(ctx, &peeradmin.PeerRequest{Url: testEnode})
+	require.NoError(t, err)
+	for _, s := range []*fakePeerAdmin{s1, s2} {
+		require.Equal(t, []string{"add " + testEnode, "removeTrusted " + testEnode}, s.calls)
+	}
+
+	// The events of all the sentries are merged
+	require.Equal(t, map[[64]byte]bool{{1}: true, {2}: true, {3}: true}, collectPeerEvents(t, client))
+
+	// A failing sentry doesn't stop the others, and the reply tells which one failed
+	s1.err = errors.New("p2p server was not started")
+	reply, err = client.RemovePeer(ctx, &peeradmin.PeerRequest{Url: testEnode})
+	require.NoError(t, err)
+	require.False(t, reply.Success)
+	require.False(t, reply.Sentries[0].Success)
+	require.Equal(t, "p2p server was not started", reply.Sentries[0].Error)
+	require.True(t, reply.Sentries[1].Success)
+	require.Equal(t, "remove "+testEnode, s2.calls[2])
+
+	_, err = NewMultiPeerAdmin(nil).AddPeer(ctx, &peeradmin.PeerRequest{Url: testEnode})
+	require.ErrorContains(t, err, "no sentries")
+}
+
+func TestPeerAdminGrpc(t *testing.T) {
+	fake := &fakePeerAdmin{reply: true, peerIds: [][64]byte{{1}, {2}}}
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	peeradmin.RegisterPeerAdminServer(server, fake)
+	go server.Serve(listener) //nolint:errcheck
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := peeradmin.NewPeerAdminClient(conn)
+
+	reply, err := client.AddTrustedPeer(context.Background(), &peeradmin.PeerRequest{Url: testEnode})
+	require.NoError(t, err)
+	require.True(t, reply.Success)
+	require.Equal(t, []string{"addTrusted " + testEnode}, fake.calls)
+	require.Equal(t, map[[64]byte]bool{{1}: true, {2}: true}, collectPeerEvents(t, client))
+}
+
+func TestGrpcServerPeerAdminErrors(t *testing.T) {
+	ss := &GrpcServer{}
+	s := NewPeerAdminServer(ss)
+	_, err := s.AddPeer(context.Background(), &peeradmin.PeerRequest{Url: testEnode})
+	require.ErrorContains(t, err, "p2p server was not started")
+
+	ss.P2pServer = &p2p.Server{}
+	_, err = s.RemovePeer(context.Background(), &peeradmin.PeerRequest{Url: "enode://" + common.Bytes2Hex([]byte{1})})
+	require.ErrorContains(t, err, "invalid enode")
+}
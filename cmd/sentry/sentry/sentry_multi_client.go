@@ -36,6 +36,7 @@ import (
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/eth/ethconfig"
 	"github.com/ledgerwatch/erigon/eth/protocols/eth"
+	"github.com/ledgerwatch/erigon/p2p/peeradmin"
 	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/turbo/engineapi"
 	"github.com/ledgerwatch/erigon/turbo/services"
@@ -805,7 +806,8 @@ func (cs *MultiClient) makeStatusData() *proto_sentry.StatusData {
 	}
 }
 
-func GrpcClient(ctx context.Context, sentryAddr string) (*direct.SentryClientRemote, error) {
+// GrpcClient connects to a remote sentry, its Sentry and PeerAdmin services share the connection
+func GrpcClient(ctx context.Context, sentryAddr string) (*direct.SentryClientRemote, peeradmin.PeerAdminClient, error) {
 	// creating grpc client connection
 	var dialOpts []grpc.DialOption
 
@@ -821,7 +823,7 @@ func GrpcClient(ctx context.Context, sentryAddr string) (*direct.SentryClientRem
 	dialOpts = append(dialOpts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	conn, err := grpc.DialContext(ctx, sentryAddr, dialOpts...)
 	if err != nil {
-		return nil, fmt.Errorf("creating client connection to sentry P2P: %w", err)
+		return nil, nil, fmt.Errorf("creating client connection to sentry P2P: %w", err)
 	}
-	return direct.NewSentryClientRemote(proto_sentry.NewSentryClient(conn)), nil
+	return direct.NewSentryClientRemote(proto_sentry.NewSentryClient(conn)), peeradmin.NewPeerAdminClient(conn), nil
 }
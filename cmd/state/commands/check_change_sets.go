@@ -83,7 +83,7 @@ func CheckChangeSets(genesis *types.Genesis, blockNum uint64, chaindata string,
 	if err := allSnapshots.ReopenFolder(); err != nil {
 		return fmt.Errorf("reopen snapshot segments: %w", err)
 	}
-	blockReader := freezeblocks.NewBlockReader(allSnapshots)
+	blockReader := freezeblocks.NewBlockReader(allSnapshots, nil)
 
 	chainDb := db
 	defer chainDb.Close()
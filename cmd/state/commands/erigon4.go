@@ -239,7 +239,7 @@ func Erigon4(genesis *types.Genesis, chainConfig *chain2.Config, logger log.Logg
 	if err := allSnapshots.ReopenFolder(); err != nil {
 		return fmt.Errorf("reopen snapshot segments: %w", err)
 	}
-	blockReader = freezeblocks.NewBlockReader(allSnapshots)
+	blockReader = freezeblocks.NewBlockReader(allSnapshots, nil)
 	engine := initConsensusEngine(chainConfig, allSnapshots, logger)
 
 	getHeader := func(hash libcommon.Hash, number uint64) *types.Header {
@@ -617,5 +617,5 @@ func initConsensusEngine(cc *chain2.Config, snapshots *freezeblocks.RoSnapshots,
 		consensusConfig = &config.Ethash
 	}
 	return ethconsensusconfig.CreateConsensusEngine(cc, consensusConfig, config.Miner.Notify, config.Miner.Noverify, config.HeimdallgRPCAddress,
-		config.HeimdallURL, config.WithoutHeimdall, datadirCli, true /* readonly */, logger)
+		config.HeimdallURL, config.WithoutHeimdall, config.HeimdallCacheMode, datadirCli, true /* readonly */, logger)
 }
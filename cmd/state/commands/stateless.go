@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	kv2 "github.com/ledgerwatch/erigon-lib/kv/mdbx"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/spf13/cobra"
+
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/turbo/debug"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/freezeblocks"
+	"github.com/ledgerwatch/erigon/turbo/transactions"
+	"github.com/ledgerwatch/erigon/turbo/trie"
+)
+
+var witnessFile string
+
+func init() {
+	withBlock(statelessCmd)
+	withDataDir(statelessCmd)
+	withChain(statelessCmd)
+	statelessCmd.Flags().StringVar(&witnessFile, "witness", "", "path to the witness of the block, binary or hex encoded as returned by debug_executionWitness")
+	must(statelessCmd.MarkFlagRequired("witness"))
+	rootCmd.AddCommand(statelessCmd)
+}
+
+var statelessCmd = &cobra.Command{
+	Use:   "stateless",
+	Short: "Executes a block using only the state witness of its parent and checks the resulting state root",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var logger log.Logger
+		var err error
+		if logger, err = debug.SetupCobra(cmd, "stateless"); err != nil {
+			logger.Error("Setting up", "error", err)
+			return err
+		}
+		return Stateless(genesis, block, chaindata, witnessFile, logger)
+	},
+}
+
+// Stateless executes the block on the state of the witness and compares the resulting state root with the root of
+// the block header. The database is only used to read the block and the headers of its ancestors.
+func Stateless(genesis *types.Genesis, blockNum uint64, chaindata string, witnessFile string, logger log.Logger) error {
+	witness, err := readWitness(witnessFile)
+	if err != nil {
+		return err
+	}
+
+	db, err := kv2.NewMDBX(logger).Path(chaindata).Readonly().Open()
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	allSnapshots := freezeblocks.NewRoSnapshots(ethconfig.NewSnapCfg(true, false, true), path.Join(datadirCli, "snapshots"), logger)
+	defer allSnapshots.Close()
+	if err := allSnapshots.ReopenFolder(); err != nil {
+		return fmt.Errorf("reopen snapshot segments: %w", err)
+	}
+	blockReader := freezeblocks.NewBlockReader(allSnapshots, nil)
+
+	ctx := context.Background()
+	tx, err := db.BeginRo(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if blockNum == 0 {
+		return fmt.Errorf("genesis block has no parent")
+	}
+	blockHash, err := blockReader.CanonicalHash(ctx, tx, blockNum)
+	if err != nil {
+		return err
+	}
+	b, _, err := blockReader.BlockWithSenders(ctx, tx, blockHash, blockNum)
+	if err != nil {
+		return err
+	}
+	if b == nil {
+		return fmt.Errorf("block %d not found", blockNum)
+	}
+	parent, err := blockReader.Header(ctx, tx, b.ParentHash(), blockNum-1)
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		return fmt.Errorf("parent of block %d not found", blockNum)
+	}
+
+	chainConfig := genesis.Config
+	engine := initConsensusEngine(chainConfig, allSnapshots, logger)
+	getHeader := func(hash libcommon.Hash, number uint64) *types.Header {
+		h, _ := blockReader.Header(ctx, tx, hash, number)
+		return h
+	}
+	chainReader := stagedsync.NewChainReaderImpl(chainConfig, tx, blockReader)
+	root, err := transactions.ExecuteBlockStateless(engine, b, parent.Root, witness, chainConfig, getHeader, chainReader)
+	if err != nil {
+		return fmt.Errorf("block %d: %w", blockNum, err)
+	}
+	if root != b.Root() {
+		return fmt.Errorf("block %d: state root %x after stateless execution, expected %x", blockNum, root, b.Root())
+	}
+	logger.Info("Stateless execution matches the state root", "block", blockNum, "root", root)
+	return nil
+}
+
+// readWitness reads a serialized witness, which may be hex encoded with 0x prefix (optionally as a JSON string)
+func readWitness(fileName string) (*trie.Witness, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if trimmed := bytes.Trim(bytes.TrimSpace(data), `"`); bytes.HasPrefix(trimmed, []byte("0x")) {
+		if data, err = hexutil.Decode(string(trimmed)); err != nil {
+			return nil, fmt.Errorf("decoding witness: %w", err)
+		}
+	}
+	return trie.NewWitnessFromReader(bytes.NewReader(data), false /* trace */)
+}
@@ -159,7 +159,16 @@ func (rw *Worker) RunTxTaskNoLock(txTask *exec22.TxTask) {
 		syscall := func(contract libcommon.Address, data []byte, ibs *state.IntraBlockState, header *types.Header, constCall bool) ([]byte, error) {
 			return core.SysCallContract(contract, data, rw.chainConfig, ibs, header, rw.engine, constCall /* constCall */)
 		}
-		rw.engine.Initialize(rw.chainConfig, rw.chain, header, ibs, txTask.Txs, txTask.Uncles, syscall)
+		// The parallel workers don't verify the header, only the serial execution does
+		if !rw.background {
+			if err := core.VerifyBlockFamily(rw.engine, rw.chain, header, rw.chainConfig, rw.stateReader); err != nil {
+				txTask.Error = err
+				break
+			}
+		}
+		if err := rw.engine.Initialize(rw.chainConfig, rw.chain, header, ibs, txTask.Txs, txTask.Uncles, syscall); err != nil {
+			txTask.Error = err
+		}
 	case txTask.Final:
 		if txTask.BlockNum == 0 {
 			break
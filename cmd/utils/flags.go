@@ -25,6 +25,7 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/c2h5oh/datasize"
 	"github.com/ledgerwatch/log/v3"
@@ -136,6 +137,21 @@ var (
 		Name:  "internalcl",
 		Usage: "enables internal consensus",
 	}
+	InternalConsensusCheckpointSyncUrlFlag = cli.StringFlag{
+		Name:  "internalcl.checkpoint-sync.url",
+		Usage: "Comma-separated checkpoint sync endpoints of the internal consensus, which must all serve the same state",
+		Value: "",
+	}
+	InternalConsensusCheckpointSyncFileFlag = cli.StringFlag{
+		Name:  "internalcl.checkpoint-sync.file",
+		Usage: "SSZ encoded checkpoint state for the internal consensus to start from, instead of the checkpoint sync endpoints",
+		Value: "",
+	}
+	InternalConsensusWeakSubjectivityCheckpointFlag = cli.StringFlag{
+		Name:  "internalcl.weak-subjectivity-checkpoint",
+		Usage: "Trusted checkpoint of the internal consensus, as block_root:epoch, which the checkpoint state must match",
+		Value: "",
+	}
 	// Transaction pool settings
 	TxPoolDisableFlag = cli.BoolFlag{
 		Name:  "txpool.disable",
@@ -366,6 +382,16 @@ var (
 		Usage: "Maximum number of bytes returned from eth_call or similar invocations",
 		Value: 100_000,
 	}
+	RpcGetProofMaxRewindFlag = cli.Uint64Flag{
+		Name:  "rpc.getproof.maxrewind",
+		Usage: "Maximum number of blocks eth_getProof may rewind the state to prove a past block. Higher values make old proofs more expensive to compute",
+		Value: 1_000,
+	}
+	RpcLogsReplayMaxBlocksFlag = cli.Uint64Flag{
+		Name:  "rpc.logs.replay.maxblocks",
+		Usage: "Maximum number of past blocks whose logs a logs subscription with fromBlock may replay",
+		Value: 10_000,
+	}
 	HTTPTraceFlag = cli.BoolFlag{
 		Name:  "http.trace",
 		Usage: "Trace HTTP requests with INFO level",
@@ -379,6 +405,10 @@ var (
 		Name:  "rpc.accessList",
 		Usage: "Specify granular (method-by-method) API allowlist",
 	}
+	RpcRateLimitsFlag = cli.StringFlag{
+		Name:  "rpc.ratelimits",
+		Usage: "Specify a JSON file with the per-method and per-origin rate limits, costs and concurrency caps of the JSON-RPC calls",
+	}
 
 	RpcGasCapFlag = cli.UintFlag{
 		Name:  "rpc.gascap",
@@ -734,6 +764,13 @@ var (
 		Usage: "Run without Heimdall service (for testing purpose)",
 	}
 
+	// HeimdallCacheModeFlag what is kept of the heimdall responses
+	HeimdallCacheModeFlag = cli.StringFlag{
+		Name:  "bor.heimdall.cache",
+		Usage: "What is kept of the Heimdall responses in the bor DB: nothing with 'off', the ones which never change with 'cache', all of them with 'record', or 'replay' the recorded ones without calling Heimdall",
+		Value: "off",
+	}
+
 	// HeimdallgRPCAddressFlag flag for heimdall gRPC address
 	HeimdallgRPCAddressFlag = cli.StringFlag{
 		Name:  "bor.heimdallgRPC",
@@ -771,6 +808,30 @@ var (
 		Usage: "Port for sentinel",
 		Value: 7777,
 	}
+	BeaconAPIFlag = cli.BoolFlag{
+		Name:  "beacon.api",
+		Usage: "Enables the Beacon API of the internal consensus client (--internalcl)",
+	}
+	BeaconAPIAddrFlag = cli.StringFlag{
+		Name:  "beacon.api.addr",
+		Usage: "Host to listen for Beacon API requests",
+		Value: "localhost",
+	}
+	BeaconAPIPortFlag = cli.Uint64Flag{
+		Name:  "beacon.api.port",
+		Usage: "Port to listen for Beacon API requests",
+		Value: 5555,
+	}
+	BeaconAPIReadTimeoutFlag = cli.DurationFlag{
+		Name:  "beacon.api.read.timeout",
+		Usage: "Read timeout of the Beacon API",
+		Value: 5 * time.Second,
+	}
+	BeaconAPIWriteTimeoutFlag = cli.DurationFlag{
+		Name:  "beacon.api.write.timeout",
+		Usage: "Write timeout of the Beacon API",
+		Value: 5 * time.Second,
+	}
 )
 
 var MetricFlags = []cli.Flag{&MetricsEnabledFlag, &MetricsHTTPFlag, &MetricsPortFlag}
@@ -1339,6 +1400,7 @@ func setClique(ctx *cli.Context, cfg *params.ConsensusSnapshotConfig, datadir st
 func setBorConfig(ctx *cli.Context, cfg *ethconfig.Config) {
 	cfg.HeimdallURL = ctx.String(HeimdallURLFlag.Name)
 	cfg.WithoutHeimdall = ctx.Bool(WithoutHeimdallFlag.Name)
+	cfg.HeimdallCacheMode = ctx.String(HeimdallCacheModeFlag.Name)
 	cfg.HeimdallgRPCAddress = ctx.String(HeimdallgRPCAddressFlag.Name)
 }
 
@@ -1440,6 +1502,16 @@ func SetEthConfig(ctx *cli.Context, nodeConfig *nodecfg.Config, cfg *ethconfig.C
 	cfg.LightClientDiscoveryTCPPort = ctx.Uint64(LightClientDiscoveryTCPPortFlag.Name)
 	cfg.SentinelAddr = ctx.String(SentinelAddrFlag.Name)
 	cfg.SentinelPort = ctx.Uint64(SentinelPortFlag.Name)
+	cfg.BeaconAPI = ctx.Bool(BeaconAPIFlag.Name)
+	cfg.BeaconAPIAddr = ctx.String(BeaconAPIAddrFlag.Name)
+	cfg.BeaconAPIPort = ctx.Uint64(BeaconAPIPortFlag.Name)
+	cfg.BeaconAPIReadTimeout = ctx.Duration(BeaconAPIReadTimeoutFlag.Name)
+	cfg.BeaconAPIWriteTimeout = ctx.Duration(BeaconAPIWriteTimeoutFlag.Name)
+	if urls := ctx.String(InternalConsensusCheckpointSyncUrlFlag.Name); urls != "" {
+		cfg.CheckpointSyncUrls = SplitAndTrim(urls)
+	}
+	cfg.CheckpointSyncFile = ctx.String(InternalConsensusCheckpointSyncFileFlag.Name)
+	cfg.WeakSubjectivityCheckpoint = ctx.String(InternalConsensusWeakSubjectivityCheckpointFlag.Name)
 
 	cfg.Sync.UseSnapshots = ethconfig.UseSnapshotsByChainName(ctx.String(ChainFlag.Name))
 	if ctx.IsSet(SnapshotFlag.Name) { //force override default by cli
@@ -1628,6 +1700,8 @@ func CobraFlags(cmd *cobra.Command, urfaveCliFlagsLists ...[]cli.Flag) {
 				flags.String(f.Name, f.Value, f.Usage)
 			case *cli.BoolFlag:
 				flags.Bool(f.Name, false, f.Usage)
+			case *cli.Float64Flag:
+				flags.Float64(f.Name, f.Value, f.Usage)
 			default:
 				panic(fmt.Errorf("unexpected type: %T", flag))
 			}
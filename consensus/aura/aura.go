@@ -24,6 +24,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru/arc/v2"
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/log/v3"
 
@@ -92,9 +93,24 @@ func (s *Step) optCalibrate() bool {
 	return true
 }
 
+// checkFuture rejects the steps too far ahead of the current step. It's analog of check_future in OE,
+// but it doesn't make the caller wait for the steps of the near future.
+func (s *Step) checkFuture(given uint64) error {
+	if given <= s.inner.Load() {
+		return nil
+	}
+	// Make absolutely sure that the given step is incorrect.
+	if s.calibrate {
+		s.optCalibrate()
+	}
+	if current := s.inner.Load(); given > current+rejectedStepDrift {
+		return fmt.Errorf("%w: step=%d, current=%d", errFutureStep, given, current)
+	}
+	return nil
+}
+
 type ReceivedStepHashes map[uint64]map[libcommon.Address]libcommon.Hash //BTreeMap<(u64, Address), H256>
 
-// nolint
 func (r ReceivedStepHashes) get(step uint64, author libcommon.Address) (libcommon.Hash, bool) {
 	res, ok := r[step]
 	if !ok {
@@ -104,7 +120,6 @@ func (r ReceivedStepHashes) get(step uint64, author libcommon.Address) (libcommo
 	return result, ok
 }
 
-// nolint
 func (r ReceivedStepHashes) insert(step uint64, author libcommon.Address, blockHash libcommon.Hash) {
 	res, ok := r[step]
 	if !ok {
@@ -114,7 +129,6 @@ func (r ReceivedStepHashes) insert(step uint64, author libcommon.Address, blockH
 	res[author] = blockHash
 }
 
-// nolint
 func (r ReceivedStepHashes) dropAncient(step uint64) {
 	for i := range r {
 		if i < step {
@@ -125,6 +139,8 @@ func (r ReceivedStepHashes) dropAncient(step uint64) {
 
 // nolint
 type EpochManager struct {
+	lock sync.Mutex // Guards the zoom and the finality checker
+
 	epochTransitionHash   libcommon.Hash // H256,
 	epochTransitionNumber uint64         // BlockNumber
 	finalityChecker       *RollingFinality
@@ -141,7 +157,7 @@ func NewEpochManager() *EpochManager {
 func (e *EpochManager) noteNewEpoch() { e.force = true }
 
 // zoomValidators - Zooms to the epoch after the header with the given hash. Returns true if succeeded, false otherwise.
-// It's analog of zoom_to_after function in OE, but doesn't require external locking
+// It's analog of zoom_to_after function in OE, the caller must hold e.lock
 // nolint
 func (e *EpochManager) zoomToAfter(chain consensus.ChainHeaderReader, er *NonTransactionalEpochReader, validators ValidatorSet, hash libcommon.Hash, call consensus.SystemCall) (*RollingFinality, uint64, bool) {
 	var lastWasParent bool
@@ -231,7 +247,10 @@ type AuRa struct {
 
 	step PermissionedStep
 	// History of step hashes recently received from peers.
-	receivedStepHashes ReceivedStepHashes
+	receivedStepHashes     ReceivedStepHashes
+	receivedStepHashesLock sync.Mutex
+
+	signatures *lru.ARCCache[libcommon.Hash, libcommon.Address] // Signers of recent headers
 
 	cfg           AuthorityRoundParams
 	EmptyStepsSet *EmptyStepSet
@@ -306,6 +325,10 @@ func NewAuRa(spec *chain.AuRaConfig, db kv.RwDB) (*AuRa, error) {
 	*/
 
 	exitCh := make(chan struct{})
+	signatures, err := lru.NewARC[libcommon.Hash, libcommon.Address](inmemorySignatures)
+	if err != nil {
+		return nil, err
+	}
 
 	c := &AuRa{
 		e:                  newEpochReader(db),
@@ -313,6 +336,7 @@ func NewAuRa(spec *chain.AuRaConfig, db kv.RwDB) (*AuRa, error) {
 		step:               PermissionedStep{inner: step},
 		cfg:                auraParams,
 		receivedStepHashes: ReceivedStepHashes{},
+		signatures:         signatures,
 		EpochManager:       NewEpochManager(),
 	}
 	c.step.canPropose.Store(true)
@@ -358,81 +382,85 @@ func (c *AuRa) Author(header *types.Header) (libcommon.Address, error) {
 }
 
 // VerifyHeader checks whether a header conforms to the consensus rules.
-func (c *AuRa) VerifyHeader(chain consensus.ChainHeaderReader, header *types.Header, _ bool) error {
+func (c *AuRa) VerifyHeader(chain consensus.ChainHeaderReader, header *types.Header, seal bool) error {
 	number := header.Number.Uint64()
 	parent := chain.GetHeader(header.ParentHash, number-1)
 	if parent == nil {
 		log.Error("consensus.ErrUnknownAncestor", "parentNum", number-1, "hash", header.ParentHash.String())
 		return consensus.ErrUnknownAncestor
 	}
-	return ethash.VerifyHeaderBasics(chain, header, parent, true /*checkTimestamp*/, c.HasGasLimitContract() /*skipGasLimit*/)
+	if err := ethash.VerifyHeaderBasics(chain, header, parent, true /*checkTimestamp*/, c.HasGasLimitContract() /*skipGasLimit*/); err != nil {
+		return err
+	}
+	if err := c.verifyStepTransition(header, parent); err != nil {
+		return err
+	}
+	if seal {
+		return c.VerifySeal(chain, header)
+	}
+	return nil
+}
+
+// verifyStepTransition checks that the header is from a step after its parent, and that its difficulty is
+// the score of the steps. The seals of Gnosis and Chiado don't carry empty steps, so none of them count.
+func (c *AuRa) verifyStepTransition(header, parent *types.Header) error {
+	step, parentStep := header.AuRaStep, parent.AuRaStep
+	if step == parentStep ||
+		(header.Number.Uint64() >= c.cfg.ValidateStepTransition && step <= parentStep) {
+		log.Trace("[aura] Multiple blocks proposed for step", "num", parentStep)
+		return fmt.Errorf("double vote: %x", header.Coinbase)
+	}
+	if header.Number.Uint64() >= c.cfg.ValidateScoreTransition {
+		expectedDifficulty := calculateScore(parentStep, step, 0)
+		if header.Difficulty.Cmp(expectedDifficulty.ToBig()) != 0 {
+			return fmt.Errorf("invalid difficulty: expect=%s, found=%s", expectedDifficulty, header.Difficulty)
+		}
+	}
+	return nil
 }
 
-// nolint
 func (c *AuRa) hasReceivedStepHashes(step uint64, author libcommon.Address, newHash libcommon.Hash) bool {
-	/*
-		self
-			       .received_step_hashes
-			       .read()
-			       .get(&received_step_key)
-			       .map_or(false, |h| *h != new_hash)
-	*/
-	return false
+	c.receivedStepHashesLock.Lock()
+	defer c.receivedStepHashesLock.Unlock()
+	hash, ok := c.receivedStepHashes.get(step, author)
+	return ok && hash != newHash
 }
 
-// nolint
 func (c *AuRa) insertReceivedStepHashes(step uint64, author libcommon.Address, newHash libcommon.Hash) {
-	/*
-	   	    self.received_step_hashes
-	                      .write()
-	                      .insert(received_step_key, new_hash);
-	*/
+	c.receivedStepHashesLock.Lock()
+	defer c.receivedStepHashesLock.Unlock()
+	c.receivedStepHashes.insert(step, author, newHash)
 }
 
-// nolint
-func (c *AuRa) verifyFamily(chain consensus.ChainHeaderReader, e *NonTransactionalEpochReader, header *types.Header, call consensus.Call, syscall consensus.SystemCall) error {
-	// TODO: I call it from Initialize - because looks like no much reason to have separated "verifyFamily" call
+func (c *AuRa) dropAncientReceivedStepHashes(step uint64) {
+	c.receivedStepHashesLock.Lock()
+	defer c.receivedStepHashesLock.Unlock()
+	c.receivedStepHashes.dropAncient(step)
+}
 
+// verifyFamily checks the header against its parent and the validator set of its epoch. It's analog of
+// verify_block_family and verify_block_external in OE.
+func (c *AuRa) verifyFamily(chain consensus.ChainHeaderReader, e *NonTransactionalEpochReader, header *types.Header, call consensus.Call, syscall consensus.SystemCall) error {
 	step := header.AuRaStep
 	parent := chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	if parent == nil {
+		return consensus.ErrUnknownAncestor
+	}
 	parentStep := parent.AuRaStep
-	//nolint
 	validators, setNumber, err := c.epochSet(chain, e, header, syscall)
 	if err != nil {
 		return err
 	}
-	return nil
-	// TODO(yperbasis): re-enable the rest
 
 	// Ensure header is from the step after parent.
-	//nolint
-	if step == parentStep ||
-		(header.Number.Uint64() >= c.cfg.ValidateStepTransition && step <= parentStep) {
-		log.Trace("[aura] Multiple blocks proposed for step", "num", parentStep)
-		_ = setNumber
-		/*
-			self.validators.report_malicious(
-				header.author(),
-				set_number,
-				header.number(),
-				Default::default(),
-			);
-			Err(EngineError::DoubleVote(*header.author()))?;
-		*/
-		return fmt.Errorf("double vote: %x", header.Coinbase)
+	if err := c.verifyStepTransition(header, parent); err != nil {
+		log.Trace("[aura] Reporting malicious validator", "validator", header.Coinbase, "set", setNumber, "num", header.Number.Uint64(), "err", err)
+		return err
 	}
 
 	// Report malice if the validator produced other sibling blocks in the same step.
-	if !c.hasReceivedStepHashes(step, header.Coinbase, header.Hash()) {
-		/*
-		   trace!(target: "engine", "Validator {} produced sibling blocks in the same step", header.author());
-		   self.validators.report_malicious(
-		       header.author(),
-		       set_number,
-		       header.number(),
-		       Default::default(),
-		   );
-		*/
+	if c.hasReceivedStepHashes(step, header.Coinbase, header.Hash()) {
+		log.Trace("[aura] Validator produced sibling blocks in the same step", "validator", header.Coinbase, "step", step, "set", setNumber)
 	} else {
 		c.insertReceivedStepHashes(step, header.Coinbase, header.Hash())
 	}
@@ -448,95 +476,25 @@ func (c *AuRa) verifyFamily(chain consensus.ChainHeaderReader, e *NonTransaction
 	if parentStep > siblingMaliceDetectionPeriod {
 		oldestStep = parentStep - siblingMaliceDetectionPeriod
 	}
-	//nolint
 	if oldestStep > 0 {
-		/*
-		   let mut rsh = self.received_step_hashes.write();
-		   let new_rsh = rsh.split_off(&(oldest_step, Address::zero()));
-		   *rsh = new_rsh;
-		*/
+		c.dropAncientReceivedStepHashes(oldestStep)
 	}
 
-	emptyStepLen := uint64(0)
-	//self.report_skipped(header, step, parent_step, &*validators, set_number);
-
-	/*
-	   // If empty step messages are enabled we will validate the messages in the seal, missing messages are not
-	   // reported as there's no way to tell whether the empty step message was never sent or simply not included.
-	   let empty_steps_len = if header.number() >= self.empty_steps_transition {
-	       let validate_empty_steps = || -> Result<usize, Error> {
-	           let strict_empty_steps = header.number() >= self.strict_empty_steps_transition;
-	           let empty_steps = header_empty_steps(header)?;
-	           let empty_steps_len = empty_steps.len();
-	           let mut prev_empty_step = 0;
-
-	           for empty_step in empty_steps {
-	               if empty_step.step <= parent_step || empty_step.step >= step {
-	                   Err(EngineError::InsufficientProof(format!(
-	                       "empty step proof for invalid step: {:?}",
-	                       empty_step.step
-	                   )))?;
-	               }
-
-	               if empty_step.parent_hash != *header.parent_hash() {
-	                   Err(EngineError::InsufficientProof(format!(
-	                       "empty step proof for invalid parent hash: {:?}",
-	                       empty_step.parent_hash
-	                   )))?;
-	               }
-
-	               if !empty_step.verify(&*validators).unwrap_or(false) {
-	                   Err(EngineError::InsufficientProof(format!(
-	                       "invalid empty step proof: {:?}",
-	                       empty_step
-	                   )))?;
-	               }
-
-	               if strict_empty_steps {
-	                   if empty_step.step <= prev_empty_step {
-	                       Err(EngineError::InsufficientProof(format!(
-	                           "{} empty step: {:?}",
-	                           if empty_step.step == prev_empty_step {
-	                               "duplicate"
-	                           } else {
-	                               "unordered"
-	                           },
-	                           empty_step
-	                       )))?;
-	                   }
-
-	                   prev_empty_step = empty_step.step;
-	               }
-	           }
-
-	           Ok(empty_steps_len)
-	       };
-
-	       match validate_empty_steps() {
-	           Ok(len) => len,
-	           Err(err) => {
-	               trace!(
-	                   target: "engine",
-	                   "Reporting benign misbehaviour (cause: invalid empty steps) \
-	                   at block #{}, epoch set number {}. Own address: {}",
-	                   header.number(), set_number, self.address().unwrap_or_default()
-	               );
-	               self.validators
-	                   .report_benign(header.author(), set_number, header.number());
-	               return Err(err);
-	           }
-	       }
-	   } else {
-	       self.report_skipped(header, step, parent_step, &*validators, set_number);
-
-	       0
-	   };
-	*/
-	if header.Number.Uint64() >= c.cfg.ValidateScoreTransition {
-		expectedDifficulty := calculateScore(parentStep, step, emptyStepLen)
-		if header.Difficulty.Cmp(expectedDifficulty.ToBig()) != 0 {
-			return fmt.Errorf("invlid difficulty: expect=%s, found=%s\n", expectedDifficulty, header.Difficulty)
-		}
+	// Verify the signature against the expected proposer of the step
+	correctProposer, err := stepProposer(validators, header.ParentHash, step, call)
+	if err != nil {
+		return err
+	}
+	if header.Coinbase != correctProposer {
+		log.Trace("[aura] Reporting benign misbehaviour: bad proposer", "step", step, "set", setNumber, "num", header.Number.Uint64())
+		return fmt.Errorf("not proposer: expected=%x, found=%x", correctProposer, header.Coinbase)
+	}
+	signer, err := ecrecover(header, c.signatures)
+	if err != nil {
+		return err
+	}
+	if signer != correctProposer {
+		return fmt.Errorf("not proposer: expected=%x, signer=%x", correctProposer, signer)
 	}
 	return nil
 }
@@ -553,13 +511,19 @@ func (c *AuRa) VerifyUncles(chain consensus.ChainReader, header *types.Header, u
 
 // VerifySeal implements consensus.Engine, checking whether the signature contained
 // in the header satisfies the consensus protocol requirements.
+// It doesn't need the validator set: the signer only has to be the author of the header.
 func (c *AuRa) VerifySeal(chain consensus.ChainHeaderReader, header *types.Header) error {
+	if err := c.step.inner.checkFuture(header.AuRaStep); err != nil {
+		return err
+	}
+	signer, err := ecrecover(header, c.signatures)
+	if err != nil {
+		return err
+	}
+	if signer != header.Coinbase {
+		return fmt.Errorf("invalid seal: signer=%x, author=%x", signer, header.Coinbase)
+	}
 	return nil
-	//snap, err := c.Snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
-	//if err != nil {
-	//	return err
-	//}
-	//return c.verifySeal(chain, header, snap)
 }
 
 // Prepare implements consensus.Engine, preparing all the consensus fields of the
@@ -631,7 +595,7 @@ func (c *AuRa) Prepare(chain consensus.ChainHeaderReader, header *types.Header,
 	//return nil
 }
 
-func (c *AuRa) Initialize(config *chain.Config, chain consensus.ChainHeaderReader, header *types.Header, state *state.IntraBlockState, txs []types.Transaction, uncles []*types.Header, syscallCustom consensus.SysCallCustom) {
+func (c *AuRa) Initialize(config *chain.Config, chain consensus.ChainHeaderReader, header *types.Header, state *state.IntraBlockState, txs []types.Transaction, uncles []*types.Header, syscallCustom consensus.SysCallCustom) error {
 	blockNum := header.Number.Uint64()
 
 	//Check block gas limit from smart contract, if applicable
@@ -650,39 +614,53 @@ func (c *AuRa) Initialize(config *chain.Config, chain consensus.ChainHeaderReade
 	}
 	c.certifierLock.Unlock()
 
-	if blockNum == 1 {
-		proof, err := c.GenesisEpochData(header, syscall)
-		if err != nil {
-			panic(err)
-		}
-		err = c.e.PutEpoch(header.ParentHash, 0, proof) //TODO: block 0 hardcoded - need fix it inside validators
-		if err != nil {
-			panic(err)
-		}
+	if err := c.putGenesisEpoch(header, syscall); err != nil {
+		return err
 	}
 
-	//if err := c.verifyFamily(chain, e, header, call, syscall); err != nil { //TODO: OE has it as a separate engine call? why?
-	//	panic(err)
-	//}
-
 	// check_and_lock_block -> check_epoch_end_signal
 
 	epoch, err := c.e.GetEpoch(header.ParentHash, blockNum-1)
 	if err != nil {
 		log.Warn("[aura] initialize block: on epoch begin", "err", err)
-		return
+		return nil
 	}
 	isEpochBegin := epoch != nil
 	if !isEpochBegin {
-		return
+		return nil
 	}
 	err = c.cfg.Validators.onEpochBegin(isEpochBegin, header, syscall)
 	if err != nil {
 		log.Warn("[aura] initialize block: on epoch begin", "err", err)
-		return
+		return nil
 	}
 	// check_and_lock_block -> check_epoch_end_signal END (before enact)
+	return nil
+}
 
+// VerifyFamily implements consensus.FamilyVerifier. Before the transactions are executed, it verifies the
+// header against its parent and the validator set of its epoch.
+func (c *AuRa) VerifyFamily(chain consensus.ChainHeaderReader, header *types.Header, state *state.IntraBlockState, syscallCustom consensus.SysCallCustom) error {
+	syscall := func(addr libcommon.Address, data []byte) ([]byte, error) {
+		return syscallCustom(addr, data, state, header, true)
+	}
+	// The epoch of the first block is only stored by Initialize, which runs after
+	if err := c.putGenesisEpoch(header, syscall); err != nil {
+		return err
+	}
+	return c.verifyFamily(chain, c.e, header, syscall, syscall)
+}
+
+// putGenesisEpoch stores the epoch of the genesis when the first block begins.
+func (c *AuRa) putGenesisEpoch(header *types.Header, syscall consensus.SystemCall) error {
+	if header.Number.Uint64() != 1 {
+		return nil
+	}
+	proof, err := c.GenesisEpochData(header, syscall)
+	if err != nil {
+		return err
+	}
+	return c.e.PutEpoch(header.ParentHash, 0, proof) //TODO: block 0 hardcoded - need fix it inside validators
 }
 
 func (c *AuRa) applyRewards(header *types.Header, state *state.IntraBlockState, syscall consensus.SystemCall) error {
@@ -742,6 +720,8 @@ func (c *AuRa) Finalize(config *chain.Config, header *types.Header, state *state
 
 func buildFinality(e *EpochManager, chain consensus.ChainHeaderReader, er *NonTransactionalEpochReader, validators ValidatorSet, header *types.Header, syscall consensus.SystemCall) []unAssembledHeader {
 	// commit_block -> aura.build_finality
+	e.lock.Lock()
+	defer e.lock.Unlock()
 	_, _, ok := e.zoomToAfter(chain, er, validators, header.ParentHash, syscall)
 	if !ok {
 		return []unAssembledHeader{}
@@ -1049,6 +1029,8 @@ func (c *AuRa) epochSet(chain consensus.ChainHeaderReader, e *NonTransactionalEp
 		return c.cfg.Validators, h.Number.Uint64(), nil
 	}
 
+	c.EpochManager.lock.Lock()
+	defer c.EpochManager.lock.Unlock()
 	finalityChecker, epochTransitionNumber, ok := c.EpochManager.zoomToAfter(chain, e, c.cfg.Validators, h.ParentHash, call)
 	if !ok {
 		return nil, 0, fmt.Errorf("unable to zoomToAfter to epoch")
@@ -1075,7 +1057,7 @@ func calculateScore(parentStep, currentStep, currentEmptySteps uint64) *uint256.
 }
 
 func (c *AuRa) SealHash(header *types.Header) libcommon.Hash {
-	return clique.SealHash(header)
+	return sealHash(header)
 }
 
 // See https://openethereum.github.io/Permissioning.html#gas-price
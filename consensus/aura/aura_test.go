@@ -1,12 +1,14 @@
 package aura_test
 
 import (
+	"crypto/ecdsa"
 	"math/big"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/ledgerwatch/erigon-lib/chain"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv/memdb"
 
@@ -15,32 +17,32 @@ import (
 	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/core/state"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
 	"github.com/ledgerwatch/erigon/turbo/stages"
 	"github.com/ledgerwatch/erigon/turbo/trie"
 )
 
-// Check that the first block of Gnosis Chain, which doesn't have any transactions,
-// does not change the state root.
-func TestEmptyBlock(t *testing.T) {
-	require := require.New(t)
-	genesis := core.GnosisGenesisBlock()
-	genesisBlock, _, err := core.GenesisToBlock(genesis, "")
-	require.NoError(err)
-
-	genesis.Config.TerminalTotalDifficultyPassed = false
-
-	chainConfig := genesis.Config
-	auraDB := memdb.NewTestDB(t)
-	engine, err := aura.NewAuRa(chainConfig.Aura, auraDB)
-	require.NoError(err)
-	m := stages.MockWithGenesisEngine(t, genesis, engine, false)
+// newTestEngine returns the AuRa engine of Gnosis Chain, whose first validator is replaced by the address of
+// the returned key, at the given step.
+func newTestEngine(t *testing.T, genesis *types.Genesis, step uint64) (*aura.AuRa, *ecdsa.PrivateKey) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	auraConfig := *genesis.Config.Aura
+	auraConfig.Validators = &chain.ValidatorSetJson{List: []libcommon.Address{crypto.PubkeyToAddress(key.PublicKey)}}
+	auraConfig.StartStep = &step
+	engine, err := aura.NewAuRa(&auraConfig, memdb.NewTestDB(t))
+	require.NoError(t, err)
+	return engine, key
+}
 
-	time := uint64(1539016985)
+// newTestHeader returns the first block of Gnosis Chain, sealed by the given key at the given step.
+func newTestHeader(t *testing.T, engine *aura.AuRa, genesisBlock *types.Block, chainConfig *chain.Config, key *ecdsa.PrivateKey, step uint64) *types.Header {
+	time := step * 5
 	header := core.MakeEmptyHeader(genesisBlock.Header(), chainConfig, time, nil)
 	header.UncleHash = types.EmptyUncleHash
 	header.TxHash = trie.EmptyRoot
 	header.ReceiptHash = trie.EmptyRoot
-	header.Coinbase = libcommon.HexToAddress("0xcace5b3c29211740e595850e80478416ee77ca21")
+	header.Coinbase = crypto.PubkeyToAddress(key.PublicKey)
 	header.Difficulty = engine.CalcDifficulty(nil, time,
 		0,
 		genesisBlock.Difficulty(),
@@ -49,7 +51,29 @@ func TestEmptyBlock(t *testing.T) {
 		genesisBlock.UncleHash(),
 		genesisBlock.Header().AuRaStep,
 	)
+	header.AuRaStep = step
+	var err error
+	header.AuRaSeal, err = crypto.Sign(engine.SealHash(header).Bytes(), key)
+	require.NoError(t, err)
+	return header
+}
 
+// Check that the first block of Gnosis Chain, which doesn't have any transactions,
+// does not change the state root.
+func TestEmptyBlock(t *testing.T) {
+	require := require.New(t)
+	genesis := core.GnosisGenesisBlock()
+	genesisBlock, _, err := core.GenesisToBlock(genesis, "")
+	require.NoError(err)
+
+	genesis.Config.TerminalTotalDifficultyPassed = false
+
+	chainConfig := genesis.Config
+	step := uint64(1539016985 / 5)
+	engine, key := newTestEngine(t, genesis, step)
+	m := stages.MockWithGenesisEngine(t, genesis, engine, false)
+
+	header := newTestHeader(t, engine, genesisBlock, chainConfig, key, step)
 	block := types.NewBlockWithHeader(header)
 
 	headers, blocks, receipts := make([]*types.Header, 1), make(types.Blocks, 1), make([]types.Receipts, 1)
@@ -61,6 +85,73 @@ func TestEmptyBlock(t *testing.T) {
 	require.NoError(err)
 }
 
+func TestAuRaSeal(t *testing.T) {
+	require := require.New(t)
+	genesis := core.GnosisGenesisBlock()
+	genesisBlock, _, err := core.GenesisToBlock(genesis, "")
+	require.NoError(err)
+	chainConfig := genesis.Config
+	step := uint64(1539016985 / 5)
+	engine, key := newTestEngine(t, genesis, step)
+
+	header := newTestHeader(t, engine, genesisBlock, chainConfig, key, step)
+	require.NoError(engine.VerifySeal(nil, header))
+
+	// the seal must be signed by the author
+	forged := types.CopyHeader(header)
+	forged.Coinbase = libcommon.HexToAddress("0xcace5b3c29211740e595850e80478416ee77ca21")
+	require.Error(engine.VerifySeal(nil, forged))
+	forged = types.CopyHeader(header)
+	forged.AuRaSeal = nil
+	require.Error(engine.VerifySeal(nil, forged))
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(err)
+	forged = types.CopyHeader(header)
+	forged.AuRaSeal, err = crypto.Sign(engine.SealHash(forged).Bytes(), otherKey)
+	require.NoError(err)
+	require.Error(engine.VerifySeal(nil, forged))
+
+	// the step can't be too far in the future
+	future := newTestHeader(t, engine, genesisBlock, chainConfig, key, step+5)
+	require.Error(engine.VerifySeal(nil, future))
+	require.NoError(engine.VerifySeal(nil, newTestHeader(t, engine, genesisBlock, chainConfig, key, step+4)))
+}
+
+func TestAuRaForgedBlock(t *testing.T) {
+	genesis := core.GnosisGenesisBlock()
+	genesisBlock, _, err := core.GenesisToBlock(genesis, "")
+	require.NoError(t, err)
+	genesis.Config.TerminalTotalDifficultyPassed = false
+	chainConfig := genesis.Config
+	step := uint64(1539016985 / 5)
+
+	for name, forge := range map[string]func(engine *aura.AuRa, header *types.Header){
+		"unsealed": func(_ *aura.AuRa, header *types.Header) {
+			header.AuRaSeal = nil
+		},
+		"not a validator": func(engine *aura.AuRa, header *types.Header) {
+			key, err := crypto.GenerateKey()
+			require.NoError(t, err)
+			header.Coinbase = crypto.PubkeyToAddress(key.PublicKey)
+			header.AuRaSeal, err = crypto.Sign(engine.SealHash(header).Bytes(), key)
+			require.NoError(t, err)
+		},
+		"wrong difficulty": func(_ *aura.AuRa, header *types.Header) {
+			header.Difficulty = new(big.Int).Add(header.Difficulty, big.NewInt(1))
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			engine, key := newTestEngine(t, genesis, step)
+			m := stages.MockWithGenesisEngine(t, genesis, engine, false)
+			header := newTestHeader(t, engine, genesisBlock, chainConfig, key, step)
+			forge(engine, header)
+			block := types.NewBlockWithHeader(header)
+			chain := &core.ChainPack{Headers: []*types.Header{header}, Blocks: types.Blocks{block}, Receipts: make([]types.Receipts, 1), TopBlock: block}
+			require.Error(t, m.InsertChain(chain, nil))
+		})
+	}
+}
+
 func TestAuRaSkipGasLimit(t *testing.T) {
 	require := require.New(t)
 	genesis := core.GnosisGenesisBlock()
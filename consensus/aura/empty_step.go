@@ -9,6 +9,7 @@ import (
 
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 
+	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/crypto"
 	"github.com/ledgerwatch/erigon/rlp"
 )
@@ -60,20 +61,16 @@ func (s *EmptyStep) LessOrEqual(other *EmptyStep) bool {
 }
 
 // Returns `true` if the message has a valid signature by the expected proposer in the message's step.
-func (s *EmptyStep) verify(validators ValidatorSet) (bool, error) { //nolint
-	//sRlp, err := EmptyStepRlp(s.step, s.parentHash)
-	//if err != nil {
-	//	return false, err
-	//}
-	//message := crypto.Keccak256(sRlp)
-
-	/*
-		let correct_proposer = step_proposer(validators, &self.parent_hash, self.step);
-
-		publickey::verify_address(&correct_proposer, &self.signature.into(), &message)
-		.map_err(|e| e.into())
-	*/
-	return true, nil
+func (s *EmptyStep) verify(validators ValidatorSet, call consensus.Call) (bool, error) { //nolint
+	author, err := s.author()
+	if err != nil {
+		return false, err
+	}
+	correctProposer, err := stepProposer(validators, s.parentHash, s.step, call)
+	if err != nil {
+		return false, err
+	}
+	return author == correctProposer, nil
 }
 
 // nolint
@@ -119,17 +116,17 @@ func (s *EmptyStepSet) ForEach(f func(int, *EmptyStep)) {
 
 func EmptyStepFullRlp(signature []byte, emptyStepRlp []byte) ([]byte, error) {
 	type A struct {
-		s []byte
-		r []byte
+		S []byte
+		R rlp.RawValue
 	}
 
-	return rlp.EncodeToBytes(A{s: signature, r: emptyStepRlp})
+	return rlp.EncodeToBytes(A{S: signature, R: emptyStepRlp})
 }
 
 func EmptyStepRlp(step uint64, parentHash libcommon.Hash) ([]byte, error) {
 	type A struct {
-		s uint64
-		h libcommon.Hash
+		S uint64
+		H libcommon.Hash
 	}
-	return rlp.EncodeToBytes(A{s: step, h: parentHash})
+	return rlp.EncodeToBytes(A{S: step, H: parentHash})
 }
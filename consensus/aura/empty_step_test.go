@@ -0,0 +1,50 @@
+package aura
+
+import (
+	"crypto/ecdsa"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/crypto"
+)
+
+func signEmptyStep(t *testing.T, key *ecdsa.PrivateKey, step uint64, parentHash libcommon.Hash) EmptyStep {
+	message, err := EmptyStepRlp(step, parentHash)
+	require.NoError(t, err)
+	signature, err := crypto.Sign(crypto.Keccak256(message), key)
+	require.NoError(t, err)
+	return EmptyStep{signature: signature, step: step, parentHash: parentHash}
+}
+
+func TestEmptyStepVerify(t *testing.T) {
+	keys := make([]*ecdsa.PrivateKey, 2)
+	addresses := make([]libcommon.Address, len(keys))
+	for i := range keys {
+		var err error
+		keys[i], err = crypto.GenerateKey()
+		require.NoError(t, err)
+		addresses[i] = crypto.PubkeyToAddress(keys[i].PublicKey)
+	}
+	validators := NewSimpleList(addresses)
+	parentHash := libcommon.Hash{1}
+
+	// The proposer of a step is the validator at the step modulo the number of validators
+	emptyStep := signEmptyStep(t, keys[1], 7, parentHash)
+	ok, err := emptyStep.verify(validators, nil)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	emptyStep = signEmptyStep(t, keys[0], 7, parentHash)
+	ok, err = emptyStep.verify(validators, nil)
+	require.NoError(t, err)
+	require.False(t, ok, "not the proposer of the step")
+
+	emptyStep = signEmptyStep(t, keys[1], 7, parentHash)
+	emptyStep.parentHash = libcommon.Hash{2}
+	ok, err = emptyStep.verify(validators, nil)
+	require.NoError(t, err)
+	require.False(t, ok, "signed for another parent")
+}
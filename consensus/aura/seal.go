@@ -0,0 +1,87 @@
+package aura
+
+import (
+	"errors"
+	"io"
+
+	lru "github.com/hashicorp/golang-lru/arc/v2"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/crypto/cryptopool"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+const (
+	// Number of recent block signatures to keep in memory
+	inmemorySignatures = 4096
+	// Headers more than this number of steps ahead of the local step are rejected
+	rejectedStepDrift = 4
+)
+
+var (
+	// errMissingSignature is returned if the seal of a header doesn't contain a 65 byte signature.
+	errMissingSignature = errors.New("aura seal 65 byte signature missing")
+
+	// errFutureStep is returned if the step of a header is too far ahead of the local step.
+	errFutureStep = errors.New("aura step in the future")
+)
+
+// sealHash returns the hash of a header prior to it being sealed, i.e. without the step and the signature.
+// It's the analog of bare_hash in OE.
+func sealHash(header *types.Header) (hash libcommon.Hash) {
+	hasher := cryptopool.NewLegacyKeccak256()
+	defer cryptopool.ReturnToPoolKeccak256(hasher)
+
+	encodeSealHeader(hasher, header)
+	hasher.Sum(hash[:0])
+	return hash
+}
+
+func encodeSealHeader(w io.Writer, header *types.Header) {
+	enc := []interface{}{
+		header.ParentHash,
+		header.UncleHash,
+		header.Coinbase,
+		header.Root,
+		header.TxHash,
+		header.ReceiptHash,
+		header.Bloom,
+		header.Difficulty,
+		header.Number,
+		header.GasLimit,
+		header.GasUsed,
+		header.Time,
+		header.Extra,
+	}
+	if header.BaseFee != nil {
+		enc = append(enc, header.BaseFee)
+	}
+	if err := rlp.Encode(w, enc); err != nil {
+		panic("can't encode: " + err.Error())
+	}
+}
+
+// ecrecover extracts the Ethereum account address from a signed header.
+func ecrecover(header *types.Header, sigcache *lru.ARCCache[libcommon.Hash, libcommon.Address]) (libcommon.Address, error) {
+	// If the signature's already cached, return that
+	hash := header.Hash()
+	if address, known := sigcache.Peek(hash); known {
+		return address, nil
+	}
+	if len(header.AuRaSeal) != crypto.SignatureLength {
+		return libcommon.Address{}, errMissingSignature
+	}
+
+	// Recover the public key and the Ethereum address
+	pubkey, err := crypto.Ecrecover(sealHash(header).Bytes(), header.AuRaSeal)
+	if err != nil {
+		return libcommon.Address{}, err
+	}
+	var signer libcommon.Address
+	copy(signer[:], crypto.Keccak256(pubkey[1:])[12:])
+
+	sigcache.Add(hash, signer)
+	return signer, nil
+}
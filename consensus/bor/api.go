@@ -259,7 +259,21 @@ func (api *API) GetRootHash(start uint64, end uint64) (string, error) {
 	wg.Wait()
 	close(concurrent)
 
-	headers := make([][32]byte, NextPowerOfTwo(length))
+	rootHash, err := ComputeHeadersRootHash(blockHeaders)
+	if err != nil {
+		return "", err
+	}
+
+	root := hex.EncodeToString(rootHash)
+	api.rootHashCache.Add(key, root)
+
+	return root, nil
+}
+
+// ComputeHeadersRootHash returns the merkle root of consecutive block headers,
+// as committed by Heimdall checkpoints.
+func ComputeHeadersRootHash(blockHeaders []*types.Header) ([]byte, error) {
+	headers := make([][32]byte, NextPowerOfTwo(uint64(len(blockHeaders))))
 
 	for i := 0; i < len(blockHeaders); i++ {
 		blockHeader := blockHeaders[i]
@@ -278,13 +292,10 @@ func (api *API) GetRootHash(start uint64, end uint64) (string, error) {
 
 	tree := merkle.NewTreeWithOpts(merkle.TreeOptions{EnableHashSorting: false, DisableHashLeaves: true})
 	if err := tree.Generate(Convert(headers), sha3.NewLegacyKeccak256()); err != nil {
-		return "", err
+		return nil, err
 	}
 
-	root := hex.EncodeToString(tree.Root().Hash)
-	api.rootHashCache.Add(key, root)
-
-	return root, nil
+	return tree.Root().Hash, nil
 }
 
 func (api *API) initializeRootHashCache() error {
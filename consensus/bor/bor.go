@@ -35,16 +35,17 @@ import (
 	"github.com/ledgerwatch/erigon/core/types/accounts"
 	"github.com/ledgerwatch/erigon/crypto"
 	"github.com/ledgerwatch/erigon/crypto/cryptopool"
+	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/erigon/rlp"
 	"github.com/ledgerwatch/erigon/rpc"
+	"github.com/ledgerwatch/erigon/turbo/services"
 )
 
 const (
-	spanLength              = 6400 // Number of blocks in a span
-	zerothSpanEnd           = 255  // End block of 0th span
-	snapshotPersistInterval = 1024 // Number of blocks after which to persist the vote snapshot to the database
-	inmemorySnapshots       = 128  // Number of recent vote snapshots to keep in memory
-	inmemorySignatures      = 4096 // Number of recent block signatures to keep in memory
+	zerothSpanEnd           = span.ZerothSpanEnd // End block of 0th span
+	snapshotPersistInterval = 1024               // Number of blocks after which to persist the vote snapshot to the database
+	inmemorySnapshots       = 128                // Number of recent vote snapshots to keep in memory
+	inmemorySignatures      = 4096               // Number of recent block signatures to keep in memory
 )
 
 // Bor protocol constants.
@@ -102,6 +103,10 @@ var (
 	// invalid list of validators (i.e. non divisible by 40 bytes).
 	errInvalidSpanValidators = errors.New("invalid validator list on sprint end block")
 
+	// errInvalidExtraData is returned if the extra-data payload of a block past
+	// the parallel universe fork is not a valid BlockExtraData encoding.
+	errInvalidExtraData = errors.New("invalid extra-data payload")
+
 	// errInvalidMixDigest is returned if a block's mix digest is non-zero.
 	errInvalidMixDigest = errors.New("non-zero mix digest")
 
@@ -241,6 +246,7 @@ type Bor struct {
 	spanner                Spanner
 	GenesisContractsClient GenesisContract
 	HeimdallClient         IHeimdallClient
+	borReader              services.BorReader // frozen and stored state sync events and spans, nil to always ask Heimdall
 
 	// scope event.SubscriptionScope
 	// The fields below are for testing only
@@ -249,6 +255,11 @@ type Bor struct {
 
 	closeOnce sync.Once
 	logger    log.Logger
+
+	// parallelUniverseBlock is the block from which the header extra payload
+	// is RLP encoded BlockExtraData carrying Block-STM dependencies, nil if
+	// the fork is not scheduled.
+	parallelUniverseBlock *big.Int
 }
 
 type signer struct {
@@ -288,6 +299,7 @@ func New(
 		spanCache:              btree.New(32),
 		execCtx:                context.Background(),
 		logger:                 logger,
+		parallelUniverseBlock:  params.BorParallelUniverseBlock(chainConfig.ChainName),
 	}
 
 	c.authorizedSigner.Store(&signer{
@@ -372,8 +384,13 @@ func (c *Bor) verifyHeader(chain consensus.ChainHeaderReader, header *types.Head
 	// check extr adata
 	isSprintEnd := isSprintStart(number+1, c.config.CalculateSprint(number))
 
+	validatorBytes, err := c.getValidatorBytes(header)
+	if err != nil {
+		return err
+	}
+
 	// Ensure that the extra-data contains a signer list on checkpoint, but none otherwise
-	signersBytes := len(header.Extra) - extraVanity - extraSeal
+	signersBytes := len(validatorBytes)
 	if !isSprintEnd && signersBytes != 0 {
 		return errExtraValidators
 	}
@@ -494,7 +511,12 @@ func (c *Bor) verifyCascadingFields(chain consensus.ChainHeaderReader, header *t
 
 		sort.Sort(valset.ValidatorsByAddress(producerSet))
 
-		headerVals, err := valset.ParseValidators(header.Extra[extraVanity : len(header.Extra)-extraSeal])
+		validatorBytes, err := c.getValidatorBytes(header)
+		if err != nil {
+			return err
+		}
+
+		headerVals, err := valset.ParseValidators(validatorBytes)
 
 		if err != nil {
 			return err
@@ -518,7 +540,10 @@ func (c *Bor) verifyCascadingFields(chain consensus.ChainHeaderReader, header *t
 	// verify the validator list in the last sprint block
 	if isSprintStart(number, sprintLength) {
 		// Retrieve the snapshot needed to verify this header and cache it
-		parentValidatorBytes := parent.Extra[extraVanity : len(parent.Extra)-extraSeal]
+		parentValidatorBytes, err := c.getValidatorBytes(parent)
+		if err != nil {
+			return err
+		}
 		validatorsBytes := make([]byte, len(snap.ValidatorSet.Validators)*validatorHeaderBytesLength)
 
 		currentValidators := snap.ValidatorSet.Copy().Validators
@@ -527,7 +552,6 @@ func (c *Bor) verifyCascadingFields(chain consensus.ChainHeaderReader, header *t
 		for i, validator := range currentValidators {
 			copy(validatorsBytes[i*validatorHeaderBytesLength:], validator.HeaderBytes())
 		}
-		// len(header.Extra) >= extraVanity+extraSeal has already been validated in validateHeaderExtraField, so this won't result in a panic
 		if !bytes.Equal(parentValidatorBytes, validatorsBytes) {
 			return &MismatchingValidatorsError{number - 1, validatorsBytes, parentValidatorBytes}
 		}
@@ -627,7 +651,7 @@ func (c *Bor) snapshot(chain consensus.ChainHeaderReader, number uint64, hash li
 		headers[i], headers[len(headers)-1-i] = headers[len(headers)-1-i], headers[i]
 	}
 
-	snap, err := snap.apply(headers, c.logger)
+	snap, err := snap.apply(headers, c.getValidatorBytes, c.logger)
 	if err != nil {
 		return nil, err
 	}
@@ -744,6 +768,8 @@ func (c *Bor) Prepare(chain consensus.ChainHeaderReader, header *types.Header, s
 
 	header.Extra = header.Extra[:extraVanity]
 
+	var validatorBytes []byte
+
 	// get validator set if number
 	// Note: headers.Extra has producer set and not validator set. The bor
 	// client calls `GetCurrentValidators` because it makes a contract call
@@ -759,10 +785,22 @@ func (c *Bor) Prepare(chain consensus.ChainHeaderReader, header *types.Header, s
 		sort.Sort(valset.ValidatorsByAddress(newValidators))
 
 		for _, validator := range newValidators {
-			header.Extra = append(header.Extra, validator.HeaderBytes()...)
+			validatorBytes = append(validatorBytes, validator.HeaderBytes()...)
 		}
 	}
 
+	if c.IsParallelUniverse(number) {
+		// Block-STM dependencies are filled in by the miner once the
+		// transactions have been executed
+		payload, err := rlp.EncodeToBytes(&types.BlockExtraData{ValidatorBytes: validatorBytes})
+		if err != nil {
+			return err
+		}
+		header.Extra = append(header.Extra, payload...)
+	} else {
+		header.Extra = append(header.Extra, validatorBytes...)
+	}
+
 	// add extra seal space
 	header.Extra = append(header.Extra, make([]byte, extraSeal)...)
 
@@ -925,7 +963,8 @@ func (c *Bor) GenerateSeal(chain consensus.ChainHeaderReader, currnt, parent *ty
 }
 
 func (c *Bor) Initialize(config *chain.Config, chain consensus.ChainHeaderReader, header *types.Header,
-	state *state.IntraBlockState, txs []types.Transaction, uncles []*types.Header, syscall consensus.SysCallCustom) {
+	state *state.IntraBlockState, txs []types.Transaction, uncles []*types.Header, syscall consensus.SysCallCustom) error {
+	return nil
 }
 
 // Authorize injects a private key into the consensus engine to mint new blocks
@@ -937,6 +976,25 @@ func (c *Bor) Authorize(currentSigner libcommon.Address, signFn SignerFn) {
 	})
 }
 
+// IsParallelUniverse returns whether the header extra payload of the given
+// block is RLP encoded BlockExtraData.
+func (c *Bor) IsParallelUniverse(number uint64) bool {
+	return c.parallelUniverseBlock != nil && c.parallelUniverseBlock.Uint64() <= number
+}
+
+// getValidatorBytes returns the validator bytes carried by the extra field of
+// a header, whose length must have been checked by validateHeaderExtraField.
+func (c *Bor) getValidatorBytes(header *types.Header) ([]byte, error) {
+	if !c.IsParallelUniverse(header.Number.Uint64()) {
+		return header.Extra[extraVanity : len(header.Extra)-extraSeal], nil
+	}
+	data, err := types.DecodeBlockExtraData(header.Extra)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidExtraData, err)
+	}
+	return data.ValidatorBytes, nil
+}
+
 // Seal implements consensus.Engine, attempting to create a sealed block using
 // the local signing credentials.
 func (c *Bor) Seal(chain consensus.ChainHeaderReader, block *types.Block, results chan<- *types.Block, stop <-chan struct{}) error {
@@ -1125,16 +1183,11 @@ func (c *Bor) getSpanForBlock(blockNum uint64) (*span.HeimdallSpan, error) {
 	}
 
 	// Span with given block block number is not loaded
-	// As span has fixed set of blocks (except 0th span), we can
-	// formulate it and get the exact ID we'd need to fetch.
-	var spanID uint64
-	if blockNum > zerothSpanEnd {
-		spanID = 1 + (blockNum-zerothSpanEnd-1)/spanLength
-	}
+	spanID := span.IDAt(blockNum)
 
 	c.logger.Info("Span with given block number is not loaded", "fetching span", spanID)
 
-	response, err := c.HeimdallClient.Span(c.execCtx, spanID)
+	response, err := c.span(c.execCtx, spanID)
 	if err != nil {
 		return nil, err
 	}
@@ -1166,7 +1219,7 @@ func (c *Bor) fetchAndCommitSpan(
 
 		heimdallSpan = *s
 	} else {
-		response, err := c.HeimdallClient.Span(c.execCtx, newSpanID)
+		response, err := c.span(c.execCtx, newSpanID)
 		if err != nil {
 			return err
 		}
@@ -1227,12 +1280,12 @@ func (c *Bor) CommitStates(
 		"to", to.Format(time.RFC3339),
 	)
 
-	eventRecords, err := c.HeimdallClient.StateSyncEvents(c.execCtx, lastStateID+1, to.Unix())
+	eventRecords, stored, err := c.stateSyncEvents(c.execCtx, header, lastStateID+1, to)
 	if err != nil {
 		return err
 	}
 
-	if c.config.OverrideStateSyncRecords != nil {
+	if c.config.OverrideStateSyncRecords != nil && !stored {
 		if val, ok := c.config.OverrideStateSyncRecords[strconv.FormatUint(number, 10)]; ok {
 			eventRecords = eventRecords[0:val]
 		}
@@ -1241,6 +1294,7 @@ func (c *Bor) CommitStates(
 	fetchTime := time.Since(fetchStart)
 	processStart := time.Now()
 	chainID := c.chainConfig.ChainID.String()
+	committed := make([]*clerk.EventRecordWithTime, 0, len(eventRecords))
 
 	for _, eventRecord := range eventRecords {
 		if eventRecord.ID <= lastStateID {
@@ -1256,9 +1310,14 @@ func (c *Bor) CommitStates(
 			return err
 		}
 
+		committed = append(committed, eventRecord)
 		lastStateID++
 	}
 
+	if !stored {
+		c.storeStateSyncEvents(c.execCtx, header, committed)
+	}
+
 	processTime := time.Since(processStart)
 
 	c.logger.Info("StateSyncData", "number", number, "lastStateID", lastStateID, "total records", len(eventRecords), "fetch time", int(fetchTime.Milliseconds()), "process time", int(processTime.Milliseconds()))
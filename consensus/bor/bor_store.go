@@ -0,0 +1,114 @@
+package bor
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/consensus/bor/clerk"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/span"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/turbo/services"
+)
+
+// The state sync events committed by every block and the Heimdall spans are
+// stored in the Bor database the first time they are fetched, and later
+// frozen into the Bor snapshots, so that re-executing the chain does not need
+// Heimdall.
+
+// SetBorReader makes the engine read the state sync events and spans from the
+// Bor snapshots and database before asking Heimdall.
+func (c *Bor) SetBorReader(reader services.BorReader) {
+	c.borReader = reader
+}
+
+// span returns the Heimdall span with the given ID: the frozen or stored one,
+// or else the one fetched from Heimdall, which is then stored.
+func (c *Bor) span(ctx context.Context, spanID uint64) (*span.HeimdallSpan, error) {
+	if c.borReader != nil && c.DB != nil {
+		var data []byte
+		if err := c.DB.View(ctx, func(tx kv.Tx) (err error) {
+			data, err = c.borReader.Span(ctx, tx, spanID)
+			return err
+		}); err != nil {
+			return nil, err
+		}
+		if data != nil {
+			var heimdallSpan span.HeimdallSpan
+			if err := json.Unmarshal(data, &heimdallSpan); err != nil {
+				return nil, err
+			}
+			return &heimdallSpan, nil
+		}
+	}
+
+	heimdallSpan, err := c.HeimdallClient.Span(ctx, spanID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.store(ctx, "span", heimdallSpan, func(tx kv.RwTx, data []byte) error {
+		return rawdb.WriteBorSpan(tx, spanID, data)
+	})
+
+	return heimdallSpan, nil
+}
+
+// stateSyncEvents returns the state sync events to commit in a block: the
+// frozen or stored ones if the block was executed before, or else the ones
+// Heimdall has from fromID up to the given time. stored tells which.
+func (c *Bor) stateSyncEvents(ctx context.Context, header *types.Header, fromID uint64, to time.Time) (events []*clerk.EventRecordWithTime, stored bool, err error) {
+	if c.borReader != nil && c.DB != nil {
+		var data []byte
+		var found bool
+		if err := c.DB.View(ctx, func(tx kv.Tx) (err error) {
+			data, found, err = c.borReader.EventsByBlock(ctx, tx, header.Hash(), header.Number.Uint64())
+			return err
+		}); err != nil {
+			return nil, false, err
+		}
+		if found {
+			if len(data) > 0 {
+				if err := json.Unmarshal(data, &events); err != nil {
+					return nil, false, err
+				}
+			}
+			return events, true, nil
+		}
+	}
+
+	events, err = c.HeimdallClient.StateSyncEvents(ctx, fromID, to.Unix())
+	return events, false, err
+}
+
+// storeStateSyncEvents stores the state sync events committed in a block.
+func (c *Bor) storeStateSyncEvents(ctx context.Context, header *types.Header, events []*clerk.EventRecordWithTime) {
+	if events == nil {
+		events = []*clerk.EventRecordWithTime{}
+	}
+
+	c.store(ctx, "state sync events", events, func(tx kv.RwTx, data []byte) error {
+		return rawdb.WriteBorEvents(tx, header.Hash(), header.Number.Uint64(), data)
+	})
+}
+
+func (c *Bor) store(ctx context.Context, what string, v interface{}, put func(tx kv.RwTx, data []byte) error) {
+	if c.DB == nil {
+		return
+	}
+
+	data, err := json.Marshal(v)
+	if err == nil {
+		err = c.DB.Update(ctx, func(tx kv.RwTx) error {
+			return put(tx, data)
+		})
+	}
+
+	// they are fetched from Heimdall again if missing, e.g. in a read-only DB
+	if err != nil {
+		c.logger.Debug("[bor] Could not store "+what, "err", err)
+	}
+}
@@ -0,0 +1,78 @@
+package bor
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+)
+
+// dbBorReader reads the stored events and spans only, like a BlockReader
+// without Bor snapshots
+type dbBorReader struct{}
+
+func (dbBorReader) EventsByBlock(ctx context.Context, tx kv.Getter, hash libcommon.Hash, blockNum uint64) ([]byte, bool, error) {
+	events, err := rawdb.ReadBorEvents(tx, hash, blockNum)
+	return events, events != nil, err
+}
+
+func (dbBorReader) Span(ctx context.Context, tx kv.Getter, spanID uint64) ([]byte, error) {
+	return rawdb.ReadBorSpan(tx, spanID)
+}
+
+func TestStoredStateSyncEventsAndSpans(t *testing.T) {
+	ctx := context.Background()
+	live := &countingHeimdallClient{}
+	c := &Bor{DB: memdb.NewTestDB(t), HeimdallClient: live, logger: log.New()}
+	header := &types.Header{Number: big.NewInt(16)}
+
+	// Without reader every request goes to Heimdall
+	_, stored, err := c.stateSyncEvents(ctx, header, 10, time.Unix(1000, 0))
+	require.NoError(t, err)
+	require.False(t, stored)
+	require.Equal(t, 1, live.calls)
+
+	c.SetBorReader(dbBorReader{})
+	events, stored, err := c.stateSyncEvents(ctx, header, 10, time.Unix(1000, 0))
+	require.NoError(t, err)
+	require.False(t, stored)
+	require.Equal(t, 2, live.calls)
+
+	// Once committed, the events of the block are read from the Bor DB
+	c.storeStateSyncEvents(ctx, header, events)
+	events, stored, err = c.stateSyncEvents(ctx, header, 10, time.Unix(1000, 0))
+	require.NoError(t, err)
+	require.True(t, stored)
+	require.Len(t, events, 1)
+	require.Equal(t, uint64(10), events[0].ID)
+	require.Equal(t, 2, live.calls)
+
+	// An empty list is stored too, and other blocks are unaffected
+	empty := &types.Header{Number: big.NewInt(32)}
+	c.storeStateSyncEvents(ctx, empty, nil)
+	events, stored, err = c.stateSyncEvents(ctx, empty, 11, time.Unix(2000, 0))
+	require.NoError(t, err)
+	require.True(t, stored)
+	require.Empty(t, events)
+	_, stored, err = c.stateSyncEvents(ctx, &types.Header{Number: big.NewInt(16), Time: 1}, 10, time.Unix(1000, 0))
+	require.NoError(t, err)
+	require.False(t, stored)
+	require.Equal(t, 3, live.calls)
+
+	// Spans are fetched once
+	for i := 0; i < 2; i++ {
+		s, err := c.span(ctx, 3)
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), s.ID)
+	}
+	require.Equal(t, 4, live.calls)
+}
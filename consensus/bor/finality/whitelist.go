@@ -0,0 +1,219 @@
+package finality
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/consensus/bor"
+	"github.com/ledgerwatch/erigon/consensus/bor/finality/whitelist"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/turbo/services"
+)
+
+const (
+	whitelistCheckpointInterval = 100 * time.Second
+	whitelistMilestoneInterval  = 12 * time.Second
+	whitelistTimeout            = 30 * time.Second
+)
+
+var (
+	// errMissingBlocks is returned when the local chain does not reach the end
+	// of the checkpoint yet
+	errMissingBlocks = errors.New("missing blocks")
+
+	// errRootHash is returned when the local chain does not match the root
+	// hash of the checkpoint
+	errRootHash = errors.New("root hash mismatch")
+
+	// errEndBlock is returned when the local chain does not match the end
+	// block hash of the milestone
+	errEndBlock = errors.New("end block hash mismatch")
+)
+
+type config struct {
+	heimdall    bor.IHeimdallClient
+	db          kv.RoDB
+	blockReader services.FullBlockReader
+	service     *whitelist.Service
+	logger      log.Logger
+}
+
+// Whitelist starts verifying the latest Heimdall checkpoint and milestone
+// against the local chain, periodically, until ctx is cancelled. Verified
+// blocks are whitelisted in service.
+func Whitelist(ctx context.Context, service *whitelist.Service, heimdall bor.IHeimdallClient, db kv.RoDB, blockReader services.FullBlockReader, logger log.Logger) {
+	config := &config{
+		heimdall:    heimdall,
+		db:          db,
+		blockReader: blockReader,
+		service:     service,
+		logger:      logger,
+	}
+
+	go startWhitelistService(ctx, config, "checkpoint", whitelistCheckpointInterval, handleWhitelistCheckpoint)
+	go startWhitelistService(ctx, config, "milestone", whitelistMilestoneInterval, handleWhitelistMilestone)
+}
+
+func startWhitelistService(ctx context.Context, config *config, name string, interval time.Duration, handle func(context.Context, *config) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := handle(ctx, config); err != nil {
+			if errors.Is(err, errMissingBlocks) || errors.Is(err, heimdall.ErrServiceUnavailable) {
+				config.logger.Debug("[bor] Skipping "+name+" whitelisting", "err", err)
+			} else {
+				config.logger.Warn("[bor] Failed to whitelist "+name, "err", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// rewindPoint returns the block to unwind to in order to drop the canonical
+// blocks of a checkpoint or milestone starting at start: any of them may be
+// the one which contradicts Heimdall. The genesis block is always kept.
+func rewindPoint(start uint64) uint64 {
+	if start == 0 {
+		return 0
+	}
+	return start - 1
+}
+
+// handleWhitelistCheckpoint fetches the latest checkpoint and whitelists its
+// end block if the local chain matches it. Otherwise, the Headers stage is
+// asked to unwind the canonical chain below the start of the checkpoint.
+func handleWhitelistCheckpoint(ctx context.Context, config *config) error {
+	ctx, cancel := context.WithTimeout(ctx, whitelistTimeout)
+	defer cancel()
+
+	checkpoint, err := config.heimdall.FetchCheckpoint(ctx, -1)
+	if err != nil {
+		return err
+	}
+
+	start, end := checkpoint.StartBlock.Uint64(), checkpoint.EndBlock.Uint64()
+	if start > end || end == 0 || end-start+1 > bor.MaxCheckpointLength {
+		return fmt.Errorf("invalid checkpoint range %d-%d", start, end)
+	}
+
+	var (
+		rootHash  []byte
+		endHash   libcommon.Hash
+		firstHash libcommon.Hash
+	)
+
+	if err := config.db.View(ctx, func(tx kv.Tx) error {
+		head := rawdb.ReadCurrentBlockNumber(tx)
+		if head == nil || *head < end {
+			return fmt.Errorf("%w: checkpoint end %d", errMissingBlocks, end)
+		}
+
+		headers := make([]*types.Header, 0, end-start+1)
+		for number := start; number <= end; number++ {
+			header, err := config.blockReader.HeaderByNumber(ctx, tx, number)
+			if err != nil {
+				return err
+			}
+			if header == nil {
+				return fmt.Errorf("%w: header %d", errMissingBlocks, number)
+			}
+			headers = append(headers, header)
+		}
+
+		if rootHash, err = bor.ComputeHeadersRootHash(headers); err != nil {
+			return err
+		}
+		endHash = headers[len(headers)-1].Hash()
+		firstHash = headers[rewindPoint(start)+1-start].Hash()
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(rootHash, checkpoint.RootHash.Bytes()) {
+		config.service.RequestRewind(whitelist.Rewind{Number: rewindPoint(start), FirstBlock: firstHash})
+
+		return fmt.Errorf("%w: checkpoint %d-%d, local %x, heimdall %x", errRootHash, start, end, rootHash, checkpoint.RootHash)
+	}
+
+	config.service.ProcessCheckpoint(end, endHash)
+	config.logger.Debug("[bor] Whitelisted checkpoint", "start", start, "end", end, "hash", endHash)
+
+	return nil
+}
+
+// handleWhitelistMilestone fetches the latest milestone and whitelists its end
+// block if the local chain matches it. Otherwise, the Headers stage is asked
+// to unwind the canonical chain below the start of the milestone, the blocks
+// of the milestone aren't marked as bad so that they may be downloaded again.
+func handleWhitelistMilestone(ctx context.Context, config *config) error {
+	ctx, cancel := context.WithTimeout(ctx, whitelistTimeout)
+	defer cancel()
+
+	milestone, err := config.heimdall.FetchMilestone(ctx)
+	if err != nil {
+		return err
+	}
+
+	start, end := milestone.StartBlock.Uint64(), milestone.EndBlock.Uint64()
+	if end == 0 || start > end {
+		return fmt.Errorf("invalid milestone range %d-%d", start, end)
+	}
+
+	var endHash, firstHash libcommon.Hash
+
+	if err := config.db.View(ctx, func(tx kv.Tx) error {
+		head := rawdb.ReadCurrentBlockNumber(tx)
+		if head == nil || *head < end {
+			return fmt.Errorf("%w: milestone end %d", errMissingBlocks, end)
+		}
+
+		header, err := config.blockReader.HeaderByNumber(ctx, tx, end)
+		if err != nil {
+			return err
+		}
+		if header == nil {
+			return fmt.Errorf("%w: header %d", errMissingBlocks, end)
+		}
+		endHash = header.Hash()
+
+		if header, err = config.blockReader.HeaderByNumber(ctx, tx, rewindPoint(start)+1); err != nil {
+			return err
+		}
+		if header == nil {
+			return fmt.Errorf("%w: header %d", errMissingBlocks, rewindPoint(start)+1)
+		}
+		firstHash = header.Hash()
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if endHash != milestone.Hash {
+		config.service.RequestRewind(whitelist.Rewind{Number: rewindPoint(start), FirstBlock: firstHash})
+
+		return fmt.Errorf("%w: milestone end %d, local %x, heimdall %x", errEndBlock, end, endHash, milestone.Hash)
+	}
+
+	config.service.ProcessMilestone(end, endHash)
+	config.logger.Debug("[bor] Whitelisted milestone", "end", end, "hash", endHash)
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package whitelist
+
+import (
+	"sync"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+// finality holds the end block of the latest Heimdall checkpoint or milestone
+// which has been verified against the local chain.
+type finality struct {
+	sync.RWMutex
+
+	doExist bool
+	number  uint64
+	hash    libcommon.Hash
+}
+
+func (f *finality) process(number uint64, hash libcommon.Hash) {
+	f.Lock()
+	defer f.Unlock()
+
+	f.doExist = true
+	f.number = number
+	f.hash = hash
+}
+
+func (f *finality) get() (bool, uint64, libcommon.Hash) {
+	f.RLock()
+	defer f.RUnlock()
+
+	return f.doExist, f.number, f.hash
+}
+
+func (f *finality) purge() {
+	f.Lock()
+	defer f.Unlock()
+
+	f.doExist = false
+	f.number = 0
+	f.hash = libcommon.Hash{}
+}
+
+// isValidChain reports whether a header chain keeps the whitelisted block,
+// see Service.IsValidChain.
+func (f *finality) isValidChain(currentHead uint64, forkingPoint uint64, chainHead uint64, hashAt func(number uint64) (libcommon.Hash, error)) (bool, error) {
+	doExist, number, hash := f.get()
+
+	// Nothing to validate the chain against, or the chain does not touch the
+	// whitelisted block
+	if !doExist || forkingPoint >= number {
+		return true, nil
+	}
+
+	// The chain does not reach the whitelisted block yet: it may only replace
+	// a canonical chain which does not reach it either
+	if chainHead < number {
+		return currentHead < number, nil
+	}
+
+	chainHash, err := hashAt(number)
+	if err != nil {
+		return false, err
+	}
+
+	return chainHash == hash, nil
+}
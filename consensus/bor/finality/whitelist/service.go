@@ -0,0 +1,121 @@
+package whitelist
+
+import (
+	"sync"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+)
+
+// Service keeps the Bor blocks which are final according to Heimdall and
+// rejects header chains which would reorganise the canonical chain past them.
+type Service struct {
+	checkpoint finality
+	milestone  finality
+
+	rewindLock sync.Mutex
+	rewind     *Rewind
+}
+
+// Rewind is a request to unwind the canonical chain because it contradicts a
+// Heimdall checkpoint or milestone. The dropped blocks aren't marked as bad:
+// the Heimdall data may be wrong or transient, and any block of the range may
+// be the divergent one.
+type Rewind struct {
+	Number     uint64         // block to unwind to
+	FirstBlock libcommon.Hash // first canonical block dropped by the unwind
+}
+
+// NewService creates an empty whitelisting service.
+func NewService() *Service {
+	return &Service{}
+}
+
+// ProcessCheckpoint whitelists the end block of a verified checkpoint.
+func (s *Service) ProcessCheckpoint(number uint64, hash libcommon.Hash) {
+	s.checkpoint.process(number, hash)
+}
+
+// GetWhitelistedCheckpoint returns the end block of the latest verified
+// checkpoint, if any.
+func (s *Service) GetWhitelistedCheckpoint() (bool, uint64, libcommon.Hash) {
+	return s.checkpoint.get()
+}
+
+// PurgeWhitelistedCheckpoint forgets the whitelisted checkpoint.
+func (s *Service) PurgeWhitelistedCheckpoint() {
+	s.checkpoint.purge()
+}
+
+// ProcessMilestone whitelists the end block of a verified milestone.
+func (s *Service) ProcessMilestone(number uint64, hash libcommon.Hash) {
+	s.milestone.process(number, hash)
+}
+
+// GetWhitelistedMilestone returns the end block of the latest verified
+// milestone, if any.
+func (s *Service) GetWhitelistedMilestone() (bool, uint64, libcommon.Hash) {
+	return s.milestone.get()
+}
+
+// PurgeWhitelistedMilestone forgets the whitelisted milestone.
+func (s *Service) PurgeWhitelistedMilestone() {
+	s.milestone.purge()
+}
+
+// GetFinalizedBlock returns the latest block which is final according to
+// Heimdall: the end of the latest verified milestone, or of the latest
+// verified checkpoint if it is further.
+func (s *Service) GetFinalizedBlock() (bool, uint64, libcommon.Hash) {
+	doExist, number, hash := s.milestone.get()
+
+	if cpDoExist, cpNumber, cpHash := s.checkpoint.get(); cpDoExist && (!doExist || cpNumber > number) {
+		return cpDoExist, cpNumber, cpHash
+	}
+
+	return doExist, number, hash
+}
+
+// GetSafeBlock returns the latest block which is safe according to Heimdall:
+// the end of the latest verified milestone, which lags the chain by seconds
+// rather than by the tens of minutes of checkpoints. Without milestones, the
+// end of the latest verified checkpoint is returned.
+func (s *Service) GetSafeBlock() (bool, uint64, libcommon.Hash) {
+	if doExist, number, hash := s.milestone.get(); doExist {
+		return doExist, number, hash
+	}
+
+	return s.checkpoint.get()
+}
+
+// IsValidChain reports whether a header chain may become canonical, i.e.
+// whether it keeps both the whitelisted checkpoint and milestone. The chain
+// forks off the canonical chain, whose head is currentHead, at forkingPoint
+// and reaches chainHead; hashAt returns the hash of its block at a given
+// height, which is at most chainHead.
+func (s *Service) IsValidChain(currentHead uint64, forkingPoint uint64, chainHead uint64, hashAt func(number uint64) (libcommon.Hash, error)) (bool, error) {
+	valid, err := s.checkpoint.isValidChain(currentHead, forkingPoint, chainHead, hashAt)
+	if err != nil || !valid {
+		return false, err
+	}
+
+	return s.milestone.isValidChain(currentHead, forkingPoint, chainHead, hashAt)
+}
+
+// RequestRewind asks the Headers stage to unwind the canonical chain.
+func (s *Service) RequestRewind(rewind Rewind) {
+	s.rewindLock.Lock()
+	defer s.rewindLock.Unlock()
+
+	s.rewind = &rewind
+}
+
+// PopRewind returns the pending rewind request, if any, and clears it.
+func (s *Service) PopRewind() *Rewind {
+	s.rewindLock.Lock()
+	defer s.rewindLock.Unlock()
+
+	rewind := s.rewind
+	s.rewind = nil
+
+	return rewind
+}
@@ -0,0 +1,104 @@
+package whitelist
+
+import (
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsValidChain(t *testing.T) {
+	canonical := map[uint64]libcommon.Hash{10: {0x0a}, 20: {0x14}}
+	fork := map[uint64]libcommon.Hash{10: {0x0a}, 20: {0xff}}
+	hashAt := func(chain map[uint64]libcommon.Hash) func(uint64) (libcommon.Hash, error) {
+		return func(number uint64) (libcommon.Hash, error) {
+			return chain[number], nil
+		}
+	}
+
+	s := NewService()
+
+	// Nothing whitelisted yet
+	valid, err := s.IsValidChain(30, 5, 30, hashAt(fork))
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	s.ProcessCheckpoint(10, canonical[10])
+	s.ProcessMilestone(20, canonical[20])
+
+	// Fork keeping the checkpoint, but replacing the milestone
+	valid, err = s.IsValidChain(30, 15, 30, hashAt(fork))
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	// Same chain as the canonical one
+	valid, err = s.IsValidChain(30, 15, 30, hashAt(canonical))
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	// Fork above the milestone
+	valid, err = s.IsValidChain(30, 20, 30, hashAt(fork))
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	// Fork not reaching the milestone yet, replacing a chain which does
+	valid, err = s.IsValidChain(30, 15, 19, hashAt(fork))
+	require.NoError(t, err)
+	require.False(t, valid)
+
+	// Fork not reaching the milestone yet, replacing a chain which does not
+	valid, err = s.IsValidChain(18, 15, 19, hashAt(fork))
+	require.NoError(t, err)
+	require.True(t, valid)
+
+	s.PurgeWhitelistedMilestone()
+	valid, err = s.IsValidChain(30, 15, 30, hashAt(fork))
+	require.NoError(t, err)
+	require.True(t, valid)
+}
+
+func TestGetFinalizedBlock(t *testing.T) {
+	s := NewService()
+
+	doExist, _, _ := s.GetFinalizedBlock()
+	require.False(t, doExist)
+
+	s.ProcessCheckpoint(10, libcommon.Hash{0x0a})
+	doExist, number, hash := s.GetFinalizedBlock()
+	require.True(t, doExist)
+	require.Equal(t, uint64(10), number)
+	require.Equal(t, libcommon.Hash{0x0a}, hash)
+
+	s.ProcessMilestone(20, libcommon.Hash{0x14})
+	_, number, hash = s.GetFinalizedBlock()
+	require.Equal(t, uint64(20), number)
+	require.Equal(t, libcommon.Hash{0x14}, hash)
+
+	s.ProcessCheckpoint(30, libcommon.Hash{0x1e})
+	_, number, _ = s.GetFinalizedBlock()
+	require.Equal(t, uint64(30), number)
+}
+
+func TestGetSafeBlock(t *testing.T) {
+	s := NewService()
+
+	doExist, _, _ := s.GetSafeBlock()
+	require.False(t, doExist)
+
+	// without milestones, checkpoints are the safe blocks
+	s.ProcessCheckpoint(10, libcommon.Hash{0x0a})
+	_, number, _ := s.GetSafeBlock()
+	require.Equal(t, uint64(10), number)
+
+	s.ProcessMilestone(20, libcommon.Hash{0x14})
+	_, number, hash := s.GetSafeBlock()
+	require.Equal(t, uint64(20), number)
+	require.Equal(t, libcommon.Hash{0x14}, hash)
+
+	// a further checkpoint finalizes the chain but the safe block is the latest milestone
+	s.ProcessCheckpoint(30, libcommon.Hash{0x1e})
+	_, number, _ = s.GetSafeBlock()
+	require.Equal(t, uint64(20), number)
+	_, number, _ = s.GetFinalizedBlock()
+	require.Equal(t, uint64(30), number)
+}
@@ -0,0 +1,91 @@
+package finality
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/consensus/bor"
+	"github.com/ledgerwatch/erigon/consensus/bor/finality/whitelist"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/checkpoint"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/milestone"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/tests/bor/mocks"
+	"github.com/ledgerwatch/erigon/turbo/snapshotsync/freezeblocks"
+)
+
+func TestWhitelistRewind(t *testing.T) {
+	db := memdb.NewTestDB(t)
+	headers := make([]*types.Header, 21)
+	require.NoError(t, db.Update(context.Background(), func(tx kv.RwTx) error {
+		parentHash := libcommon.Hash{}
+		for i := range headers {
+			headers[i] = &types.Header{Number: big.NewInt(int64(i)), ParentHash: parentHash, Time: uint64(i), Difficulty: big.NewInt(1)}
+			parentHash = headers[i].Hash()
+			if err := rawdb.WriteHeader(tx, headers[i]); err != nil {
+				return err
+			}
+			if err := rawdb.WriteCanonicalHash(tx, parentHash, uint64(i)); err != nil {
+				return err
+			}
+		}
+		rawdb.WriteHeadHeaderHash(tx, parentHash)
+		return nil
+	}))
+
+	ctrl := gomock.NewController(t)
+	heimdall := mocks.NewMockIHeimdallClient(ctrl)
+	config := &config{
+		heimdall:    heimdall,
+		db:          db,
+		blockReader: freezeblocks.NewBlockReader(freezeblocks.NewRoSnapshots(ethconfig.BlocksFreezing{Enabled: false}, "", log.New()), nil),
+		service:     whitelist.NewService(),
+		logger:      log.New(),
+	}
+
+	rootHash, err := bor.ComputeHeadersRootHash(headers[5:11])
+	require.NoError(t, err)
+
+	// matching checkpoint
+	heimdall.EXPECT().FetchCheckpoint(gomock.Any(), int64(-1)).Return(&checkpoint.Checkpoint{
+		StartBlock: big.NewInt(5), EndBlock: big.NewInt(10), RootHash: libcommon.BytesToHash(rootHash),
+	}, nil)
+	require.NoError(t, handleWhitelistCheckpoint(context.Background(), config))
+	doExist, number, hash := config.service.GetWhitelistedCheckpoint()
+	require.True(t, doExist)
+	require.Equal(t, uint64(10), number)
+	require.Equal(t, headers[10].Hash(), hash)
+	require.Nil(t, config.service.PopRewind())
+
+	// any block of the checkpoint may be the tampered one: the whole range is dropped
+	heimdall.EXPECT().FetchCheckpoint(gomock.Any(), int64(-1)).Return(&checkpoint.Checkpoint{
+		StartBlock: big.NewInt(11), EndBlock: big.NewInt(15), RootHash: libcommon.Hash{0x01},
+	}, nil)
+	require.ErrorIs(t, handleWhitelistCheckpoint(context.Background(), config), errRootHash)
+	require.Equal(t, &whitelist.Rewind{Number: 10, FirstBlock: headers[11].Hash()}, config.service.PopRewind())
+
+	// same for milestones
+	heimdall.EXPECT().FetchMilestone(gomock.Any()).Return(&milestone.Milestone{
+		StartBlock: big.NewInt(16), EndBlock: big.NewInt(20), Hash: libcommon.Hash{0x01},
+	}, nil)
+	require.ErrorIs(t, handleWhitelistMilestone(context.Background(), config), errEndBlock)
+	require.Equal(t, &whitelist.Rewind{Number: 15, FirstBlock: headers[16].Hash()}, config.service.PopRewind())
+
+	heimdall.EXPECT().FetchMilestone(gomock.Any()).Return(&milestone.Milestone{
+		StartBlock: big.NewInt(16), EndBlock: big.NewInt(20), Hash: headers[20].Hash(),
+	}, nil)
+	require.NoError(t, handleWhitelistMilestone(context.Background(), config))
+	doExist, number, hash = config.service.GetWhitelistedMilestone()
+	require.True(t, doExist)
+	require.Equal(t, uint64(20), number)
+	require.Equal(t, headers[20].Hash(), hash)
+}
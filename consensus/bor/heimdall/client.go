@@ -13,6 +13,7 @@ import (
 
 	"github.com/ledgerwatch/erigon/consensus/bor/clerk"
 	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/checkpoint"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/milestone"
 	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/span"
 	"github.com/ledgerwatch/log/v3"
 )
@@ -22,6 +23,7 @@ var (
 	ErrShutdownDetected      = errors.New("shutdown detected")
 	ErrNoResponse            = errors.New("got a nil response")
 	ErrNotSuccessfulResponse = errors.New("error while fetching data from Heimdall")
+	ErrServiceUnavailable    = errors.New("service unavailable")
 )
 
 const (
@@ -68,6 +70,9 @@ const (
 	fetchCheckpoint            = "/checkpoints/%s"
 	fetchCheckpointCount       = "/checkpoints/count"
 
+	fetchMilestone      = "/milestone/latest"
+	fetchMilestoneCount = "/milestone/count"
+
 	fetchSpanFormat = "bor/span/%d"
 )
 
@@ -160,6 +165,40 @@ func (h *HeimdallClient) FetchCheckpointCount(ctx context.Context) (int64, error
 	return response.Result.Result, nil
 }
 
+// FetchMilestone fetches the latest milestone from heimdall
+func (h *HeimdallClient) FetchMilestone(ctx context.Context) (*milestone.Milestone, error) {
+	url, err := milestoneURL(h.urlString)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = withRequestType(ctx, milestoneRequest)
+
+	response, err := FetchWithRetry[milestone.MilestoneResponse](ctx, h.client, url, h.closeCh)
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.Result, nil
+}
+
+// FetchMilestoneCount fetches the milestone count from heimdall
+func (h *HeimdallClient) FetchMilestoneCount(ctx context.Context) (int64, error) {
+	url, err := milestoneCountURL(h.urlString)
+	if err != nil {
+		return 0, err
+	}
+
+	ctx = withRequestType(ctx, milestoneCountRequest)
+
+	response, err := FetchWithRetry[milestone.MilestoneCountResponse](ctx, h.client, url, h.closeCh)
+	if err != nil {
+		return 0, err
+	}
+
+	return response.Result.Count, nil
+}
+
 // FetchWithRetry returns data from heimdall with retry
 func FetchWithRetry[T any](ctx context.Context, client http.Client, url *url.URL, closeCh chan struct{}) (*T, error) {
 	// request data once
@@ -170,6 +209,12 @@ func FetchWithRetry[T any](ctx context.Context, client http.Client, url *url.URL
 		return result, nil
 	}
 
+	// heimdall does not serve this endpoint (e.g. milestones before they are
+	// enabled), retrying will not help
+	if errors.Is(err, ErrServiceUnavailable) {
+		return nil, err
+	}
+
 	// attempt counter
 	attempt := 1
 
@@ -270,6 +315,14 @@ func checkpointCountURL(urlString string) (*url.URL, error) {
 	return makeURL(urlString, fetchCheckpointCount, "")
 }
 
+func milestoneURL(urlString string) (*url.URL, error) {
+	return makeURL(urlString, fetchMilestone, "")
+}
+
+func milestoneCountURL(urlString string) (*url.URL, error) {
+	return makeURL(urlString, fetchMilestoneCount, "")
+}
+
 func makeURL(urlString, rawPath, rawQuery string) (*url.URL, error) {
 	u, err := url.Parse(urlString)
 	if err != nil {
@@ -296,6 +349,10 @@ func internalFetch(ctx context.Context, client http.Client, u *url.URL) ([]byte,
 
 	defer res.Body.Close()
 
+	if res.StatusCode == http.StatusServiceUnavailable {
+		return nil, fmt.Errorf("%w: %s", ErrServiceUnavailable, u.Path)
+	}
+
 	// check status code
 	if res.StatusCode != 200 && res.StatusCode != 204 {
 		return nil, fmt.Errorf("%w: response code %d", ErrNotSuccessfulResponse, res.StatusCode)
@@ -21,6 +21,9 @@ const (
 	spanRequest            requestType = "span"
 	checkpointRequest      requestType = "checkpoint"
 	checkpointCountRequest requestType = "checkpoint-count"
+
+	milestoneRequest      requestType = "milestone"
+	milestoneCountRequest requestType = "milestone-count"
 )
 
 func withRequestType(ctx context.Context, reqType requestType) context.Context {
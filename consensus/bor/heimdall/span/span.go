@@ -28,3 +28,18 @@ func (hs *HeimdallSpan) Less(other btree.Item) bool {
 	}
 	return hs.EndBlock < otherHs.EndBlock
 }
+
+const (
+	ZerothSpanEnd = 255  // End block of 0th span
+	SpanLength    = 6400 // Number of blocks in a span
+)
+
+// IDAt returns the ID of the span which contains the given block. As spans
+// have a fixed number of blocks (except the 0th one), it can be computed
+// without fetching the span.
+func IDAt(blockNum uint64) uint64 {
+	if blockNum <= ZerothSpanEnd {
+		return 0
+	}
+	return 1 + (blockNum-ZerothSpanEnd-1)/SpanLength
+}
@@ -0,0 +1,203 @@
+package bor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/log/v3"
+
+	"github.com/ledgerwatch/erigon/consensus/bor/clerk"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/checkpoint"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/milestone"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/span"
+)
+
+// HeimdallCacheMode is what CachingHeimdallClient does with the responses of Heimdall
+type HeimdallCacheMode int
+
+const (
+	// HeimdallNoCache sends every request to Heimdall
+	HeimdallNoCache HeimdallCacheMode = iota
+	// HeimdallCache stores the responses which never change: spans, numbered checkpoints and past state sync events.
+	// Only the latest state sync events of every first event ID are kept.
+	HeimdallCache
+	// HeimdallRecord also stores the latest response of every other request, to be replayed later. Nothing is
+	// deleted, so the records grow with the chain.
+	HeimdallRecord
+	// HeimdallReplay answers every request from the stored responses, without Heimdall
+	HeimdallReplay
+)
+
+func ParseHeimdallCacheMode(s string) (HeimdallCacheMode, error) {
+	switch s {
+	case "", "off":
+		return HeimdallNoCache, nil
+	case "cache":
+		return HeimdallCache, nil
+	case "record":
+		return HeimdallRecord, nil
+	case "replay":
+		return HeimdallReplay, nil
+	}
+	return HeimdallNoCache, fmt.Errorf("unknown Heimdall cache mode %q, expected cache, record, replay or off", s)
+}
+
+// ErrNotRecorded is returned in replay mode for the requests whose response was not recorded
+var ErrNotRecorded = errors.New("heimdall response was not recorded")
+
+const (
+	heimdallCachePrefix = "heimdall-" // of the keys in kv.BorSeparate, next to the "bor-" snapshots
+	// Heimdall may still add state sync events with a record time just before now, so the events up to a time are
+	// only cached once the time is that old
+	stateSyncCacheDelay = time.Hour
+)
+
+// CachingHeimdallClient keeps the responses of Heimdall in the bor DB, so that a resync does not download them again,
+// and can record all the responses to replay them without Heimdall, e.g. in tests and devnets
+type CachingHeimdallClient struct {
+	client IHeimdallClient // nil in replay mode
+	db     kv.RwDB
+	mode   HeimdallCacheMode
+	logger log.Logger
+}
+
+func NewCachingHeimdallClient(client IHeimdallClient, db kv.RwDB, mode HeimdallCacheMode, logger log.Logger) *CachingHeimdallClient {
+	return &CachingHeimdallClient{client: client, db: db, mode: mode, logger: logger}
+}
+
+// cached returns the stored response of the request if it may be used in this mode, or else fetches the response and
+// stores it if it never changes or if the responses are recorded
+func cached[T any](ctx context.Context, c *CachingHeimdallClient, key string, immutable bool, fetch func(IHeimdallClient) (T, error)) (T, error) {
+	return cachedReplacing(ctx, c, "", key, immutable, fetch)
+}
+
+// cachedReplacing is cached which, unless the responses are recorded, deletes the stored responses of the other keys
+// with the prefix when it stores the response of the key
+func cachedReplacing[T any](ctx context.Context, c *CachingHeimdallClient, prefix, key string, immutable bool, fetch func(IHeimdallClient) (T, error)) (T, error) {
+	var res T
+	store := (immutable && c.mode != HeimdallNoCache) || c.mode == HeimdallRecord
+	if c.mode == HeimdallReplay || (immutable && c.mode != HeimdallNoCache) {
+		found, err := c.get(ctx, key, &res)
+		if err != nil {
+			return res, err
+		}
+		if found {
+			return res, nil
+		}
+		if c.mode == HeimdallReplay {
+			return res, fmt.Errorf("%w: %s", ErrNotRecorded, key)
+		}
+	}
+	res, err := fetch(c.client)
+	if err != nil {
+		return res, err
+	}
+	if store {
+		if c.mode == HeimdallRecord {
+			prefix = ""
+		}
+		c.put(ctx, prefix, key, res)
+	}
+	return res, nil
+}
+
+func (c *CachingHeimdallClient) get(ctx context.Context, key string, res interface{}) (found bool, err error) {
+	err = c.db.View(ctx, func(tx kv.Tx) error {
+		v, err := tx.GetOne(kv.BorSeparate, []byte(heimdallCachePrefix+key))
+		if err != nil || v == nil {
+			return err
+		}
+		found = true
+		return json.Unmarshal(v, res)
+	})
+	return found, err
+}
+
+// put stores the response of the key, after deleting the responses of the keys with the prefix if it isn't empty
+func (c *CachingHeimdallClient) put(ctx context.Context, prefix, key string, res interface{}) {
+	err := c.db.Update(ctx, func(tx kv.RwTx) error {
+		v, err := json.Marshal(res)
+		if err != nil {
+			return err
+		}
+		if prefix != "" {
+			var stale [][]byte
+			if err := tx.ForPrefix(kv.BorSeparate, []byte(heimdallCachePrefix+prefix), func(k, _ []byte) error {
+				stale = append(stale, common.Copy(k))
+				return nil
+			}); err != nil {
+				return err
+			}
+			for _, k := range stale {
+				if err := tx.Delete(kv.BorSeparate, k); err != nil {
+					return err
+				}
+			}
+		}
+		return tx.Put(kv.BorSeparate, []byte(heimdallCachePrefix+key), v)
+	})
+	if err == nil {
+		return
+	}
+	// a cache miss is harmless, e.g. in a read-only DB, a missing record is not
+	if c.mode == HeimdallRecord {
+		c.logger.Warn("[bor] Could not record Heimdall response", "key", key, "err", err)
+	} else {
+		c.logger.Debug("[bor] Could not cache Heimdall response", "key", key, "err", err)
+	}
+}
+
+// StateSyncEvents caches the events of one time for every first event ID. The first ID stays the same for all the
+// sprints without events, so caching every time would keep a response per sprint.
+func (c *CachingHeimdallClient) StateSyncEvents(ctx context.Context, fromID uint64, to int64) ([]*clerk.EventRecordWithTime, error) {
+	immutable := time.Unix(to, 0).Before(time.Now().Add(-stateSyncCacheDelay))
+	prefix := fmt.Sprintf("state-sync-%d-", fromID)
+	return cachedReplacing(ctx, c, prefix, fmt.Sprintf("%s%d", prefix, to), immutable, func(client IHeimdallClient) ([]*clerk.EventRecordWithTime, error) {
+		return client.StateSyncEvents(ctx, fromID, to)
+	})
+}
+
+func (c *CachingHeimdallClient) Span(ctx context.Context, spanID uint64) (*span.HeimdallSpan, error) {
+	return cached(ctx, c, fmt.Sprintf("span-%d", spanID), true, func(client IHeimdallClient) (*span.HeimdallSpan, error) {
+		return client.Span(ctx, spanID)
+	})
+}
+
+func (c *CachingHeimdallClient) FetchCheckpoint(ctx context.Context, number int64) (*checkpoint.Checkpoint, error) {
+	key := fmt.Sprintf("checkpoint-%d", number)
+	if number == -1 {
+		key = "checkpoint-latest"
+	}
+	return cached(ctx, c, key, number >= 0, func(client IHeimdallClient) (*checkpoint.Checkpoint, error) {
+		return client.FetchCheckpoint(ctx, number)
+	})
+}
+
+func (c *CachingHeimdallClient) FetchCheckpointCount(ctx context.Context) (int64, error) {
+	return cached(ctx, c, "checkpoint-count", false, func(client IHeimdallClient) (int64, error) {
+		return client.FetchCheckpointCount(ctx)
+	})
+}
+
+func (c *CachingHeimdallClient) FetchMilestone(ctx context.Context) (*milestone.Milestone, error) {
+	return cached(ctx, c, "milestone-latest", false, func(client IHeimdallClient) (*milestone.Milestone, error) {
+		return client.FetchMilestone(ctx)
+	})
+}
+
+func (c *CachingHeimdallClient) FetchMilestoneCount(ctx context.Context) (int64, error) {
+	return cached(ctx, c, "milestone-count", false, func(client IHeimdallClient) (int64, error) {
+		return client.FetchMilestoneCount(ctx)
+	})
+}
+
+func (c *CachingHeimdallClient) Close() {
+	if c.client != nil {
+		c.client.Close()
+	}
+}
@@ -0,0 +1,172 @@
+package bor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/consensus/bor/clerk"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/checkpoint"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/milestone"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/span"
+)
+
+// countingHeimdallClient answers every request and counts them
+type countingHeimdallClient struct {
+	calls          int
+	checkpoints    int64
+	unavailableErr error
+}
+
+func (h *countingHeimdallClient) StateSyncEvents(ctx context.Context, fromID uint64, to int64) ([]*clerk.EventRecordWithTime, error) {
+	h.calls++
+	return []*clerk.EventRecordWithTime{{EventRecord: clerk.EventRecord{ID: fromID}, Time: time.Unix(to-1, 0).UTC()}}, nil
+}
+
+func (h *countingHeimdallClient) Span(ctx context.Context, spanID uint64) (*span.HeimdallSpan, error) {
+	h.calls++
+	if h.unavailableErr != nil {
+		return nil, h.unavailableErr
+	}
+	return &span.HeimdallSpan{Span: span.Span{ID: spanID, StartBlock: spanID * 6400}}, nil
+}
+
+func (h *countingHeimdallClient) FetchCheckpoint(ctx context.Context, number int64) (*checkpoint.Checkpoint, error) {
+	h.calls++
+	return &checkpoint.Checkpoint{Timestamp: uint64(number)}, nil
+}
+
+func (h *countingHeimdallClient) FetchCheckpointCount(ctx context.Context) (int64, error) {
+	h.calls++
+	return h.checkpoints, nil
+}
+
+func (h *countingHeimdallClient) FetchMilestone(ctx context.Context) (*milestone.Milestone, error) {
+	h.calls++
+	return &milestone.Milestone{}, nil
+}
+
+func (h *countingHeimdallClient) FetchMilestoneCount(ctx context.Context) (int64, error) {
+	h.calls++
+	return 0, nil
+}
+
+func (h *countingHeimdallClient) Close() {}
+
+func TestCachingHeimdallClient(t *testing.T) {
+	ctx := context.Background()
+	db := memdb.NewTestDB(t)
+	logger := log.New()
+	live := &countingHeimdallClient{checkpoints: 3}
+
+	// The immutable responses are only fetched once
+	c := NewCachingHeimdallClient(live, db, HeimdallCache, logger)
+	for i := 0; i < 2; i++ {
+		s, err := c.Span(ctx, 7)
+		require.NoError(t, err)
+		require.Equal(t, uint64(7*6400), s.StartBlock)
+		_, err = c.FetchCheckpoint(ctx, 2)
+		require.NoError(t, err)
+		past := time.Now().Add(-2 * stateSyncCacheDelay).Unix()
+		events, err := c.StateSyncEvents(ctx, 10, past)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, uint64(10), events[0].ID)
+		require.Equal(t, time.Unix(past-1, 0).UTC(), events[0].Time)
+	}
+	require.Equal(t, 3, live.calls)
+	// The recent state sync events and the latest values are always fetched
+	for i := 0; i < 2; i++ {
+		_, err := c.StateSyncEvents(ctx, 10, time.Now().Unix())
+		require.NoError(t, err)
+		_, err = c.FetchCheckpointCount(ctx)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 7, live.calls)
+	// The errors are not cached
+	live.unavailableErr = heimdall.ErrServiceUnavailable
+	_, err := c.Span(ctx, 8)
+	require.ErrorIs(t, err, heimdall.ErrServiceUnavailable)
+	live.unavailableErr = nil
+
+	// Replay without recording fails for the latest values
+	replay := NewCachingHeimdallClient(nil, db, HeimdallReplay, logger)
+	_, err = replay.FetchCheckpointCount(ctx)
+	require.ErrorIs(t, err, ErrNotRecorded)
+	_, err = replay.Span(ctx, 8)
+	require.ErrorIs(t, err, ErrNotRecorded)
+
+	record := NewCachingHeimdallClient(live, db, HeimdallRecord, logger)
+	_, err = record.FetchCheckpointCount(ctx)
+	require.NoError(t, err)
+
+	// Replay answers from the records and the cache only
+	live.checkpoints = 4
+	calls := live.calls
+	count, err := replay.FetchCheckpointCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), count)
+	s, err := replay.Span(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, uint64(7), s.ID)
+	require.Equal(t, calls, live.calls)
+	replay.Close()
+
+	// Without cache every request goes to Heimdall
+	noCache := NewCachingHeimdallClient(live, db, HeimdallNoCache, logger)
+	_, err = noCache.Span(ctx, 7)
+	require.NoError(t, err)
+	require.Equal(t, calls+1, live.calls)
+
+	mode, err := ParseHeimdallCacheMode("")
+	require.NoError(t, err)
+	require.Equal(t, HeimdallNoCache, mode)
+	_, err = ParseHeimdallCacheMode("replay")
+	require.NoError(t, err)
+	_, err = ParseHeimdallCacheMode("other")
+	require.Error(t, err)
+}
+
+func TestCachingHeimdallClientStateSyncPruning(t *testing.T) {
+	ctx := context.Background()
+	logger := log.New()
+	live := &countingHeimdallClient{}
+	past := time.Now().Add(-2 * stateSyncCacheDelay).Unix()
+	stored := func(db kv.RoDB) (keys []string) {
+		require.NoError(t, db.View(ctx, func(tx kv.Tx) error {
+			return tx.ForPrefix(kv.BorSeparate, []byte(heimdallCachePrefix+"state-sync-"), func(k, _ []byte) error {
+				keys = append(keys, string(k[len(heimdallCachePrefix):]))
+				return nil
+			})
+		}))
+		return keys
+	}
+
+	// The cache keeps the events of the latest time of every first event ID
+	cacheDB := memdb.NewTestDB(t)
+	c := NewCachingHeimdallClient(live, cacheDB, HeimdallCache, logger)
+	for _, fetch := range []struct {
+		fromID uint64
+		to     int64
+	}{{10, past - 20}, {10, past - 10}, {11, past - 20}, {10, past}} {
+		_, err := c.StateSyncEvents(ctx, fetch.fromID, fetch.to)
+		require.NoError(t, err)
+	}
+	require.ElementsMatch(t, []string{fmt.Sprintf("state-sync-10-%d", past), fmt.Sprintf("state-sync-11-%d", past-20)}, stored(cacheDB))
+
+	// The records keep all of them for the replay
+	recordDB := memdb.NewTestDB(t)
+	record := NewCachingHeimdallClient(live, recordDB, HeimdallRecord, logger)
+	for _, to := range []int64{past - 10, past} {
+		_, err := record.StateSyncEvents(ctx, 10, to)
+		require.NoError(t, err)
+	}
+	require.Len(t, stored(recordDB), 2)
+}
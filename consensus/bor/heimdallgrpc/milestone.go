@@ -0,0 +1,183 @@
+package heimdallgrpc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ledgerwatch/log/v3"
+	proto "github.com/maticnetwork/polyproto/heimdall"
+	protoutils "github.com/maticnetwork/polyproto/utils"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/milestone"
+)
+
+// The milestone RPCs of the heimdall service are newer than the polyproto
+// version we build against, so their messages are encoded by hand. They
+// follow the layout of heimdall.proto:
+//
+//	message Milestone {
+//	    H160 Proposer = 1;
+//	    uint64 StartBlock = 2;
+//	    uint64 EndBlock = 3;
+//	    H256 RootHash = 4;
+//	    string BorChainID = 5;
+//	    google.protobuf.Timestamp Timestamp = 6;
+//	}
+//	message MilestoneCount { int64 Count = 1; }
+//
+// and every response is a { string Height = 1; <message> Result = 2; }.
+const (
+	fetchMilestoneMethod      = "/heimdall.Heimdall/FetchMilestone"
+	fetchMilestoneCountMethod = "/heimdall.Heimdall/FetchMilestoneCount"
+)
+
+func (h *HeimdallGRPCClient) FetchMilestone(ctx context.Context) (*milestone.Milestone, error) {
+	log.Info("Fetching milestone")
+
+	result, err := h.invokeMilestone(ctx, fetchMilestoneMethod)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := parseFields(result)
+	if err != nil {
+		return nil, err
+	}
+
+	proposer := &proto.H160{}
+	if err := protobuf.Unmarshal(fields[1].bytes, proposer); err != nil {
+		return nil, err
+	}
+
+	rootHash := &proto.H256{}
+	if err := protobuf.Unmarshal(fields[4].bytes, rootHash); err != nil {
+		return nil, err
+	}
+
+	timestamp := &timestamppb.Timestamp{}
+	if err := protobuf.Unmarshal(fields[6].bytes, timestamp); err != nil {
+		return nil, err
+	}
+
+	log.Info("Fetched milestone")
+
+	milestone := &milestone.Milestone{
+		StartBlock: new(big.Int).SetUint64(fields[2].varint),
+		EndBlock:   new(big.Int).SetUint64(fields[3].varint),
+		Hash:       protoutils.ConvertH256ToHash(rootHash),
+		Proposer:   protoutils.ConvertH160toAddress(proposer),
+		BorChainID: string(fields[5].bytes),
+		Timestamp:  uint64(timestamp.GetSeconds()),
+	}
+
+	return milestone, nil
+}
+
+func (h *HeimdallGRPCClient) FetchMilestoneCount(ctx context.Context) (int64, error) {
+	log.Info("Fetching milestone count")
+
+	result, err := h.invokeMilestone(ctx, fetchMilestoneCountMethod)
+	if err != nil {
+		return 0, err
+	}
+
+	fields, err := parseFields(result)
+	if err != nil {
+		return 0, err
+	}
+
+	log.Info("Fetched milestone count")
+
+	return int64(fields[1].varint), nil
+}
+
+// invokeMilestone calls a milestone RPC, whose requests are empty, and returns
+// the encoded Result of its response.
+func (h *HeimdallGRPCClient) invokeMilestone(ctx context.Context, method string) ([]byte, error) {
+	req, res := rawMessage(nil), rawMessage(nil)
+
+	if err := h.conn.Invoke(ctx, method, &req, &res, grpc.ForceCodec(rawCodec{})); err != nil {
+		// heimdall without milestones, like the HTTP API does when they are not enabled
+		if status.Code(err) == codes.Unimplemented {
+			return nil, fmt.Errorf("%w: %v", heimdall.ErrServiceUnavailable, err)
+		}
+
+		return nil, err
+	}
+
+	fields, err := parseFields(res)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+
+	return fields[2].bytes, nil
+}
+
+// rawMessage is an encoded protobuf message.
+type rawMessage []byte
+
+// rawCodec passes encoded protobuf messages through to the wire. It is named
+// after the proto codec, so that the server decodes them as usual.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	return *v.(*rawMessage), nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	*v.(*rawMessage) = append(rawMessage(nil), data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// field is the last value of a protobuf field: varint holds varint values,
+// bytes holds strings and embedded messages.
+type field struct {
+	varint uint64
+	bytes  []byte
+}
+
+// parseFields decodes the top level fields of an encoded protobuf message.
+// Missing fields have their zero value.
+func parseFields(b []byte) (map[protowire.Number]field, error) {
+	fields := map[protowire.Number]field{}
+
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var f field
+
+		switch typ {
+		case protowire.VarintType:
+			f.varint, n = protowire.ConsumeVarint(b)
+		case protowire.BytesType:
+			f.bytes, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		fields[num] = f
+	}
+
+	return fields, nil
+}
@@ -0,0 +1,97 @@
+package heimdallgrpc
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	proto "github.com/maticnetwork/polyproto/heimdall"
+	protoutils "github.com/maticnetwork/polyproto/utils"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	protobuf "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall"
+)
+
+// startHeimdall serves the milestone RPCs with the given encoded responses.
+func startHeimdall(t *testing.T, responses map[string][]byte) *HeimdallGRPCClient {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := grpc.NewServer(grpc.ForceServerCodec(rawCodec{}), grpc.UnknownServiceHandler(func(_ interface{}, stream grpc.ServerStream) error {
+		method, _ := grpc.MethodFromServerStream(stream)
+
+		var req rawMessage
+		if err := stream.RecvMsg(&req); err != nil {
+			return err
+		}
+
+		res, ok := responses[method]
+		if !ok {
+			return status.Error(codes.Unimplemented, method)
+		}
+
+		return stream.SendMsg((*rawMessage)(&res))
+	}))
+	go server.Serve(listener) //nolint:errcheck
+	t.Cleanup(server.Stop)
+
+	client := NewHeimdallGRPCClient(listener.Addr().String())
+	t.Cleanup(client.Close)
+
+	return client
+}
+
+func TestFetchMilestone(t *testing.T) {
+	proposer := libcommon.HexToAddress("0x1d1Ab2a5f1A7A1dB2C16D1b9C9e3F1f5a3a7a2c1")
+	rootHash := libcommon.HexToHash("0x6b1a0e0d2c55a37c8d2ad1e4d1f9f7ccf0d77ad2b1d2b6b1b2bd8d3c2e2e8b1a")
+
+	// a milestone has the layout of a checkpoint
+	milestone, err := protobuf.Marshal(&proto.FetchCheckpointResponse{
+		Height: "100",
+		Result: &proto.Checkpoint{
+			Proposer:   protoutils.ConvertAddressToH160(proposer),
+			StartBlock: 10,
+			EndBlock:   20,
+			RootHash:   protoutils.ConvertHashToH256(rootHash),
+			BorChainID: "137",
+			Timestamp:  &timestamppb.Timestamp{Seconds: 1000},
+		},
+	})
+	require.NoError(t, err)
+
+	count, err := protobuf.Marshal(&proto.FetchCheckpointCountResponse{Height: "100", Result: &proto.CheckpointCount{Result: 42}})
+	require.NoError(t, err)
+
+	client := startHeimdall(t, map[string][]byte{
+		fetchMilestoneMethod:      milestone,
+		fetchMilestoneCountMethod: count,
+	})
+	ctx := context.Background()
+
+	m, err := client.FetchMilestone(ctx)
+	require.NoError(t, err)
+	require.Equal(t, proposer, m.Proposer)
+	require.Equal(t, big.NewInt(10), m.StartBlock)
+	require.Equal(t, big.NewInt(20), m.EndBlock)
+	require.Equal(t, rootHash, m.Hash)
+	require.Equal(t, "137", m.BorChainID)
+	require.Equal(t, uint64(1000), m.Timestamp)
+
+	n, err := client.FetchMilestoneCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(42), n)
+}
+
+func TestFetchMilestoneUnimplemented(t *testing.T) {
+	client := startHeimdall(t, map[string][]byte{})
+
+	_, err := client.FetchMilestone(context.Background())
+	require.ErrorIs(t, err, heimdall.ErrServiceUnavailable)
+}
@@ -118,7 +118,7 @@ func (s *Snapshot) copy() *Snapshot {
 	return cpy
 }
 
-func (s *Snapshot) apply(headers []*types.Header, logger log.Logger) (*Snapshot, error) {
+func (s *Snapshot) apply(headers []*types.Header, getValidatorBytes func(header *types.Header) ([]byte, error), logger log.Logger) (*Snapshot, error) {
 	// Allow passing in no headers for cleaner code
 	if len(headers) == 0 {
 		return s, nil
@@ -169,7 +169,10 @@ func (s *Snapshot) apply(headers []*types.Header, logger log.Logger) (*Snapshot,
 			if err := validateHeaderExtraField(header.Extra); err != nil {
 				return nil, err
 			}
-			validatorBytes := header.Extra[extraVanity : len(header.Extra)-extraSeal]
+			validatorBytes, err := getValidatorBytes(header)
+			if err != nil {
+				return nil, err
+			}
 
 			// get validators from headers and use that for new validator set
 			newVals, _ := valset.ParseValidators(validatorBytes)
@@ -132,7 +132,7 @@ type EngineWriter interface {
 
 	// Initialize runs any pre-transaction state modifications (e.g. epoch start)
 	Initialize(config *chain.Config, chain ChainHeaderReader, header *types.Header,
-		state *state.IntraBlockState, txs []types.Transaction, uncles []*types.Header, syscall SysCallCustom)
+		state *state.IntraBlockState, txs []types.Transaction, uncles []*types.Header, syscall SysCallCustom) error
 
 	// Finalize runs any post-transaction state modifications (e.g. block rewards)
 	// but does not assemble the block.
@@ -178,6 +178,13 @@ type EngineWriter interface {
 	Close() error
 }
 
+// FamilyVerifier is a consensus engine which checks the headers against the state of their parents.
+// Unlike Initialize, VerifyFamily is only called once per block, from the serial execution of the chain.
+type FamilyVerifier interface {
+	// VerifyFamily checks the header against its parent, before the transactions of the block are executed.
+	VerifyFamily(chain ChainHeaderReader, header *types.Header, state *state.IntraBlockState, syscall SysCallCustom) error
+}
+
 // PoW is a consensus engine based on proof-of-work.
 type PoW interface {
 	Engine
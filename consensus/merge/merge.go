@@ -270,8 +270,15 @@ func (s *Merge) IsServiceTransaction(sender libcommon.Address, syscall consensus
 	return s.eth1Engine.IsServiceTransaction(sender, syscall)
 }
 
-func (s *Merge) Initialize(config *chain.Config, chain consensus.ChainHeaderReader, header *types.Header, state *state.IntraBlockState, txs []types.Transaction, uncles []*types.Header, syscall consensus.SysCallCustom) {
-	s.eth1Engine.Initialize(config, chain, header, state, txs, uncles, syscall)
+func (s *Merge) Initialize(config *chain.Config, chain consensus.ChainHeaderReader, header *types.Header, state *state.IntraBlockState, txs []types.Transaction, uncles []*types.Header, syscall consensus.SysCallCustom) error {
+	return s.eth1Engine.Initialize(config, chain, header, state, txs, uncles, syscall)
+}
+
+func (s *Merge) VerifyFamily(chain consensus.ChainHeaderReader, header *types.Header, state *state.IntraBlockState, syscall consensus.SysCallCustom) error {
+	if verifier, ok := s.eth1Engine.(consensus.FamilyVerifier); ok && !misc.IsPoSHeader(header) {
+		return verifier.VerifyFamily(chain, header, state, syscall)
+	}
+	return nil
 }
 
 func (s *Merge) APIs(chain consensus.ChainHeaderReader) []rpc.API {
@@ -342,10 +342,24 @@ func FinalizeBlockExecution(
 	return newBlock, newTxs, newReceipt, nil
 }
 
-func InitializeBlockExecution(engine consensus.Engine, chain consensus.ChainHeaderReader, header *types.Header, txs types.Transactions, uncles []*types.Header, cc *chain.Config, ibs *state.IntraBlockState) error {
-	engine.Initialize(cc, chain, header, ibs, txs, uncles, func(contract libcommon.Address, data []byte, ibState *state.IntraBlockState, header *types.Header, constCall bool) ([]byte, error) {
+// VerifyBlockFamily checks the header against the state of its parent, for the engines which need it.
+// It's only called from the serial execution, unlike InitializeBlockExecution which the tracing calls too.
+func VerifyBlockFamily(engine consensus.Engine, chain consensus.ChainHeaderReader, header *types.Header, cc *chain.Config, stateReader state.StateReader) error {
+	verifier, ok := engine.(consensus.FamilyVerifier)
+	if !ok {
+		return nil
+	}
+	return verifier.VerifyFamily(chain, header, state.New(stateReader), func(contract libcommon.Address, data []byte, ibState *state.IntraBlockState, header *types.Header, constCall bool) ([]byte, error) {
 		return SysCallContract(contract, data, cc, ibState, header, engine, constCall)
 	})
+}
+
+func InitializeBlockExecution(engine consensus.Engine, chain consensus.ChainHeaderReader, header *types.Header, txs types.Transactions, uncles []*types.Header, cc *chain.Config, ibs *state.IntraBlockState) error {
+	if err := engine.Initialize(cc, chain, header, ibs, txs, uncles, func(contract libcommon.Address, data []byte, ibState *state.IntraBlockState, header *types.Header, constCall bool) ([]byte, error) {
+		return SysCallContract(contract, data, cc, ibState, header, engine, constCall)
+	}); err != nil {
+		return err
+	}
 	noop := state.NewNoopWriter()
 	ibs.FinalizeTx(cc.Rules(header.Number.Uint64(), header.Time), noop)
 	return nil
@@ -37,11 +37,9 @@ type ExecVersionView struct {
 	sender common.Address
 }
 
-var NumSpeculativeProcs int = 8
-
-func SetProcs(specProcs int) {
-	NumSpeculativeProcs = specProcs
-}
+// DefaultSpeculativeProcs is the number of speculative workers used when the
+// caller doesn't configure one
+const DefaultSpeculativeProcs = 8
 
 func (ev *ExecVersionView) Execute() (er ExecResult) {
 	er.ver = ev.ver
@@ -234,6 +232,9 @@ type ParallelExecutor struct {
 	// Enable profiling
 	profile bool
 
+	// Number of workers executing speculative tasks
+	numSpeculativeProcs int
+
 	// Worker wait group
 	workerWg sync.WaitGroup
 }
@@ -246,9 +247,13 @@ type ExecutionStat struct {
 	Worker      int
 }
 
-func NewParallelExecutor(tasks []ExecTask, profile bool, metadata bool) *ParallelExecutor {
+func NewParallelExecutor(tasks []ExecTask, profile bool, metadata bool, numSpeculativeProcs int) *ParallelExecutor {
 	numTasks := len(tasks)
 
+	if numSpeculativeProcs <= 0 {
+		numSpeculativeProcs = DefaultSpeculativeProcs
+	}
+
 	var resultQueue SafeQueue
 
 	var specTaskQueue SafeQueue
@@ -262,25 +267,26 @@ func NewParallelExecutor(tasks []ExecTask, profile bool, metadata bool) *Paralle
 	}
 
 	pe := &ParallelExecutor{
-		tasks:              tasks,
-		stats:              make(map[int]ExecutionStat, numTasks),
-		chTasks:            make(chan ExecVersionView, numTasks),
-		chSpeculativeTasks: make(chan struct{}, numTasks),
-		chResults:          make(chan struct{}, numTasks),
-		specTaskQueue:      specTaskQueue,
-		resultQueue:        resultQueue,
-		skipCheck:          make(map[int]bool),
-		execTasks:          makeStatusManager(numTasks),
-		validateTasks:      makeStatusManager(0),
-		diagExecSuccess:    make([]int, numTasks),
-		diagExecAbort:      make([]int, numTasks),
-		mvh:                MakeMVHashMap(),
-		lastTxIO:           MakeTxnInputOutput(numTasks),
-		txIncarnations:     make([]int, numTasks),
-		estimateDeps:       make(map[int][]int),
-		preValidated:       make(map[int]bool),
-		begin:              time.Now(),
-		profile:            profile,
+		tasks:               tasks,
+		stats:               make(map[int]ExecutionStat, numTasks),
+		chTasks:             make(chan ExecVersionView, numTasks),
+		chSpeculativeTasks:  make(chan struct{}, numTasks),
+		chResults:           make(chan struct{}, numTasks),
+		specTaskQueue:       specTaskQueue,
+		resultQueue:         resultQueue,
+		skipCheck:           make(map[int]bool),
+		execTasks:           makeStatusManager(numTasks),
+		validateTasks:       makeStatusManager(0),
+		diagExecSuccess:     make([]int, numTasks),
+		diagExecAbort:       make([]int, numTasks),
+		mvh:                 MakeMVHashMap(),
+		lastTxIO:            MakeTxnInputOutput(numTasks),
+		txIncarnations:      make([]int, numTasks),
+		estimateDeps:        make(map[int][]int),
+		preValidated:        make(map[int]bool),
+		begin:               time.Now(),
+		profile:             profile,
+		numSpeculativeProcs: numSpeculativeProcs,
 	}
 
 	return pe
@@ -318,10 +324,10 @@ func (pe *ParallelExecutor) Prepare() error {
 		}
 	}
 
-	pe.workerWg.Add(NumSpeculativeProcs + numGoProcs)
+	pe.workerWg.Add(pe.numSpeculativeProcs + numGoProcs)
 
 	// Launch workers that execute transactions
-	for i := 0; i < NumSpeculativeProcs+numGoProcs; i++ {
+	for i := 0; i < pe.numSpeculativeProcs+numGoProcs; i++ {
 		go func(procNum int) {
 			defer pe.workerWg.Done()
 
@@ -355,7 +361,7 @@ func (pe *ParallelExecutor) Prepare() error {
 				}
 			}
 
-			if procNum < NumSpeculativeProcs {
+			if procNum < pe.numSpeculativeProcs {
 				for range pe.chSpeculativeTasks {
 					doWork(pe.specTaskQueue.Pop().(ExecVersionView))
 				}
@@ -563,12 +569,12 @@ func (pe *ParallelExecutor) Step(res *ExecResult) (result ParallelExecutionResul
 
 type PropertyCheck func(*ParallelExecutor) error
 
-func executeParallelWithCheck(tasks []ExecTask, profile bool, check PropertyCheck, metadata bool, interruptCtx context.Context) (result ParallelExecutionResult, err error) {
+func executeParallelWithCheck(tasks []ExecTask, profile bool, check PropertyCheck, metadata bool, numSpeculativeProcs int, interruptCtx context.Context) (result ParallelExecutionResult, err error) {
 	if len(tasks) == 0 {
 		return ParallelExecutionResult{MakeTxnInputOutput(len(tasks)), nil, nil, nil}, nil
 	}
 
-	pe := NewParallelExecutor(tasks, profile, metadata)
+	pe := NewParallelExecutor(tasks, profile, metadata, numSpeculativeProcs)
 	err = pe.Prepare()
 
 	if err != nil {
@@ -602,6 +608,8 @@ func executeParallelWithCheck(tasks []ExecTask, profile bool, check PropertyChec
 	return
 }
 
-func ExecuteParallel(tasks []ExecTask, profile bool, metadata bool, interruptCtx context.Context) (result ParallelExecutionResult, err error) {
-	return executeParallelWithCheck(tasks, profile, nil, metadata, interruptCtx)
+// ExecuteParallel runs the tasks with numSpeculativeProcs speculative workers,
+// DefaultSpeculativeProcs if it's not positive.
+func ExecuteParallel(tasks []ExecTask, profile bool, metadata bool, numSpeculativeProcs int, interruptCtx context.Context) (result ParallelExecutionResult, err error) {
+	return executeParallelWithCheck(tasks, profile, nil, metadata, numSpeculativeProcs, interruptCtx)
 }
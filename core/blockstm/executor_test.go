@@ -427,7 +427,7 @@ func runParallel(t *testing.T, tasks []ExecTask, validation PropertyCheck, metad
 	profile := false
 
 	start := time.Now()
-	result, err := executeParallelWithCheck(tasks, false, validation, metadata, context.Background())
+	result, err := executeParallelWithCheck(tasks, false, validation, metadata, DefaultSpeculativeProcs, context.Background())
 
 	if result.Deps != nil && profile {
 		result.Deps.Report(*result.Stats, func(str string) { fmt.Println(str) })
@@ -460,7 +460,7 @@ func runParallel(t *testing.T, tasks []ExecTask, validation PropertyCheck, metad
 func runParallelGetMetadata(t *testing.T, tasks []ExecTask, validation PropertyCheck) map[int]map[int]bool {
 	t.Helper()
 
-	res, err := executeParallelWithCheck(tasks, true, validation, false, context.Background())
+	res, err := executeParallelWithCheck(tasks, true, validation, false, DefaultSpeculativeProcs, context.Background())
 
 	assert.NoError(t, err, "error occur during parallel execution")
 
@@ -1,24 +1,22 @@
 package core
 
 import (
-	"context"
+	"errors"
 	"fmt"
-	"math/big"
 	"time"
 
+	metrics2 "github.com/VictoriaMetrics/metrics"
 	"github.com/ledgerwatch/erigon-lib/chain"
-	"github.com/ledgerwatch/erigon-lib/common"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
-	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/common/math"
 	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/consensus/misc"
 	"github.com/ledgerwatch/erigon/core/blockstm"
 	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
 	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
 	"github.com/ledgerwatch/erigon/crypto"
-	"github.com/ledgerwatch/erigon/ethdb"
-	"github.com/ledgerwatch/erigon/turbo/services"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
 	"github.com/ledgerwatch/log/v3"
 	"golang.org/x/exp/slices"
 
@@ -26,25 +24,26 @@ import (
 	"github.com/ledgerwatch/erigon/core/vm"
 )
 
-type ParallelEVMConfig struct {
-	Enable               bool
-	SpeculativeProcesses int
-}
-
-// StateProcessor is a basic Processor, which takes care of transitioning
-// state from one point to another.
+// ParallelStateProcessor executes Bor blocks with Block-STM: transactions
+// are run speculatively on several goroutines against a multi-version hashmap,
+// validated, re-executed on conflicts and finally settled in block order.
 //
-// StateProcessor implements Processor.
+// Whenever the parallel run cannot complete (ParallelExecFailedError) or its
+// result does not match the block, the block is re-executed serially with
+// ExecuteBlockEphemerally, so the outcome is always identical to serial
+// execution.
 type ParallelStateProcessor struct {
 	config *chain.Config    // Chain configuration options
 	engine consensus.Engine // Consensus engine used for block rewards
+	procs  int              // Number of speculative Block-STM workers
 }
 
-// NewStateProcessor initialises a new StateProcessor.
-func NewParallelStateProcessor(config *chain.Config, engine consensus.Engine) *ParallelStateProcessor {
+// NewParallelStateProcessor initialises a new ParallelStateProcessor.
+func NewParallelStateProcessor(config *chain.Config, engine consensus.Engine, cfg ethconfig.ParallelEVMConfig) *ParallelStateProcessor {
 	return &ParallelStateProcessor{
 		config: config,
 		engine: engine,
+		procs:  cfg.SpeculativeProcesses,
 	}
 }
 
@@ -68,78 +67,102 @@ type ExecutionTask struct {
 	receipts                   *types.Receipts
 	allLogs                    *[]*types.Log
 	stateWriter                state.StateWriter
-	excessDataGas              *big.Int
+	stateReader                state.StateReader
+
+	// tracer of the latest incarnation, forked from evmConfig.Tracer
+	tracer vm.EVMLogger
 
-	// length of dependencies          -> 2 + k (k = a whole number)
-	// first 2 element in dependencies -> transaction index, and flag representing if delay is allowed or not
-	//                                       (0 -> delay is not allowed, 1 -> delay is allowed)
-	// next k elements in dependencies -> transaction indexes on which transaction i is dependent on
+	// indexes of the transactions this transaction depends on, as supplied
+	// by the block producer in the header
 	dependencies []int
 
-	blockContext  evmtypes.BlockContext
-	evm           *vm.EVM
-	engine        consensus.Engine
-	msg           *types.Message
-	db            kv.RwDB
-	batch         ethdb.Database
-	dbtx          kv.Tx
-	rules         *chain.Rules
-	blockHashFunc func(n uint64) libcommon.Hash
-	getHeader     func(hash libcommon.Hash, number uint64) *types.Header
-	chainReader   consensus.ChainHeaderReader
-	blockReader   services.FullBlockReader
-	ctx           context.Context
+	blockContext evmtypes.BlockContext
+	evm          *vm.EVM
+	msg          *types.Message
+	rules        *chain.Rules
 }
 
-type dbWrapper struct {
-	kv.Tx
-	m ethdb.Database
+// serialReader funnels state and block hash reads issued by Block-STM workers
+// into the goroutine which owns the database transaction: read-write
+// transactions are bound to the OS thread which opened them and must not be
+// used concurrently.
+type serialReader struct {
+	stateReader   state.StateReader
+	blockHashFunc func(n uint64) libcommon.Hash
+	reqs          chan func()
 }
 
-func (m dbWrapper) Put(table string, key []byte, value []byte) error {
-	panic("Shouldn't happen. This is a read-only transaction")
+func newSerialReader(stateReader state.StateReader, blockHashFunc func(n uint64) libcommon.Hash) *serialReader {
+	return &serialReader{stateReader: stateReader, blockHashFunc: blockHashFunc, reqs: make(chan func())}
 }
 
-func (m dbWrapper) GetOne(table string, key []byte) ([]byte, error) {
-	if value, err := m.m.GetOne(table, key); err != nil {
-		return m.Tx.GetOne(table, key)
-	} else {
-		return value, nil
+func (r *serialReader) do(f func()) {
+	done := make(chan struct{})
+	r.reqs <- func() {
+		defer close(done)
+		f()
 	}
+	<-done
 }
 
-func (task *ExecutionTask) Execute(mvh *blockstm.MVHashMap, incarnation int) (err error) {
-	if task.dbtx == nil {
-		task.dbtx, err = task.db.BeginRo(task.ctx)
-		if err != nil {
-			err = blockstm.ErrExecAbortError{OriginError: err}
+// serve executes queued reads on the calling goroutine until run returns.
+func (r *serialReader) serve(run func() error) error {
+	errCh := make(chan error, 1)
+	go func() { errCh <- run() }()
+	for {
+		select {
+		case f := <-r.reqs:
+			f()
+		case err := <-errCh:
 			return err
 		}
 	}
+}
 
-	m := dbWrapper{task.dbtx, task.batch}
+func (r *serialReader) ReadAccountData(address libcommon.Address) (acc *accounts.Account, err error) {
+	r.do(func() { acc, err = r.stateReader.ReadAccountData(address) })
+	return acc, err
+}
 
-	task.statedb = state.NewWithMVHashmap(state.NewPlainStateReader(m), mvh)
-	task.statedb.SetTxContext(task.tx.Hash(), task.blockHash, task.index)
+func (r *serialReader) ReadAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash) (v []byte, err error) {
+	r.do(func() { v, err = r.stateReader.ReadAccountStorage(address, incarnation, key) })
+	return v, err
+}
 
-	task.statedb.SetBlockSTMIncarnation(incarnation)
+func (r *serialReader) ReadAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (code []byte, err error) {
+	r.do(func() { code, err = r.stateReader.ReadAccountCode(address, incarnation, codeHash) })
+	return code, err
+}
 
-	if !task.evmConfig.ReadOnly {
-		if err := InitializeBlockExecution(task.engine, task.chainReader, task.block.Header(), task.block.Transactions(), task.block.Uncles(), task.config, task.statedb); err != nil {
-			return err
-		}
-	}
+func (r *serialReader) ReadAccountCodeSize(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (size int, err error) {
+	r.do(func() { size, err = r.stateReader.ReadAccountCodeSize(address, incarnation, codeHash) })
+	return size, err
+}
 
-	getHeader := func(hash common.Hash, number uint64) *types.Header {
-		h, _ := task.blockReader.Header(context.Background(), task.dbtx, hash, number)
-		return h
-	}
+func (r *serialReader) ReadAccountIncarnation(address libcommon.Address) (inc uint64, err error) {
+	r.do(func() { inc, err = r.stateReader.ReadAccountIncarnation(address) })
+	return inc, err
+}
 
-	getHashFn := GetHashFn(task.block.Header(), getHeader)
+func (r *serialReader) BlockHash(n uint64) (h libcommon.Hash) {
+	r.do(func() { h = r.blockHashFunc(n) })
+	return h
+}
 
-	blockContext := NewEVMBlockContext(task.header, getHashFn, task.engine, nil)
+func (task *ExecutionTask) Execute(mvh *blockstm.MVHashMap, incarnation int) (err error) {
+	task.statedb = state.NewWithMVHashmap(task.stateReader, mvh)
+	task.statedb.SetTxContext(task.tx.Hash(), task.blockHash, task.index)
+
+	task.statedb.SetBlockSTMIncarnation(incarnation)
+	task.shouldRerunWithoutFeeDelay = false
+
+	evmConfig := *task.evmConfig
+	if forkable, ok := evmConfig.Tracer.(vm.ForkableTracer); ok && evmConfig.Debug {
+		task.tracer = forkable.Fork()
+		evmConfig.Tracer = task.tracer
+	}
 
-	evm := vm.NewEVM(blockContext, evmtypes.TxContext{}, task.statedb, task.config, *task.evmConfig)
+	evm := vm.NewEVM(task.blockContext, evmtypes.TxContext{}, task.statedb, task.config, evmConfig)
 
 	task.evm = evm
 
@@ -171,8 +194,8 @@ func (task *ExecutionTask) Execute(mvh *blockstm.MVHashMap, incarnation int) (er
 
 		reads := task.statedb.MVReadMap()
 
-		if _, ok := reads[blockstm.NewSubpathKey(blockContext.Coinbase, state.BalancePath)]; ok {
-			log.Debug("Coinbase is in MVReadMap", "address", blockContext.Coinbase)
+		if _, ok := reads[blockstm.NewSubpathKey(task.blockContext.Coinbase, state.BalancePath)]; ok {
+			log.Debug("Coinbase is in MVReadMap", "address", task.blockContext.Coinbase)
 
 			task.shouldRerunWithoutFeeDelay = true
 		}
@@ -231,7 +254,7 @@ func (task *ExecutionTask) Settle() {
 	}
 
 	if *task.shouldDelayFeeCal {
-		if task.config.IsLondon(task.block.NumberU64()) {
+		if task.result.FeeBurnt != nil {
 			task.finalStateDB.AddBalance(task.result.BurntContractAddress, task.result.FeeBurnt)
 		}
 
@@ -292,29 +315,72 @@ func (task *ExecutionTask) Settle() {
 	*task.allLogs = append(*task.allLogs, receipt.Logs...)
 }
 
-// Process processes the state changes according to the Ethereum rules by running
-// the transaction messages using the statedb and applying any rewards to both
-// the processor (coinbase) and any included uncles.
+// Process executes the block with Block-STM, transitioning the state read
+// from stateReader and writing the result to stateWriter, just like
+// ExecuteBlockEphemerally does.
 //
-// Process returns the receipts and logs accumulated during the process and
-// returns the amount of gas that was used in the process. If any of the
-// transactions failed to execute due to insufficient gas it will return an error.
-// nolint:gocognit
-func ParallelExecuteBlockEphemerallyBor(
-	chainConfig *chain.Config,
+// The block is re-executed serially whenever the parallel run fails with
+// blockstm.ParallelExecFailedError, a transaction's fee calculation cannot be
+// delayed, the configured tracer cannot be forked per transaction, or the
+// parallel result does not match the block header.
+func (p *ParallelStateProcessor) Process(
 	vmConfig *vm.Config,
 	blockHashFunc func(n uint64) libcommon.Hash,
-	engine consensus.Engine,
 	block *types.Block,
 	stateReader state.StateReader,
 	stateWriter state.WriterWithChangeSets,
 	chainReader consensus.ChainHeaderReader,
 	getTracer func(txIndex int, txHash libcommon.Hash) (vm.EVMLogger, error),
-	getHeader func(hash libcommon.Hash, number uint64) *types.Header,
-	db kv.RwDB,
-	batch ethdb.Database,
-	blockReader services.FullBlockReader,
 ) (*EphemeralExecResult, error) {
+	serial := func() (*EphemeralExecResult, error) {
+		parallelExecFallbacks.Inc()
+		return ExecuteBlockEphemerally(p.config, vmConfig, blockHashFunc, p.engine, block, stateReader, stateWriter, chainReader, getTracer)
+	}
+
+	if vmConfig.Debug {
+		if _, ok := vmConfig.Tracer.(vm.ForkableTracer); !ok {
+			return serial()
+		}
+	}
+
+	execRs, err := p.executeParallel(vmConfig, blockHashFunc, block, stateReader, stateWriter, chainReader)
+	if err != nil {
+		var parallelErr blockstm.ParallelExecFailedError
+		var mismatchErr parallelMismatchError
+		if errors.As(err, &parallelErr) || errors.As(err, &mismatchErr) || errors.Is(err, errRerunWithoutFeeDelay) {
+			log.Debug("blockstm falling back to serial execution", "block", block.NumberU64(), "err", err)
+			return serial()
+		}
+		return nil, err
+	}
+	return execRs, nil
+}
+
+var (
+	parallelExecFallbacks = metrics2.GetOrCreateCounter("chain_execution_parallel_fallbacks")
+
+	errRerunWithoutFeeDelay = errors.New("fee calculation can't be delayed")
+)
+
+// parallelMismatchError means the settled parallel result disagrees with the
+// block header; the block is re-executed serially before it is declared bad.
+type parallelMismatchError struct {
+	err error
+}
+
+func (e parallelMismatchError) Error() string { return e.err.Error() }
+func (e parallelMismatchError) Unwrap() error { return e.err }
+
+// nolint:gocognit
+func (p *ParallelStateProcessor) executeParallel(
+	vmConfig *vm.Config,
+	blockHashFunc func(n uint64) libcommon.Hash,
+	block *types.Block,
+	stateReader state.StateReader,
+	stateWriter state.WriterWithChangeSets,
+	chainReader consensus.ChainHeaderReader,
+) (*EphemeralExecResult, error) {
+	chainConfig, engine := p.config, p.engine
 
 	defer BlockExecutionTimer.UpdateDuration(time.Now())
 	block.Uncles()
@@ -322,8 +388,6 @@ func ParallelExecuteBlockEphemerallyBor(
 	header := block.Header()
 
 	usedGas := new(uint64)
-	gp := new(GasPool)
-	gp.AddGas(block.GasLimit()).AddDataGas(chain.MaxDataGasPerBlock)
 
 	var (
 		rejectedTxs []*RejectedTx
@@ -345,15 +409,22 @@ func ParallelExecuteBlockEphemerallyBor(
 	shouldDelayFeeCal := true
 	tasks := make([]blockstm.ExecTask, 0, len(block.Transactions()))
 
+	// Dependency metadata is advisory: Block-STM validates every read, so
+	// wrong dependencies only cost re-executions.
+	var deps map[int][]int
+	if txDependency := block.GetTxDependency(); len(txDependency) == len(block.Transactions()) {
+		deps = GetDeps(txDependency)
+	}
+
 	var logs []*types.Log
 
-	blockContext := NewEVMBlockContext(header, blockHashFunc, engine, nil)
+	reader := newSerialReader(stateReader, blockHashFunc)
+
+	blockContext := NewEVMBlockContext(header, reader.BlockHash, engine, nil)
 	vmenv := vm.NewEVM(blockContext, evmtypes.TxContext{}, ibs, chainConfig, *vmConfig)
 
 	rules := vmenv.ChainRules()
 
-	ctx := context.Background()
-
 	vmConfig.SkipAnalysis = SkipAnalysis(chainConfig, header.Number.Uint64())
 
 	for i, tx := range block.Transactions() {
@@ -371,18 +442,6 @@ func ParallelExecuteBlockEphemerallyBor(
 			msg.SetIsFree(engine.IsServiceTransaction(msg.From(), syscall))
 		}
 
-		evmConfig := vmConfig.Copy()
-		evmConfig.Debug = false
-
-		if evmConfig.Debug {
-			tracer, err := getTracer(i, tx.Hash())
-			if err != nil {
-				return nil, fmt.Errorf("could not obtain tracer: %w", err)
-			}
-
-			evmConfig.Tracer = tracer
-		}
-
 		task := &ExecutionTask{
 			config:            chainConfig,
 			gasLimit:          block.GasLimit(),
@@ -392,49 +451,34 @@ func ParallelExecuteBlockEphemerallyBor(
 			index:             i,
 			finalStateDB:      ibs,
 			header:            header,
-			evmConfig:         evmConfig,
+			evmConfig:         vmConfig,
 			shouldDelayFeeCal: &shouldDelayFeeCal,
 			totalUsedGas:      usedGas,
 			receipts:          &receipts,
 			allLogs:           &logs,
-			dependencies:      nil,
+			dependencies:      deps[i],
 			blockContext:      blockContext,
 			stateWriter:       noop,
-			// excessDataGas:     excessDataGas,
-			db:            db,
-			batch:         batch,
-			sender:        msg.From(),
-			msg:           &msg,
-			rules:         rules,
-			blockHashFunc: blockHashFunc,
-			engine:        engine,
-			getHeader:     getHeader,
-			chainReader:   chainReader,
-			blockReader:   blockReader,
-			ctx:           ctx,
+			stateReader:       reader,
+			sender:            msg.From(),
+			msg:               &msg,
+			rules:             rules,
 		}
 
 		tasks = append(tasks, task)
-
-		defer func(t *ExecutionTask) {
-			if t.dbtx != nil {
-				t.dbtx.Rollback()
-			}
-		}(task)
 	}
 
-	var err error
-
-	_, err = blockstm.ExecuteParallel(tasks, false, false, nil)
-
-	if err != nil {
-		return ExecuteBlockEphemerally(chainConfig, vmConfig, blockHashFunc, engine, block, stateReader, stateWriter, chainReader, getTracer)
+	if err := reader.serve(func() error {
+		_, err := blockstm.ExecuteParallel(tasks, false, deps != nil, p.procs, nil)
+		return err
+	}); err != nil {
+		return nil, err
 	}
 
 	for _, task := range tasks {
 		task := task.(*ExecutionTask)
 		if task.shouldRerunWithoutFeeDelay {
-			return ExecuteBlockEphemerally(chainConfig, vmConfig, blockHashFunc, engine, block, stateReader, stateWriter, chainReader, getTracer)
+			return nil, errRerunWithoutFeeDelay
 		}
 	}
 
@@ -443,35 +487,39 @@ func ParallelExecuteBlockEphemerallyBor(
 		task.(*ExecutionTask).Settle()
 	}
 
-	if err != nil {
-		log.Error("blockstm error executing block", "err", err)
-		return nil, err
-	}
-
 	receiptSha := types.DeriveSha(receipts)
 	if !vmConfig.StatelessExec && chainConfig.IsByzantium(header.Number.Uint64()) && !vmConfig.NoReceipts && receiptSha != block.ReceiptHash() {
 		for i, l := range logs {
-			log.Info("Log", "index", i, "address", l.Address, "topics", l.Topics, "data", fmt.Sprintf("%x", l.Data))
+			log.Debug("Log", "index", i, "address", l.Address, "topics", l.Topics, "data", fmt.Sprintf("%x", l.Data))
 		}
 
 		for i, r := range tasks {
-			log.Info("Receipt", "index", i, "incarnation", r.(*ExecutionTask).statedb.Version().Incarnation, "usedGas", r.(*ExecutionTask).result.UsedGas)
+			log.Debug("Receipt", "index", i, "incarnation", r.(*ExecutionTask).statedb.Version().Incarnation, "usedGas", r.(*ExecutionTask).result.UsedGas)
 		}
 
-		return nil, fmt.Errorf("mismatched receipt headers for block %d (%s != %s)", block.NumberU64(), receiptSha.Hex(), block.ReceiptHash().Hex())
+		return nil, parallelMismatchError{fmt.Errorf("mismatched receipt headers for block %d (%s != %s)", block.NumberU64(), receiptSha.Hex(), block.ReceiptHash().Hex())}
 	}
 
 	if !vmConfig.StatelessExec && *usedGas != header.GasUsed {
-		return nil, fmt.Errorf("gas used by execution: %d, in header: %d", *usedGas, header.GasUsed)
+		return nil, parallelMismatchError{fmt.Errorf("gas used by execution: %d, in header: %d", *usedGas, header.GasUsed)}
 	}
 
 	var bloom types.Bloom
 	if !vmConfig.NoReceipts {
 		bloom = types.CreateBloom(receipts)
 		if !vmConfig.StatelessExec && bloom != header.Bloom {
-			return nil, fmt.Errorf("bloom computed by execution: %x, in header: %x", bloom, header.Bloom)
+			return nil, parallelMismatchError{fmt.Errorf("bloom computed by execution: %x, in header: %x", bloom, header.Bloom)}
+		}
+	}
+
+	// The parallel result is accepted: only now the traces of the forked
+	// tracers are joined, so a serial re-execution starts from a clean tracer.
+	for _, task := range tasks {
+		if task := task.(*ExecutionTask); task.tracer != nil {
+			vmConfig.Tracer.(vm.ForkableTracer).Join(task.tracer)
 		}
 	}
+
 	if !vmConfig.ReadOnly {
 		txs := block.Transactions()
 		if _, _, _, err := FinalizeBlockExecution(engine, stateReader, block.Header(), txs, block.Uncles(), stateWriter, chainConfig, ibs, receipts, block.Withdrawals(), chainReader, false); err != nil {
@@ -508,22 +556,21 @@ func ParallelExecuteBlockEphemerallyBor(
 	return execRs, nil
 }
 
-func GetDeps(txDependency [][]uint64) (map[int][]int, map[int]bool) {
-	deps := make(map[int][]int)
-	delayMap := make(map[int]bool)
-
-	for i := 0; i <= len(txDependency)-1; i++ {
-		idx := int(txDependency[i][0])
-		shouldDelay := txDependency[i][1] == 1
+// GetDeps converts the Block-STM dependencies found in a Bor header into
+// dependency lists indexed by transaction. Entries which do not point to an
+// earlier transaction are dropped.
+func GetDeps(txDependency [][]uint64) map[int][]int {
+	deps := make(map[int][]int, len(txDependency))
 
-		delayMap[idx] = shouldDelay
+	for i := range txDependency {
+		deps[i] = []int{}
 
-		deps[idx] = []int{}
-
-		for j := 2; j <= len(txDependency[i])-1; j++ {
-			deps[idx] = append(deps[idx], int(txDependency[i][j]))
+		for _, dep := range txDependency[i] {
+			if dep < uint64(i) {
+				deps[i] = append(deps[i], int(dep))
+			}
 		}
 	}
 
-	return deps, delayMap
+	return deps
 }
@@ -0,0 +1,139 @@
+package core_test
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	metrics2 "github.com/VictoriaMetrics/metrics"
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/common/u256"
+	"github.com/ledgerwatch/erigon/consensus/ethash"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/calltracer"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/params"
+)
+
+// TestParallelStateProcessor checks that Block-STM execution of a block with
+// conflicting transactions yields the same result as serial execution,
+// without falling back to it, with and without producer supplied
+// dependencies.
+func TestParallelStateProcessor(t *testing.T) {
+	var (
+		key1, _ = crypto.GenerateKey()
+		key2, _ = crypto.GenerateKey()
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2   = crypto.PubkeyToAddress(key2.PublicKey)
+		addr3   = libcommon.HexToAddress("0x000000000000000000000000000000000000aaaa")
+		funds   = big.NewInt(1_000_000_000)
+		signer  = types.LatestSignerForChainID(nil)
+		engine  = ethash.NewFaker()
+		dirs    = datadir.New(t.TempDir())
+	)
+	config := *params.TestChainConfig
+	config.Bor = &chain.BorConfig{}
+	gspec := &types.Genesis{
+		Config: &config,
+		Alloc:  types.GenesisAlloc{addr1: {Balance: funds}, addr2: {Balance: funds}},
+	}
+	db := memdb.NewTestDB(t)
+	genesis := core.MustCommitGenesis(gspec, db, dirs.Tmp)
+
+	// SSTORE(0, 1)
+	initCode := []byte{0x60, 0x01, 0x60, 0x00, 0x55, 0x00}
+	chainPack, err := core.GenerateChain(&config, genesis, engine, db, 1, func(i int, b *core.BlockGen) {
+		for nonce := uint64(0); nonce < 4; nonce++ {
+			// addr1 funds addr2, which spends the received value right away
+			tx, err := types.SignTx(types.NewTransaction(nonce, addr2, u256.Num1, 21000, u256.Num1, nil), *signer, key1)
+			require.NoError(t, err)
+			b.AddTx(tx)
+			tx, err = types.SignTx(types.NewTransaction(nonce, addr3, u256.Num1, 21000, u256.Num1, nil), *signer, key2)
+			require.NoError(t, err)
+			b.AddTx(tx)
+		}
+		tx, err := types.SignTx(types.NewContractCreation(4, u256.Num0, 100000, u256.Num1, initCode), *signer, key1)
+		require.NoError(t, err)
+		b.AddTx(tx)
+	})
+	require.NoError(t, err)
+	block := chainPack.TopBlock
+
+	execute := func(block *types.Block, parallel bool) (*core.EphemeralExecResult, *calltracer.CallTracer, libcommon.Hash) {
+		tx, err := db.BeginRw(context.Background())
+		require.NoError(t, err)
+		defer tx.Rollback()
+
+		tracer := calltracer.NewCallTracer()
+		vmConfig := vm.Config{Debug: true, Tracer: tracer}
+		getHashFn := core.GetHashFn(block.Header(), func(hash libcommon.Hash, number uint64) *types.Header { return nil })
+		stateReader := state.NewPlainStateReader(tx)
+		stateWriter := state.NewPlainStateWriter(tx, tx, block.NumberU64())
+		chainReader := &core.FakeChainReader{Cfg: &config}
+
+		var execRs *core.EphemeralExecResult
+		if parallel {
+			processor := core.NewParallelStateProcessor(&config, engine, ethconfig.ParallelEVMConfig{Enable: true, SpeculativeProcesses: 4})
+			execRs, err = processor.Process(&vmConfig, getHashFn, block, stateReader, stateWriter, chainReader, nil)
+		} else {
+			execRs, err = core.ExecuteBlockEphemerally(&config, &vmConfig, getHashFn, engine, block, stateReader, stateWriter, chainReader, nil)
+		}
+		require.NoError(t, err)
+
+		root, err := core.CalcHashRootForTests(tx, block.Header(), false)
+		require.NoError(t, err)
+		return execRs, tracer, root
+	}
+
+	// the same block, with the producer declaring that each transaction
+	// depends on the previous one
+	txDependency := make([][]uint64, block.Transactions().Len())
+	for i := range txDependency {
+		txDependency[i] = []uint64{}
+		if i > 0 {
+			txDependency[i] = append(txDependency[i], uint64(i-1))
+		}
+	}
+	header := block.Header()
+	header.Extra, err = types.EncodeBlockExtraData(make([]byte, types.BorExtraVanityLength+types.BorExtraSealLength), &types.BlockExtraData{TxDependency: txDependency})
+	require.NoError(t, err)
+	blockWithDeps := block.WithSeal(header)
+	require.Equal(t, txDependency, blockWithDeps.GetTxDependency())
+
+	serialRs, serialTracer, serialRoot := execute(block, false)
+	require.Equal(t, block.Root(), serialRoot)
+
+	for _, b := range []*types.Block{block, blockWithDeps} {
+		fallbacks := metrics2.GetOrCreateCounter("chain_execution_parallel_fallbacks")
+		before := fallbacks.Get()
+		parallelRs, parallelTracer, parallelRoot := execute(b, true)
+		require.Equal(t, before, fallbacks.Get(), "parallel execution fell back to serial")
+
+		require.Equal(t, serialRoot, parallelRoot)
+		require.Equal(t, serialRs.ReceiptRoot, parallelRs.ReceiptRoot)
+		require.Equal(t, serialRs.Bloom, parallelRs.Bloom)
+		require.Equal(t, serialRs.GasUsed, parallelRs.GasUsed)
+		require.Equal(t, len(serialRs.Receipts), len(parallelRs.Receipts))
+		for i := range serialRs.Receipts {
+			require.Equal(t, serialRs.Receipts[i].CumulativeGasUsed, parallelRs.Receipts[i].CumulativeGasUsed, i)
+			require.Equal(t, serialRs.Receipts[i].ContractAddress, parallelRs.Receipts[i].ContractAddress, i)
+			require.Equal(t, len(serialRs.Receipts[i].Logs), len(parallelRs.Receipts[i].Logs), i)
+			for j, l := range serialRs.Receipts[i].Logs {
+				require.Equal(t, l.Index, parallelRs.Receipts[i].Logs[j].Index)
+				require.Equal(t, l.Address, parallelRs.Receipts[i].Logs[j].Address)
+				require.Equal(t, l.Topics, parallelRs.Receipts[i].Logs[j].Topics)
+				require.Equal(t, l.Data, parallelRs.Receipts[i].Logs[j].Data)
+			}
+		}
+		require.Equal(t, serialTracer, parallelTracer)
+	}
+}
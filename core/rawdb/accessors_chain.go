@@ -36,6 +36,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon-lib/kv/rawdbv3"
 	"github.com/ledgerwatch/log/v3"
+	"golang.org/x/exp/slices"
 
 	"github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/common/dbutils"
@@ -1314,6 +1315,92 @@ func WriteSnapshots(tx kv.RwTx, list, histList []string) error {
 	return nil
 }
 
+// BadBlocks is the table of recently rejected blocks: block_hash -> BadBlock (RLP), and BadBlocksKey -> their hashes.
+// kv.ChaindataTables of erigon-lib doesn't have it, so it's registered by init.
+const BadBlocks = "BadBlock"
+
+func init() {
+	kv.ChaindataTables = append(kv.ChaindataTables, BadBlocks)
+	slices.Sort(kv.ChaindataTables)
+	kv.ChaindataTablesCfg[BadBlocks] = kv.TableCfgItem{}
+}
+
+// BadBlocksKey is the key in BadBlocks of the hashes of recently rejected blocks, the most recent first
+var BadBlocksKey = []byte("BadBlocks")
+
+// BadBlocksLimit is the number of rejected blocks kept in the database
+const BadBlocksLimit = 10
+
+// BadBlock is a block rejected by the execution or by the payload validation, together with the rejection reason
+type BadBlock struct {
+	Block  *types.Block
+	Reason string
+}
+
+// ReadBadBlocks returns the rejected blocks, the most recently rejected first.
+func ReadBadBlocks(db kv.Getter) ([]*BadBlock, error) {
+	hashes, err := db.GetOne(BadBlocks, BadBlocksKey)
+	if err != nil {
+		return nil, err
+	}
+	badBlocks := make([]*BadBlock, 0, len(hashes)/length.Hash)
+	for i := 0; i+length.Hash <= len(hashes); i += length.Hash {
+		badBlock, err := ReadBadBlock(db, libcommon.BytesToHash(hashes[i:i+length.Hash]))
+		if err != nil {
+			return nil, err
+		}
+		if badBlock != nil {
+			badBlocks = append(badBlocks, badBlock)
+		}
+	}
+	return badBlocks, nil
+}
+
+// ReadBadBlock returns the rejected block with the given hash, or nil if there is no such block.
+func ReadBadBlock(db kv.Getter, hash libcommon.Hash) (*BadBlock, error) {
+	data, err := db.GetOne(BadBlocks, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	badBlock := &BadBlock{}
+	if err := rlp.DecodeBytes(data, badBlock); err != nil {
+		return nil, fmt.Errorf("decode bad block %x: %w", hash, err)
+	}
+	return badBlock, nil
+}
+
+// WriteBadBlock stores a rejected block with the rejection reason. Only the last BadBlocksLimit blocks are kept.
+func WriteBadBlock(tx kv.RwTx, block *types.Block, reason string) error {
+	hash := block.Hash()
+	if has, err := tx.Has(BadBlocks, hash[:]); err != nil || has {
+		return err
+	}
+	data, err := rlp.EncodeToBytes(&BadBlock{Block: block, Reason: reason})
+	if err != nil {
+		return fmt.Errorf("encode bad block: %w", err)
+	}
+	if err := tx.Put(BadBlocks, hash[:], data); err != nil {
+		return err
+	}
+	prevHashes, err := tx.GetOne(BadBlocks, BadBlocksKey)
+	if err != nil {
+		return err
+	}
+	hashes := append(libcommon.Copy(hash[:]), prevHashes...)
+	if len(hashes) > BadBlocksLimit*length.Hash {
+		for i := BadBlocksLimit * length.Hash; i+length.Hash <= len(hashes); i += length.Hash {
+			if err := tx.Delete(BadBlocks, hashes[i:i+length.Hash]); err != nil {
+				return err
+			}
+		}
+		hashes = hashes[:BadBlocksLimit*length.Hash]
+	}
+	return tx.Put(BadBlocks, BadBlocksKey, hashes)
+}
+
 // PruneTable has `limit` parameter to avoid too large data deletes per one sync cycle - better delete by small portions to reduce db.FreeList size
 func PruneTable(tx kv.RwTx, table string, pruneTo uint64, ctx context.Context, limit int) error {
 	c, err := tx.RwCursor(table)
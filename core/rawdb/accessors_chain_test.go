@@ -747,3 +747,42 @@ func checkReceiptsRLP(have, want types.Receipts) error {
 	}
 	return nil
 }
+
+// Tests that rejected blocks are stored with their reason and that only the most recent ones are kept.
+func TestBadBlockStorage(t *testing.T) {
+	_, tx := memdb.NewTestTx(t)
+	require := require.New(t)
+
+	badBlocks, err := rawdb.ReadBadBlocks(tx)
+	require.NoError(err)
+	require.Empty(badBlocks)
+
+	txn := types.NewTransaction(1, libcommon.HexToAddress("0x01"), u256.Num1, 21000, u256.Num1, nil)
+	blocks := make([]*types.Block, rawdb.BadBlocksLimit+2)
+	for i := range blocks {
+		header := &types.Header{Number: big.NewInt(int64(100 - i)), Extra: []byte("test bad block")}
+		blocks[i] = types.NewBlock(header, []types.Transaction{txn}, nil, nil, nil)
+		require.NoError(rawdb.WriteBadBlock(tx, blocks[i], fmt.Sprintf("reason %d", i)))
+	}
+	// Rejecting the same block again doesn't duplicate it
+	require.NoError(rawdb.WriteBadBlock(tx, blocks[len(blocks)-1], "again"))
+
+	badBlocks, err = rawdb.ReadBadBlocks(tx)
+	require.NoError(err)
+	require.Len(badBlocks, rawdb.BadBlocksLimit)
+	for i, badBlock := range badBlocks {
+		j := len(blocks) - 1 - i
+		require.Equal(blocks[j].Hash(), badBlock.Block.Hash())
+		require.Equal(fmt.Sprintf("reason %d", j), badBlock.Reason)
+		require.Len(badBlock.Block.Transactions(), 1)
+		require.Equal(txn.Hash(), badBlock.Block.Transactions()[0].Hash())
+	}
+
+	badBlock, err := rawdb.ReadBadBlock(tx, blocks[0].Hash())
+	require.NoError(err)
+	require.Nil(badBlock)
+	badBlock, err = rawdb.ReadBadBlock(tx, blocks[5].Hash())
+	require.NoError(err)
+	require.NotNil(badBlock)
+	require.Equal("reason 5", badBlock.Reason)
+}
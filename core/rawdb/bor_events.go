@@ -0,0 +1,136 @@
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/kv"
+)
+
+// The state sync events committed by Bor blocks and the Heimdall spans are
+// kept in kv.BorSeparate of the Bor database, next to the Bor snapshots,
+// until they are frozen into the borevents and borspans segments.
+var (
+	borEventsPrefix = []byte("borevents-") // + block_num_u64 + block_hash -> json([]clerk.EventRecordWithTime)
+	borSpanPrefix   = []byte("borspan-")   // + span_id_u64 -> json(span.HeimdallSpan)
+)
+
+func borEventsKey(number uint64, hash libcommon.Hash) []byte {
+	k := make([]byte, len(borEventsPrefix)+8+length.Hash)
+	copy(k, borEventsPrefix)
+	binary.BigEndian.PutUint64(k[len(borEventsPrefix):], number)
+	copy(k[len(borEventsPrefix)+8:], hash[:])
+	return k
+}
+
+func borSpanKey(spanID uint64) []byte {
+	k := make([]byte, len(borSpanPrefix)+8)
+	copy(k, borSpanPrefix)
+	binary.BigEndian.PutUint64(k[len(borSpanPrefix):], spanID)
+	return k
+}
+
+// ReadBorEvents returns the state sync events committed by a block, nil if
+// they are not stored. A block which committed no event has an empty list.
+func ReadBorEvents(db kv.Getter, hash libcommon.Hash, number uint64) ([]byte, error) {
+	return db.GetOne(kv.BorSeparate, borEventsKey(number, hash))
+}
+
+// WriteBorEvents stores the state sync events committed by a block. Events
+// are stored by block hash as well, as their time range depends on the
+// header of the block.
+func WriteBorEvents(db kv.Putter, hash libcommon.Hash, number uint64, events []byte) error {
+	return db.Put(kv.BorSeparate, borEventsKey(number, hash), events)
+}
+
+// ForEachBorEvents walks the stored state sync events of the blocks in
+// [from, to), in block order.
+func ForEachBorEvents(tx kv.Tx, from, to uint64, walker func(number uint64, hash libcommon.Hash, events []byte) error) error {
+	c, err := tx.Cursor(kv.BorSeparate)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, v, err := c.Seek(borEventsKey(from, libcommon.Hash{})); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		if !bytes.HasPrefix(k, borEventsPrefix) {
+			break
+		}
+		number := binary.BigEndian.Uint64(k[len(borEventsPrefix):])
+		if number >= to {
+			break
+		}
+		if err := walker(number, libcommon.BytesToHash(k[len(borEventsPrefix)+8:]), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadBorSpan returns a stored Heimdall span, nil if it is not stored.
+func ReadBorSpan(db kv.Getter, spanID uint64) ([]byte, error) {
+	return db.GetOne(kv.BorSeparate, borSpanKey(spanID))
+}
+
+// WriteBorSpan stores a Heimdall span.
+func WriteBorSpan(db kv.Putter, spanID uint64, span []byte) error {
+	return db.Put(kv.BorSeparate, borSpanKey(spanID), span)
+}
+
+// ForEachBorSpan walks the stored Heimdall spans with IDs in [from, to).
+func ForEachBorSpan(tx kv.Tx, from, to uint64, walker func(spanID uint64, span []byte) error) error {
+	c, err := tx.Cursor(kv.BorSeparate)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, v, err := c.Seek(borSpanKey(from)); k != nil; k, v, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		if !bytes.HasPrefix(k, borSpanPrefix) {
+			break
+		}
+		spanID := binary.BigEndian.Uint64(k[len(borSpanPrefix):])
+		if spanID >= to {
+			break
+		}
+		if err := walker(spanID, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PruneBorBlocks deletes the stored state sync events of the blocks below
+// blockTo and the spans below spanTo, once they are frozen.
+func PruneBorBlocks(tx kv.RwTx, blockTo, spanTo uint64) error {
+	if err := pruneBorSeparate(tx, borEventsPrefix, blockTo); err != nil {
+		return err
+	}
+	return pruneBorSeparate(tx, borSpanPrefix, spanTo)
+}
+
+func pruneBorSeparate(tx kv.RwTx, prefix []byte, to uint64) error {
+	c, err := tx.RwCursor(kv.BorSeparate)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	for k, _, err := c.Seek(prefix); k != nil; k, _, err = c.Next() {
+		if err != nil {
+			return err
+		}
+		if !bytes.HasPrefix(k, prefix) || binary.BigEndian.Uint64(k[len(prefix):]) >= to {
+			break
+		}
+		if err := c.DeleteCurrent(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
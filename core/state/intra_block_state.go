@@ -499,6 +499,16 @@ func (sdb *IntraBlockState) TxIndex() int {
 	return sdb.txIndex
 }
 
+// TxHash returns the current transaction hash set by SetTxContext.
+func (sdb *IntraBlockState) TxHash() libcommon.Hash {
+	return sdb.thash
+}
+
+// BlockHash returns the current block hash set by SetTxContext.
+func (sdb *IntraBlockState) BlockHash() libcommon.Hash {
+	return sdb.bhash
+}
+
 // DESCRIBED: docs/programmers_guide/guide.md#address---identifier-of-an-account
 func (sdb *IntraBlockState) GetCode(addr libcommon.Address) []byte {
 	return MVRead(sdb, blockstm.NewSubpathKey(addr, CodePath), nil, func(s *IntraBlockState) []byte {
@@ -0,0 +1,159 @@
+package state
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/holiman/uint256"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/turbo/trie"
+)
+
+// Stateless is the state reader and writer of a block executed without the state, only from its witness.
+// It reads the accounts, storage and code from the trie built from the witness and fails on anything which is
+// not part of it. The writes are buffered and applied to the trie by Finalize, which returns the new state root.
+type Stateless struct {
+	t              *trie.Trie
+	codes          map[libcommon.Hash][]byte
+	deleted        map[libcommon.Hash]struct{}
+	accountUpdates map[libcommon.Hash]*accounts.Account
+	storageUpdates map[libcommon.Hash]map[libcommon.Hash][]byte
+}
+
+// NewStateless builds the trie from the witness and checks that its root is the given state root
+func NewStateless(stateRoot libcommon.Hash, witness *trie.Witness) (*Stateless, error) {
+	t, err := trie.BuildTrieFromWitness(witness, false /* trace */)
+	if err != nil {
+		return nil, err
+	}
+	if root := t.Hash(); root != stateRoot {
+		return nil, fmt.Errorf("witness has state root %x, expected %x", root, stateRoot)
+	}
+	return &Stateless{
+		t:              t,
+		codes:          map[libcommon.Hash][]byte{},
+		deleted:        map[libcommon.Hash]struct{}{},
+		accountUpdates: map[libcommon.Hash]*accounts.Account{},
+		storageUpdates: map[libcommon.Hash]map[libcommon.Hash][]byte{},
+	}, nil
+}
+
+func (s *Stateless) ReadAccountData(address libcommon.Address) (*accounts.Account, error) {
+	addrHash := crypto.Keccak256(address[:])
+	acc, ok := s.t.GetAccount(addrHash)
+	if !ok {
+		return nil, fmt.Errorf("account %x is not in the witness", address)
+	}
+	return acc, nil
+}
+
+func (s *Stateless) ReadAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash) ([]byte, error) {
+	storageKey := append(crypto.Keccak256(address[:]), crypto.Keccak256(key[:])...)
+	v, ok := s.t.Get(storageKey)
+	if !ok {
+		return nil, fmt.Errorf("storage item %x of account %x is not in the witness", *key, address)
+	}
+	return v, nil
+}
+
+func (s *Stateless) ReadAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) ([]byte, error) {
+	if bytes.Equal(codeHash[:], emptyCodeHash) {
+		return nil, nil
+	}
+	if code, ok := s.codes[codeHash]; ok {
+		return code, nil
+	}
+	code, ok := s.t.GetAccountCode(crypto.Keccak256(address[:]))
+	if !ok || code == nil {
+		return nil, fmt.Errorf("code of account %x is not in the witness", address)
+	}
+	return code, nil
+}
+
+func (s *Stateless) ReadAccountCodeSize(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (int, error) {
+	code, err := s.ReadAccountCode(address, incarnation, codeHash)
+	return len(code), err
+}
+
+// ReadAccountIncarnation always returns 0, because incarnations are not part of the trie
+func (s *Stateless) ReadAccountIncarnation(address libcommon.Address) (uint64, error) {
+	return 0, nil
+}
+
+func (s *Stateless) UpdateAccountData(address libcommon.Address, original, account *accounts.Account) error {
+	addrHash := libcommon.BytesToHash(crypto.Keccak256(address[:]))
+	var acc accounts.Account
+	acc.Copy(account)
+	s.accountUpdates[addrHash] = &acc
+	return nil
+}
+
+func (s *Stateless) UpdateAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash, code []byte) error {
+	s.codes[codeHash] = code
+	return nil
+}
+
+func (s *Stateless) DeleteAccount(address libcommon.Address, original *accounts.Account) error {
+	addrHash := libcommon.BytesToHash(crypto.Keccak256(address[:]))
+	s.deleted[addrHash] = struct{}{}
+	delete(s.accountUpdates, addrHash)
+	delete(s.storageUpdates, addrHash)
+	return nil
+}
+
+func (s *Stateless) WriteAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash, original, value *uint256.Int) error {
+	addrHash := libcommon.BytesToHash(crypto.Keccak256(address[:]))
+	m, ok := s.storageUpdates[addrHash]
+	if !ok {
+		m = map[libcommon.Hash][]byte{}
+		s.storageUpdates[addrHash] = m
+	}
+	m[libcommon.BytesToHash(crypto.Keccak256(key[:]))] = value.Bytes()
+	return nil
+}
+
+// CreateContract clears the storage of the account, which is then recreated with the new account
+func (s *Stateless) CreateContract(address libcommon.Address) error {
+	addrHash := libcommon.BytesToHash(crypto.Keccak256(address[:]))
+	s.deleted[addrHash] = struct{}{}
+	delete(s.storageUpdates, addrHash)
+	return nil
+}
+
+func (s *Stateless) WriteChangeSets() error {
+	return nil
+}
+
+func (s *Stateless) WriteHistory() error {
+	return nil
+}
+
+// Finalize applies the buffered writes to the trie and returns the resulting state root
+func (s *Stateless) Finalize() libcommon.Hash {
+	for addrHash := range s.deleted {
+		s.t.Delete(addrHash[:])
+	}
+	for addrHash, acc := range s.accountUpdates {
+		if _, ok := s.deleted[addrHash]; ok {
+			acc.Root = trie.EmptyRoot
+		}
+		s.t.UpdateAccount(addrHash[:], acc)
+	}
+	for addrHash, m := range s.storageUpdates {
+		for keyHash, v := range m {
+			storageKey := append(libcommon.Copy(addrHash[:]), keyHash[:]...)
+			if len(v) == 0 {
+				s.t.Delete(storageKey)
+			} else {
+				s.t.Update(storageKey, v)
+			}
+		}
+	}
+	s.deleted = map[libcommon.Hash]struct{}{}
+	s.accountUpdates = map[libcommon.Hash]*accounts.Account{}
+	s.storageUpdates = map[libcommon.Hash]map[libcommon.Hash][]byte{}
+	return s.t.Hash()
+}
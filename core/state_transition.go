@@ -210,6 +210,17 @@ func (st *StateTransition) to() libcommon.Address {
 	return *st.msg.To()
 }
 
+// dataGasPrice returns the price of a unit of data gas in the current block.
+func (st *StateTransition) dataGasPrice() (*uint256.Int, error) {
+	if st.evm.Context().DataGasPrice != nil {
+		return st.evm.Context().DataGasPrice, nil
+	}
+	if st.evm.Context().ExcessDataGas == nil {
+		return nil, fmt.Errorf("%w: Cancun is active but ExcessDataGas is nil", ErrInternalFailure)
+	}
+	return misc.GetDataGasPrice(*st.evm.Context().ExcessDataGas)
+}
+
 func (st *StateTransition) buyGas(gasBailout bool) error {
 	mgval := st.sharedBuyGas
 	mgval.SetUint64(st.msg.Gas())
@@ -221,10 +232,7 @@ func (st *StateTransition) buyGas(gasBailout bool) error {
 	// compute data fee for eip-4844 data blobs if any
 	dgval := new(uint256.Int)
 	if st.evm.ChainRules().IsCancun {
-		if st.evm.Context().ExcessDataGas == nil {
-			return fmt.Errorf("%w: Cancun is active but ExcessDataGas is nil", ErrInternalFailure)
-		}
-		dataGasPrice, err := misc.GetDataGasPrice(*st.evm.Context().ExcessDataGas)
+		dataGasPrice, err := st.dataGasPrice()
 		if err != nil {
 			return err
 		}
@@ -324,10 +332,7 @@ func (st *StateTransition) preCheck(gasBailout bool) error {
 		}
 	}
 	if st.msg.DataGas() > 0 && st.evm.ChainRules().IsCancun {
-		if st.evm.Context().ExcessDataGas == nil {
-			return fmt.Errorf("%w: Cancun is active but ExcessDataGas is nil", ErrInternalFailure)
-		}
-		dataGasPrice, err := misc.GetDataGasPrice(*st.evm.Context().ExcessDataGas)
+		dataGasPrice, err := st.dataGasPrice()
 		if err != nil {
 			return err
 		}
@@ -420,7 +425,7 @@ func (st *StateTransition) TransitionDb(refunds bool, gasBailout bool) (*Executi
 	// Execute the preparatory steps for state transition which includes:
 	// - prepare accessList(post-berlin)
 	// - reset transient storage(eip 1153)
-	st.state.Prepare(rules, msg.From(), coinbase, msg.To(), vm.ActivePrecompiles(rules), msg.AccessList())
+	st.state.Prepare(rules, msg.From(), coinbase, msg.To(), st.evm.ActivePrecompiles(), msg.AccessList())
 
 	var (
 		ret   []byte
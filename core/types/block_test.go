@@ -486,3 +486,28 @@ func TestBlockRawBodyPostShanghaiWithdrawals(t *testing.T) {
 	require.Equal(0, len(body.Transactions))
 	require.Equal(2, len(body.Withdrawals))
 }
+
+func TestBorBlockExtraData(t *testing.T) {
+	vanity := bytes.Repeat([]byte{0x01}, BorExtraVanityLength)
+	seal := bytes.Repeat([]byte{0x02}, BorExtraSealLength)
+	validatorBytes := bytes.Repeat([]byte{0xc3}, 40)
+
+	extra, err := EncodeBlockExtraData(append(append([]byte{}, vanity...), seal...), &BlockExtraData{
+		ValidatorBytes: validatorBytes,
+		TxDependency:   [][]uint64{{}, {0}, {0, 1}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, vanity, extra[:BorExtraVanityLength])
+	assert.Equal(t, seal, extra[len(extra)-BorExtraSealLength:])
+
+	decoded, err := DecodeBlockExtraData(extra)
+	require.NoError(t, err)
+	assert.Equal(t, validatorBytes, decoded.ValidatorBytes)
+
+	block := NewBlockWithHeader(&Header{Number: big.NewInt(1), Extra: extra})
+	assert.Equal(t, [][]uint64{{}, {0}, {0, 1}}, block.GetTxDependency())
+
+	// pre-fork headers carry the raw validator bytes
+	block = NewBlockWithHeader(&Header{Number: big.NewInt(1), Extra: append(append(append([]byte{}, vanity...), validatorBytes...), seal...)})
+	assert.Nil(t, block.GetTxDependency())
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+const (
+	BorExtraVanityLength = 32 // Fixed number of extra-data prefix bytes reserved for signer vanity
+	BorExtraSealLength   = 65 // Fixed number of extra-data suffix bytes reserved for signer seal
+)
+
+// BlockExtraData is the payload of a Bor header extra field between the
+// vanity and the seal, once the parallel universe fork is active. Before the
+// fork the payload is made of the raw validator bytes only.
+type BlockExtraData struct {
+	// Validator bytes of the next span, set at the end of a sprint only
+	ValidatorBytes []byte
+
+	// Block-STM dependencies of the block transactions: TxDependency[i]
+	// holds the indices of the earlier transactions which transaction i
+	// depends on. Empty when the producer did not provide any metadata.
+	TxDependency [][]uint64
+}
+
+// DecodeBlockExtraData decodes the RLP payload of a Bor header extra field.
+func DecodeBlockExtraData(extra []byte) (*BlockExtraData, error) {
+	if len(extra) < BorExtraVanityLength+BorExtraSealLength {
+		return nil, fmt.Errorf("bor extra data too short: %d", len(extra))
+	}
+	var data BlockExtraData
+	if err := rlp.DecodeBytes(extra[BorExtraVanityLength:len(extra)-BorExtraSealLength], &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// EncodeBlockExtraData returns a copy of the Bor header extra field with its
+// payload replaced by the RLP encoding of data. The vanity and the seal are
+// kept untouched.
+func EncodeBlockExtraData(extra []byte, data *BlockExtraData) ([]byte, error) {
+	if len(extra) < BorExtraVanityLength+BorExtraSealLength {
+		return nil, fmt.Errorf("bor extra data too short: %d", len(extra))
+	}
+	payload, err := rlp.EncodeToBytes(data)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]byte, 0, BorExtraVanityLength+len(payload)+BorExtraSealLength)
+	res = append(res, extra[:BorExtraVanityLength]...)
+	res = append(res, payload...)
+	res = append(res, extra[len(extra)-BorExtraSealLength:]...)
+	return res, nil
+}
+
+// GetTxDependency returns the Block-STM dependencies encoded by the producer
+// of a Bor block, or nil if the header does not carry any.
+func (b *Block) GetTxDependency() [][]uint64 {
+	data, err := DecodeBlockExtraData(b.header.Extra)
+	if err != nil {
+		return nil
+	}
+	return data.TxDependency
+}
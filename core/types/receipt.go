@@ -466,39 +466,56 @@ func (r Receipts) DeriveFields(hash libcommon.Hash, number uint64, txs Transacti
 		return fmt.Errorf("transaction and senders count mismatch, tx count = %d, senders count = %d", len(txs), len(senders))
 	}
 
-	blockNumber := new(big.Int).SetUint64(number)
 	for i := 0; i < len(r); i++ {
-		// The transaction type and hash can be retrieved from the transaction itself
-		r[i].Type = txs[i].Type()
-		r[i].TxHash = txs[i].Hash()
-
-		// block location fields
-		r[i].BlockHash = hash
-		r[i].BlockNumber = blockNumber
-		r[i].TransactionIndex = uint(i)
-
-		// The contract address can be derived from the transaction itself
-		if txs[i].GetTo() == nil {
-			// If one wants to deploy a contract, one needs to send a transaction that does not have `To` field
-			// and then the address of the contract one is creating this way will depend on the `tx.From`
-			// and the nonce of the creating account (which is `tx.From`).
-			r[i].ContractAddress = crypto.CreateAddress(senders[i], txs[i].GetNonce())
-		}
-		// The used gas can be calculated based on previous r
-		if i == 0 {
-			r[i].GasUsed = r[i].CumulativeGasUsed
-		} else {
-			r[i].GasUsed = r[i].CumulativeGasUsed - r[i-1].CumulativeGasUsed
-		}
-		// The derived log fields can simply be set from the block and transaction
-		for j := 0; j < len(r[i].Logs); j++ {
-			r[i].Logs[j].BlockNumber = number
-			r[i].Logs[j].BlockHash = hash
-			r[i].Logs[j].TxHash = r[i].TxHash
-			r[i].Logs[j].TxIndex = uint(i)
-			r[i].Logs[j].Index = logIndex
-			logIndex++
-		}
+		logIndex = r.deriveFields(i, hash, number, txs[i], senders[i], logIndex)
 	}
 	return nil
 }
+
+// DeriveFieldsAt is DeriveFields for the i-th receipt alone, it needs only its
+// transaction and sender. The preceding receipts give the used gas and the log
+// indices.
+func (r Receipts) DeriveFieldsAt(i int, hash libcommon.Hash, number uint64, txn Transaction, sender libcommon.Address) {
+	logIndex := uint(0)
+	for j := 0; j < i; j++ {
+		logIndex += uint(len(r[j].Logs))
+	}
+	r.deriveFields(i, hash, number, txn, sender, logIndex)
+}
+
+// deriveFields fills the i-th receipt, whose first log is at logIndex in the
+// block, and returns the index of the log following its last one.
+func (r Receipts) deriveFields(i int, hash libcommon.Hash, number uint64, txn Transaction, sender libcommon.Address, logIndex uint) uint {
+	// The transaction type and hash can be retrieved from the transaction itself
+	r[i].Type = txn.Type()
+	r[i].TxHash = txn.Hash()
+
+	// block location fields
+	r[i].BlockHash = hash
+	r[i].BlockNumber = new(big.Int).SetUint64(number)
+	r[i].TransactionIndex = uint(i)
+
+	// The contract address can be derived from the transaction itself
+	if txn.GetTo() == nil {
+		// If one wants to deploy a contract, one needs to send a transaction that does not have `To` field
+		// and then the address of the contract one is creating this way will depend on the `tx.From`
+		// and the nonce of the creating account (which is `tx.From`).
+		r[i].ContractAddress = crypto.CreateAddress(sender, txn.GetNonce())
+	}
+	// The used gas can be calculated based on previous r
+	if i == 0 {
+		r[i].GasUsed = r[i].CumulativeGasUsed
+	} else {
+		r[i].GasUsed = r[i].CumulativeGasUsed - r[i-1].CumulativeGasUsed
+	}
+	// The derived log fields can simply be set from the block and transaction
+	for j := 0; j < len(r[i].Logs); j++ {
+		r[i].Logs[j].BlockNumber = number
+		r[i].Logs[j].BlockHash = hash
+		r[i].Logs[j].TxHash = r[i].TxHash
+		r[i].Logs[j].TxIndex = uint(i)
+		r[i].Logs[j].Index = logIndex
+		logIndex++
+	}
+	return logIndex
+}
@@ -257,6 +257,14 @@ func TestDeriveFields(t *testing.T) {
 			logIndex++
 		}
 	}
+
+	// Deriving the last receipt alone gives the same fields
+	want := receipts[2].Copy()
+	clearComputedFieldsOnReceipt(t, receipts[2])
+	receipts.DeriveFieldsAt(2, hash, number.Uint64(), txs[2], libcommon.BytesToAddress([]byte{0x0}))
+	if got := receipts[2].Copy(); !reflect.DeepEqual(got, want) {
+		t.Errorf("DeriveFieldsAt(2, ...) = %+v, want %+v", got, want)
+	}
 }
 
 // TestTypedReceiptEncodingDecoding reproduces a flaw that existed in the receipt
@@ -167,6 +167,34 @@ func ActivePrecompiles(rules *chain.Rules) []libcommon.Address {
 	}
 }
 
+// precompiledContracts returns the precompiled contracts enabled with the current configuration.
+// The map is shared, it must not be modified.
+func precompiledContracts(rules *chain.Rules) map[libcommon.Address]PrecompiledContract {
+	switch {
+	case rules.IsCancun:
+		return PrecompiledContractsCancun
+	case rules.IsBerlin:
+		return PrecompiledContractsBerlin
+	case rules.IsIstanbul:
+		return PrecompiledContractsIstanbul
+	case rules.IsByzantium:
+		return PrecompiledContractsByzantium
+	default:
+		return PrecompiledContractsHomestead
+	}
+}
+
+// ActivePrecompiledContracts returns a copy of the precompiled contracts enabled with the current configuration.
+// The copy may be modified freely, e.g. to relocate precompiles for call simulation.
+func ActivePrecompiledContracts(rules *chain.Rules) map[libcommon.Address]PrecompiledContract {
+	precompiles := precompiledContracts(rules)
+	res := make(map[libcommon.Address]PrecompiledContract, len(precompiles))
+	for addr, p := range precompiles {
+		res[addr] = p
+	}
+	return res
+}
+
 // RunPrecompiledContract runs and evaluates the output of a precompiled contract.
 // It returns
 // - the returned bytes,
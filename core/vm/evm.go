@@ -35,20 +35,11 @@ import (
 var emptyCodeHash = crypto.Keccak256Hash(nil)
 
 func (evm *EVM) precompile(addr libcommon.Address) (PrecompiledContract, bool) {
-	var precompiles map[libcommon.Address]PrecompiledContract
-	switch {
-	case evm.chainRules.IsCancun:
-		precompiles = PrecompiledContractsCancun
-	case evm.chainRules.IsBerlin:
-		precompiles = PrecompiledContractsBerlin
-	case evm.chainRules.IsIstanbul:
-		precompiles = PrecompiledContractsIstanbul
-	case evm.chainRules.IsByzantium:
-		precompiles = PrecompiledContractsByzantium
-	default:
-		precompiles = PrecompiledContractsHomestead
+	if evm.precompiles != nil {
+		p, ok := evm.precompiles[addr]
+		return p, ok
 	}
-	p, ok := precompiles[addr]
+	p, ok := precompiledContracts(evm.chainRules)[addr]
 	return p, ok
 }
 
@@ -90,6 +81,8 @@ type EVM struct {
 	// available gas is calculated in gasCall* according to the 63/64 rule and later
 	// applied in opCall*.
 	callGasTemp uint64
+	// precompiles, if set, replaces the precompiled contracts implied by chainRules
+	precompiles map[libcommon.Address]PrecompiledContract
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -471,6 +464,24 @@ func (evm *EVM) ChainConfig() *chain.Config {
 	return evm.chainConfig
 }
 
+// SetPrecompiles overrides the set of precompiled contracts available to the EVM.
+// It is meant for call simulation only, where precompiles may be moved to other addresses.
+func (evm *EVM) SetPrecompiles(precompiles map[libcommon.Address]PrecompiledContract) {
+	evm.precompiles = precompiles
+}
+
+// ActivePrecompiles returns the addresses of the precompiled contracts available to the EVM
+func (evm *EVM) ActivePrecompiles() []libcommon.Address {
+	if evm.precompiles == nil {
+		return ActivePrecompiles(evm.chainRules)
+	}
+	addrs := make([]libcommon.Address, 0, len(evm.precompiles))
+	for addr := range evm.precompiles {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
 // ChainRules returns the environment's chain rules
 func (evm *EVM) ChainRules() *chain.Rules {
 	return evm.chainRules
@@ -33,6 +33,7 @@ type BlockContext struct {
 	BaseFee       *uint256.Int   // Provides information for BASEFEE
 	PrevRanDao    *common.Hash   // Provides information for PREVRANDAO
 	ExcessDataGas *uint64        // Provides information for handling data blobs
+	DataGasPrice  *uint256.Int   // Overrides the data gas price derived from ExcessDataGas (used by RPC block overrides)
 }
 
 // TxContext provides the EVM with information about a transaction.
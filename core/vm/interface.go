@@ -48,6 +48,7 @@ type VMInterface interface {
 	Config() Config
 	ChainConfig() *chain.Config
 	ChainRules() *chain.Rules
+	ActivePrecompiles() []libcommon.Address
 	Context() evmtypes.BlockContext
 	IntraBlockState() evmtypes.IntraBlockState
 	TxContext() evmtypes.TxContext
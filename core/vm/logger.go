@@ -49,3 +49,12 @@ type FlushableTracer interface {
 	EVMLogger
 	Flush(tx types.Transaction)
 }
+
+// ForkableTracer is a Tracer extension which can be split into independent
+// per-transaction tracers, e.g. for parallel block execution. The forks are
+// joined back in transaction order once execution is settled.
+type ForkableTracer interface {
+	EVMLogger
+	Fork() EVMLogger
+	Join(fork EVMLogger)
+}
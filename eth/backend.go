@@ -68,9 +68,12 @@ import (
 	"github.com/ledgerwatch/erigon-lib/txpool/txpooluitl"
 	types2 "github.com/ledgerwatch/erigon-lib/types"
 
+	"github.com/ledgerwatch/erigon/cl/beacon"
 	"github.com/ledgerwatch/erigon/cl/clparams"
 	"github.com/ledgerwatch/erigon/cl/cltypes"
 	"github.com/ledgerwatch/erigon/cl/fork"
+	"github.com/ledgerwatch/erigon/cl/freezer"
+	"github.com/ledgerwatch/erigon/cl/phase1/light_client"
 	"github.com/ledgerwatch/erigon/cmd/caplin-phase1/caplin1"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/cli"
 	"github.com/ledgerwatch/erigon/cmd/rpcdaemon/commands"
@@ -80,6 +83,8 @@ import (
 	"github.com/ledgerwatch/erigon/common/debug"
 	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/consensus/bor"
+	"github.com/ledgerwatch/erigon/consensus/bor/finality"
+	"github.com/ledgerwatch/erigon/consensus/bor/finality/whitelist"
 	"github.com/ledgerwatch/erigon/consensus/clique"
 	"github.com/ledgerwatch/erigon/consensus/ethash"
 	"github.com/ledgerwatch/erigon/consensus/merge"
@@ -101,6 +106,7 @@ import (
 	"github.com/ledgerwatch/erigon/node"
 	"github.com/ledgerwatch/erigon/p2p"
 	"github.com/ledgerwatch/erigon/p2p/enode"
+	"github.com/ledgerwatch/erigon/p2p/peeradmin"
 	"github.com/ledgerwatch/erigon/params"
 	"github.com/ledgerwatch/erigon/rpc"
 	"github.com/ledgerwatch/erigon/turbo/engineapi"
@@ -147,6 +153,7 @@ type Ethereum struct {
 	sentryCancel   context.CancelFunc
 	sentriesClient *sentry.MultiClient
 	sentryServers  []*sentry.GrpcServer
+	peerAdmin      *sentry.MultiPeerAdmin
 
 	stagedSync      *stagedsync.Sync
 	syncStages      []*stagedsync.Stage
@@ -169,6 +176,7 @@ type Ethereum struct {
 	txPoolGrpcServer        txpool_proto.TxpoolServer
 	notifyMiningAboutNewTxs chan struct{}
 	forkValidator           *engineapi.ForkValidator
+	whitelist               *whitelist.Service // Heimdall finality of Bor blocks
 	downloader              *downloader3.Downloader
 
 	agg            *libstate.AggregatorV3
@@ -314,13 +322,15 @@ func New(stack *node.Node, config *ethconfig.Config, logger log.Logger) (*Ethere
 	backend.gasPrice, _ = uint256.FromBig(config.Miner.GasPrice)
 
 	var sentries []direct.SentryClient
+	var peerAdmins []peeradmin.PeerAdminClient
 	if len(stack.Config().P2P.SentryAddr) > 0 {
 		for _, addr := range stack.Config().P2P.SentryAddr {
-			sentryClient, err := sentry.GrpcClient(backend.sentryCtx, addr)
+			sentryClient, peerAdminClient, err := sentry.GrpcClient(backend.sentryCtx, addr)
 			if err != nil {
 				return nil, err
 			}
 			sentries = append(sentries, sentryClient)
+			peerAdmins = append(peerAdmins, peerAdminClient)
 		}
 	} else {
 		var readNodeInfo = func() *eth.NodeInfo {
@@ -382,6 +392,7 @@ func New(stack *node.Node, config *ethconfig.Config, logger log.Logger) (*Ethere
 			server := sentry.NewGrpcServer(backend.sentryCtx, discovery, readNodeInfo, &cfg, protocol, logger)
 			backend.sentryServers = append(backend.sentryServers, server)
 			sentries = append(sentries, direct.NewSentryClientDirect(protocol, server))
+			peerAdmins = append(peerAdmins, peeradmin.NewPeerAdminClientDirect(sentry.NewPeerAdminServer(server)))
 		}
 
 		go func() {
@@ -460,9 +471,16 @@ func New(stack *node.Node, config *ethconfig.Config, logger log.Logger) (*Ethere
 		consensusConfig = &config.Ethash
 	}
 	backend.engine = ethconsensusconfig.CreateConsensusEngine(chainConfig, consensusConfig, config.Miner.Notify, config.Miner.Noverify, config.HeimdallgRPCAddress, config.HeimdallURL,
-		config.WithoutHeimdall, stack.DataDir(), false /* readonly */, logger)
+		config.WithoutHeimdall, config.HeimdallCacheMode, stack.DataDir(), false /* readonly */, logger)
+	if casted, ok := backend.engine.(*bor.Bor); ok {
+		casted.SetBorReader(blockReader)
+		if casted.HeimdallClient != nil {
+			backend.whitelist = whitelist.NewService()
+		}
+	}
 	backend.forkValidator = engineapi.NewForkValidator(currentBlockNumber, inMemoryExecution, tmpdir, backend.blockReader)
 
+	backend.peerAdmin = sentry.NewMultiPeerAdmin(peerAdmins)
 	backend.sentriesClient, err = sentry.NewMultiClient(
 		chainKv,
 		stack.Config().NodeName(),
@@ -564,6 +582,7 @@ func New(stack *node.Node, config *ethconfig.Config, logger log.Logger) (*Ethere
 			ethBackendRPC,
 			backend.txPoolGrpcServer,
 			miningRPC,
+			backend.peerAdmin,
 			stack.Config().PrivateApiAddr,
 			stack.Config().PrivateApiRateLimit,
 			creds,
@@ -580,8 +599,16 @@ func New(stack *node.Node, config *ethconfig.Config, logger log.Logger) (*Ethere
 		if err != nil {
 			return nil, err
 		}
-		state, err := clcore.RetrieveBeaconState(ctx, beaconCfg, genesisCfg,
-			clparams.GetCheckpointSyncEndpoint(clparams.NetworkType(config.NetworkID)))
+		checkpointSync := clcore.CheckpointSyncConfig{Uris: config.CheckpointSyncUrls, File: config.CheckpointSyncFile}
+		if len(checkpointSync.Uris) == 0 {
+			checkpointSync.Uris = []string{clparams.GetCheckpointSyncEndpoint(clparams.NetworkType(config.NetworkID))}
+		}
+		if config.WeakSubjectivityCheckpoint != "" {
+			if checkpointSync.WeakSubjectivityCheckpoint, err = clcore.ParseWeakSubjectivityCheckpoint(config.WeakSubjectivityCheckpoint); err != nil {
+				return nil, err
+			}
+		}
+		state, err := clcore.RetrieveVerifiedBeaconState(ctx, beaconCfg, genesisCfg, checkpointSync)
 		if err != nil {
 			return nil, err
 		}
@@ -590,6 +617,12 @@ func New(stack *node.Node, config *ethconfig.Config, logger log.Logger) (*Ethere
 			return nil, err
 		}
 
+		caplinFreezer := &freezer.RootPathOsFs{Root: filepath.Join(dirs.DataDir, "caplin")}
+		blobs := freezer.NewBlobSidecarStore(caplinFreezer)
+		lightClient, err := light_client.NewStore(beaconCfg, caplinFreezer)
+		if err != nil {
+			return nil, err
+		}
 		client, err := service.StartSentinelService(&sentinel.SentinelConfig{
 			IpAddr:        config.LightClientDiscoveryAddr,
 			Port:          int(config.LightClientDiscoveryPort),
@@ -598,6 +631,8 @@ func New(stack *node.Node, config *ethconfig.Config, logger log.Logger) (*Ethere
 			NetworkConfig: networkCfg,
 			BeaconConfig:  beaconCfg,
 			TmpDir:        tmpdir,
+			BlobSidecars:  blobs,
+			LightClient:   lightClient,
 		}, chainKv, &service.ServerConfig{Network: "tcp", Addr: fmt.Sprintf("%s:%d", config.SentinelAddr, config.SentinelPort)}, creds, &cltypes.Status{
 			ForkDigest:     forkDigest,
 			FinalizedRoot:  state.FinalizedCheckpoint().BlockRoot(),
@@ -614,7 +649,18 @@ func New(stack *node.Node, config *ethconfig.Config, logger log.Logger) (*Ethere
 			return nil, err
 		}
 
-		go caplin1.RunCaplinPhase1(ctx, client, beaconCfg, genesisCfg, engine, state, nil)
+		var beaconApiCfg *beacon.RouterConfiguration
+		if config.BeaconAPI {
+			beaconApiCfg = &beacon.RouterConfiguration{
+				Protocol:        "tcp",
+				Address:         fmt.Sprintf("%s:%d", config.BeaconAPIAddr, config.BeaconAPIPort),
+				ReadTimeTimeout: config.BeaconAPIReadTimeout,
+				WriteTimeout:    config.BeaconAPIWriteTimeout,
+				IdleTimeout:     config.BeaconAPIWriteTimeout,
+			}
+		}
+
+		go caplin1.RunCaplinPhase1(ctx, client, beaconCfg, genesisCfg, engine, state, nil, blobs, lightClient, beaconApiCfg)
 	}
 
 	if currentBlock == nil {
@@ -695,9 +741,13 @@ func New(stack *node.Node, config *ethconfig.Config, logger log.Logger) (*Ethere
 	}
 
 	backend.ethBackendRPC, backend.miningRPC, backend.stateChangesClient = ethBackendRPC, miningRPC, stateDiffClient
-	blockRetire := freezeblocks.NewBlockRetire(1, dirs, blockReader, blockWriter, backend.chainDB, backend.notifications.Events, logger)
+	var borDB kv.RwDB
+	if casted, ok := backend.engine.(*bor.Bor); ok {
+		borDB = casted.DB
+	}
+	blockRetire := freezeblocks.NewBlockRetire(1, dirs, blockReader, blockWriter, backend.chainDB, borDB, backend.notifications.Events, logger)
 
-	backend.syncStages = stages2.NewDefaultStages(backend.sentryCtx, backend.chainDB, stack.Config().P2P, config, backend.sentriesClient, backend.notifications, backend.downloaderClient, blockReader, blockRetire, backend.agg, backend.forkValidator, logger)
+	backend.syncStages = stages2.NewDefaultStages(backend.sentryCtx, backend.chainDB, stack.Config().P2P, config, backend.sentriesClient, backend.notifications, backend.downloaderClient, blockReader, blockRetire, backend.agg, backend.forkValidator, backend.whitelist, logger)
 	backend.syncUnwindOrder = stagedsync.DefaultUnwindOrder
 	backend.syncPruneOrder = stagedsync.DefaultPruneOrder
 	backend.stagedSync = stagedsync.New(backend.syncStages, backend.syncUnwindOrder, backend.syncPruneOrder, logger)
@@ -745,7 +795,7 @@ func (s *Ethereum) Init(stack *node.Node, config *ethconfig.Config) error {
 	}
 	// start HTTP API
 	httpRpcCfg := stack.Config().Http
-	ethRpcClient, txPoolRpcClient, miningRpcClient, stateCache, ff, err := cli.EmbeddedServices(ctx, chainKv, httpRpcCfg.StateCache, blockReader, ethBackendRPC,
+	ethRpcClient, txPoolRpcClient, miningRpcClient, stateCache, ff, err := cli.EmbeddedServices(ctx, chainKv, httpRpcCfg.StateCache, blockReader, ethBackendRPC, s.peerAdmin,
 		s.txPoolGrpcServer, miningRPC, stateDiffClient, s.logger)
 	if err != nil {
 		return err
@@ -1027,7 +1077,11 @@ func setUpBlockReader(ctx context.Context, db kv.RwDB, dirs datadir.Dirs, snConf
 	if !snConfig.NoDownloader {
 		allSnapshots.OptimisticalyReopenWithDB(db)
 	}
-	blockReader := freezeblocks.NewBlockReader(allSnapshots)
+	allBorSnapshots := freezeblocks.NewBorRoSnapshots(snConfig, dirs.Snap, logger)
+	if !snConfig.NoDownloader {
+		allBorSnapshots.OptimisticalyReopenFolder()
+	}
+	blockReader := freezeblocks.NewBlockReader(allSnapshots, allBorSnapshots)
 	blockWriter := blockio.NewBlockWriter(histV3)
 
 	dir.MustExist(dirs.SnapHistory)
@@ -1069,6 +1123,10 @@ func (s *Ethereum) Start() error {
 	s.sentriesClient.StartStreamLoops(s.sentryCtx)
 	time.Sleep(10 * time.Millisecond) // just to reduce logs order confusion
 
+	if casted, ok := s.engine.(*bor.Bor); ok && s.whitelist != nil {
+		finality.Whitelist(s.sentryCtx, s.whitelist, casted.HeimdallClient, s.chainDB, s.blockReader, s.logger)
+	}
+
 	hook := stages2.NewHook(s.sentryCtx, s.notifications, s.stagedSync, s.blockReader, s.chainConfig, s.logger, s.sentriesClient.UpdateHead)
 	go stages2.StageLoop(s.sentryCtx, s.chainDB, s.stagedSync, s.sentriesClient.Hd, s.waitForStageLoopStop, s.config.Sync.LoopThrottle, s.logger, s.blockReader, hook)
 
@@ -60,6 +60,22 @@ func (ct *CallTracer) CaptureEnd(output []byte, usedGas uint64, err error) {
 func (ct *CallTracer) CaptureExit(output []byte, usedGas uint64, err error) {
 }
 
+// Fork returns an empty CallTracer for a single transaction
+func (ct *CallTracer) Fork() vm.EVMLogger {
+	return NewCallTracer()
+}
+
+// Join merges the addresses collected by a forked CallTracer
+func (ct *CallTracer) Join(fork vm.EVMLogger) {
+	other := fork.(*CallTracer)
+	for addr := range other.froms {
+		ct.froms[addr] = struct{}{}
+	}
+	for addr, created := range other.tos {
+		ct.tos[addr] = ct.tos[addr] || created
+	}
+}
+
 func (ct *CallTracer) WriteToDb(tx kv.StatelessWriteTx, block *types.Block, vmConfig vm.Config) error {
 	ct.tos[block.Coinbase()] = false
 	for _, uncle := range block.Uncles() {
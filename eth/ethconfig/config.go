@@ -235,6 +235,9 @@ type Config struct {
 
 	// No heimdall service
 	WithoutHeimdall bool
+
+	// What is kept of the Heimdall responses in the bor DB: cache, record, replay or off
+	HeimdallCacheMode string
 	// Ethstats service
 	Ethstats string
 	// Consensus layer
@@ -244,6 +247,17 @@ type Config struct {
 	LightClientDiscoveryTCPPort uint64
 	SentinelAddr                string
 	SentinelPort                uint64
+	// Beacon API of the internal consensus, off unless BeaconAPI is set
+	BeaconAPI             bool
+	BeaconAPIAddr         string
+	BeaconAPIPort         uint64
+	BeaconAPIReadTimeout  time.Duration
+	BeaconAPIWriteTimeout time.Duration
+	// Checkpoint sync of the internal consensus: the state comes from the file, or else from all the URLs, which
+	// default to one of the known endpoints of the network, and must match the weak subjectivity checkpoint if set
+	CheckpointSyncUrls         []string
+	CheckpointSyncFile         string
+	WeakSubjectivityCheckpoint string
 
 	OverrideShanghaiTime *big.Int `toml:",omitempty"`
 
@@ -259,6 +273,14 @@ type Sync struct {
 
 	BodyCacheLimit             datasize.ByteSize
 	BodyDownloadTimeoutSeconds int // TODO: change to duration
+
+	ParallelEVM ParallelEVMConfig
+}
+
+// ParallelEVMConfig configures Block-STM execution of Bor blocks in the Execution stage
+type ParallelEVMConfig struct {
+	Enable               bool
+	SpeculativeProcesses int // number of speculative workers, 0 - use default
 }
 
 // Chains where snapshots are enabled by default
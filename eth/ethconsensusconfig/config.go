@@ -24,7 +24,7 @@ import (
 )
 
 func CreateConsensusEngine(chainConfig *chain.Config, config interface{}, notify []string, noVerify bool,
-	heimdallGrpcAddress string, heimdallUrl string, withoutHeimdall bool, dataDir string, readonly bool,
+	heimdallGrpcAddress string, heimdallUrl string, withoutHeimdall bool, heimdallCacheMode string, dataDir string, readonly bool,
 	logger log.Logger,
 ) consensus.Engine {
 	var eng consensus.Engine
@@ -81,11 +81,20 @@ func CreateConsensusEngine(chainConfig *chain.Config, config interface{}, notify
 			if withoutHeimdall {
 				return bor.New(chainConfig, db, spanner, nil, genesisContractsClient, logger)
 			} else {
-				if heimdallGrpcAddress != "" {
+				cacheMode, err := bor.ParseHeimdallCacheMode(heimdallCacheMode)
+				if err != nil {
+					panic(err)
+				}
+				if cacheMode == bor.HeimdallReplay {
+					// every response comes from the bor DB
+				} else if heimdallGrpcAddress != "" {
 					heimdallClient = heimdallgrpc.NewHeimdallGRPCClient(heimdallGrpcAddress)
 				} else {
 					heimdallClient = heimdall.NewHeimdallClient(heimdallUrl)
 				}
+				if cacheMode != bor.HeimdallNoCache {
+					heimdallClient = bor.NewCachingHeimdallClient(heimdallClient, db, cacheMode, logger)
+				}
 				eng = bor.New(chainConfig, db, spanner, heimdallClient, genesisContractsClient, logger)
 			}
 		}
@@ -118,5 +127,5 @@ func CreateConsensusEngineBareBones(chainConfig *chain.Config, logger log.Logger
 	}
 
 	return CreateConsensusEngine(chainConfig, consensusConfig, nil /* notify */, true, /* noVerify */
-		"" /* heimdallGrpcAddress */, "" /* heimdallUrl */, true /* withoutHeimdall */, "" /*dataDir*/, false /* readonly */, logger)
+		"" /* heimdallGrpcAddress */, "" /* heimdallUrl */, true /* withoutHeimdall */, "" /* heimdallCacheMode */, "" /*dataDir*/, false /* readonly */, logger)
 }
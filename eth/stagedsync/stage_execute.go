@@ -83,6 +83,8 @@ type ExecuteBlockCfg struct {
 	syncCfg   ethconfig.Sync
 	genesis   *types.Genesis
 	agg       *libstate.AggregatorV3
+
+	parallelProcessor *core.ParallelStateProcessor // nil - serial execution
 }
 
 func StageExecuteBlocksCfg(
@@ -105,6 +107,10 @@ func StageExecuteBlocksCfg(
 	syncCfg ethconfig.Sync,
 	agg *libstate.AggregatorV3,
 ) ExecuteBlockCfg {
+	var parallelProcessor *core.ParallelStateProcessor
+	if syncCfg.ParallelEVM.Enable && chainConfig.Bor != nil && !historyV3 {
+		parallelProcessor = core.NewParallelStateProcessor(chainConfig, engine, syncCfg.ParallelEVM)
+	}
 	return ExecuteBlockCfg{
 		db:            db,
 		prune:         pm,
@@ -123,6 +129,8 @@ func StageExecuteBlocksCfg(
 		historyV3:     historyV3,
 		syncCfg:       syncCfg,
 		agg:           agg,
+
+		parallelProcessor: parallelProcessor,
 	}
 }
 
@@ -162,11 +170,15 @@ func executeBlock(
 	var stateSyncReceipt *types.Receipt
 	var execRs *core.EphemeralExecResult
 	getHashFn := core.GetHashFn(block.Header(), getHeader)
+	chainReader := ChainReaderImpl{config: cfg.chainConfig, tx: tx, blockReader: cfg.blockReader}
 
-	if cfg.chainConfig.Bor != nil {
-		execRs, err = core.ParallelExecuteBlockEphemerallyBor(cfg.chainConfig, &vmConfig, getHashFn, cfg.engine, block, stateReader, stateWriter, ChainReaderImpl{config: cfg.chainConfig, tx: tx, blockReader: cfg.blockReader}, getTracer, getHeader, cfg.db, batch, cfg.blockReader)
+	if err = core.VerifyBlockFamily(cfg.engine, chainReader, block.HeaderNoCopy(), cfg.chainConfig, stateReader); err != nil {
+		return err
+	}
+	if cfg.parallelProcessor != nil {
+		execRs, err = cfg.parallelProcessor.Process(&vmConfig, getHashFn, block, stateReader, stateWriter, chainReader, getTracer)
 	} else {
-		execRs, err = core.ExecuteBlockEphemerally(cfg.chainConfig, &vmConfig, getHashFn, cfg.engine, block, stateReader, stateWriter, ChainReaderImpl{config: cfg.chainConfig, tx: tx, blockReader: cfg.blockReader}, getTracer)
+		execRs, err = core.ExecuteBlockEphemerally(cfg.chainConfig, &vmConfig, getHashFn, cfg.engine, block, stateReader, stateWriter, chainReader, getTracer)
 	}
 	if err != nil {
 		return err
@@ -459,6 +471,10 @@ Loop:
 		if err = executeBlock(block, tx, batch, cfg, *cfg.vmConfig, writeChangeSets, writeReceipts, writeCallTraces, initialCycle, stateStream); err != nil {
 			if !errors.Is(err, context.Canceled) {
 				logger.Warn(fmt.Sprintf("[%s] Execution failed", logPrefix), "block", blockNum, "hash", block.Hash().String(), "err", err)
+				// The unwind which follows doesn't touch the bad blocks, they are kept for debug_getBadBlocks
+				if err := rawdb.WriteBadBlock(tx, block, err.Error()); err != nil {
+					return err
+				}
 				if cfg.hd != nil {
 					cfg.hd.ReportBadHeaderPoS(blockHash, block.ParentHash())
 				}
@@ -15,6 +15,7 @@ import (
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/erigon/turbo/services"
 	"github.com/ledgerwatch/log/v3"
+	"golang.org/x/exp/slices"
 
 	common2 "github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/common/dbutils"
@@ -124,7 +125,7 @@ func PruneFinish(u *PruneState, tx kv.RwTx, cfg FinishCfg, ctx context.Context)
 	return nil
 }
 
-func NotifyNewHeaders(ctx context.Context, finishStageBeforeSync uint64, finishStageAfterSync uint64, unwindTo *uint64, notifier ChainEventNotifier, tx kv.Tx, logger log.Logger, blockReader services.FullBlockReader) error {
+func NotifyNewHeaders(ctx context.Context, finishStageBeforeSync uint64, finishStageAfterSync uint64, unwindTo *uint64, notifier ChainEventNotifier, recentLogs *RecentLogs, tx kv.Tx, logger log.Logger, blockReader services.FullBlockReader) error {
 	t := time.Now()
 	if notifier == nil {
 		logger.Trace("RPC Daemon notification channel not set. No headers notifications will be sent")
@@ -175,11 +176,17 @@ func NotifyNewHeaders(ctx context.Context, finishStageBeforeSync uint64, finishS
 
 		t = time.Now()
 		if notifier.HasLogSubsriptions() {
-			logs, err := ReadLogs(tx, notifyFrom, isUnwind, blockReader)
+			logs, err := ReadLogs(tx, notifyFrom, blockReader)
 			if err != nil {
 				return err
 			}
+			// The logs of the unwound blocks are sent again as removed, before the logs of the new canonical blocks
+			if removed := recentLogs.Replace(notifyFrom, notifyTo, logs); isUnwind && len(removed) > 0 {
+				logs = append(removed, logs...)
+			}
 			notifier.OnLogs(logs)
+		} else {
+			recentLogs.Reset()
 		}
 		logTiming := time.Since(t)
 		logger.Info("RPC Daemon notified of new headers", "from", notifyFrom-1, "to", notifyTo, "hash", notifyToHash, "header sending", headerTiming, "log sending", logTiming)
@@ -187,7 +194,7 @@ func NotifyNewHeaders(ctx context.Context, finishStageBeforeSync uint64, finishS
 	return nil
 }
 
-func ReadLogs(tx kv.Tx, from uint64, isUnwind bool, blockReader services.FullBlockReader) ([]*remote.SubscribeLogsReply, error) {
+func ReadLogs(tx kv.Tx, from uint64, blockReader services.FullBlockReader) ([]*remote.SubscribeLogsReply, error) {
 	logs, err := tx.Cursor(kv.Log)
 	if err != nil {
 		return nil, err
@@ -228,7 +235,6 @@ func ReadLogs(tx kv.Tx, from uint64, isUnwind bool, blockReader services.FullBlo
 				Topics:           make([]*types2.H256, 0, len(l.Topics)),
 				TransactionHash:  gointerfaces.ConvertHashToH256(txHash),
 				TransactionIndex: txIndex,
-				Removed:          isUnwind,
 			}
 			logIndex++
 			for _, topic := range l.Topics {
@@ -240,3 +246,87 @@ func ReadLogs(tx kv.Tx, from uint64, isUnwind bool, blockReader services.FullBlo
 
 	return reply, nil
 }
+
+// RecentLogs keeps the logs sent to the subscribers for the latest blocks, so that they can be sent again as removed
+// when these blocks are unwound. At most maxBlocks blocks and maxLogs logs are kept, the oldest blocks are forgotten
+// first. Unwinds deeper than the kept blocks only remove the logs of the kept blocks.
+type RecentLogs struct {
+	maxBlocks uint64
+	maxLogs   int
+	count     int
+	blocks    map[uint64][]*remote.SubscribeLogsReply
+}
+
+func NewRecentLogs(maxBlocks uint64, maxLogs int) *RecentLogs {
+	return &RecentLogs{maxBlocks: maxBlocks, maxLogs: maxLogs, blocks: map[uint64][]*remote.SubscribeLogsReply{}}
+}
+
+// Replace forgets the logs of the blocks from the given one, remembers the logs of the new blocks up to the head block
+// instead, and returns copies of the forgotten logs marked as removed, in the order they were added
+func (r *RecentLogs) Replace(from, head uint64, logs []*remote.SubscribeLogsReply) []*remote.SubscribeLogsReply {
+	if r == nil {
+		return nil
+	}
+	var unwound []uint64
+	for blockNum := range r.blocks {
+		if blockNum >= from {
+			unwound = append(unwound, blockNum)
+		}
+	}
+	slices.Sort(unwound)
+	var removed []*remote.SubscribeLogsReply
+	for _, blockNum := range unwound {
+		for _, l := range r.blocks[blockNum] {
+			removed = append(removed, &remote.SubscribeLogsReply{
+				Address:          l.Address,
+				BlockHash:        l.BlockHash,
+				BlockNumber:      l.BlockNumber,
+				Data:             l.Data,
+				LogIndex:         l.LogIndex,
+				Topics:           l.Topics,
+				TransactionHash:  l.TransactionHash,
+				TransactionIndex: l.TransactionIndex,
+				Removed:          true,
+			})
+		}
+		r.count -= len(r.blocks[blockNum])
+		delete(r.blocks, blockNum)
+	}
+
+	for _, l := range logs {
+		if l.BlockNumber+r.maxBlocks <= head {
+			continue
+		}
+		r.blocks[l.BlockNumber] = append(r.blocks[l.BlockNumber], l)
+		r.count++
+	}
+	kept := make([]uint64, 0, len(r.blocks))
+	for blockNum := range r.blocks {
+		if blockNum+r.maxBlocks <= head {
+			r.count -= len(r.blocks[blockNum])
+			delete(r.blocks, blockNum)
+			continue
+		}
+		kept = append(kept, blockNum)
+	}
+	if r.count > r.maxLogs {
+		slices.Sort(kept)
+		for _, blockNum := range kept {
+			if r.count <= r.maxLogs {
+				break
+			}
+			r.count -= len(r.blocks[blockNum])
+			delete(r.blocks, blockNum)
+		}
+	}
+	return removed
+}
+
+// Reset forgets all the logs, it is called when nobody is subscribed to the logs
+func (r *RecentLogs) Reset() {
+	if r == nil {
+		return
+	}
+	r.blocks = map[uint64][]*remote.SubscribeLogsReply{}
+	r.count = 0
+}
@@ -0,0 +1,56 @@
+package stagedsync
+
+import (
+	"testing"
+
+	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecentLogs(t *testing.T) {
+	logsOf := func(blocks ...uint64) []*remote.SubscribeLogsReply {
+		var logs []*remote.SubscribeLogsReply
+		for _, blockNum := range blocks {
+			logs = append(logs, &remote.SubscribeLogsReply{BlockNumber: blockNum, LogIndex: uint64(len(logs))})
+		}
+		return logs
+	}
+	r := NewRecentLogs(4, 100)
+	require.Empty(t, r.Replace(1, 3, logsOf(1, 2, 2, 3)))
+	require.Empty(t, r.Replace(4, 6, logsOf(5, 6)))
+
+	// Unwinding to block 2 removes the logs of blocks 3, 5 and 6
+	removed := r.Replace(3, 4, logsOf(3, 4))
+	require.Len(t, removed, 3)
+	for i, blockNum := range []uint64{3, 5, 6} {
+		require.Equal(t, blockNum, removed[i].BlockNumber)
+		require.True(t, removed[i].Removed)
+	}
+
+	// Only the logs of the latest 4 blocks are kept: 4, 5, 6 and 7
+	require.Empty(t, r.Replace(5, 7, logsOf(5, 7)))
+	removed = r.Replace(1, 1, nil)
+	require.Len(t, removed, 3)
+	for i, blockNum := range []uint64{4, 5, 7} {
+		require.Equal(t, blockNum, removed[i].BlockNumber)
+	}
+	// The blocks without logs count too
+	require.Empty(t, r.Replace(1, 2, logsOf(1, 2)))
+	require.Empty(t, r.Replace(3, 10, nil))
+	require.Empty(t, r.Replace(1, 1, nil))
+
+	r.Replace(1, 1, logsOf(1))
+	r.Reset()
+	require.Empty(t, r.Replace(1, 1, nil))
+	var nilLogs *RecentLogs
+	require.Empty(t, nilLogs.Replace(1, 1, logsOf(1)))
+
+	// Beyond the maximum number of logs, the oldest blocks are forgotten
+	r = NewRecentLogs(100, 3)
+	require.Empty(t, r.Replace(1, 3, logsOf(1, 2, 2, 3)))
+	removed = r.Replace(1, 1, nil)
+	require.Len(t, removed, 3)
+	for i, blockNum := range []uint64{2, 2, 3} {
+		require.Equal(t, blockNum, removed[i].BlockNumber)
+	}
+}
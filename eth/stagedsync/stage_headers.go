@@ -21,6 +21,7 @@ import (
 
 	"github.com/ledgerwatch/erigon/common"
 	"github.com/ledgerwatch/erigon/common/math"
+	"github.com/ledgerwatch/erigon/consensus/bor/finality/whitelist"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/ethdb/privateapi"
@@ -52,6 +53,8 @@ type HeadersCfg struct {
 	blockWriter   *blockio.BlockWriter
 	forkValidator *engineapi.ForkValidator
 	notifications *shards.Notifications
+
+	whitelist *whitelist.Service // Heimdall finality of Bor blocks, nil if not verified
 }
 
 func StageHeadersCfg(
@@ -68,7 +71,8 @@ func StageHeadersCfg(
 	blockWriter *blockio.BlockWriter,
 	tmpdir string,
 	notifications *shards.Notifications,
-	forkValidator *engineapi.ForkValidator) HeadersCfg {
+	forkValidator *engineapi.ForkValidator,
+	whitelist *whitelist.Service) HeadersCfg {
 	return HeadersCfg{
 		db:                db,
 		hd:                headerDownload,
@@ -84,6 +88,7 @@ func StageHeadersCfg(
 		blockWriter:       blockWriter,
 		forkValidator:     forkValidator,
 		notifications:     notifications,
+		whitelist:         whitelist,
 	}
 }
 
@@ -753,6 +758,39 @@ func handleInterrupt(interrupt engineapi.Interrupt, cfg HeadersCfg, tx kv.RwTx,
 	return false, nil
 }
 
+// writeBorFinality records the Heimdall finalized and safe blocks as the
+// fork choice, so that the "finalized" and "safe" block tags resolve to them
+// in rpcdaemon, whether it runs within Erigon or reads the database remotely.
+// Blocks which are not canonical (yet) are skipped.
+func writeBorFinality(ctx context.Context, tx kv.RwTx, blockReader services.FullBlockReader, whitelistService *whitelist.Service) error {
+	isCanonical := func(number uint64, hash libcommon.Hash) (bool, error) {
+		canonicalHash, err := blockReader.CanonicalHash(ctx, tx, number)
+		return canonicalHash == hash, err
+	}
+
+	if doExist, number, hash := whitelistService.GetFinalizedBlock(); doExist {
+		canonical, err := isCanonical(number, hash)
+		if err != nil {
+			return err
+		}
+		if canonical {
+			rawdb.WriteForkchoiceFinalized(tx, hash)
+		}
+	}
+
+	if doExist, number, hash := whitelistService.GetSafeBlock(); doExist {
+		canonical, err := isCanonical(number, hash)
+		if err != nil {
+			return err
+		}
+		if canonical {
+			rawdb.WriteForkchoiceSafe(tx, hash)
+		}
+	}
+
+	return nil
+}
+
 // HeadersPOW progresses Headers stage for Proof-of-Work headers
 func HeadersPOW(
 	s *StageState,
@@ -802,6 +840,27 @@ func HeadersPOW(
 		return nil
 	}
 
+	whitelistService := cfg.whitelist
+	if whitelistService != nil {
+		// Unwind the canonical chain if it contradicts a Heimdall checkpoint or milestone
+		if rewind := whitelistService.PopRewind(); rewind != nil && rewind.Number < headerProgress {
+			firstHash, err := cfg.blockReader.CanonicalHash(ctx, tx, rewind.Number+1)
+			if err != nil {
+				return err
+			}
+			if firstHash == rewind.FirstBlock {
+				logger.Warn(fmt.Sprintf("[%s] Canonical chain does not match Heimdall finality, unwinding", logPrefix), "to", rewind.Number, "firstBlock", rewind.FirstBlock)
+				// Like bor, the dropped blocks aren't marked as bad, they may be downloaded again
+				u.UnwindTo(rewind.Number, libcommon.Hash{})
+				return nil
+			}
+		}
+
+		if err := writeBorFinality(ctx, tx, cfg.blockReader, whitelistService); err != nil {
+			return err
+		}
+	}
+
 	logger.Info(fmt.Sprintf("[%s] Waiting for headers...", logPrefix), "from", headerProgress)
 
 	localTd, err := rawdb.ReadTd(tx, hash, headerProgress)
@@ -812,6 +871,9 @@ func HeadersPOW(
 		return fmt.Errorf("localTD is nil: %d, %x", headerProgress, hash)
 	}
 	headerInserter := headerdownload.NewHeaderInserter(logPrefix, localTd, headerProgress, cfg.blockReader)
+	if whitelistService != nil {
+		headerInserter.SetChainValidator(whitelistService)
+	}
 	cfg.hd.SetHeaderReader(&ChainReaderImpl{config: &cfg.chainConfig, tx: tx, blockReader: cfg.blockReader})
 
 	stopped := false
@@ -31,6 +31,11 @@ type MiningBlock struct {
 	Receipts    types.Receipts
 	Withdrawals []*types.Withdrawal
 	PreparedTxs types.TransactionsStream
+
+	// Block-STM dependencies of Txs, encoded into the header by the finish
+	// stage when the consensus engine supports it
+	TxDependency [][]uint64
+	txDeps       *txDependencies
 }
 
 type MiningState struct {
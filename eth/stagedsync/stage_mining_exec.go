@@ -11,6 +11,7 @@ import (
 	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/holiman/uint256"
 	"github.com/ledgerwatch/log/v3"
+	"golang.org/x/exp/slices"
 	"golang.org/x/net/context"
 
 	"github.com/ledgerwatch/erigon-lib/chain"
@@ -22,6 +23,7 @@ import (
 	"github.com/ledgerwatch/erigon/consensus"
 	"github.com/ledgerwatch/erigon/consensus/misc"
 	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/blockstm"
 	"github.com/ledgerwatch/erigon/core/rawdb"
 	"github.com/ledgerwatch/erigon/core/state"
 	"github.com/ledgerwatch/erigon/core/systemcontracts"
@@ -104,6 +106,13 @@ func SpawnMiningExecStage(s *StageState, tx kv.RwTx, cfg MiningExecCfg, quit <-c
 
 	getHeader := func(hash libcommon.Hash, number uint64) *types.Header { return rawdb.ReadHeader(tx, hash, number) }
 
+	// Record the read and write sets of the transactions so that the block
+	// producer can ship their Block-STM dependencies in the header
+	if engine, ok := cfg.engine.(parallelUniverseEngine); ok && engine.IsParallelUniverse(current.Header.Number.Uint64()) {
+		current.txDeps = newTxDependencies(cfg.miningState.MiningConfig.Etherbase, cfg.chainConfig.Eip1559FeeCollector)
+		ibs.SetMVHashmap(blockstm.MakeMVHashMap())
+	}
+
 	// Short circuit if there is no available pending transactions.
 	// But if we disable empty precommit already, ignore it. Since
 	// empty block is necessary to keep the liveness of the network.
@@ -151,6 +160,11 @@ func SpawnMiningExecStage(s *StageState, tx kv.RwTx, cfg MiningExecCfg, quit <-c
 		}
 	}
 
+	if current.txDeps != nil {
+		ibs.SetMVHashmap(nil)
+		current.TxDependency = current.txDeps.result()
+	}
+
 	logger.Debug("SpawnMiningExecStage", "block txn", current.Txs.Len(), "payload", cfg.payloadId)
 	if current.Uncles == nil {
 		current.Uncles = []*types.Header{}
@@ -373,6 +387,10 @@ func addTransactionsToMiningBlock(logPrefix string, current *MiningBlock, chainC
 		gasSnap := gasPool.Gas()
 		dataGasSnap := gasPool.DataGas()
 		snap := ibs.Snapshot()
+		if current.txDeps != nil {
+			ibs.ClearReadMap()
+			ibs.ClearWriteMap()
+		}
 		logger.Debug("addTransactionsToMiningBlock", "txn hash", txn.Hash())
 		receipt, _, err := core.ApplyTransaction(&chainConfig, core.GetHashFn(header, getHeader), engine, &coinbase, gasPool, ibs, noop, header, txn, &header.GasUsed, header.DataGasUsed, *vmConfig)
 		if err != nil {
@@ -383,6 +401,9 @@ func addTransactionsToMiningBlock(logPrefix string, current *MiningBlock, chainC
 
 		current.Txs = append(current.Txs, txn)
 		current.Receipts = append(current.Receipts, receipt)
+		if current.txDeps != nil {
+			current.txDeps.add(ibs.MVReadList(), ibs.MVFullWriteList())
+		}
 		return receipt.Logs, nil
 	}
 
@@ -485,6 +506,73 @@ LOOP:
 
 }
 
+// parallelUniverseEngine is implemented by consensus engines whose headers
+// can carry Block-STM dependency metadata.
+type parallelUniverseEngine interface {
+	IsParallelUniverse(number uint64) bool
+}
+
+// txDependencies derives the Block-STM dependencies of the transactions
+// included in a mining block from their read and write sets.
+//
+// Reads and writes of the coinbase and fee collector accounts are left out:
+// Block-STM delays the fee transfers of Bor blocks to the settlement of each
+// transaction, so they do not make transactions conflict. A transaction which
+// does read those balances is detected by the executor, which then falls back
+// to serial execution anyway.
+type txDependencies struct {
+	skip          map[libcommon.Address]struct{}
+	fullWriteList [][]blockstm.WriteDescriptor
+	deps          map[int]map[int]bool
+}
+
+func newTxDependencies(coinbase libcommon.Address, feeCollector *libcommon.Address) *txDependencies {
+	d := &txDependencies{
+		skip: map[libcommon.Address]struct{}{coinbase: {}},
+		deps: map[int]map[int]bool{},
+	}
+	if feeCollector != nil {
+		d.skip[*feeCollector] = struct{}{}
+	}
+	return d
+}
+
+// add records the read and write sets of the next transaction of the block.
+func (d *txDependencies) add(reads []blockstm.ReadDescriptor, writes []blockstm.WriteDescriptor) {
+	readList := make([]blockstm.ReadDescriptor, 0, len(reads))
+	for _, rd := range reads {
+		if _, ok := d.skip[rd.Path.GetAddress()]; !ok {
+			readList = append(readList, rd)
+		}
+	}
+	writeList := make([]blockstm.WriteDescriptor, 0, len(writes))
+	for _, wd := range writes {
+		if _, ok := d.skip[wd.Path.GetAddress()]; !ok {
+			writeList = append(writeList, wd)
+		}
+	}
+	d.fullWriteList = append(d.fullWriteList, writeList)
+	d.deps = blockstm.UpdateDeps(d.deps, blockstm.TxDep{
+		Index:         len(d.fullWriteList) - 1,
+		ReadList:      readList,
+		FullWriteList: d.fullWriteList,
+	})
+}
+
+// result returns the dependencies in the header format: entry i holds the
+// sorted indices of the transactions which transaction i depends on.
+func (d *txDependencies) result() [][]uint64 {
+	res := make([][]uint64, len(d.fullWriteList))
+	for i := range res {
+		res[i] = []uint64{}
+		for j := range d.deps[i] {
+			res[i] = append(res[i], uint64(j))
+		}
+		slices.Sort(res[i])
+	}
+	return res
+}
+
 func NotifyPendingLogs(logPrefix string, notifier ChainEventNotifier, logs types.Logs, logger log.Logger) {
 	if len(logs) == 0 {
 		return
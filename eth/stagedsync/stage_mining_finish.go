@@ -48,6 +48,17 @@ func SpawnMiningFinishStage(s *StageState, tx kv.RwTx, cfg MiningFinishCfg, quit
 	//	continue
 	//}
 
+	if current.TxDependency != nil {
+		extraData, err := types.DecodeBlockExtraData(current.Header.Extra)
+		if err != nil {
+			return fmt.Errorf("[%s] cannot decode header extra: %w", logPrefix, err)
+		}
+		extraData.TxDependency = current.TxDependency
+		if current.Header.Extra, err = types.EncodeBlockExtraData(current.Header.Extra, extraData); err != nil {
+			return fmt.Errorf("[%s] cannot encode header extra: %w", logPrefix, err)
+		}
+	}
+
 	block := types.NewBlock(current.Header, current.Txs, current.Uncles, current.Receipts, current.Withdrawals)
 	blockWithReceipts := &types.BlockWithReceipts{Block: block, Receipts: current.Receipts}
 	*current = MiningBlock{} // hack to clean global data
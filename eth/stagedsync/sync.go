@@ -9,10 +9,16 @@ import (
 	"github.com/ledgerwatch/erigon-lib/common/dbg"
 	"github.com/ledgerwatch/erigon-lib/kv"
 	"github.com/ledgerwatch/log/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
 )
 
+var tracer = otel.Tracer("github.com/ledgerwatch/erigon/eth/stagedsync")
+
 type Sync struct {
 	unwindPoint     *uint64 // used to run stages
 	prevUnwindPoint *uint64 // used to get value from outside of staged sync after cycle (for example to notify RPCDaemon)
@@ -191,7 +197,7 @@ func (s *Sync) StageState(stage stages.SyncStage, tx kv.Tx, db kv.RoDB) (*StageS
 	return &StageState{s, stage, blockNum}, nil
 }
 
-func (s *Sync) RunUnwind(db kv.RwDB, tx kv.RwTx) error {
+func (s *Sync) RunUnwind(ctx context.Context, db kv.RwDB, tx kv.RwTx) error {
 	if s.unwindPoint == nil {
 		return nil
 	}
@@ -199,7 +205,7 @@ func (s *Sync) RunUnwind(db kv.RwDB, tx kv.RwTx) error {
 		if s.unwindOrder[j] == nil || s.unwindOrder[j].Disabled || s.unwindOrder[j].Unwind == nil {
 			continue
 		}
-		if err := s.unwindStage(false, s.unwindOrder[j], db, tx); err != nil {
+		if err := s.unwindStage(ctx, false, s.unwindOrder[j], db, tx); err != nil {
 			return err
 		}
 	}
@@ -211,7 +217,10 @@ func (s *Sync) RunUnwind(db kv.RwDB, tx kv.RwTx) error {
 	}
 	return nil
 }
-func (s *Sync) Run(db kv.RwDB, tx kv.RwTx, firstCycle bool) error {
+func (s *Sync) Run(ctx context.Context, db kv.RwDB, tx kv.RwTx, firstCycle bool) error {
+	// The spans of the stages of a cycle are the children of its span
+	ctx, span := tracer.Start(ctx, "sync cycle")
+	defer span.End()
 	s.prevUnwindPoint = nil
 	s.timings = s.timings[:0]
 
@@ -222,7 +231,7 @@ func (s *Sync) Run(db kv.RwDB, tx kv.RwTx, firstCycle bool) error {
 				if s.unwindOrder[j] == nil || s.unwindOrder[j].Disabled || s.unwindOrder[j].Unwind == nil {
 					continue
 				}
-				if err := s.unwindStage(firstCycle, s.unwindOrder[j], db, tx); err != nil {
+				if err := s.unwindStage(ctx, firstCycle, s.unwindOrder[j], db, tx); err != nil {
 					return err
 				}
 			}
@@ -259,7 +268,7 @@ func (s *Sync) Run(db kv.RwDB, tx kv.RwTx, firstCycle bool) error {
 			continue
 		}
 
-		if err := s.runStage(stage, db, tx, firstCycle, badBlockUnwind); err != nil {
+		if err := s.runStage(ctx, stage, db, tx, firstCycle, badBlockUnwind); err != nil {
 			return err
 		}
 
@@ -278,13 +287,13 @@ func (s *Sync) Run(db kv.RwDB, tx kv.RwTx, firstCycle bool) error {
 	s.currentStage = 0
 	return nil
 }
-func (s *Sync) RunPrune(db kv.RwDB, tx kv.RwTx, firstCycle bool) error {
+func (s *Sync) RunPrune(ctx context.Context, db kv.RwDB, tx kv.RwTx, firstCycle bool) error {
 	s.timings = s.timings[:0]
 	for i := 0; i < len(s.pruningOrder); i++ {
 		if s.pruningOrder[i] == nil || s.pruningOrder[i].Disabled || s.pruningOrder[i].Prune == nil {
 			continue
 		}
-		if err := s.pruneStage(firstCycle, s.pruningOrder[i], db, tx); err != nil {
+		if err := s.pruneStage(ctx, firstCycle, s.pruningOrder[i], db, tx); err != nil {
 			return err
 		}
 	}
@@ -350,12 +359,14 @@ func PrintTables(db kv.RoDB, tx kv.RwTx) []interface{} {
 	return bucketSizes
 }
 
-func (s *Sync) runStage(stage *Stage, db kv.RwDB, tx kv.RwTx, firstCycle bool, badBlockUnwind bool) (err error) {
+func (s *Sync) runStage(ctx context.Context, stage *Stage, db kv.RwDB, tx kv.RwTx, firstCycle bool, badBlockUnwind bool) (err error) {
 	start := time.Now()
 	stageState, err := s.StageState(stage.ID, tx, db)
 	if err != nil {
 		return err
 	}
+	span := startStageSpan(ctx, "stage", stage, attribute.Int64("from", int64(stageState.BlockNumber)))
+	defer func() { endStageSpan(span, err) }()
 
 	if err = stage.Forward(firstCycle, badBlockUnwind, stageState, s, tx, s.logger); err != nil {
 		wrappedError := fmt.Errorf("[%s] %w", s.LogPrefix(), err)
@@ -374,7 +385,7 @@ func (s *Sync) runStage(stage *Stage, db kv.RwDB, tx kv.RwTx, firstCycle bool, b
 	return nil
 }
 
-func (s *Sync) unwindStage(firstCycle bool, stage *Stage, db kv.RwDB, tx kv.RwTx) error {
+func (s *Sync) unwindStage(ctx context.Context, firstCycle bool, stage *Stage, db kv.RwDB, tx kv.RwTx) (err error) {
 	start := time.Now()
 	s.logger.Trace("Unwind...", "stage", stage.ID)
 	stageState, err := s.StageState(stage.ID, tx, db)
@@ -389,6 +400,8 @@ func (s *Sync) unwindStage(firstCycle bool, stage *Stage, db kv.RwDB, tx kv.RwTx
 		return nil
 	}
 
+	span := startStageSpan(ctx, "unwind", stage, attribute.Int64("from", int64(stageState.BlockNumber)), attribute.Int64("unwind_point", int64(unwind.UnwindPoint)))
+	defer func() { endStageSpan(span, err) }()
 	if err = s.SetCurrentStage(stage.ID); err != nil {
 		return err
 	}
@@ -407,7 +420,7 @@ func (s *Sync) unwindStage(firstCycle bool, stage *Stage, db kv.RwDB, tx kv.RwTx
 	return nil
 }
 
-func (s *Sync) pruneStage(firstCycle bool, stage *Stage, db kv.RwDB, tx kv.RwTx) error {
+func (s *Sync) pruneStage(ctx context.Context, firstCycle bool, stage *Stage, db kv.RwDB, tx kv.RwTx) (err error) {
 	start := time.Now()
 	s.logger.Trace("Prune...", "stage", stage.ID)
 
@@ -420,6 +433,8 @@ func (s *Sync) pruneStage(firstCycle bool, stage *Stage, db kv.RwDB, tx kv.RwTx)
 	if err != nil {
 		return err
 	}
+	span := startStageSpan(ctx, "prune", stage, attribute.Int64("from", int64(stageState.BlockNumber)))
+	defer func() { endStageSpan(span, err) }()
 	if err = s.SetCurrentStage(stage.ID); err != nil {
 		return err
 	}
@@ -438,6 +453,21 @@ func (s *Sync) pruneStage(firstCycle bool, stage *Stage, db kv.RwDB, tx kv.RwTx)
 	return nil
 }
 
+// startStageSpan records a span of one iteration of the stage, named e.g. "stage Execution" or "unwind Senders", as a
+// child of the span of the context, if there is one
+func startStageSpan(ctx context.Context, kind string, stage *Stage, attrs ...attribute.KeyValue) trace.Span {
+	attrs = append(attrs, attribute.String("stage", string(stage.ID)))
+	_, span := tracer.Start(ctx, kind+" "+string(stage.ID), trace.WithAttributes(attrs...))
+	return span
+}
+
+func endStageSpan(span trace.Span, err error) {
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
 // DisableAllStages - including their unwinds
 func (s *Sync) DisableAllStages() []stages.SyncStage {
 	var backupEnabledIds []stages.SyncStage
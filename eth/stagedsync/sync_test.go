@@ -1,6 +1,7 @@
 package stagedsync
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"testing"
@@ -44,7 +45,7 @@ func TestStagesSuccess(t *testing.T) {
 	}
 	state := New(s, nil, nil, log.New())
 	db, tx := memdb.NewTestTx(t)
-	err := state.Run(db, tx, true /* initialCycle */)
+	err := state.Run(context.Background(), db, tx, true /* initialCycle */)
 	assert.NoError(t, err)
 
 	expectedFlow := []stages.SyncStage{
@@ -84,7 +85,7 @@ func TestDisabledStages(t *testing.T) {
 	}
 	state := New(s, nil, nil, log.New())
 	db, tx := memdb.NewTestTx(t)
-	err := state.Run(db, tx, true /* initialCycle */)
+	err := state.Run(context.Background(), db, tx, true /* initialCycle */)
 	assert.NoError(t, err)
 
 	expectedFlow := []stages.SyncStage{
@@ -124,7 +125,7 @@ func TestErroredStage(t *testing.T) {
 	}
 	state := New(s, []stages.SyncStage{s[2].ID, s[1].ID, s[0].ID}, nil, log.New())
 	db, tx := memdb.NewTestTx(t)
-	err := state.Run(db, tx, true /* initialCycle */)
+	err := state.Run(context.Background(), db, tx, true /* initialCycle */)
 	assert.Equal(t, fmt.Errorf("[2/3 Bodies] %w", expectedErr), err)
 
 	expectedFlow := []stages.SyncStage{
@@ -207,7 +208,7 @@ func TestUnwindSomeStagesBehindUnwindPoint(t *testing.T) {
 	}
 	state := New(s, []stages.SyncStage{s[3].ID, s[2].ID, s[1].ID, s[0].ID}, nil, log.New())
 	db, tx := memdb.NewTestTx(t)
-	err := state.Run(db, tx, true /* initialCycle */)
+	err := state.Run(context.Background(), db, tx, true /* initialCycle */)
 	assert.NoError(t, err)
 
 	expectedFlow := []stages.SyncStage{
@@ -300,7 +301,7 @@ func TestUnwind(t *testing.T) {
 	}
 	state := New(s, []stages.SyncStage{s[3].ID, s[2].ID, s[1].ID, s[0].ID}, nil, log.New())
 	db, tx := memdb.NewTestTx(t)
-	err := state.Run(db, tx, true /* initialCycle */)
+	err := state.Run(context.Background(), db, tx, true /* initialCycle */)
 	assert.NoError(t, err)
 
 	expectedFlow := []stages.SyncStage{
@@ -327,7 +328,7 @@ func TestUnwind(t *testing.T) {
 	flow = flow[:0]
 	state.unwindOrder = []*Stage{s[3], s[2], s[1], s[0]}
 	state.UnwindTo(100, libcommon.Hash{})
-	err = state.Run(db, tx, true /* initialCycle */)
+	err = state.Run(context.Background(), db, tx, true /* initialCycle */)
 	assert.NoError(t, err)
 
 	expectedFlow = []stages.SyncStage{
@@ -389,7 +390,7 @@ func TestUnwindEmptyUnwinder(t *testing.T) {
 	}
 	state := New(s, []stages.SyncStage{s[2].ID, s[1].ID, s[0].ID}, nil, log.New())
 	db, tx := memdb.NewTestTx(t)
-	err := state.Run(db, tx, true /* initialCycle */)
+	err := state.Run(context.Background(), db, tx, true /* initialCycle */)
 	assert.NoError(t, err)
 
 	expectedFlow := []stages.SyncStage{
@@ -445,11 +446,11 @@ func TestSyncDoTwice(t *testing.T) {
 
 	state := New(s, nil, nil, log.New())
 	db, tx := memdb.NewTestTx(t)
-	err := state.Run(db, tx, true /* initialCycle */)
+	err := state.Run(context.Background(), db, tx, true /* initialCycle */)
 	assert.NoError(t, err)
 
 	state = New(s, nil, nil, log.New())
-	err = state.Run(db, tx, true /* initialCycle */)
+	err = state.Run(context.Background(), db, tx, true /* initialCycle */)
 	assert.NoError(t, err)
 
 	expectedFlow := []stages.SyncStage{
@@ -503,13 +504,13 @@ func TestStateSyncInterruptRestart(t *testing.T) {
 
 	state := New(s, nil, nil, log.New())
 	db, tx := memdb.NewTestTx(t)
-	err := state.Run(db, tx, true /* initialCycle */)
+	err := state.Run(context.Background(), db, tx, true /* initialCycle */)
 	assert.Equal(t, fmt.Errorf("[2/3 Bodies] %w", expectedErr), err)
 
 	expectedErr = nil
 
 	state = New(s, nil, nil, log.New())
-	err = state.Run(db, tx, true /* initialCycle */)
+	err = state.Run(context.Background(), db, tx, true /* initialCycle */)
 	assert.NoError(t, err)
 
 	expectedFlow := []stages.SyncStage{
@@ -582,7 +583,7 @@ func TestSyncInterruptLongUnwind(t *testing.T) {
 	}
 	state := New(s, []stages.SyncStage{s[2].ID, s[1].ID, s[0].ID}, nil, log.New())
 	db, tx := memdb.NewTestTx(t)
-	err := state.Run(db, tx, true /* initialCycle */)
+	err := state.Run(context.Background(), db, tx, true /* initialCycle */)
 	assert.Error(t, errInterrupted, err)
 
 	//state = NewState(s)
@@ -590,7 +591,7 @@ func TestSyncInterruptLongUnwind(t *testing.T) {
 	//err = state.LoadUnwindInfo(tx)
 	//assert.NoError(t, err)
 	//state.UnwindTo(500, libcommon.Hash{})
-	err = state.Run(db, tx, true /* initialCycle */)
+	err = state.Run(context.Background(), db, tx, true /* initialCycle */)
 	assert.NoError(t, err)
 
 	expectedFlow := []stages.SyncStage{
@@ -3,6 +3,8 @@ package tracers
 import (
 	"encoding/json"
 
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
 	"github.com/ledgerwatch/erigon/eth/tracers/logger"
 	"github.com/ledgerwatch/erigon/turbo/adapter/ethapi"
 )
@@ -16,7 +18,14 @@ type TraceConfig struct {
 	Reexec         *uint64
 	NoRefunds      *bool // Turns off gas refunds when tracing
 	StateOverrides *ethapi.StateOverrides
+	BlockOverrides *ethapi.BlockOverrides
 
 	BorTraceEnabled *bool
 	BorTx           *bool
 }
+
+// StdTraceConfig holds extra parameters to the standard-json trace functions.
+type StdTraceConfig struct {
+	*logger.LogConfig
+	TxHash libcommon.Hash `json:"txHash"`
+}
@@ -0,0 +1,186 @@
+package tracetest
+
+import (
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/holiman/uint256"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/common"
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+	"github.com/ledgerwatch/erigon/tests"
+	"github.com/ledgerwatch/erigon/turbo/stages"
+)
+
+// flatCallTrace is the result of a flatCallTracer run.
+type flatCallTrace struct {
+	Action struct {
+		CallType       string             `json:"callType"`
+		From           *libcommon.Address `json:"from"`
+		To             *libcommon.Address `json:"to"`
+		SelfDestructed *libcommon.Address `json:"address"`
+		Gas            *hexutil.Uint64    `json:"gas"`
+	} `json:"action"`
+	BlockNumber uint64 `json:"blockNumber"`
+	Error       string `json:"error"`
+	Result      *struct {
+		GasUsed *hexutil.Uint64 `json:"gasUsed"`
+	} `json:"result"`
+	Subtraces           int             `json:"subtraces"`
+	TraceAddress        []int           `json:"traceAddress"`
+	TransactionHash     *libcommon.Hash `json:"transactionHash"`
+	TransactionPosition uint64          `json:"transactionPosition"`
+	Type                string          `json:"type"`
+}
+
+// TestFlatCallTracerNative checks that the flat traces are exactly the nested
+// frames of the call_tracer test suite, visited in depth-first order.
+func TestFlatCallTracerNative(t *testing.T) {
+	files, err := os.ReadDir(filepath.Join("testdata", "call_tracer"))
+	if err != nil {
+		t.Fatalf("failed to retrieve tracer test suite: %v", err)
+	}
+	for _, file := range files {
+		if !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+		file := file // capture range variable
+		t.Run(camel(strings.TrimSuffix(file.Name(), ".json")), func(t *testing.T) {
+			t.Parallel()
+
+			test := new(callTracerTest)
+			blob, err := os.ReadFile(filepath.Join("testdata", "call_tracer", file.Name()))
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(blob, test))
+			if len(test.TracerConfig) > 0 {
+				t.Skip("expected result depends on the callTracer config")
+			}
+
+			txCtx := &tracers.Context{BlockNumber: uint64(test.Context.Number), TxHash: libcommon.HexToHash("0x01"), TxIndex: 3}
+			res := runTracer(t, "flatCallTracer", test, txCtx, json.RawMessage(`{"includePrecompiles": true}`))
+			var flat []flatCallTrace
+			require.NoError(t, json.Unmarshal(res, &flat))
+
+			var nested []callTrace
+			var addresses [][]int
+			var walk func(call callTrace, address []int)
+			walk = func(call callTrace, address []int) {
+				nested = append(nested, call)
+				addresses = append(addresses, address)
+				for i, child := range call.Calls {
+					walk(child, append(append([]int{}, address...), i))
+				}
+			}
+			walk(*test.Result, []int{})
+			require.Len(t, flat, len(nested))
+
+			for i, frame := range flat {
+				call := nested[i]
+				require.Equal(t, addresses[i], frame.TraceAddress, "frame %d", i)
+				require.Equal(t, len(call.Calls), frame.Subtraces, "frame %d", i)
+				require.Equal(t, call.Error, frame.Error, "frame %d", i)
+				require.Equal(t, uint64(test.Context.Number), frame.BlockNumber)
+				require.Equal(t, txCtx.TxHash, *frame.TransactionHash)
+				require.Equal(t, uint64(txCtx.TxIndex), frame.TransactionPosition)
+				switch call.Type {
+				case "CREATE", "CREATE2":
+					require.Equal(t, "create", frame.Type, "frame %d", i)
+					require.Equal(t, call.From, *frame.Action.From, "frame %d", i)
+				case "SELFDESTRUCT":
+					require.Equal(t, "suicide", frame.Type, "frame %d", i)
+					require.Equal(t, call.From, *frame.Action.SelfDestructed, "frame %d", i)
+				default:
+					require.Equal(t, "call", frame.Type, "frame %d", i)
+					require.Equal(t, strings.ToLower(call.Type), frame.Action.CallType, "frame %d", i)
+					require.Equal(t, call.From, *frame.Action.From, "frame %d", i)
+					require.Equal(t, call.To, *frame.Action.To, "frame %d", i)
+				}
+				// Unlike callTracer, the top frame excludes the intrinsic gas and refunds, as in Parity
+				if frame.Result != nil && i > 0 {
+					require.Equal(t, *call.GasUsed, *frame.Result.GasUsed, "frame %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestFlatCallTracerParityErrors(t *testing.T) {
+	test := new(callTracerTest)
+	blob, err := os.ReadFile(filepath.Join("testdata", "call_tracer", "inner_throw_outer_revert.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(blob, test))
+
+	res := runTracer(t, "flatCallTracer", test, new(tracers.Context), json.RawMessage(`{"convertParityErrors": true}`))
+	var flat []flatCallTrace
+	require.NoError(t, json.Unmarshal(res, &flat))
+	require.NotEmpty(t, flat)
+	require.Equal(t, "Reverted", flat[0].Error)
+	for _, frame := range flat[1:] {
+		require.NotContains(t, []string{"execution reverted", "out of gas"}, frame.Error)
+	}
+}
+
+func TestFlatCallTracerInMux(t *testing.T) {
+	test := new(callTracerTest)
+	blob, err := os.ReadFile(filepath.Join("testdata", "call_tracer", "deep_calls.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(blob, test))
+
+	flat := runTracer(t, "flatCallTracer", test, new(tracers.Context), nil)
+	res := runTracer(t, "muxTracer", test, new(tracers.Context), json.RawMessage(`{"flatCallTracer": {}, "callTracer": {}}`))
+	var mux map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(res, &mux))
+	require.JSONEq(t, string(flat), string(mux["flatCallTracer"]))
+}
+
+func runTracer(t *testing.T, tracerName string, test *callTracerTest, txCtx *tracers.Context, cfg json.RawMessage) json.RawMessage {
+	tx, err := types.UnmarshalTransactionFromBinary(common.FromHex(test.Input))
+	require.NoError(t, err)
+	var (
+		signer    = types.MakeSigner(test.Genesis.Config, uint64(test.Context.Number), uint64(test.Context.Time))
+		origin, _ = signer.Sender(tx)
+		txContext = evmtypes.TxContext{
+			Origin:   origin,
+			GasPrice: tx.GetPrice(),
+		}
+		context = evmtypes.BlockContext{
+			CanTransfer: core.CanTransfer,
+			Transfer:    core.Transfer,
+			Coinbase:    test.Context.Miner,
+			BlockNumber: uint64(test.Context.Number),
+			Time:        uint64(test.Context.Time),
+			Difficulty:  (*big.Int)(test.Context.Difficulty),
+			GasLimit:    uint64(test.Context.GasLimit),
+		}
+		rules = test.Genesis.Config.Rules(context.BlockNumber, context.Time)
+	)
+	m := stages.Mock(t)
+	dbTx, err := m.DB.BeginRw(m.Ctx)
+	require.NoError(t, err)
+	defer dbTx.Rollback()
+	statedb, _ := tests.MakePreState(rules, dbTx, test.Genesis.Alloc, uint64(test.Context.Number))
+	if test.Genesis.BaseFee != nil {
+		context.BaseFee, _ = uint256.FromBig(test.Genesis.BaseFee)
+	}
+	tracer, err := tracers.New(tracerName, txCtx, cfg)
+	require.NoError(t, err)
+	evm := vm.NewEVM(context, txContext, statedb, test.Genesis.Config, vm.Config{Debug: true, Tracer: tracer})
+	msg, err := tx.AsMessage(*signer, test.Genesis.BaseFee, rules)
+	require.NoError(t, err)
+	_, err = core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(tx.GetGas()).AddDataGas(tx.GetDataGas()), true /* refunds */, false /* gasBailout */)
+	require.NoError(t, err)
+	res, err := tracer.GetResult()
+	require.NoError(t, err)
+	return res
+}
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"net"
 
-	"github.com/ledgerwatch/erigon-lib/gointerfaces/grpcutil"
 	"github.com/ledgerwatch/erigon-lib/gointerfaces/remote"
 	txpool_proto "github.com/ledgerwatch/erigon-lib/gointerfaces/txpool"
 	"github.com/ledgerwatch/erigon-lib/kv/remotedbserver"
@@ -13,10 +12,14 @@ import (
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/health"
 	"google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/ledgerwatch/erigon/p2p/peeradmin"
+	"github.com/ledgerwatch/erigon/turbo/grpcutil"
+	"github.com/ledgerwatch/erigon/turbo/tracing"
 )
 
 func StartGrpc(kv *remotedbserver.KvServer, ethBackendSrv *EthBackendServer, txPoolServer txpool_proto.TxpoolServer,
-	miningServer txpool_proto.MiningServer, addr string, rateLimit uint32, creds credentials.TransportCredentials,
+	miningServer txpool_proto.MiningServer, peerAdminServer peeradmin.PeerAdminServer, addr string, rateLimit uint32, creds credentials.TransportCredentials,
 	healthCheck bool, logger log.Logger) (*grpc.Server, error) {
 	logger.Info("Starting private RPC server", "on", addr)
 	lis, err := net.Listen("tcp", addr)
@@ -24,7 +27,11 @@ func StartGrpc(kv *remotedbserver.KvServer, ethBackendSrv *EthBackendServer, txP
 		return nil, fmt.Errorf("could not create listener: %w, addr=%s", err, addr)
 	}
 
-	grpcServer := grpcutil.NewServer(rateLimit, creds)
+	// The spans of the calls continue the traces of the clients
+	grpcServer := grpcutil.NewServer(rateLimit, creds,
+		grpcutil.WithStreamInterceptors(tracing.StreamServerInterceptor()),
+		grpcutil.WithUnaryInterceptors(tracing.UnaryServerInterceptor()),
+	)
 	remote.RegisterETHBACKENDServer(grpcServer, ethBackendSrv)
 	if txPoolServer != nil {
 		txpool_proto.RegisterTxpoolServer(grpcServer, txPoolServer)
@@ -32,6 +39,9 @@ func StartGrpc(kv *remotedbserver.KvServer, ethBackendSrv *EthBackendServer, txP
 	if miningServer != nil {
 		txpool_proto.RegisterMiningServer(grpcServer, miningServer)
 	}
+	if peerAdminServer != nil {
+		peeradmin.RegisterPeerAdminServer(grpcServer, peerAdminServer)
+	}
 	remote.RegisterKVServer(grpcServer, kv)
 	var healthServer *health.Server
 	if healthCheck {
@@ -0,0 +1,102 @@
+package peeradmin
+
+import (
+	"context"
+	"io"
+
+	proto_sentry "github.com/ledgerwatch/erigon-lib/gointerfaces/sentry"
+	"google.golang.org/grpc"
+)
+
+// The generated code is produced from peeradmin.proto, its p2psentry/sentry.proto import is the one of erigon-lib:
+//
+//	protoc --proto_path=. --proto_path=<erigon-lib>/interfaces \
+//		--go_out=paths=source_relative:. --go-grpc_out=paths=source_relative:. \
+//		--go_opt=Mp2psentry/sentry.proto=github.com/ledgerwatch/erigon-lib/gointerfaces/sentry \
+//		--go-grpc_opt=Mp2psentry/sentry.proto=github.com/ledgerwatch/erigon-lib/gointerfaces/sentry \
+//		peeradmin/peeradmin.proto
+
+// PeerAdminClientDirect calls a PeerAdminServer of the same process
+type PeerAdminClientDirect struct {
+	server PeerAdminServer
+}
+
+func NewPeerAdminClientDirect(server PeerAdminServer) *PeerAdminClientDirect {
+	return &PeerAdminClientDirect{server: server}
+}
+
+func (c *PeerAdminClientDirect) AddPeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*PeerReply, error) {
+	return c.server.AddPeer(ctx, in)
+}
+
+func (c *PeerAdminClientDirect) RemovePeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*PeerReply, error) {
+	return c.server.RemovePeer(ctx, in)
+}
+
+func (c *PeerAdminClientDirect) AddTrustedPeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*PeerReply, error) {
+	return c.server.AddTrustedPeer(ctx, in)
+}
+
+func (c *PeerAdminClientDirect) RemoveTrustedPeer(ctx context.Context, in *PeerRequest, opts ...grpc.CallOption) (*PeerReply, error) {
+	return c.server.RemoveTrustedPeer(ctx, in)
+}
+
+func (c *PeerAdminClientDirect) PeerEvents(ctx context.Context, in *proto_sentry.PeerEventsRequest, opts ...grpc.CallOption) (PeerAdmin_PeerEventsClient, error) {
+	ch := make(chan *peerEventReply, 16384)
+	streamServer := &peerEventsStreamS{ch: ch, ctx: ctx}
+	go func() {
+		defer close(ch)
+		streamServer.Err(c.server.PeerEvents(in, streamServer))
+	}()
+	return &peerEventsStreamC{ch: ch, ctx: ctx}, nil
+}
+
+type peerEventReply struct {
+	r   *proto_sentry.PeerEvent
+	err error
+}
+
+// peerEventsStreamS implements PeerAdmin_PeerEventsServer
+type peerEventsStreamS struct {
+	ch  chan *peerEventReply
+	ctx context.Context
+	grpc.ServerStream
+}
+
+func (s *peerEventsStreamS) Send(m *proto_sentry.PeerEvent) error {
+	select {
+	case s.ch <- &peerEventReply{r: m}:
+		return nil
+	case <-s.ctx.Done():
+		return s.ctx.Err()
+	}
+}
+
+func (s *peerEventsStreamS) Context() context.Context { return s.ctx }
+
+func (s *peerEventsStreamS) Err(err error) {
+	if err == nil {
+		return
+	}
+	select {
+	case s.ch <- &peerEventReply{err: err}:
+	case <-s.ctx.Done():
+	}
+}
+
+// peerEventsStreamC implements PeerAdmin_PeerEventsClient
+type peerEventsStreamC struct {
+	ch  chan *peerEventReply
+	ctx context.Context
+	grpc.ClientStream
+}
+
+func (c *peerEventsStreamC) Recv() (*proto_sentry.PeerEvent, error) {
+	m, ok := <-c.ch
+	if !ok || m == nil {
+		return nil, io.EOF
+	}
+	return m.r, m.err
+}
+
+func (c *peerEventsStreamC) Context() context.Context { return c.ctx }
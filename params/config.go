@@ -48,6 +48,29 @@ func readChainSpec(filename string) *chain.Config {
 	return spec
 }
 
+// borParallelUniverseSpec is the part of a Bor chain spec not covered by
+// chain.BorConfig of the pinned erigon-lib. It goes away once
+// chain.BorConfig has the parallelUniverseBlock field.
+type borParallelUniverseSpec struct {
+	ChainName string
+	Bor       struct {
+		ParallelUniverseBlock *big.Int `json:"parallelUniverseBlock"` // Block-STM metadata switch block (nil = no fork, 0 = already on parallel universe)
+	} `json:"bor"`
+}
+
+func readBorParallelUniverseBlock(filename string) (string, *big.Int) {
+	f, err := chainspecs.Open(filename)
+	if err != nil {
+		panic(fmt.Sprintf("Could not open chainspec for %s: %v", filename, err))
+	}
+	defer f.Close()
+	spec := &borParallelUniverseSpec{}
+	if err := json.NewDecoder(f).Decode(spec); err != nil {
+		panic(fmt.Sprintf("Could not parse chainspec for %s: %v", filename, err))
+	}
+	return spec.ChainName, spec.Bor.ParallelUniverseBlock
+}
+
 // Genesis hashes to enforce below configs on.
 var (
 	MainnetGenesisHash    = libcommon.HexToHash("0xd4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3")
@@ -122,6 +145,18 @@ var (
 
 	BorDevnetChainConfig = readChainSpec("chainspecs/bor-devnet.json")
 
+	// borParallelUniverseBlocks holds the "bor.parallelUniverseBlock" of the
+	// Bor chain specs by chain name
+	borParallelUniverseBlocks = func() map[string]*big.Int {
+		blocks := map[string]*big.Int{}
+		for _, filename := range []string{"chainspecs/mumbai.json", "chainspecs/bor-mainnet.json", "chainspecs/bor-devnet.json"} {
+			if name, block := readBorParallelUniverseBlock(filename); block != nil {
+				blocks[name] = block
+			}
+		}
+		return blocks
+	}()
+
 	GnosisChainConfig = readChainSpec("chainspecs/gnosis.json")
 
 	ChiadoChainConfig = readChainSpec("chainspecs/chiado.json")
@@ -209,6 +244,14 @@ func ChainConfigByChainName(chain string) *chain.Config {
 	}
 }
 
+// BorParallelUniverseBlock returns the block from which the headers of the
+// given Bor chain carry Block-STM dependencies, nil if the fork isn't
+// scheduled. It's a chain rule like any other fork block, but chain.BorConfig
+// has no field for it, so it's read from the chain spec separately.
+func BorParallelUniverseBlock(chainName string) *big.Int {
+	return borParallelUniverseBlocks[chainName]
+}
+
 func GenesisHashByChainName(chain string) *libcommon.Hash {
 	switch chain {
 	case networkname.MainnetChainName:
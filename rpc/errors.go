@@ -25,6 +25,7 @@ var (
 	_ Error = new(invalidRequestError)
 	_ Error = new(invalidMessageError)
 	_ Error = new(InvalidParamsError)
+	_ Error = new(limitExceededError)
 	_ Error = new(CustomError)
 )
 
@@ -74,6 +75,13 @@ func (e *InvalidParamsError) ErrorCode() int { return -32602 }
 
 func (e *InvalidParamsError) Error() string { return e.Message }
 
+// a rate limit or a concurrency cap of the server is exceeded
+type limitExceededError struct{ message string }
+
+func (e *limitExceededError) ErrorCode() int { return -32005 }
+
+func (e *limitExceededError) Error() string { return e.message }
+
 type CustomError struct {
 	Code    int
 	Message string
@@ -29,6 +29,9 @@ import (
 
 	jsoniter "github.com/json-iterator/go"
 	"github.com/ledgerwatch/log/v3"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // handler handles JSON-RPC messages. There is one handler per connection. Note that
@@ -66,6 +69,7 @@ type handler struct {
 
 	allowList     AllowList // a list of explicitly allowed methods, if empty -- everything is allowed
 	forbiddenList ForbiddenList
+	rateLimiter   *rateLimiter // limits of the calls, nil if there are none
 
 	subLock             sync.Mutex
 	serverSubs          map[ID]*Subscription
@@ -424,6 +428,14 @@ func (h *handler) isMethodAllowedByGranularControl(method string) bool {
 
 // handleCall processes method calls.
 func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage, stream *jsoniter.Stream) *jsonrpcMessage {
+	if !msg.isUnsubscribe() {
+		release, err := h.rateLimiter.acquire(rateLimitOrigin(h.conn), msg.Method)
+		if err != nil {
+			limitedRequestGauge.Inc()
+			return msg.errorResponse(err)
+		}
+		defer release()
+	}
 	if msg.isSubscribe() {
 		return h.handleSubscribe(cp, msg, stream)
 	}
@@ -441,7 +453,14 @@ func (h *handler) handleCall(cp *callProc, msg *jsonrpcMessage, stream *jsoniter
 		return msg.errorResponse(&InvalidParamsError{err.Error()})
 	}
 	start := time.Now()
-	answer := h.runMethod(cp.ctx, msg, callb, args, stream)
+	ctx, span := tracer.Start(cp.ctx, msg.Method, trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(semconv.RPCSystemKey.String("jsonrpc"), semconv.RPCMethod(msg.Method)))
+	answer := h.runMethod(ctx, msg, callb, args, stream)
+	if answer != nil && answer.Error != nil {
+		span.SetStatus(codes.Error, answer.Error.Message)
+		span.SetAttributes(semconv.RPCJsonrpcErrorCode(answer.Error.Code))
+	}
+	span.End()
 
 	// Collect the statistics for RPC calls if metrics is enabled.
 	// We only care about pure rpc call. Filter out subscription.
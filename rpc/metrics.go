@@ -20,11 +20,15 @@ import (
 	"fmt"
 
 	"github.com/VictoriaMetrics/metrics"
+	"go.opentelemetry.io/otel"
 )
 
 var (
-	rpcRequestGauge    = metrics.GetOrCreateCounter("rpc_total")
-	failedReqeustGauge = metrics.GetOrCreateCounter("rpc_failure")
+	rpcRequestGauge     = metrics.GetOrCreateCounter("rpc_total")
+	failedReqeustGauge  = metrics.GetOrCreateCounter("rpc_failure")
+	limitedRequestGauge = metrics.GetOrCreateCounter("rpc_limited")
+
+	tracer = otel.Tracer("github.com/ledgerwatch/erigon/rpc") // spans of the served calls
 )
 
 func newRPCServingTimerMS(method string, valid bool) *metrics.Summary {
@@ -0,0 +1,320 @@
+package rpc
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"golang.org/x/time/rate"
+)
+
+// maxRateLimitedOrigins is the number of origins whose token buckets are kept, the least recently seen origins
+// start again with full buckets
+const maxRateLimitedOrigins = 10_000
+
+// RateLimits is the policy limiting the calls handled by a server. It is read from a JSON file like the allow list:
+//
+//	{
+//	  "costs": {"eth_getLogs": 20, "trace": 50},
+//	  "origin": {"rate": 100, "burst": 200},
+//	  "methods": {"eth_getLogs": {"rate": 5, "burst": 40}},
+//	  "concurrency": {"debug": 2, "trace_filter": 4},
+//	  "originHeader": "X-Forwarded-For",
+//	  "trustedProxies": ["10.0.0.0/8"]
+//	}
+//
+// The keys of all the maps are either method names or namespaces, a method name takes precedence over its
+// namespace. Every call spends the cost of its method (1 by default) from the token bucket of its origin, which is
+// the remote host of the connection, and from the bucket of its method for that origin, if there is one. The rates
+// are in tokens per second, and the bursts must not be lower than the costs. The concurrency caps limit the calls
+// of a method or namespace which run at the same time, for all origins. A call exceeding any limit fails with the
+// error code -32005.
+//
+// Behind a reverse proxy all the calls come from the host of the proxy. If OriginHeader is set, the origin of an
+// HTTP or Websocket connection from one of the TrustedProxies (IP addresses or CIDR ranges) is read from that header
+// instead: the last address of the header which isn't a trusted proxy. The header is ignored for the connections
+// from other hosts, as they could put anything in it.
+type RateLimits struct {
+	Costs          map[string]int         `json:"costs"`
+	Origin         *TokenBucket           `json:"origin"`
+	Methods        map[string]TokenBucket `json:"methods"`
+	Concurrency    map[string]int         `json:"concurrency"`
+	OriginHeader   string                 `json:"originHeader"`
+	TrustedProxies []string               `json:"trustedProxies"`
+}
+
+// TokenBucket is refilled with Rate tokens per second up to Burst tokens
+type TokenBucket struct {
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+func (b TokenBucket) limiter() *rate.Limiter {
+	return rate.NewLimiter(rate.Limit(b.Rate), b.Burst)
+}
+
+// lookup returns the key of the map which applies to the method: the method itself or its namespace
+func lookup[V any](m map[string]V, method string) (string, V, bool) {
+	if v, ok := m[method]; ok {
+		return method, v, true
+	}
+	if i := strings.IndexByte(method, '_'); i > 0 {
+		if v, ok := m[method[:i]]; ok {
+			return method[:i], v, true
+		}
+	}
+	var v V
+	return "", v, false
+}
+
+// rateLimiter enforces RateLimits. It is shared by all the handlers of a server, a nil rateLimiter doesn't limit
+// anything.
+type rateLimiter struct {
+	limits     RateLimits
+	origins    *lru.Cache[string, *originBuckets]
+	semaphores map[string]chan struct{}
+	proxies    []*net.IPNet
+}
+
+// originBuckets are the token buckets of an origin
+type originBuckets struct {
+	lock    sync.Mutex
+	origin  *rate.Limiter
+	methods map[string]*rate.Limiter
+}
+
+func newRateLimiter(limits RateLimits) (*rateLimiter, error) {
+	for key, cost := range limits.Costs {
+		if cost < 0 {
+			return nil, fmt.Errorf("negative cost %d of %s", cost, key)
+		}
+	}
+	// A bucket whose burst is lower than the cost of a call would reject that call forever
+	if limits.Origin != nil {
+		if key, cost := maxCost(limits.Costs, ""); limits.Origin.Burst < cost {
+			return nil, fmt.Errorf("burst %d of the origin bucket is lower than the cost %d of %s", limits.Origin.Burst, cost, key)
+		}
+	}
+	for bucketKey, bucket := range limits.Methods {
+		if key, cost := maxCost(limits.Costs, bucketKey); bucket.Burst < cost {
+			return nil, fmt.Errorf("burst %d of the bucket of %s is lower than the cost %d of %s", bucket.Burst, bucketKey, cost, key)
+		}
+	}
+	proxies, err := parseTrustedProxies(limits.TrustedProxies)
+	if err != nil {
+		return nil, err
+	}
+	if limits.OriginHeader != "" && len(proxies) == 0 {
+		return nil, fmt.Errorf("origin header %s needs trusted proxies", limits.OriginHeader)
+	}
+	origins, err := lru.New[string, *originBuckets](maxRateLimitedOrigins)
+	if err != nil {
+		return nil, err
+	}
+	semaphores := make(map[string]chan struct{}, len(limits.Concurrency))
+	for key, max := range limits.Concurrency {
+		if max <= 0 {
+			return nil, fmt.Errorf("concurrency cap of %s must be positive, got %d", key, max)
+		}
+		semaphores[key] = make(chan struct{}, max)
+	}
+	return &rateLimiter{limits: limits, origins: origins, semaphores: semaphores, proxies: proxies}, nil
+}
+
+// maxCost returns the highest cost of the calls spending from the bucket of the method or namespace, and the key of
+// that cost. An empty bucket key stands for the origin bucket, which every call spends from.
+func maxCost(costs map[string]int, bucketKey string) (string, int) {
+	key, max := "a call without a cost", 1
+	if bucketKey != "" {
+		// The calls of the bucket take the cost of the bucket key itself, or of its namespace, or the default one
+		if costKey, cost, ok := lookup(costs, bucketKey); ok {
+			key, max = costKey, cost
+		}
+	}
+	for costKey, cost := range costs {
+		if cost <= max {
+			continue
+		}
+		if bucketKey == "" || costKey == bucketKey || strings.HasPrefix(costKey, bucketKey+"_") {
+			key, max = costKey, cost
+		}
+	}
+	return key, max
+}
+
+// parseTrustedProxies parses IP addresses and CIDR ranges
+func parseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if !strings.Contains(proxy, "/") {
+			ip := net.ParseIP(proxy)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %s", proxy)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %s: %w", proxy, err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// originOf returns the host of the remote address of a connection
+func originOf(remoteAddr string) string {
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		return host
+	}
+	return remoteAddr
+}
+
+func (l *rateLimiter) trusted(host string) bool {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+	for _, proxy := range l.proxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedOrigin returns the origin of the HTTP request read from the origin header, if the request comes from a
+// trusted proxy and has the header
+func (l *rateLimiter) forwardedOrigin(r *http.Request) (string, bool) {
+	if l == nil || l.limits.OriginHeader == "" || !l.trusted(originOf(r.RemoteAddr)) {
+		return "", false
+	}
+	var hops []string
+	for _, value := range r.Header.Values(l.limits.OriginHeader) {
+		for _, hop := range strings.Split(value, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				hops = append(hops, originOf(hop))
+			}
+		}
+	}
+	if len(hops) == 0 {
+		return "", false
+	}
+	// Every proxy appends the address it got the request from, so the last untrusted one is the client
+	for i := len(hops) - 1; i > 0; i-- {
+		if !l.trusted(hops[i]) {
+			return hops[i], true
+		}
+	}
+	return hops[0], true
+}
+
+// originCodec is a codec whose calls are rate limited as the calls of origin rather than of its remote address
+type originCodec struct {
+	ServerCodec
+	origin string
+}
+
+// withForwardedOrigin wraps the codec of the HTTP request in an originCodec if the request has a forwarded origin
+func (l *rateLimiter) withForwardedOrigin(codec ServerCodec, r *http.Request) ServerCodec {
+	if origin, ok := l.forwardedOrigin(r); ok {
+		return &originCodec{ServerCodec: codec, origin: origin}
+	}
+	return codec
+}
+
+// rateLimitOrigin returns the origin whose buckets the calls of the connection spend from
+func rateLimitOrigin(conn jsonWriter) string {
+	if c, ok := conn.(*originCodec); ok {
+		return c.origin
+	}
+	return originOf(conn.remoteAddr())
+}
+
+// acquire checks the limits for a call of the method from the origin. If the call is allowed, the returned function
+// has to be called when the call completes.
+func (l *rateLimiter) acquire(origin, method string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	release := func() {}
+	if key, sem, ok := lookup(l.semaphores, method); ok {
+		select {
+		case sem <- struct{}{}:
+			release = func() { <-sem }
+		default:
+			return nil, &limitExceededError{fmt.Sprintf("too many concurrent calls of %s, the limit is %d", key, cap(sem))}
+		}
+	}
+	if err := l.spend(origin, method); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// spend takes the cost of the method from the buckets of the origin, either from all of them or from none
+func (l *rateLimiter) spend(origin, method string) error {
+	cost := 1
+	if _, c, ok := lookup(l.limits.Costs, method); ok {
+		cost = c
+	}
+	methodKey, methodBucket, hasMethodBucket := lookup(l.limits.Methods, method)
+	if cost == 0 || (l.limits.Origin == nil && !hasMethodBucket) {
+		return nil
+	}
+
+	buckets, ok := l.origins.Get(origin)
+	if !ok {
+		buckets = &originBuckets{methods: map[string]*rate.Limiter{}}
+		if l.limits.Origin != nil {
+			buckets.origin = l.limits.Origin.limiter()
+		}
+		if previous, found, _ := l.origins.PeekOrAdd(origin, buckets); found {
+			buckets = previous
+		}
+	}
+	buckets.lock.Lock()
+	defer buckets.lock.Unlock()
+
+	now := time.Now()
+	var reservations []*rate.Reservation
+	cancel := func() {
+		for _, r := range reservations {
+			r.CancelAt(now)
+		}
+	}
+	reserve := func(limiter *rate.Limiter) bool {
+		r := limiter.ReserveN(now, cost)
+		if !r.OK() {
+			return false
+		}
+		reservations = append(reservations, r)
+		return r.DelayFrom(now) == 0
+	}
+	if buckets.origin != nil && !reserve(buckets.origin) {
+		cancel()
+		return &limitExceededError{fmt.Sprintf("rate limit of %s exceeded", origin)}
+	}
+	if hasMethodBucket {
+		limiter, ok := buckets.methods[methodKey]
+		if !ok {
+			limiter = methodBucket.limiter()
+			buckets.methods[methodKey] = limiter
+		}
+		if !reserve(limiter) {
+			cancel()
+			return &limitExceededError{fmt.Sprintf("rate limit of %s exceeded for %s", methodKey, origin)}
+		}
+	}
+	return nil
+}
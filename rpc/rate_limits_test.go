@@ -0,0 +1,127 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	var limits RateLimits
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"costs": {"test": 2, "test_echo": 5, "test_free": 0},
+		"origin": {"rate": 0.001, "burst": 20},
+		"methods": {"test_echo": {"rate": 0.001, "burst": 10}}
+	}`), &limits))
+	l, err := newRateLimiter(limits)
+	require.NoError(t, err)
+
+	call := func(origin, method string) error {
+		release, err := l.acquire(origin, method)
+		if err == nil {
+			release()
+		}
+		return err
+	}
+	// The method bucket of test_echo only has room for two calls
+	require.NoError(t, call("a", "test_echo"))
+	require.NoError(t, call("a", "test_echo"))
+	err = call("a", "test_echo")
+	require.Error(t, err)
+	require.Equal(t, -32005, err.(Error).ErrorCode())
+	// The rejected call spent nothing from the origin bucket: 10 of 20 tokens are left for the namespace cost of 2
+	for i := 0; i < 5; i++ {
+		require.NoError(t, call("a", "test_other"), "call %d", i)
+	}
+	require.Error(t, call("a", "test_other"))
+	require.NoError(t, call("a", "test_free"))
+	// Other origins have their own buckets
+	require.NoError(t, call("b", "test_echo"))
+
+	// Concurrency caps must be positive
+	_, err = newRateLimiter(RateLimits{Concurrency: map[string]int{"test": 0}})
+	require.Error(t, err)
+	var nilLimiter *rateLimiter
+	release, err := nilLimiter.acquire("a", "test_echo")
+	require.NoError(t, err)
+	release()
+}
+
+func TestRateLimitsValidation(t *testing.T) {
+	for name, limits := range map[string]string{
+		"origin burst below the default cost": `{"origin": {"rate": 1, "burst": 0}}`,
+		"origin burst below a cost":           `{"costs": {"trace": 50}, "origin": {"rate": 1, "burst": 40}}`,
+		"method burst below its cost":         `{"costs": {"eth_getLogs": 20}, "methods": {"eth_getLogs": {"rate": 1, "burst": 10}}}`,
+		"method burst below its namespace":    `{"costs": {"trace": 50}, "methods": {"trace_filter": {"rate": 1, "burst": 10}}}`,
+		"namespace burst below a method":      `{"costs": {"trace_filter": 50}, "methods": {"trace": {"rate": 1, "burst": 10}}}`,
+		"header without trusted proxies":      `{"originHeader": "X-Forwarded-For"}`,
+		"invalid trusted proxy":               `{"originHeader": "X-Forwarded-For", "trustedProxies": ["10.0.0"]}`,
+	} {
+		var l RateLimits
+		require.NoError(t, json.Unmarshal([]byte(limits), &l), name)
+		_, err := newRateLimiter(l)
+		require.Error(t, err, name)
+	}
+	var l RateLimits
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"costs": {"trace": 50, "trace_block": 10, "eth": 0},
+		"origin": {"rate": 1, "burst": 50},
+		"methods": {"trace_block": {"rate": 1, "burst": 10}, "eth": {"rate": 1, "burst": 0}}
+	}`), &l))
+	_, err := newRateLimiter(l)
+	require.NoError(t, err)
+}
+
+func TestForwardedOrigin(t *testing.T) {
+	l, err := newRateLimiter(RateLimits{OriginHeader: "X-Forwarded-For", TrustedProxies: []string{"10.0.0.0/8", "192.168.1.1"}})
+	require.NoError(t, err)
+	origin := func(remoteAddr string, forwarded ...string) string {
+		r := &http.Request{RemoteAddr: remoteAddr, Header: http.Header{}}
+		for _, value := range forwarded {
+			r.Header.Add("X-Forwarded-For", value)
+		}
+		return rateLimitOrigin(l.withForwardedOrigin(&jsonCodec{remote: remoteAddr}, r))
+	}
+	require.Equal(t, "1.2.3.4", origin("10.1.2.3:5000", "1.2.3.4"))
+	// The proxies in front of the trusted one are skipped, the addresses before the first untrusted one can be forged
+	require.Equal(t, "1.2.3.4", origin("10.1.2.3:5000", "6.6.6.6, 1.2.3.4", "192.168.1.1"))
+	require.Equal(t, "1.2.3.4", origin("192.168.1.1:5000", "10.0.0.1, 1.2.3.4:80, 10.0.0.2"))
+	require.Equal(t, "10.0.0.1", origin("10.1.2.3:5000", "10.0.0.1"))
+	// The header is ignored if the connection doesn't come from a trusted proxy or doesn't have it
+	require.Equal(t, "5.6.7.8", origin("5.6.7.8:5000", "1.2.3.4"))
+	require.Equal(t, "10.1.2.3", origin("10.1.2.3:5000"))
+
+	var nilLimiter *rateLimiter
+	r := &http.Request{RemoteAddr: "10.1.2.3:5000", Header: http.Header{"X-Forwarded-For": {"1.2.3.4"}}}
+	require.Equal(t, "10.1.2.3", rateLimitOrigin(nilLimiter.withForwardedOrigin(&jsonCodec{remote: r.RemoteAddr}, r)))
+}
+
+func TestServerConcurrencyCap(t *testing.T) {
+	logger := log.New()
+	server := newTestServer(logger)
+	defer server.Stop()
+	require.NoError(t, server.SetRateLimits(RateLimits{Concurrency: map[string]int{"test_block": 1}}))
+	client := DialInProc(server, logger)
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	blocked := make(chan error, 1)
+	go func() { blocked <- client.CallContext(ctx, nil, "test_block") }()
+
+	// The second call is rejected while the first one is running
+	var rpcErr Error
+	require.Eventually(t, func() bool {
+		err := client.Call(nil, "test_block")
+		return errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32005
+	}, 5*time.Second, 10*time.Millisecond)
+	var res echoResult
+	require.NoError(t, client.Call(&res, "test_echo", "x", 1, &echoArgs{"y"}))
+	cancel()
+	<-blocked
+}
@@ -54,6 +54,7 @@ type Server struct {
 	disableStreaming bool
 	traceRequests    bool // Whether to print requests at INFO level
 	batchLimit       int  // Maximum number of requests in a batch
+	rateLimiter      *rateLimiter
 	logger           log.Logger
 }
 
@@ -78,6 +79,16 @@ func (s *Server) SetBatchLimit(limit int) {
 	s.batchLimit = limit
 }
 
+// SetRateLimits sets the rate limits and the concurrency caps of the calls handled by this server
+func (s *Server) SetRateLimits(limits RateLimits) error {
+	limiter, err := newRateLimiter(limits)
+	if err != nil {
+		return err
+	}
+	s.rateLimiter = limiter
+	return nil
+}
+
 // RegisterName creates a service for the given receiver type under the given name. When no
 // methods on the given receiver match the criteria to be either a RPC method or a
 // subscription an error is returned. Otherwise a new service is created and added to the
@@ -103,7 +114,7 @@ func (s *Server) ServeCodec(codec ServerCodec, options CodecOption) {
 	s.codecs.Add(codec)
 	defer s.codecs.Remove(codec)
 
-	c := initClient(codec, s.idgen, &s.services, s.logger)
+	c := initClient(codec, s.idgen, &s.services, s.rateLimiter, s.logger)
 	<-codec.closed()
 	c.Close()
 }
@@ -119,6 +130,7 @@ func (s *Server) serveSingleRequest(ctx context.Context, codec ServerCodec, stre
 
 	h := newHandler(ctx, codec, s.idgen, &s.services, s.methodAllowList, s.batchConcurrency, s.traceRequests, s.logger)
 	h.allowSubscribe = false
+	h.rateLimiter = s.rateLimiter
 	defer h.close(io.EOF, nil)
 
 	reqs, batch, err := codec.readBatch()
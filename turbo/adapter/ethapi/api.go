@@ -166,6 +166,8 @@ type Account struct {
 	Balance   **hexutil.Big                   `json:"balance"`
 	State     *map[libcommon.Hash]uint256.Int `json:"state"`
 	StateDiff *map[libcommon.Hash]uint256.Int `json:"stateDiff"`
+	// MovePrecompileTo relocates the precompile at this address, see StateOverrides.OverridePrecompiles
+	MovePrecompileTo *libcommon.Address `json:"movePrecompileToAddress"`
 }
 
 func NewRevertError(result *core.ExecutionResult) *RevertError {
@@ -0,0 +1,70 @@
+package ethapi
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/holiman/uint256"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/common/hexutil"
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+)
+
+// BlockOverrides is a set of header fields to override when executing a call
+// on top of a block, e.g. to simulate it against "next block" conditions.
+type BlockOverrides struct {
+	Number      *hexutil.Big       `json:"number"`
+	Difficulty  *hexutil.Big       `json:"difficulty"`
+	Time        *hexutil.Uint64    `json:"time"`
+	GasLimit    *hexutil.Uint64    `json:"gasLimit"`
+	Coinbase    *libcommon.Address `json:"coinbase"`
+	PrevRandao  *libcommon.Hash    `json:"prevRandao"`
+	BaseFee     *hexutil.Big       `json:"baseFee"`
+	BlobBaseFee *hexutil.Big       `json:"blobBaseFee"`
+}
+
+// Override overwrites the fields of the block context with the specified ones.
+func (overrides *BlockOverrides) Override(blockCtx *evmtypes.BlockContext) error {
+	if overrides == nil {
+		return nil
+	}
+	if overrides.Number != nil {
+		number := overrides.Number.ToInt()
+		if !number.IsUint64() {
+			return fmt.Errorf("block number override %v overflows uint64", number)
+		}
+		blockCtx.BlockNumber = number.Uint64()
+	}
+	if overrides.Difficulty != nil {
+		blockCtx.Difficulty = new(big.Int).Set(overrides.Difficulty.ToInt())
+	}
+	if overrides.Time != nil {
+		blockCtx.Time = uint64(*overrides.Time)
+	}
+	if overrides.GasLimit != nil {
+		blockCtx.GasLimit = uint64(*overrides.GasLimit)
+	}
+	if overrides.Coinbase != nil {
+		blockCtx.Coinbase = *overrides.Coinbase
+	}
+	if overrides.PrevRandao != nil {
+		prevRandao := *overrides.PrevRandao
+		blockCtx.PrevRanDao = &prevRandao
+	}
+	if overrides.BaseFee != nil {
+		baseFee, overflow := uint256.FromBig(overrides.BaseFee.ToInt())
+		if overflow {
+			return fmt.Errorf("base fee override %v overflows uint256", overrides.BaseFee)
+		}
+		blockCtx.BaseFee = baseFee
+	}
+	if overrides.BlobBaseFee != nil {
+		blobBaseFee, overflow := uint256.FromBig(overrides.BlobBaseFee.ToInt())
+		if overflow {
+			return fmt.Errorf("blob base fee override %v overflows uint256", overrides.BlobBaseFee)
+		}
+		blockCtx.DataGasPrice = blobBaseFee
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 
 	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/vm"
 )
 
 type StateOverrides map[libcommon.Address]Account
@@ -49,3 +50,31 @@ func (overrides *StateOverrides) Override(state *state.IntraBlockState) error {
 
 	return nil
 }
+
+// OverridePrecompiles moves the precompiled contracts which have MovePrecompileTo set to their new addresses.
+// The original addresses are left without a precompile, so their code can be overridden as well.
+func (overrides *StateOverrides) OverridePrecompiles(precompiles map[libcommon.Address]vm.PrecompiledContract) error {
+	moved := make(map[libcommon.Address]vm.PrecompiledContract)
+	for addr, account := range *overrides {
+		if account.MovePrecompileTo == nil {
+			continue
+		}
+		p, ok := precompiles[addr]
+		if !ok {
+			return fmt.Errorf("account %s is not a precompile", addr.Hex())
+		}
+		if _, ok := moved[*account.MovePrecompileTo]; ok {
+			return fmt.Errorf("account %s is already a target of another precompile move", account.MovePrecompileTo.Hex())
+		}
+		moved[*account.MovePrecompileTo] = p
+	}
+	for addr, account := range *overrides {
+		if account.MovePrecompileTo != nil {
+			delete(precompiles, addr)
+		}
+	}
+	for addr, p := range moved {
+		precompiles[addr] = p
+	}
+	return nil
+}
@@ -415,10 +415,20 @@ func doRetireCommand(cliCtx *cli.Context) error {
 	if err := snapshots.ReopenFolder(); err != nil {
 		return err
 	}
-	blockReader := freezeblocks.NewBlockReader(snapshots)
+	borSnapshots := freezeblocks.NewBorRoSnapshots(cfg, dirs.Snap, logger)
+	if err := borSnapshots.ReopenFolder(); err != nil {
+		return err
+	}
+	blockReader := freezeblocks.NewBlockReader(snapshots, borSnapshots)
 	blockWriter := blockio.NewBlockWriter(fromdb.HistV3(db))
 
-	br := freezeblocks.NewBlockRetire(estimate.CompressSnapshot.Workers(), dirs, blockReader, blockWriter, db, nil, logger)
+	var borDB kv.RwDB
+	if fromdb.ChainConfig(db).Bor != nil {
+		borDB = mdbx.NewMDBX(logger).Label(kv.ConsensusDB).Path(filepath.Join(dirs.DataDir, "bor")).MustOpen()
+		defer borDB.Close()
+	}
+
+	br := freezeblocks.NewBlockRetire(estimate.CompressSnapshot.Workers(), dirs, blockReader, blockWriter, db, borDB, nil, logger)
 	agg, err := libstate.NewAggregatorV3(ctx, dirs.SnapHistory, dirs.Tmp, ethconfig.HistoryV3AggregationStep, db, logger)
 	if err != nil {
 		return err
@@ -447,6 +457,11 @@ func doRetireCommand(cliCtx *cli.Context) error {
 		if err := br.RetireBlocks(ctx, i, i+every, log.LvlInfo, nil); err != nil {
 			panic(err)
 		}
+		if borDB != nil {
+			if err := br.RetireBorBlocks(ctx, i, i+every, log.LvlInfo); err != nil {
+				panic(err)
+			}
+		}
 		if err := db.UpdateNosync(ctx, func(tx kv.RwTx) error {
 			if err := rawdb.WriteSnapshots(tx, blockReader.FrozenFiles(), agg.Files()); err != nil {
 				return err
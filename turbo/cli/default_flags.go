@@ -46,6 +46,8 @@ var DefaultFlags = []cli.Flag{
 	&TLSCACertFlag,
 	&StateStreamDisableFlag,
 	&SyncLoopThrottleFlag,
+	&ParallelEVMEnableFlag,
+	&ParallelEVMProcsFlag,
 	&BadBlockFlag,
 
 	&utils.HTTPEnabledFlag,
@@ -68,10 +70,13 @@ var DefaultFlags = []cli.Flag{
 	&utils.RpcStreamingDisableFlag,
 	&utils.DBReadConcurrencyFlag,
 	&utils.RpcAccessListFlag,
+	&utils.RpcRateLimitsFlag,
 	&utils.RpcTraceCompatFlag,
 	&utils.RpcGasCapFlag,
 	&utils.RpcBatchLimit,
 	&utils.RpcReturnDataLimit,
+	&utils.RpcGetProofMaxRewindFlag,
+	&utils.RpcLogsReplayMaxBlocksFlag,
 	&utils.RPCGlobalTxFeeCapFlag,
 	&utils.TxpoolApiAddrFlag,
 	&utils.TraceMaxtracesFlag,
@@ -142,6 +147,7 @@ var DefaultFlags = []cli.Flag{
 	&HealthCheckFlag,
 	&utils.HeimdallURLFlag,
 	&utils.WithoutHeimdallFlag,
+	&utils.HeimdallCacheModeFlag,
 	&utils.HeimdallgRPCAddressFlag,
 	&utils.EthStatsURLFlag,
 	&utils.OverrideShanghaiTime,
@@ -153,4 +159,12 @@ var DefaultFlags = []cli.Flag{
 	&utils.LightClientDiscoveryTCPPortFlag,
 	&utils.SentinelAddrFlag,
 	&utils.SentinelPortFlag,
+	&utils.BeaconAPIFlag,
+	&utils.BeaconAPIAddrFlag,
+	&utils.BeaconAPIPortFlag,
+	&utils.BeaconAPIReadTimeoutFlag,
+	&utils.BeaconAPIWriteTimeoutFlag,
+	&utils.InternalConsensusCheckpointSyncUrlFlag,
+	&utils.InternalConsensusCheckpointSyncFileFlag,
+	&utils.InternalConsensusWeakSubjectivityCheckpointFlag,
 }
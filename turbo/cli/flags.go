@@ -147,6 +147,16 @@ var (
 		Value: "",
 	}
 
+	ParallelEVMEnableFlag = cli.BoolFlag{
+		Name:  "parallel-evm.enable",
+		Usage: "Execute Bor blocks with Block-STM in the Execution stage (falls back to serial execution on failure, not supported with --experimental.history.v3)",
+	}
+	ParallelEVMProcsFlag = cli.IntFlag{
+		Name:  "parallel-evm.procs",
+		Usage: "Number of speculative goroutines used by --parallel-evm.enable",
+		Value: 8,
+	}
+
 	BadBlockFlag = cli.StringFlag{
 		Name:  "bad.block",
 		Usage: "Marks block with given hex string as bad and forces initial reorg before normal staged sync",
@@ -254,6 +264,9 @@ func ApplyFlagsForEthConfig(ctx *cli.Context, cfg *ethconfig.Config, logger log.
 		cfg.Sync.LoopThrottle = syncLoopThrottle
 	}
 
+	cfg.Sync.ParallelEVM.Enable = ctx.Bool(ParallelEVMEnableFlag.Name)
+	cfg.Sync.ParallelEVM.SpeculativeProcesses = ctx.Int(ParallelEVMProcsFlag.Name)
+
 	if ctx.String(BadBlockFlag.Name) != "" {
 		bytes, err := hexutil.Decode(ctx.String(BadBlockFlag.Name))
 		if err != nil {
@@ -386,16 +399,19 @@ func setEmbeddedRpcDaemon(ctx *cli.Context, cfg *nodecfg.Config, logger log.Logg
 		},
 		EvmCallTimeout: ctx.Duration(EvmCallTimeoutFlag.Name),
 
-		WebsocketEnabled:     ctx.IsSet(utils.WSEnabledFlag.Name),
-		RpcBatchConcurrency:  ctx.Uint(utils.RpcBatchConcurrencyFlag.Name),
-		RpcStreamingDisable:  ctx.Bool(utils.RpcStreamingDisableFlag.Name),
-		DBReadConcurrency:    ctx.Int(utils.DBReadConcurrencyFlag.Name),
-		RpcAllowListFilePath: ctx.String(utils.RpcAccessListFlag.Name),
-		Gascap:               ctx.Uint64(utils.RpcGasCapFlag.Name),
-		MaxTraces:            ctx.Uint64(utils.TraceMaxtracesFlag.Name),
-		TraceCompatibility:   ctx.Bool(utils.RpcTraceCompatFlag.Name),
-		BatchLimit:           ctx.Int(utils.RpcBatchLimit.Name),
-		ReturnDataLimit:      ctx.Int(utils.RpcReturnDataLimit.Name),
+		WebsocketEnabled:      ctx.IsSet(utils.WSEnabledFlag.Name),
+		RpcBatchConcurrency:   ctx.Uint(utils.RpcBatchConcurrencyFlag.Name),
+		RpcStreamingDisable:   ctx.Bool(utils.RpcStreamingDisableFlag.Name),
+		DBReadConcurrency:     ctx.Int(utils.DBReadConcurrencyFlag.Name),
+		RpcAllowListFilePath:  ctx.String(utils.RpcAccessListFlag.Name),
+		RpcRateLimitsFilePath: ctx.String(utils.RpcRateLimitsFlag.Name),
+		Gascap:                ctx.Uint64(utils.RpcGasCapFlag.Name),
+		MaxTraces:             ctx.Uint64(utils.TraceMaxtracesFlag.Name),
+		TraceCompatibility:    ctx.Bool(utils.RpcTraceCompatFlag.Name),
+		BatchLimit:            ctx.Int(utils.RpcBatchLimit.Name),
+		ReturnDataLimit:       ctx.Int(utils.RpcReturnDataLimit.Name),
+		GetProofMaxRewind:     ctx.Uint64(utils.RpcGetProofMaxRewindFlag.Name),
+		LogsReplayMaxBlocks:   ctx.Uint64(utils.RpcLogsReplayMaxBlocksFlag.Name),
 
 		TxPoolApiAddr: ctx.String(utils.TxpoolApiAddrFlag.Name),
 
@@ -35,6 +35,7 @@ import (
 	"github.com/ledgerwatch/erigon/diagnostics"
 	"github.com/ledgerwatch/erigon/metrics/exp"
 	"github.com/ledgerwatch/erigon/turbo/logging"
+	"github.com/ledgerwatch/erigon/turbo/tracing"
 )
 
 var (
@@ -79,12 +80,26 @@ var (
 		Name:  "trace",
 		Usage: "Write execution trace to the given file",
 	}
+	tracingEndpointFlag = cli.StringFlag{
+		Name:  "tracing.otlp.endpoint",
+		Usage: "Export OpenTelemetry spans to the OTLP gRPC collector at the given host:port",
+	}
+	tracingInsecureFlag = cli.BoolFlag{
+		Name:  "tracing.otlp.insecure",
+		Usage: "Connect to the OTLP collector without TLS",
+	}
+	tracingSampleRatioFlag = cli.Float64Flag{
+		Name:  "tracing.sample.ratio",
+		Usage: "Ratio of the traces to sample, the traces continued from a remote parent follow its sampling decision",
+		Value: 1,
+	}
 )
 
 // Flags holds all command-line flags required for debugging.
 var Flags = []cli.Flag{
 	&pprofFlag, &pprofAddrFlag, &pprofPortFlag,
 	&cpuprofileFlag, &traceFlag,
+	&tracingEndpointFlag, &tracingInsecureFlag, &tracingSampleRatioFlag,
 }
 
 // SetupCobra sets up logging, profiling and tracing for cobra commands
@@ -119,6 +134,20 @@ func SetupCobra(cmd *cobra.Command, filePrefix string) (log.Logger, error) {
 		}
 	}
 
+	var tracingCfg tracing.Config
+	if tracingCfg.Endpoint, err = flags.GetString(tracingEndpointFlag.Name); err != nil {
+		return logger, err
+	}
+	if tracingCfg.Insecure, err = flags.GetBool(tracingInsecureFlag.Name); err != nil {
+		return logger, err
+	}
+	if tracingCfg.SampleRatio, err = flags.GetFloat64(tracingSampleRatioFlag.Name); err != nil {
+		return logger, err
+	}
+	if err := tracing.Setup(tracingCfg, filePrefix, logger); err != nil {
+		return logger, err
+	}
+
 	go ListenSignals(nil)
 	pprof, err := flags.GetBool(pprofFlag.Name)
 	if err != nil {
@@ -182,6 +211,15 @@ func Setup(ctx *cli.Context, rootLogger bool) (log.Logger, error) {
 			return logger, err
 		}
 	}
+	tracingCfg := tracing.Config{
+		Endpoint:    ctx.String(tracingEndpointFlag.Name),
+		Insecure:    ctx.Bool(tracingInsecureFlag.Name),
+		SampleRatio: ctx.Float64(tracingSampleRatioFlag.Name),
+	}
+	if err := tracing.Setup(tracingCfg, "erigon", logger); err != nil {
+		return logger, err
+	}
+
 	pprofEnabled := ctx.Bool(pprofFlag.Name)
 	metricsEnabled := ctx.Bool(metricsEnabledFlag.Name)
 	metricsAddr := ctx.String(metricsAddrFlag.Name)
@@ -236,6 +274,7 @@ func StartPProf(address string, withMetrics bool) {
 func Exit() {
 	_ = Handler.StopCPUProfile()
 	_ = Handler.StopGoTrace()
+	tracing.Shutdown()
 }
 
 // RaiseFdLimit raises out the number of allowed file handles per process
@@ -151,6 +151,11 @@ func (fv *ForkValidator) ValidatePayload(tx kv.RwTx, header *types.Header, body
 		return
 	}
 	defer fv.clean()
+	defer func() {
+		if validationError != nil && criticalError == nil {
+			fv.storeBadBlock(tx, header, body, validationError)
+		}
+	}()
 
 	// If the block is stored within the side fork it means it was already validated.
 	if _, ok := fv.sideForksBlock[header.Hash()]; ok {
@@ -302,6 +307,29 @@ func (fv *ForkValidator) validateAndStorePayload(tx kv.RwTx, header *types.Heade
 	return
 }
 
+// storeBadBlock keeps the rejected payload in the database for later inspection, e.g. with debug_getBadBlocks.
+func (fv *ForkValidator) storeBadBlock(tx kv.RwTx, header *types.Header, body *types.RawBody, validationError error) {
+	var block *types.Block
+	if body == nil {
+		bodyFromDb, err := fv.blockReader.BodyWithTransactions(context.Background(), tx, header.Hash(), header.Number.Uint64())
+		if err != nil || bodyFromDb == nil {
+			log.Warn("could not read body of bad block", "number", header.Number.Uint64(), "hash", header.Hash(), "err", err)
+			return
+		}
+		block = types.NewBlockFromStorage(header.Hash(), header, bodyFromDb.Transactions, bodyFromDb.Uncles, bodyFromDb.Withdrawals)
+	} else {
+		txs, err := types.DecodeTransactions(body.Transactions)
+		if err != nil {
+			log.Warn("could not decode transactions of bad block", "number", header.Number.Uint64(), "hash", header.Hash(), "err", err)
+			return
+		}
+		block = types.NewBlockFromStorage(header.Hash(), header, txs, body.Uncles, body.Withdrawals)
+	}
+	if err := rawdb.WriteBadBlock(tx, block, validationError.Error()); err != nil {
+		log.Warn("could not store bad block", "number", header.Number.Uint64(), "hash", header.Hash(), "err", err)
+	}
+}
+
 // clean wipes out all outdated side forks whose distance exceed the height of the head.
 func (fv *ForkValidator) clean() {
 	for hash, sb := range fv.sideForksBlock {
@@ -0,0 +1,64 @@
+// Package grpcutil extends the gRPC helpers of erigon-lib/gointerfaces/grpcutil, which can't take options at the
+// erigon-lib version this module is pinned to.
+package grpcutil
+
+import (
+	"time"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/reflection"
+)
+
+type serverOptions struct {
+	streamInterceptors []grpc.StreamServerInterceptor
+	unaryInterceptors  []grpc.UnaryServerInterceptor
+}
+
+// ServerOption configures a server created by NewServer
+type ServerOption func(*serverOptions)
+
+// WithStreamInterceptors adds interceptors of the streaming calls, they run in the given order after the panic
+// recovery
+func WithStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) ServerOption {
+	return func(o *serverOptions) {
+		o.streamInterceptors = append(o.streamInterceptors, interceptors...)
+	}
+}
+
+// WithUnaryInterceptors adds interceptors of the unary calls, they run in the given order after the panic recovery
+func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) ServerOption {
+	return func(o *serverOptions) {
+		o.unaryInterceptors = append(o.unaryInterceptors, interceptors...)
+	}
+}
+
+// NewServer is grpcutil.NewServer of erigon-lib with options. Without options, the servers are the same.
+func NewServer(rateLimit uint32, creds credentials.TransportCredentials, options ...ServerOption) *grpc.Server {
+	o := serverOptions{
+		streamInterceptors: []grpc.StreamServerInterceptor{grpc_recovery.StreamServerInterceptor()},
+		unaryInterceptors:  []grpc.UnaryServerInterceptor{grpc_recovery.UnaryServerInterceptor()},
+	}
+	for _, option := range options {
+		option(&o)
+	}
+
+	opts := []grpc.ServerOption{
+		grpc.MaxConcurrentStreams(rateLimit), // to force clients reduce concurrency level
+		// Don't drop the connection, settings according to this comment on GitHub
+		// https://github.com/grpc/grpc-go/issues/3171#issuecomment-552796779
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			MinTime:             10 * time.Second,
+			PermitWithoutStream: true,
+		}),
+		grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(o.streamInterceptors...)),
+		grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(o.unaryInterceptors...)),
+		grpc.Creds(creds),
+	}
+	grpcServer := grpc.NewServer(opts...)
+	reflection.Register(grpcServer)
+	return grpcServer
+}
@@ -131,6 +131,22 @@ func CreateHistoryStateReader(tx kv.Tx, blockNumber uint64, txnIndex int, histor
 	return r, nil
 }
 
+// CreateStateReaderAfterBlock returns a reader of the state right after the execution of the given block. Unlike
+// CreateHistoryStateReader, it doesn't need the next block to be known, so it can be used for blocks built on top of it.
+func CreateStateReaderAfterBlock(tx kv.Tx, blockNumber uint64, historyV3 bool, chainName string) (state.StateReader, error) {
+	if !historyV3 {
+		return state.NewPlainState(tx, blockNumber+1, systemcontracts.SystemContractCodeLookup[chainName]), nil
+	}
+	r := state.NewHistoryReaderV3()
+	r.SetTx(tx)
+	maxTxNum, err := rawdbv3.TxNums.Max(tx, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	r.SetTxNum(maxTxNum + 1)
+	return r, nil
+}
+
 func NewLatestStateReader(tx kv.Getter) state.StateReader {
 	if ethconfig.EnableHistoryV4InTest {
 		panic("implement me")
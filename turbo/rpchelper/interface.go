@@ -30,6 +30,11 @@ type ApiBackend interface {
 	EngineGetPayload(ctx context.Context, payloadId uint64) (*remote.EngineGetPayloadResponse, error)
 	NodeInfo(ctx context.Context, limit uint32) ([]p2p.NodeInfo, error)
 	Peers(ctx context.Context) ([]*p2p.PeerInfo, error)
+	AddPeer(ctx context.Context, url string) (bool, error)
+	RemovePeer(ctx context.Context, url string) (bool, error)
+	AddTrustedPeer(ctx context.Context, url string) (bool, error)
+	RemoveTrustedPeer(ctx context.Context, url string) (bool, error)
+	PeerEvents(ctx context.Context, cb func(*p2p.PeerEvent)) error
 	PendingBlock(ctx context.Context) (*types.Block, error)
 	EngineGetPayloadBodiesByHashV1(ctx context.Context, request *remote.EngineGetPayloadBodiesByHashV1Request) (*remote.EngineGetPayloadBodiesV1Response, error)
 	EngineGetPayloadBodiesByRangeV1(ctx context.Context, request *remote.EngineGetPayloadBodiesByRangeV1Request) (*remote.EngineGetPayloadBodiesV1Response, error)
@@ -50,6 +50,27 @@ type TxnReader interface {
 	TxnByIdxInBlock(ctx context.Context, tx kv.Getter, blockNum uint64, i int) (txn types.Transaction, err error)
 	RawTransactions(ctx context.Context, tx kv.Getter, fromBlock, toBlock uint64) (txs [][]byte, err error)
 }
+
+// BorEventReader reads the state sync events committed by Bor blocks. tx is a
+// transaction of the Bor database, which keeps the events of the blocks which
+// are not frozen yet.
+type BorEventReader interface {
+	// EventsByBlock returns the JSON list of the events committed by a block,
+	// found is false if they are neither frozen nor stored
+	EventsByBlock(ctx context.Context, tx kv.Getter, hash common.Hash, blockNum uint64) (events []byte, found bool, err error)
+}
+
+// BorSpanReader reads the Heimdall spans, see BorEventReader.
+type BorSpanReader interface {
+	// Span returns the JSON of a span, nil if it is neither frozen nor stored
+	Span(ctx context.Context, tx kv.Getter, spanID uint64) ([]byte, error)
+}
+
+type BorReader interface {
+	BorEventReader
+	BorSpanReader
+}
+
 type HeaderAndCanonicalReader interface {
 	HeaderReader
 	CanonicalReader
@@ -67,6 +88,7 @@ type FullBlockReader interface {
 	HeaderReader
 	TxnReader
 	CanonicalReader
+	BorReader
 
 	FrozenBlocks() uint64
 	FrozenFiles() (list []string)
@@ -74,6 +96,7 @@ type FullBlockReader interface {
 	CanPruneTo(currentBlockInDB uint64) (canPruneBlocksTo uint64)
 
 	Snapshots() BlockSnapshots
+	BorSnapshots() BlockSnapshots
 }
 
 type BlockSnapshots interface {
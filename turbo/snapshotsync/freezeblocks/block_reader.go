@@ -81,6 +81,7 @@ func (r *RemoteBlockReader) HeaderByNumber(ctx context.Context, tx kv.Getter, bl
 }
 
 func (r *RemoteBlockReader) Snapshots() services.BlockSnapshots    { panic("not implemented") }
+func (r *RemoteBlockReader) BorSnapshots() services.BlockSnapshots { panic("not implemented") }
 func (r *RemoteBlockReader) FrozenBlocks() uint64                  { panic("not supported") }
 func (r *RemoteBlockReader) FrozenFiles() (list []string)          { panic("not supported") }
 func (r *RemoteBlockReader) FreezingCfg() ethconfig.BlocksFreezing { panic("not supported") }
@@ -101,6 +102,15 @@ func (r *RemoteBlockReader) CanonicalHash(ctx context.Context, tx kv.Getter, blo
 	return rawdb.ReadCanonicalHash(tx, blockHeight)
 }
 
+func (r *RemoteBlockReader) EventsByBlock(ctx context.Context, tx kv.Getter, hash common.Hash, blockHeight uint64) ([]byte, bool, error) {
+	events, err := rawdb.ReadBorEvents(tx, hash, blockHeight)
+	return events, events != nil, err
+}
+
+func (r *RemoteBlockReader) Span(ctx context.Context, tx kv.Getter, spanID uint64) ([]byte, error) {
+	return rawdb.ReadBorSpan(tx, spanID)
+}
+
 func NewRemoteBlockReader(client remote.ETHBACKENDClient) *RemoteBlockReader {
 	return &RemoteBlockReader{client}
 }
@@ -208,17 +218,25 @@ func (r *RemoteBlockReader) BodyRlp(ctx context.Context, tx kv.Getter, hash comm
 // BlockReader can read blocks from db and snapshots
 type BlockReader struct {
 	sn             *RoSnapshots
+	borSn          *BorRoSnapshots // nil if the chain is not Bor
 	TransactionsV3 bool
 }
 
-func NewBlockReader(snapshots services.BlockSnapshots) *BlockReader {
-	return &BlockReader{sn: snapshots.(*RoSnapshots), TransactionsV3: true}
+func NewBlockReader(snapshots services.BlockSnapshots, borSnapshots services.BlockSnapshots) *BlockReader {
+	borSn, _ := borSnapshots.(*BorRoSnapshots)
+	return &BlockReader{sn: snapshots.(*RoSnapshots), borSn: borSn, TransactionsV3: true}
 }
 
 func (r *BlockReader) CanPruneTo(currentBlockInDB uint64) uint64 {
 	return CanDeleteTo(currentBlockInDB, r.sn.BlocksAvailable())
 }
-func (r *BlockReader) Snapshots() services.BlockSnapshots    { return r.sn }
+func (r *BlockReader) Snapshots() services.BlockSnapshots { return r.sn }
+func (r *BlockReader) BorSnapshots() services.BlockSnapshots {
+	if r.borSn == nil {
+		return nil
+	}
+	return r.borSn
+}
 func (r *BlockReader) FrozenBlocks() uint64                  { return r.sn.BlocksAvailable() }
 func (r *BlockReader) FrozenFiles() []string                 { return r.sn.Files() }
 func (r *BlockReader) FreezingCfg() ethconfig.BlocksFreezing { return r.sn.Cfg() }
@@ -891,3 +909,24 @@ func (r *BlockReader) ReadAncestor(db kv.Getter, hash common.Hash, number, ances
 	}
 	return hash, number
 }
+
+func (r *BlockReader) EventsByBlock(ctx context.Context, tx kv.Getter, hash common.Hash, blockHeight uint64) ([]byte, bool, error) {
+	if r.borSn != nil {
+		events, found, err := r.borSn.events(hash, blockHeight)
+		if err != nil || found {
+			return events, found, err
+		}
+	}
+	events, err := rawdb.ReadBorEvents(tx, hash, blockHeight)
+	return events, events != nil, err
+}
+
+func (r *BlockReader) Span(ctx context.Context, tx kv.Getter, spanID uint64) ([]byte, error) {
+	if r.borSn != nil {
+		span, err := r.borSn.span(spanID)
+		if err != nil || span != nil {
+			return span, err
+		}
+	}
+	return rawdb.ReadBorSpan(tx, spanID)
+}
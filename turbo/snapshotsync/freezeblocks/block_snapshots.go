@@ -1023,6 +1023,7 @@ type BlockRetire struct {
 	workers int
 	tmpDir  string
 	db      kv.RoDB
+	borDB   kv.RwDB // nil if the chain is not Bor
 
 	notifier    services.DBEventNotifier
 	logger      log.Logger
@@ -1031,8 +1032,8 @@ type BlockRetire struct {
 	dirs        datadir.Dirs
 }
 
-func NewBlockRetire(workers int, dirs datadir.Dirs, blockReader services.FullBlockReader, blockWriter *blockio.BlockWriter, db kv.RoDB, notifier services.DBEventNotifier, logger log.Logger) *BlockRetire {
-	return &BlockRetire{workers: workers, tmpDir: dirs.Tmp, dirs: dirs, blockReader: blockReader, blockWriter: blockWriter, db: db, notifier: notifier, logger: logger}
+func NewBlockRetire(workers int, dirs datadir.Dirs, blockReader services.FullBlockReader, blockWriter *blockio.BlockWriter, db kv.RoDB, borDB kv.RwDB, notifier services.DBEventNotifier, logger log.Logger) *BlockRetire {
+	return &BlockRetire{workers: workers, tmpDir: dirs.Tmp, dirs: dirs, blockReader: blockReader, blockWriter: blockWriter, db: db, borDB: borDB, notifier: notifier, logger: logger}
 }
 func (br *BlockRetire) snapshots() *RoSnapshots { return br.blockReader.Snapshots().(*RoSnapshots) }
 func (br *BlockRetire) HasNewFrozenFiles() bool {
@@ -1158,14 +1159,14 @@ func (br *BlockRetire) RetireBlocksInBackground(ctx context.Context, forwardProg
 		defer br.working.Store(false)
 
 		blockFrom, blockTo, ok := CanRetire(forwardProgress, br.blockReader.FrozenBlocks())
-		if !ok {
-			return
+		if ok {
+			err := br.RetireBlocks(ctx, blockFrom, blockTo, lvl, seedNewSnapshots)
+			if err != nil {
+				br.logger.Warn("[snapshots] retire blocks", "err", err, "fromBlock", blockFrom, "toBlock", blockTo)
+			}
 		}
 
-		err := br.RetireBlocks(ctx, blockFrom, blockTo, lvl, seedNewSnapshots)
-		if err != nil {
-			br.logger.Warn("[snapshots] retire blocks", "err", err, "fromBlock", blockFrom, "toBlock", blockTo)
-		}
+		br.retireBorBlocks(ctx, forwardProgress, lvl)
 	}()
 }
 func (br *BlockRetire) BuildMissedIndicesIfNeed(ctx context.Context, logPrefix string, notifier services.DBEventNotifier, cc *chain.Config) error {
@@ -0,0 +1,654 @@
+package freezeblocks
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/chain"
+	common2 "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/cmp"
+	"github.com/ledgerwatch/erigon-lib/common/dbg"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/compress"
+	"github.com/ledgerwatch/erigon-lib/downloader/snaptype"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/recsplit"
+	"github.com/ledgerwatch/log/v3"
+	"golang.org/x/exp/slices"
+
+	"github.com/ledgerwatch/erigon/cmd/hack/tool/fromdb"
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/span"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/turbo/services"
+)
+
+// Bor segment types. They live in the "bor" sub-directory of the snapshots,
+// as the downloader and snaptype only know the block segment types. They are
+// only produced and read by the local node: no preverified hashes of them are
+// published, so the downloader can neither fetch nor seed them.
+const (
+	BorEvents = "borevents" // value: block_num_u64 + block_hash + json([]clerk.EventRecordWithTime)
+	BorSpans  = "borspans"  // value: span_id_u64 + json(span.HeimdallSpan)
+)
+
+var BorSnapshotTypes = []string{BorEvents, BorSpans}
+
+func BorSnapDir(snapDir string) string { return filepath.Join(snapDir, "bor") }
+
+func BorSegmentFileName(from, to uint64, t string) string {
+	return snaptype.FileName(from, to, t) + ".seg"
+}
+
+// parseBorFileName parses the name of a Bor segment, ok is false for other files
+func parseBorFileName(fileName string) (from, to uint64, t string, ok bool) {
+	if filepath.Ext(fileName) != ".seg" {
+		return 0, 0, "", false
+	}
+	parts := strings.Split(strings.TrimSuffix(fileName, ".seg"), "-")
+	if len(parts) != 4 || parts[0] != "v1" {
+		return 0, 0, "", false
+	}
+	from, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, 0, "", false
+	}
+	to, err = strconv.ParseUint(parts[2], 10, 64)
+	if err != nil {
+		return 0, 0, "", false
+	}
+	if !slices.Contains(BorSnapshotTypes, parts[3]) {
+		return 0, 0, "", false
+	}
+	return from * 1_000, to * 1_000, parts[3], true
+}
+
+// BorSegment is a segment of state sync events or spans. Every word starts
+// with its key: the block number of the events or the span ID.
+type BorSegment struct {
+	seg    *compress.Decompressor
+	idx    *recsplit.Index // key_u64 -> segment_offset, nil if the segment is empty
+	ranges Range           // of blocks
+	t      string
+}
+
+func (sn *BorSegment) close() {
+	if sn.seg != nil {
+		sn.seg.Close()
+		sn.seg = nil
+	}
+	if sn.idx != nil {
+		sn.idx.Close()
+		sn.idx = nil
+	}
+}
+
+func (sn *BorSegment) reopen(dir string) (err error) {
+	sn.close()
+	fileName := BorSegmentFileName(sn.ranges.from, sn.ranges.to, sn.t)
+	sn.seg, err = compress.NewDecompressor(filepath.Join(dir, fileName))
+	if err != nil {
+		return fmt.Errorf("%w, fileName: %s", err, fileName)
+	}
+	if sn.seg.Count() == 0 { // recsplit can't index no key
+		return nil
+	}
+	fileName = snaptype.IdxFileName(sn.ranges.from, sn.ranges.to, sn.t)
+	sn.idx, err = recsplit.OpenIndex(filepath.Join(dir, fileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) { // the segment can be indexed later
+			return nil
+		}
+		return fmt.Errorf("%w, fileName: %s", err, fileName)
+	}
+	if sn.idx.ModTime().Before(sn.seg.ModTime()) {
+		// Index has been created before the segment file, needs to be ignored (and rebuilt) as inconsistent
+		sn.idx.Close()
+		sn.idx = nil
+	}
+	return nil
+}
+
+func (sn *BorSegment) indexed() bool { return sn.seg != nil && (sn.idx != nil || sn.seg.Count() == 0) }
+
+// spanIDs returns the range of span IDs of a spans segment.
+func (sn *BorSegment) spanIDs() (from, to uint64) {
+	return borSpanIDs(sn.ranges.from, sn.ranges.to)
+}
+
+// borSpanIDs returns the IDs of the spans frozen with the blocks in [from, to):
+// the ones which start in it.
+func borSpanIDs(blockFrom, blockTo uint64) (from, to uint64) {
+	from = span.IDAt(blockFrom)
+	if blockFrom > 0 && span.IDAt(blockFrom-1) == from {
+		from++
+	}
+	to = span.IDAt(blockTo-1) + 1
+	return from, cmp.Max(from, to)
+}
+
+// get returns the word with the given key, nil if there is none.
+func (sn *BorSegment) get(key uint64) ([]byte, error) {
+	if sn.idx == nil {
+		return nil, nil
+	}
+	var k [8]byte
+	binary.BigEndian.PutUint64(k[:], key)
+	ordinal := recsplit.NewIndexReader(sn.idx).Lookup(k[:])
+	if ordinal >= sn.idx.KeyCount() {
+		return nil, nil
+	}
+	offset := sn.idx.OrdinalLookup(ordinal)
+	g := sn.seg.MakeGetter()
+	g.Reset(offset)
+	if !g.HasNext() {
+		return nil, nil
+	}
+	word, _ := g.Next(nil)
+	// recsplit is a perfect hash: unknown keys map to some other word
+	if len(word) < 8 || !bytes.Equal(word[:8], k[:]) {
+		return nil, nil
+	}
+	return word[8:], nil
+}
+
+// BorRoSnapshots are the frozen state sync events and spans of Bor. Like
+// RoSnapshots, both segment types must exist for a range of blocks and gaps
+// are not allowed.
+type BorRoSnapshots struct {
+	lock   sync.RWMutex
+	Events []*BorSegment
+	Spans  []*BorSegment
+
+	dir         string
+	segmentsMax atomic.Uint64 // all types of .seg files are available - up to this number
+	idxMax      atomic.Uint64 // all types of .idx files are available - up to this number
+	cfg         ethconfig.BlocksFreezing
+	logger      log.Logger
+}
+
+// NewBorRoSnapshots - the Bor snapshots in the "bor" sub-directory of snapDir
+func NewBorRoSnapshots(cfg ethconfig.BlocksFreezing, snapDir string, logger log.Logger) *BorRoSnapshots {
+	return &BorRoSnapshots{dir: BorSnapDir(snapDir), cfg: cfg, logger: logger}
+}
+
+func (s *BorRoSnapshots) Cfg() ethconfig.BlocksFreezing { return s.cfg }
+func (s *BorRoSnapshots) Dir() string                   { return s.dir }
+func (s *BorRoSnapshots) SegmentsMax() uint64           { return s.segmentsMax.Load() }
+func (s *BorRoSnapshots) BlocksAvailable() uint64 {
+	return cmp.Min(s.segmentsMax.Load(), s.idxMax.Load())
+}
+
+// segments returns the segment files of the Bor snapshots directory which can
+// be opened: both types exist, without overlaps and gaps.
+func (s *BorRoSnapshots) segments() (res []snaptype.FileInfo, missingSnapshots []Range, err error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	exist := map[string]bool{}
+	var l []snaptype.FileInfo
+	for _, e := range entries {
+		from, to, t, ok := parseBorFileName(e.Name())
+		if !ok {
+			continue
+		}
+		exist[e.Name()] = true
+		if t == BorEvents && from < to {
+			l = append(l, snaptype.FileInfo{From: from, To: to, Path: filepath.Join(s.dir, e.Name())})
+		}
+	}
+	var complete []snaptype.FileInfo
+	for _, f := range l {
+		if exist[BorSegmentFileName(f.From, f.To, BorSpans)] {
+			complete = append(complete, f)
+		}
+	}
+	slices.SortFunc(complete, func(i, j snaptype.FileInfo) bool {
+		if i.From != j.From {
+			return i.From < j.From
+		}
+		return i.To < j.To
+	})
+	res, missingSnapshots = noGaps(noOverlaps(complete))
+	return res, missingSnapshots, nil
+}
+
+func (s *BorRoSnapshots) ScanDir() (map[string]struct{}, []*services.Range, error) {
+	existingFiles, missingSnapshots, err := s.segments()
+	if err != nil {
+		return nil, nil, err
+	}
+	existingFilesMap := map[string]struct{}{}
+	for _, f := range existingFiles {
+		for _, t := range BorSnapshotTypes {
+			existingFilesMap[BorSegmentFileName(f.From, f.To, t)] = struct{}{}
+		}
+	}
+	res := make([]*services.Range, 0, len(missingSnapshots))
+	for _, sn := range missingSnapshots {
+		res = append(res, &services.Range{From: sn.from, To: sn.to})
+	}
+	return existingFilesMap, res, nil
+}
+
+func (s *BorRoSnapshots) OptimisticalyReopenFolder() { _ = s.ReopenFolder() }
+
+// ReopenFolder opens the segments of the directory and closes the ones which
+// are gone, e.g. after a merge.
+func (s *BorRoSnapshots) ReopenFolder() error {
+	files, _, err := s.segments()
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var events, spans []*BorSegment
+	var segmentsMax, idxMax uint64
+	idxComplete := true
+	for _, f := range files {
+		e := s.reuse(s.Events, f.From, f.To)
+		if e == nil {
+			e = &BorSegment{ranges: Range{f.From, f.To}, t: BorEvents}
+			if err := e.reopen(s.dir); err != nil {
+				closeBorSegments(events, spans)
+				return err
+			}
+		}
+		sp := s.reuse(s.Spans, f.From, f.To)
+		if sp == nil {
+			sp = &BorSegment{ranges: Range{f.From, f.To}, t: BorSpans}
+			if err := sp.reopen(s.dir); err != nil {
+				e.close()
+				closeBorSegments(events, spans)
+				return err
+			}
+		}
+		events, spans = append(events, e), append(spans, sp)
+
+		segmentsMax = f.To - 1
+		if idxComplete = idxComplete && e.indexed() && sp.indexed(); idxComplete {
+			idxMax = f.To - 1
+		}
+	}
+
+	// close the segments which are not reused
+	for _, sn := range s.Events {
+		if !slices.Contains(events, sn) {
+			sn.close()
+		}
+	}
+	for _, sn := range s.Spans {
+		if !slices.Contains(spans, sn) {
+			sn.close()
+		}
+	}
+	s.Events, s.Spans = events, spans
+	s.segmentsMax.Store(segmentsMax)
+	s.idxMax.Store(idxMax)
+	return nil
+}
+
+// reuse returns the open and indexed segment of the given range, if any
+func (s *BorRoSnapshots) reuse(segments []*BorSegment, from, to uint64) *BorSegment {
+	for _, sn := range segments {
+		if sn.ranges.from == from && sn.ranges.to == to && sn.indexed() {
+			return sn
+		}
+	}
+	return nil
+}
+
+func closeBorSegments(lists ...[]*BorSegment) {
+	for _, l := range lists {
+		for _, sn := range l {
+			sn.close()
+		}
+	}
+}
+
+func (s *BorRoSnapshots) Close() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	closeBorSegments(s.Events, s.Spans)
+	s.Events, s.Spans = nil, nil
+	s.segmentsMax.Store(0)
+	s.idxMax.Store(0)
+}
+
+func (s *BorRoSnapshots) Ranges() (ranges []Range) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, sn := range s.Events {
+		ranges = append(ranges, sn.ranges)
+	}
+	return ranges
+}
+
+func (s *BorRoSnapshots) Files() (list []string) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, l := range [][]*BorSegment{s.Events, s.Spans} {
+		for _, sn := range l {
+			if sn.seg == nil {
+				continue
+			}
+			list = append(list, sn.seg.FileName())
+		}
+	}
+	slices.Sort(list)
+	return list
+}
+
+// filesByRange returns the segment files of each type within [from, to)
+func (s *BorRoSnapshots) filesByRange(from, to uint64) map[string][]string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	toMerge := map[string][]string{}
+	for i, sn := range s.Events {
+		if sn.ranges.from < from {
+			continue
+		}
+		if sn.ranges.to > to {
+			break
+		}
+		toMerge[BorEvents] = append(toMerge[BorEvents], sn.seg.FilePath())
+		toMerge[BorSpans] = append(toMerge[BorSpans], s.Spans[i].seg.FilePath())
+	}
+	return toMerge
+}
+
+// events returns the frozen state sync events of a block
+func (s *BorRoSnapshots) events(hash common2.Hash, blockNum uint64) ([]byte, bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, sn := range s.Events {
+		if !(blockNum >= sn.ranges.from && blockNum < sn.ranges.to) {
+			continue
+		}
+		word, err := sn.get(blockNum)
+		if err != nil || len(word) < length.Hash {
+			return nil, false, err
+		}
+		if !bytes.Equal(word[:length.Hash], hash[:]) { // only canonical blocks are frozen
+			return nil, false, nil
+		}
+		return word[length.Hash:], true, nil
+	}
+	return nil, false, nil
+}
+
+// span returns a frozen span
+func (s *BorRoSnapshots) span(spanID uint64) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, sn := range s.Spans {
+		if from, to := sn.spanIDs(); !(spanID >= from && spanID < to) {
+			continue
+		}
+		return sn.get(spanID)
+	}
+	return nil, nil
+}
+
+// DumpBorBlocks freezes the state sync events and spans of the blocks in
+// [blockFrom, blockTo) from the Bor database into segments of blocksPerFile
+// blocks.
+func DumpBorBlocks(ctx context.Context, chainConfig *chain.Config, blockFrom, blockTo, blocksPerFile uint64, tmpDir, snapDir string, chainDB, borDB kv.RoDB, workers int, lvl log.Lvl, logger log.Logger) error {
+	if blocksPerFile == 0 {
+		return nil
+	}
+	for i := blockFrom; i < blockTo; i = chooseSegmentEnd(i, blockTo, blocksPerFile) {
+		if err := dumpBorBlocksRange(ctx, chainConfig, i, chooseSegmentEnd(i, blockTo, blocksPerFile), tmpDir, snapDir, chainDB, borDB, workers, lvl, logger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func dumpBorBlocksRange(ctx context.Context, chainConfig *chain.Config, blockFrom, blockTo uint64, tmpDir, snapDir string, chainDB, borDB kv.RoDB, workers int, lvl log.Lvl, logger log.Logger) error {
+	dir := BorSnapDir(snapDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	for _, t := range BorSnapshotTypes {
+		segPath := filepath.Join(dir, BorSegmentFileName(blockFrom, blockTo, t))
+		sn, err := compress.NewCompressor(ctx, "Snapshot "+t, segPath, tmpDir, compress.MinPatternScore, workers, log.LvlTrace, logger)
+		if err != nil {
+			return err
+		}
+		defer sn.Close()
+
+		collect := func(v []byte) error { return sn.AddWord(v) }
+		switch t {
+		case BorEvents:
+			err = DumpBorEvents(ctx, chainConfig, chainDB, borDB, blockFrom, blockTo, lvl, logger, collect)
+		case BorSpans:
+			err = DumpBorSpans(ctx, borDB, blockFrom, blockTo, collect)
+		}
+		if err != nil {
+			return fmt.Errorf("dump %s: %w", t, err)
+		}
+		if err := sn.Compress(); err != nil {
+			return fmt.Errorf("compress: %w", err)
+		}
+		if err := BorIdx(ctx, segPath, blockFrom, tmpDir, lvl, logger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DumpBorEvents - [from, to). Fails if the events of a sprint start block of
+// the canonical chain are not stored, as Bor would then have to ask Heimdall
+// for them again.
+func DumpBorEvents(ctx context.Context, chainConfig *chain.Config, chainDB, borDB kv.RoDB, blockFrom, blockTo uint64, lvl log.Lvl, logger log.Logger, collect func([]byte) error) error {
+	logEvery := time.NewTicker(20 * time.Second)
+	defer logEvery.Stop()
+
+	var expected, dumped uint64
+	for n := cmp.Max(blockFrom, 1); n < blockTo; n++ {
+		if n%chainConfig.Bor.CalculateSprint(n) == 0 {
+			expected++
+		}
+	}
+
+	if err := chainDB.View(ctx, func(chainTx kv.Tx) error {
+		return borDB.View(ctx, func(borTx kv.Tx) error {
+			return rawdb.ForEachBorEvents(borTx, blockFrom, blockTo, func(blockNum uint64, hash common2.Hash, events []byte) error {
+				canonicalHash, err := rawdb.ReadCanonicalHash(chainTx, blockNum)
+				if err != nil {
+					return err
+				}
+				if canonicalHash != hash {
+					return nil
+				}
+
+				word := make([]byte, 8+length.Hash+len(events))
+				binary.BigEndian.PutUint64(word, blockNum)
+				copy(word[8:], hash[:])
+				copy(word[8+length.Hash:], events)
+				if err := collect(word); err != nil {
+					return err
+				}
+				dumped++
+
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case <-logEvery.C:
+					logger.Log(lvl, "[snapshots] Dumping bor events", "block num", blockNum)
+				default:
+				}
+				return nil
+			})
+		})
+	}); err != nil {
+		return err
+	}
+
+	if dumped != expected {
+		return fmt.Errorf("state sync events of %d sprint start blocks in [%d, %d) are stored, expected %d", dumped, blockFrom, blockTo, expected)
+	}
+	return nil
+}
+
+// DumpBorSpans - the stored spans which start in the blocks [from, to)
+func DumpBorSpans(ctx context.Context, borDB kv.RoDB, blockFrom, blockTo uint64, collect func([]byte) error) error {
+	from, to := borSpanIDs(blockFrom, blockTo)
+	return borDB.View(ctx, func(tx kv.Tx) error {
+		return rawdb.ForEachBorSpan(tx, from, to, func(spanID uint64, data []byte) error {
+			word := make([]byte, 8+len(data))
+			binary.BigEndian.PutUint64(word, spanID)
+			copy(word[8:], data)
+			return collect(word)
+		})
+	})
+}
+
+// BorIdx indexes a Bor segment by the key its words start with. Empty segments
+// have no index.
+func BorIdx(ctx context.Context, segmentFilePath string, firstBlockNumInSegment uint64, tmpDir string, lvl log.Lvl, logger log.Logger) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			_, fName := filepath.Split(segmentFilePath)
+			err = fmt.Errorf("BorIdx: at=%s, %v, %s", fName, rec, dbg.Stack())
+		}
+	}()
+
+	d, err := compress.NewDecompressor(segmentFilePath)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	if d.Count() == 0 {
+		return nil
+	}
+
+	if err := Idx(ctx, d, firstBlockNumInSegment, tmpDir, lvl, func(idx *recsplit.RecSplit, i, offset uint64, word []byte) error {
+		return idx.AddKey(word[:8], offset)
+	}, logger); err != nil {
+		return fmt.Errorf("BorIdx: %w", err)
+	}
+	return nil
+}
+
+// RetireBorBlocks freezes the state sync events and spans of [blockFrom,
+// blockTo), merges the segments like the block ones and prunes what is frozen
+// from the Bor database.
+func (br *BlockRetire) RetireBorBlocks(ctx context.Context, blockFrom, blockTo uint64, lvl log.Lvl) error {
+	chainConfig := fromdb.ChainConfig(br.db)
+	chainID, _ := uint256.FromBig(chainConfig.ChainID)
+	notifier, logger, tmpDir, workers := br.notifier, br.logger, br.tmpDir, br.workers
+	logger.Log(lvl, "[snapshots] Retire Bor Blocks", "range", fmt.Sprintf("%dk-%dk", blockFrom/1000, blockTo/1000))
+	snapshots := br.borSnapshots()
+
+	if err := DumpBorBlocks(ctx, chainConfig, blockFrom, blockTo, snaptype.Erigon2SegmentSize, tmpDir, br.dirs.Snap, br.db, br.borDB, workers, lvl, logger); err != nil {
+		return fmt.Errorf("DumpBorBlocks: %w", err)
+	}
+	if err := snapshots.ReopenFolder(); err != nil {
+		return fmt.Errorf("reopen: %w", err)
+	}
+	if notifier != nil && !reflect.ValueOf(notifier).IsNil() { // notify about new snapshots of any size
+		notifier.OnNewSnapshot()
+	}
+
+	merger := NewMerger(tmpDir, workers, lvl, *chainID, notifier, logger)
+	rangesToMerge := merger.FindMergeRanges(snapshots.Ranges())
+	if err := merger.MergeBor(ctx, snapshots, rangesToMerge); err != nil {
+		return err
+	}
+
+	if !br.blockReader.FreezingCfg().KeepBlocks {
+		frozenTo := snapshots.BlocksAvailable() + 1
+		spanTo, _ := borSpanIDs(frozenTo, frozenTo+1)
+		if err := br.borDB.Update(ctx, func(tx kv.RwTx) error {
+			return rawdb.PruneBorBlocks(tx, frozenTo, spanTo)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retireBorBlocks retires the Bor blocks which are immutable, if Bor data is
+// stored at all.
+func (br *BlockRetire) retireBorBlocks(ctx context.Context, forwardProgress uint64, lvl log.Lvl) {
+	snapshots := br.borSnapshots()
+	if snapshots == nil || br.borDB == nil {
+		return
+	}
+	var executed uint64
+	if err := br.db.View(ctx, func(tx kv.Tx) (err error) {
+		executed, err = stages.GetStageProgress(tx, stages.Execution)
+		return err
+	}); err != nil {
+		br.logger.Warn("[snapshots] retire bor blocks", "err", err)
+		return
+	}
+	// events are stored by execution
+	blockFrom, blockTo, ok := CanRetire(cmp.Min(forwardProgress, executed), snapshots.BlocksAvailable())
+	if !ok {
+		return
+	}
+	if err := br.RetireBorBlocks(ctx, blockFrom, blockTo, lvl); err != nil {
+		br.logger.Warn("[snapshots] retire bor blocks", "err", err, "fromBlock", blockFrom, "toBlock", blockTo)
+	}
+}
+
+func (br *BlockRetire) borSnapshots() *BorRoSnapshots {
+	sn, _ := br.blockReader.BorSnapshots().(*BorRoSnapshots)
+	return sn
+}
+
+// MergeBor does merge Bor segments in given ranges
+func (m *Merger) MergeBor(ctx context.Context, snapshots *BorRoSnapshots, mergeRanges []Range) error {
+	if len(mergeRanges) == 0 {
+		return nil
+	}
+	logEvery := time.NewTicker(30 * time.Second)
+	defer logEvery.Stop()
+	for _, r := range mergeRanges {
+		toMerge := snapshots.filesByRange(r.from, r.to)
+		for _, t := range BorSnapshotTypes {
+			segPath := filepath.Join(snapshots.Dir(), BorSegmentFileName(r.from, r.to, t))
+			if err := m.merge(ctx, toMerge[t], segPath, logEvery); err != nil {
+				return fmt.Errorf("mergeByAppendSegments: %w", err)
+			}
+			if err := BorIdx(ctx, segPath, r.from, m.tmpDir, m.lvl, m.logger); err != nil {
+				return err
+			}
+		}
+		if err := snapshots.ReopenFolder(); err != nil {
+			return fmt.Errorf("ReopenSegments: %w", err)
+		}
+		if m.notifier != nil { // notify about new snapshots of any size
+			m.notifier.OnNewSnapshot()
+			time.Sleep(1 * time.Second) // i working on blocking API - to ensure client does not use old snapsthos - and then delete them
+		}
+		for _, t := range BorSnapshotTypes {
+			m.removeOldFiles(toMerge[t], snapshots.Dir())
+		}
+	}
+	m.logger.Log(m.lvl, "[snapshots] Merge done", "from", mergeRanges[0].from)
+	return nil
+}
@@ -0,0 +1,217 @@
+package freezeblocks
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/datadir"
+	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/log/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/consensus/bor/heimdall/span"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/eth/ethconfig"
+)
+
+const testBorSprint = 16
+
+var testBorChainConfig = &chain.Config{Bor: &chain.BorConfig{Sprint: map[string]uint64{"0": testBorSprint}}}
+
+func testBorHash(blockNum uint64) libcommon.Hash {
+	return libcommon.Hash{byte(blockNum >> 16), byte(blockNum >> 8), byte(blockNum), 1}
+}
+
+// createBorTestDBs - a canonical chain of the given blocks, the state sync
+// events of its sprint start blocks and of a non-canonical block, and the
+// spans of the chain and the next one, which Bor fetches ahead
+func createBorTestDBs(t *testing.T, blocks uint64) (chainDB, borDB kv.RwDB) {
+	chainDB, borDB = memdb.NewTestDB(t), memdb.NewTestDB(t)
+	require.NoError(t, chainDB.Update(context.Background(), func(tx kv.RwTx) error {
+		for n := uint64(0); n < blocks; n++ {
+			if err := rawdb.WriteCanonicalHash(tx, testBorHash(n), n); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	require.NoError(t, borDB.Update(context.Background(), func(tx kv.RwTx) error {
+		for n := uint64(testBorSprint); n < blocks; n += testBorSprint {
+			if err := rawdb.WriteBorEvents(tx, testBorHash(n), n, []byte(fmt.Sprintf(`[{"id":%d}]`, n))); err != nil {
+				return err
+			}
+		}
+		if err := rawdb.WriteBorEvents(tx, libcommon.Hash{0xff}, testBorSprint, []byte(`[]`)); err != nil {
+			return err
+		}
+		for id := uint64(0); id <= span.IDAt(blocks-1)+1; id++ {
+			data, err := json.Marshal(span.HeimdallSpan{Span: span.Span{ID: id}})
+			if err != nil {
+				return err
+			}
+			if err := rawdb.WriteBorSpan(tx, id, data); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+	return chainDB, borDB
+}
+
+func TestDumpBorBlocks(t *testing.T) {
+	ctx, logger := context.Background(), log.New()
+	dir := t.TempDir()
+	chainDB, borDB := createBorTestDBs(t, 8_000)
+
+	require.NoError(t, DumpBorBlocks(ctx, testBorChainConfig, 0, 8_000, 1_000, dir, dir, chainDB, borDB, 1, log.LvlInfo, logger))
+
+	borSnapshots := NewBorRoSnapshots(ethconfig.BlocksFreezing{Enabled: true}, dir, logger)
+	defer borSnapshots.Close()
+	require.NoError(t, borSnapshots.ReopenFolder())
+	require.Equal(t, uint64(7_999), borSnapshots.BlocksAvailable())
+	require.Len(t, borSnapshots.Files(), 16)
+
+	// the frozen data is read without the Bor DB
+	r := NewBlockReader(NewRoSnapshots(ethconfig.BlocksFreezing{Enabled: false}, "", logger), borSnapshots)
+	emptyTx, err := memdb.NewTestDB(t).BeginRo(ctx)
+	require.NoError(t, err)
+	defer emptyTx.Rollback()
+
+	for _, n := range []uint64{16, 992, 1008, 7984} {
+		events, found, err := r.EventsByBlock(ctx, emptyTx, testBorHash(n), n)
+		require.NoError(t, err)
+		require.True(t, found, n)
+		require.JSONEq(t, fmt.Sprintf(`[{"id":%d}]`, n), string(events))
+	}
+	for _, n := range []uint64{17, 8_000} {
+		_, found, err := r.EventsByBlock(ctx, emptyTx, testBorHash(n), n)
+		require.NoError(t, err)
+		require.False(t, found, n)
+	}
+	_, found, err := r.EventsByBlock(ctx, emptyTx, libcommon.Hash{0xff}, testBorSprint)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	// spans 0 and 1 start in the first segment, span 2 at block 6656
+	for id := uint64(0); id <= 2; id++ {
+		data, err := r.Span(ctx, emptyTx, id)
+		require.NoError(t, err)
+		var s span.HeimdallSpan
+		require.NoError(t, json.Unmarshal(data, &s))
+		require.Equal(t, id, s.ID)
+	}
+	data, err := r.Span(ctx, emptyTx, 3)
+	require.NoError(t, err)
+	require.Nil(t, data)
+
+	// what is not frozen is read from the Bor DB
+	require.NoError(t, borDB.View(ctx, func(tx kv.Tx) error {
+		data, err := r.Span(ctx, tx, 3)
+		require.NoError(t, err)
+		require.NotNil(t, data)
+		return nil
+	}))
+}
+
+func TestDumpBorEventsMissing(t *testing.T) {
+	ctx, logger := context.Background(), log.New()
+	chainDB, borDB := createBorTestDBs(t, 2_000)
+	require.NoError(t, borDB.Update(ctx, func(tx kv.RwTx) error {
+		return tx.Delete(kv.BorSeparate, borEventsTestKey(1_024))
+	}))
+
+	collect := func([]byte) error { return nil }
+	require.NoError(t, DumpBorEvents(ctx, testBorChainConfig, chainDB, borDB, 0, 1_000, log.LvlInfo, logger, collect))
+	require.Error(t, DumpBorEvents(ctx, testBorChainConfig, chainDB, borDB, 1_000, 2_000, log.LvlInfo, logger, collect))
+}
+
+func borEventsTestKey(n uint64) []byte {
+	k := binary.BigEndian.AppendUint64([]byte("borevents-"), n)
+	h := testBorHash(n)
+	return append(k, h[:]...)
+}
+
+func TestMergeBorSnapshots(t *testing.T) {
+	ctx, logger := context.Background(), log.New()
+	dir := t.TempDir()
+	chainDB, borDB := createBorTestDBs(t, 11_000)
+	require.NoError(t, DumpBorBlocks(ctx, testBorChainConfig, 0, 11_000, 1_000, dir, dir, chainDB, borDB, 1, log.LvlInfo, logger))
+
+	borSnapshots := NewBorRoSnapshots(ethconfig.BlocksFreezing{Enabled: true}, dir, logger)
+	defer borSnapshots.Close()
+	require.NoError(t, borSnapshots.ReopenFolder())
+
+	merger := NewMerger(dir, 1, log.LvlInfo, uint256.Int{}, nil, logger)
+	ranges := merger.FindMergeRanges(borSnapshots.Ranges())
+	require.Equal(t, []Range{{0, 10_000}}, ranges)
+	require.NoError(t, merger.MergeBor(ctx, borSnapshots, ranges))
+
+	require.Equal(t, []Range{{0, 10_000}, {10_000, 11_000}}, borSnapshots.Ranges())
+	require.Equal(t, uint64(10_999), borSnapshots.BlocksAvailable())
+	_, err := os.Stat(filepath.Join(borSnapshots.Dir(), BorSegmentFileName(0, 1_000, BorEvents)))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	r := NewBlockReader(NewRoSnapshots(ethconfig.BlocksFreezing{Enabled: false}, "", logger), borSnapshots)
+	emptyTx, err := memdb.NewTestDB(t).BeginRo(ctx)
+	require.NoError(t, err)
+	defer emptyTx.Rollback()
+	for _, n := range []uint64{16, 5008, 10_992} {
+		_, found, err := r.EventsByBlock(ctx, emptyTx, testBorHash(n), n)
+		require.NoError(t, err)
+		require.True(t, found, n)
+	}
+	data, err := r.Span(ctx, emptyTx, 2)
+	require.NoError(t, err)
+	require.NotNil(t, data)
+}
+
+func TestRetireBorBlocks(t *testing.T) {
+	ctx, logger := context.Background(), log.New()
+	dirs := datadir.New(t.TempDir())
+	chainDB, borDB := createBorTestDBs(t, 3_000)
+	chainConfig := *testBorChainConfig
+	chainConfig.ChainID = big.NewInt(137)
+	require.NoError(t, chainDB.Update(ctx, func(tx kv.RwTx) error {
+		return rawdb.WriteChainConfig(tx, testBorHash(0), &chainConfig)
+	}))
+
+	borSnapshots := NewBorRoSnapshots(ethconfig.BlocksFreezing{Enabled: true}, dirs.Snap, logger)
+	defer borSnapshots.Close()
+	r := NewBlockReader(NewRoSnapshots(ethconfig.BlocksFreezing{Enabled: true}, dirs.Snap, logger), borSnapshots)
+	br := NewBlockRetire(1, dirs, r, nil, chainDB, borDB, nil, logger)
+
+	require.NoError(t, br.RetireBorBlocks(ctx, 0, 2_000, log.LvlInfo))
+	require.Equal(t, uint64(1_999), borSnapshots.BlocksAvailable())
+
+	// the frozen events and spans are pruned, the others are kept
+	require.NoError(t, borDB.View(ctx, func(tx kv.Tx) error {
+		events, err := rawdb.ReadBorEvents(tx, testBorHash(1_984), 1_984)
+		require.NoError(t, err)
+		require.Nil(t, events)
+		events, err = rawdb.ReadBorEvents(tx, testBorHash(2_000), 2_000)
+		require.NoError(t, err)
+		require.NotNil(t, events)
+		s, err := rawdb.ReadBorSpan(tx, 1)
+		require.NoError(t, err)
+		require.Nil(t, s)
+		s, err = rawdb.ReadBorSpan(tx, 2)
+		require.NoError(t, err)
+		require.NotNil(t, s)
+
+		events, found, err := r.EventsByBlock(ctx, tx, testBorHash(1_984), 1_984)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.NotEmpty(t, events)
+		return nil
+	}))
+}
@@ -5,8 +5,11 @@ import (
 	"math/big"
 	"testing"
 
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/stretchr/testify/require"
 
+	"github.com/ledgerwatch/erigon/consensus/bor/finality/whitelist"
 	"github.com/ledgerwatch/erigon/core"
 	"github.com/ledgerwatch/erigon/core/types"
 	"github.com/ledgerwatch/erigon/crypto"
@@ -61,3 +64,48 @@ func TestInserter1(t *testing.T) {
 		t.Errorf("feed empty header 2: %v", err)
 	}
 }
+
+func TestInserterChainValidator(t *testing.T) {
+	funds := big.NewInt(1000000000)
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	address := crypto.PubkeyToAddress(key.PublicKey)
+	chainConfig := params.AllProtocolChanges
+	gspec := &types.Genesis{
+		Config: chainConfig,
+		Alloc: types.GenesisAlloc{
+			address: {Balance: funds},
+		},
+	}
+	m := stages.MockWithGenesis(t, gspec, key, false)
+	db := m.DB
+	_, genesis, err := core.CommitGenesisBlock(db, gspec, "", m.Log)
+	require.NoError(t, err)
+	tx, err := db.BeginRw(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback()
+	br := m.BlockReader
+	hi := headerdownload.NewHeaderInserter("headers", big.NewInt(0), 0, br)
+
+	feed := func(h *types.Header) libcommon.Hash {
+		data, _ := rlp.EncodeToBytes(h)
+		_, err := hi.FeedHeaderPoW(tx, br, h, data, h.Hash(), h.Number.Uint64())
+		require.NoError(t, err)
+		return h.Hash()
+	}
+	h1Hash := feed(&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(10), ParentHash: genesis.Hash()})
+	h2Hash := feed(&types.Header{Number: big.NewInt(2), Difficulty: big.NewInt(10), ParentHash: h1Hash})
+
+	service := whitelist.NewService()
+	service.ProcessCheckpoint(1, h1Hash)
+	hi.SetChainValidator(service)
+
+	// heavier chain replacing the whitelisted block
+	forkHash := feed(&types.Header{Number: big.NewInt(1), Difficulty: big.NewInt(1000), ParentHash: genesis.Hash()})
+	require.Equal(t, h2Hash, hi.GetHighestHash())
+	feed(&types.Header{Number: big.NewInt(2), Difficulty: big.NewInt(1000), ParentHash: forkHash})
+	require.Equal(t, h2Hash, hi.GetHighestHash())
+
+	// heavier chain keeping the whitelisted block
+	h3Hash := feed(&types.Header{Number: big.NewInt(2), Difficulty: big.NewInt(5000), ParentHash: h1Hash})
+	require.Equal(t, h3Hash, hi.GetHighestHash())
+}
@@ -14,9 +14,11 @@ import (
 	"strings"
 	"time"
 
+	lru "github.com/hashicorp/golang-lru/v2"
 	libcommon "github.com/ledgerwatch/erigon-lib/common"
 	"github.com/ledgerwatch/erigon-lib/etl"
 	"github.com/ledgerwatch/erigon-lib/kv"
+	"github.com/ledgerwatch/log/v3"
 	"golang.org/x/exp/slices"
 
 	"github.com/ledgerwatch/erigon/dataflow"
@@ -888,22 +890,33 @@ func (hi *HeaderInserter) FeedHeaderPoW(db kv.StatelessRwTx, headerReader servic
 	td = new(big.Int).Add(parentTd, header.Difficulty)
 	// Now we can decide wether this header will create a change in the canonical head
 	if td.Cmp(hi.localTd) > 0 {
-		hi.newCanonical = true
 		forkingPoint, err := hi.ForkingPoint(db, header, parent)
 		if err != nil {
 			return nil, err
 		}
-		hi.highest = blockHeight
-		hi.highestHash = hash
-		hi.highestTimestamp = header.Time
-		hi.canonicalCache.Add(blockHeight, hash)
-		// See if the forking point affects the unwindPoint (the block number to which other stages will need to unwind before the new canonical chain is applied)
-		if forkingPoint < hi.unwindPoint {
-			hi.unwindPoint = forkingPoint
-			hi.unwind = true
+		valid := true
+		if hi.chainValidator != nil {
+			if valid, err = hi.isValidChain(db, header, hash, forkingPoint); err != nil {
+				return nil, err
+			}
+		}
+		if valid {
+			hi.newCanonical = true
+			hi.highest = blockHeight
+			hi.highestHash = hash
+			hi.highestTimestamp = header.Time
+			hi.canonicalCache.Add(blockHeight, hash)
+			// See if the forking point affects the unwindPoint (the block number to which other stages will need to unwind before the new canonical chain is applied)
+			if forkingPoint < hi.unwindPoint {
+				hi.unwindPoint = forkingPoint
+				hi.unwind = true
+			}
+			// This makes sure we end up choosing the chain with the max total difficulty
+			hi.localTd.Set(td)
+		} else {
+			// Keep the header, but do not let its chain become canonical
+			log.Warn(fmt.Sprintf("[%s] Rejected reorg past a final block", hi.logPrefix), "hash", hash, "height", blockHeight, "forkingPoint", forkingPoint)
 		}
-		// This makes sure we end up choosing the chain with the max total difficulty
-		hi.localTd.Set(td)
 	}
 	if err = rawdb.WriteTd(db, hash, blockHeight, td); err != nil {
 		return nil, fmt.Errorf("[%s] failed to WriteTd: %w", hi.logPrefix, err)
@@ -917,6 +930,48 @@ func (hi *HeaderInserter) FeedHeaderPoW(db kv.StatelessRwTx, headerReader servic
 	return td, nil
 }
 
+// SetChainValidator makes the inserter reject header chains which the
+// validator does not allow to become canonical.
+func (hi *HeaderInserter) SetChainValidator(chainValidator ChainValidator) {
+	hi.chainValidator = chainValidator
+	hi.ancestorCache, _ = lru.New[ancestorKey, libcommon.Hash](ancestorCacheSize)
+}
+
+// ancestorCacheSize is the number of ancestors remembered by the inserter:
+// headers are fed parent first, so the ancestor of a header is usually found
+// one step back, from the ancestor of its parent.
+const ancestorCacheSize = 4096
+
+func (hi *HeaderInserter) isValidChain(db kv.StatelessRwTx, header *types.Header, hash libcommon.Hash, forkingPoint uint64) (bool, error) {
+	currentHead := hi.headerProgress
+	if hi.newCanonical {
+		currentHead = hi.highest
+	}
+	blockHeight := header.Number.Uint64()
+	hashAt := func(number uint64) (libcommon.Hash, error) {
+		ancestorHash, ancestorHeight := hash, blockHeight
+		parentHash := header.ParentHash
+		for ancestorHeight > number {
+			if cached, ok := hi.ancestorCache.Get(ancestorKey{parentHash, number}); ok {
+				ancestorHash = cached
+				break
+			}
+			ancestor, err := hi.headerReader.Header(context.Background(), db, parentHash, ancestorHeight-1)
+			if err != nil {
+				return libcommon.Hash{}, err
+			}
+			if ancestor == nil {
+				return libcommon.Hash{}, fmt.Errorf("[%s] ancestor not found with hash %x and height %d", hi.logPrefix, parentHash, ancestorHeight-1)
+			}
+			ancestorHash, parentHash = parentHash, ancestor.ParentHash
+			ancestorHeight--
+		}
+		hi.ancestorCache.Add(ancestorKey{hash, number}, ancestorHash)
+		return ancestorHash, nil
+	}
+	return hi.chainValidator.IsValidChain(currentHead, forkingPoint, blockHeight, hashAt)
+}
+
 func (hi *HeaderInserter) FeedHeaderPoS(db kv.RwTx, header *types.Header, hash libcommon.Hash) error {
 	blockHeight := header.Number.Uint64()
 	// TODO(yperbasis): do we need to check if the header is already inserted (oldH)?
@@ -383,14 +383,34 @@ type HeaderInserter struct {
 	highestTimestamp uint64
 	canonicalCache   *lru.Cache[uint64, common.Hash]
 	headerReader     services.HeaderAndCanonicalReader
+	headerProgress   uint64
+	chainValidator   ChainValidator
+	ancestorCache    *lru.Cache[ancestorKey, common.Hash] // ancestors looked up for chainValidator
+}
+
+// ancestorKey identifies the ancestor at a given height of a header
+type ancestorKey struct {
+	hash   common.Hash
+	number uint64
+}
+
+// ChainValidator decides whether a header chain may replace the canonical
+// chain, e.g. because it does not reorganise blocks which are known to be
+// final.
+type ChainValidator interface {
+	// IsValidChain is given the head of the canonical chain, the block where
+	// the header chain forks off it and the head of the header chain. hashAt
+	// returns the hash of the header chain at a given height.
+	IsValidChain(currentHead uint64, forkingPoint uint64, chainHead uint64, hashAt func(number uint64) (common.Hash, error)) (bool, error)
 }
 
 func NewHeaderInserter(logPrefix string, localTd *big.Int, headerProgress uint64, headerReader services.HeaderAndCanonicalReader) *HeaderInserter {
 	hi := &HeaderInserter{
-		logPrefix:    logPrefix,
-		localTd:      localTd,
-		unwindPoint:  headerProgress,
-		headerReader: headerReader,
+		logPrefix:      logPrefix,
+		localTd:        localTd,
+		unwindPoint:    headerProgress,
+		headerReader:   headerReader,
+		headerProgress: headerProgress,
 	}
 	hi.canonicalCache, _ = lru.New[uint64, common.Hash](1000)
 	return hi
@@ -0,0 +1,88 @@
+package tracing
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/ledgerwatch/erigon/turbo/grpcutil"
+)
+
+func TestPropagationOverGrpc(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	}()
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpcutil.NewServer(16, nil, grpcutil.WithUnaryInterceptors(UnaryServerInterceptor()), grpcutil.WithStreamInterceptors(StreamServerInterceptor()))
+	healthServer := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(server, healthServer)
+	go server.Serve(lis) //nolint:errcheck
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet", grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := grpc_health_v1.NewHealthClient(ClientConn(conn))
+
+	_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "unknown"})
+	require.Error(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream, err := client.Watch(ctx, &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+	cancel()
+
+	require.Eventually(t, func() bool { return len(recorder.Ended()) == 6 }, 5*time.Second, 10*time.Millisecond)
+	spans := recorder.Ended()
+	byKind := map[trace.SpanKind][]sdktrace.ReadOnlySpan{}
+	for _, span := range spans {
+		byKind[span.SpanKind()] = append(byKind[span.SpanKind()], span)
+	}
+	require.Len(t, byKind[trace.SpanKindClient], 3)
+	require.Len(t, byKind[trace.SpanKindServer], 3)
+	// Every server span is the child of the client span of the same call
+	for _, serverSpan := range byKind[trace.SpanKindServer] {
+		var parent sdktrace.ReadOnlySpan
+		for _, clientSpan := range byKind[trace.SpanKindClient] {
+			if clientSpan.SpanContext().SpanID() == serverSpan.Parent().SpanID() {
+				parent = clientSpan
+			}
+		}
+		require.NotNil(t, parent, serverSpan.Name())
+		require.True(t, serverSpan.Parent().IsRemote())
+		require.Equal(t, parent.SpanContext().TraceID(), serverSpan.SpanContext().TraceID())
+		require.Equal(t, parent.Name(), serverSpan.Name())
+	}
+	failed := 0
+	for _, span := range spans {
+		if span.Status().Code != 0 {
+			failed++
+		}
+	}
+	require.Equal(t, 3, failed) // the unknown service on both sides, and the canceled watch on the server
+}
@@ -0,0 +1,87 @@
+// Package tracing exports OpenTelemetry traces over OTLP and propagates the trace context over gRPC.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ledgerwatch/log/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+
+	"github.com/ledgerwatch/erigon/params"
+)
+
+// Config of the span export
+type Config struct {
+	Endpoint    string  // host:port of the OTLP gRPC collector, tracing is disabled if empty
+	Insecure    bool    // connect to the collector without TLS
+	SampleRatio float64 // ratio of the sampled traces which are not started by a sampled remote parent
+}
+
+var (
+	lock     sync.Mutex
+	provider *sdktrace.TracerProvider
+)
+
+// Setup starts the export of the spans of the process, named after the service, to the OTLP collector. Until it is
+// called the spans are not recorded.
+func Setup(cfg Config, service string, logger log.Logger) error {
+	if cfg.Endpoint == "" {
+		return nil
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if provider != nil {
+		return nil // the first configuration is kept, e.g. for several devnet nodes in one process
+	}
+
+	opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(context.Background(), opts...)
+	if err != nil {
+		return fmt.Errorf("creating OTLP exporter: %w", err)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(service),
+		semconv.ServiceVersion(params.VersionWithMeta),
+	))
+	if err != nil {
+		return err
+	}
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Debug("[tracing] export failed", "err", err)
+	}))
+	logger.Info("[tracing] Exporting spans", "endpoint", cfg.Endpoint, "sample ratio", cfg.SampleRatio)
+	return nil
+}
+
+// Shutdown exports the remaining spans and stops the export
+func Shutdown() {
+	lock.Lock()
+	defer lock.Unlock()
+	if provider == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := provider.Shutdown(ctx); err != nil {
+		log.Warn("[tracing] Shutdown failed", "err", err)
+	}
+	provider = nil
+}
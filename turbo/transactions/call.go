@@ -31,6 +31,7 @@ func DoCall(
 	blockNrOrHash rpc.BlockNumberOrHash,
 	header *types.Header,
 	overrides *ethapi2.StateOverrides,
+	blockOverrides *ethapi2.BlockOverrides,
 	gasCap uint64,
 	chainConfig *chain.Config,
 	stateReader state.StateReader,
@@ -76,11 +77,17 @@ func DoCall(
 			return nil, fmt.Errorf("header.BaseFee uint256 overflow")
 		}
 	}
+	blockCtx := NewEVMBlockContext(engine, header, blockNrOrHash.RequireCanonical, tx, headerReader)
+	if err := blockOverrides.Override(&blockCtx); err != nil {
+		return nil, err
+	}
+	if blockOverrides != nil && blockOverrides.BaseFee != nil {
+		baseFee = blockCtx.BaseFee
+	}
 	msg, err := args.ToMessage(gasCap, baseFee)
 	if err != nil {
 		return nil, err
 	}
-	blockCtx := NewEVMBlockContext(engine, header, blockNrOrHash.RequireCanonical, tx, headerReader)
 	txCtx := core.NewEVMTxContext(msg)
 
 	evm := vm.NewEVM(blockCtx, txCtx, state, chainConfig, vm.Config{NoBaseFee: true})
@@ -0,0 +1,152 @@
+package transactions
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/rawdb"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/core/vm/evmtypes"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/eth/stagedsync/stages"
+	"github.com/ledgerwatch/erigon/eth/tracers"
+	"github.com/ledgerwatch/erigon/eth/tracers/logger"
+	"github.com/ledgerwatch/erigon/turbo/rpchelper"
+	"github.com/ledgerwatch/erigon/turbo/services"
+)
+
+// StandardTraceBlockToFiles executes the transactions of the block on top of the state in ibs and writes the EIP-3155
+// trace of each of them into a separate file in dir. If config.TxHash is set, only that transaction is traced, and the
+// execution stops after it. Returns the names of the files written so far, also in case of an error.
+func StandardTraceBlockToFiles(ctx context.Context, engine consensus.EngineReader, block *types.Block, blockCtx evmtypes.BlockContext, ibs *state.IntraBlockState, cfg *chain.Config, config *tracers.StdTraceConfig, dir string) ([]string, error) {
+	var (
+		logConfig *logger.LogConfig
+		txHash    libcommon.Hash
+	)
+	if config != nil {
+		logConfig = config.LogConfig
+		txHash = config.TxHash
+	}
+	if txHash != (libcommon.Hash{}) && block.Transaction(txHash) == nil {
+		return nil, fmt.Errorf("transaction %#x not found in block %#x", txHash, block.Hash())
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	header := block.HeaderNoCopy()
+	signer := types.MakeSigner(cfg, block.NumberU64(), block.Time())
+	rules := cfg.Rules(block.NumberU64(), block.Time())
+	var files []string
+	for idx, txn := range block.Transactions() {
+		select {
+		default:
+		case <-ctx.Done():
+			return files, ctx.Err()
+		}
+		ibs.SetTxContext(txn.Hash(), block.Hash(), idx)
+		msg, err := txn.AsMessage(*signer, block.BaseFee(), rules)
+		if err != nil {
+			return files, err
+		}
+		if msg.FeeCap().IsZero() && engine != nil {
+			syscall := func(contract libcommon.Address, data []byte) ([]byte, error) {
+				return core.SysCallContract(contract, data, cfg, ibs, header, engine, true /* constCall */)
+			}
+			msg.SetIsFree(engine.IsServiceTransaction(msg.From(), syscall))
+		}
+
+		var (
+			dump     *os.File
+			writer   *bufio.Writer
+			vmConfig vm.Config
+		)
+		if txHash == (libcommon.Hash{}) || txHash == txn.Hash() {
+			// The file name is unique, so that traces of the same block don't overwrite each other
+			prefix := fmt.Sprintf("block_%#x-%d-%#x-", block.Hash().Bytes()[:4], idx, txn.Hash().Bytes()[:4])
+			if dump, err = os.CreateTemp(dir, prefix); err != nil {
+				return files, err
+			}
+			files = append(files, dump.Name())
+			writer = bufio.NewWriter(dump)
+			vmConfig = vm.Config{Debug: true, Tracer: logger.NewJSONLogger(logConfig, writer)}
+		}
+		vmenv := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), ibs, cfg, vmConfig)
+		_, err = core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(txn.GetGas()).AddDataGas(txn.GetDataGas()), true /* refunds */, false /* gasBailout */)
+		if writer != nil {
+			if flushErr := writer.Flush(); flushErr != nil && err == nil {
+				err = flushErr
+			}
+			if closeErr := dump.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+		if err != nil {
+			return files, fmt.Errorf("transaction %#x failed: %w", txn.Hash(), err)
+		}
+		if txHash == txn.Hash() {
+			break
+		}
+		if err = ibs.FinalizeTx(rules, state.NewNoopWriter()); err != nil {
+			return files, err
+		}
+	}
+	return files, nil
+}
+
+// ComputeBlockEnvOnParent returns the block context of the block and the state it starts from, which is the state
+// after its parent. Unlike ComputeTxEnv, it works for blocks which aren't canonical, as long as their parent is.
+func ComputeBlockEnvOnParent(ctx context.Context, engine consensus.EngineReader, block *types.Block, cfg *chain.Config, blockReader services.FullBlockReader, dbtx kv.Tx, historyV3 bool) (evmtypes.BlockContext, *state.IntraBlockState, error) {
+	reader, err := parentStateReader(block, cfg, dbtx, historyV3)
+	if err != nil {
+		return evmtypes.BlockContext{}, nil, err
+	}
+	statedb := state.New(reader)
+
+	getHeader := func(hash libcommon.Hash, n uint64) *types.Header {
+		h, _ := blockReader.HeaderByNumber(ctx, dbtx, n)
+		return h
+	}
+	header := block.HeaderNoCopy()
+	blockContext := core.NewEVMBlockContext(header, core.GetHashFn(header, getHeader), engine, nil)
+	if engine, ok := engine.(consensus.Engine); ok {
+		chainReader := stagedsync.NewChainReaderImpl(cfg, dbtx, blockReader)
+		if err := core.InitializeBlockExecution(engine, chainReader, header, block.Transactions(), block.Uncles(), cfg, statedb); err != nil {
+			return evmtypes.BlockContext{}, nil, err
+		}
+	}
+	return blockContext, statedb, nil
+}
+
+// parentStateReader returns the reader of the state after the parent of the block, which has to be canonical and executed
+func parentStateReader(block *types.Block, cfg *chain.Config, dbtx kv.Tx, historyV3 bool) (state.StateReader, error) {
+	if block.NumberU64() == 0 {
+		return nil, fmt.Errorf("genesis block has no parent")
+	}
+	parentNum := block.NumberU64() - 1
+	canonical, err := rawdb.IsCanonicalHash(dbtx, block.ParentHash(), parentNum)
+	if err != nil {
+		return nil, err
+	}
+	if !canonical {
+		return nil, fmt.Errorf("parent %x of block %x is not canonical, its state is not available", block.ParentHash(), block.Hash())
+	}
+	executionProgress, err := stages.GetStageProgress(dbtx, stages.Execution)
+	if err != nil {
+		return nil, err
+	}
+	if parentNum > executionProgress {
+		return nil, fmt.Errorf("parent block %d is not executed yet, execution is at block %d", parentNum, executionProgress)
+	}
+	return rpchelper.CreateStateReaderAfterBlock(dbtx, parentNum, historyV3, cfg.ChainName)
+}
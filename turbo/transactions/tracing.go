@@ -80,7 +80,9 @@ func ComputeTxEnv(ctx context.Context, engine consensus.EngineReader, block *typ
 
 	consensusHeaderReader := stagedsync.NewChainReaderImpl(cfg, dbtx, nil)
 
-	core.InitializeBlockExecution(engine.(consensus.Engine), consensusHeaderReader, header, block.Transactions(), block.Uncles(), cfg, statedb)
+	if err := core.InitializeBlockExecution(engine.(consensus.Engine), consensusHeaderReader, header, block.Transactions(), block.Uncles(), cfg, statedb); err != nil {
+		return nil, evmtypes.BlockContext{}, evmtypes.TxContext{}, nil, nil, err
+	}
 
 	for idx, txn := range block.Transactions() {
 		select {
@@ -155,9 +157,7 @@ func TraceTx(
 		if config != nil && config.TracerConfig != nil {
 			cfg = *config.TracerConfig
 		}
-		if tracer, err = tracers.New(*config.Tracer, &tracers.Context{
-			TxHash: txCtx.TxHash,
-		}, cfg); err != nil {
+		if tracer, err = tracers.New(*config.Tracer, newTracerContext(blockCtx, txCtx, ibs), cfg); err != nil {
 			stream.WriteNil()
 			return err
 		}
@@ -237,6 +237,23 @@ func TraceTx(
 	return nil
 }
 
+// newTracerContext collects the location of the traced transaction. The block hash and the
+// transaction index are only known to the IntraBlockState, as set by SetTxContext.
+func newTracerContext(blockCtx evmtypes.BlockContext, txCtx evmtypes.TxContext, ibs evmtypes.IntraBlockState) *tracers.Context {
+	tracerCtx := &tracers.Context{
+		BlockNumber: blockCtx.BlockNumber,
+		TxHash:      txCtx.TxHash,
+	}
+	if s, ok := ibs.(*state.IntraBlockState); ok {
+		tracerCtx.BlockHash = s.BlockHash()
+		tracerCtx.TxIndex = s.TxIndex()
+		if tracerCtx.TxHash == (libcommon.Hash{}) {
+			tracerCtx.TxHash = s.TxHash()
+		}
+	}
+	return tracerCtx
+}
+
 func prepareCallMessage(msg core.Message) statefull.Callmsg {
 	return statefull.Callmsg{
 		CallMsg: ethereum.CallMsg{
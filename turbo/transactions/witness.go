@@ -0,0 +1,264 @@
+package transactions
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/holiman/uint256"
+	"github.com/ledgerwatch/erigon-lib/chain"
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/common/length"
+	"github.com/ledgerwatch/erigon-lib/kv"
+
+	"github.com/ledgerwatch/erigon/consensus"
+	"github.com/ledgerwatch/erigon/core"
+	"github.com/ledgerwatch/erigon/core/state"
+	"github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/core/vm"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/eth/stagedsync"
+	"github.com/ledgerwatch/erigon/turbo/services"
+	"github.com/ledgerwatch/erigon/turbo/trie"
+)
+
+// WitnessTrieLoader returns the loader of the state trie after the parent of the block, which retains the keys of rl,
+// the transaction to load the trie from and the function releasing that transaction
+type WitnessTrieLoader func(rl *trie.RetainList) (*trie.FlatDBTrieLoader, kv.Tx, func(), error)
+
+// ComputeExecutionWitness executes the block on the state after its parent and returns the witness of that state
+// which is needed to execute the block again without the state: the trie nodes on the paths to all the accounts and
+// storage items read or written by the block, the nodes which deletions merge into their parents, and the code
+// of the accounts which are called.
+func ComputeExecutionWitness(ctx context.Context, engine consensus.EngineReader, block *types.Block, cfg *chain.Config, blockReader services.FullBlockReader, dbtx kv.Tx, historyV3 bool, loadTrie WitnessTrieLoader) (*trie.Witness, error) {
+	fullEngine, ok := engine.(consensus.Engine)
+	if !ok {
+		return nil, fmt.Errorf("consensus engine does not support block execution")
+	}
+	reader, err := parentStateReader(block, cfg, dbtx, historyV3)
+	if err != nil {
+		return nil, err
+	}
+	parent, err := blockReader.Header(ctx, dbtx, block.ParentHash(), block.NumberU64()-1)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil {
+		return nil, fmt.Errorf("parent %x of block %x not found", block.ParentHash(), block.Hash())
+	}
+
+	recorder := newWitnessRecorder(reader)
+	getHeader := func(hash libcommon.Hash, n uint64) *types.Header {
+		h, _ := blockReader.HeaderByNumber(ctx, dbtx, n)
+		return h
+	}
+	chainReader := stagedsync.NewChainReaderImpl(cfg, dbtx, blockReader)
+	if _, err = core.ExecuteBlockEphemerally(cfg, &vm.Config{}, core.GetHashFn(block.HeaderNoCopy(), getHeader), fullEngine, block, recorder, recorder, chainReader, nil); err != nil {
+		return nil, err
+	}
+
+	t, err := recorder.retainedTrie(parent.Root, loadTrie, nil)
+	if err != nil {
+		return nil, err
+	}
+	if siblings := t.DeletionSiblings(recorder.deletedKeys()); len(siblings) > 0 {
+		if t, err = recorder.retainedTrie(parent.Root, loadTrie, siblings); err != nil {
+			return nil, err
+		}
+	}
+	for addrHash, code := range recorder.codes {
+		if err := t.UpdateAccountCode(addrHash[:], code); err != nil {
+			return nil, err
+		}
+	}
+	return t.ExtractWitness(false /* trace */, nil)
+}
+
+// ExecuteBlockStateless executes the block on the state of the witness, which has to be the state after the parent
+// of the block, and returns the resulting state root. Only the state is taken from the witness, the block hashes
+// and the headers required by the consensus engine are returned by getHeader and chainReader.
+func ExecuteBlockStateless(engine consensus.Engine, block *types.Block, parentRoot libcommon.Hash, witness *trie.Witness, cfg *chain.Config, getHeader func(hash libcommon.Hash, n uint64) *types.Header, chainReader consensus.ChainHeaderReader) (libcommon.Hash, error) {
+	stateless, err := state.NewStateless(parentRoot, witness)
+	if err != nil {
+		return libcommon.Hash{}, err
+	}
+	if _, err = core.ExecuteBlockEphemerally(cfg, &vm.Config{}, core.GetHashFn(block.HeaderNoCopy(), getHeader), engine, block, stateless, stateless, chainReader, nil); err != nil {
+		return libcommon.Hash{}, err
+	}
+	return stateless.Finalize(), nil
+}
+
+// witnessRecorder reads the state through another reader, and records the keys read from it and the keys deleted
+// when the block is committed
+type witnessRecorder struct {
+	r              state.StateReader
+	incarnations   map[libcommon.Hash]uint64
+	storageKeys    map[[length.Hash + length.Incarnation + length.Hash]byte]struct{}
+	codes          map[libcommon.Hash][]byte
+	deletedAccount map[libcommon.Hash]struct{}
+	deletedStorage map[[2 * length.Hash]byte]struct{}
+}
+
+func newWitnessRecorder(r state.StateReader) *witnessRecorder {
+	return &witnessRecorder{
+		r:              r,
+		incarnations:   map[libcommon.Hash]uint64{},
+		storageKeys:    map[[length.Hash + length.Incarnation + length.Hash]byte]struct{}{},
+		codes:          map[libcommon.Hash][]byte{},
+		deletedAccount: map[libcommon.Hash]struct{}{},
+		deletedStorage: map[[2 * length.Hash]byte]struct{}{},
+	}
+}
+
+func addressHash(address libcommon.Address) libcommon.Hash {
+	return libcommon.BytesToHash(crypto.Keccak256(address[:]))
+}
+
+func (wr *witnessRecorder) ReadAccountData(address libcommon.Address) (*accounts.Account, error) {
+	acc, err := wr.r.ReadAccountData(address)
+	if err != nil {
+		return nil, err
+	}
+	var incarnation uint64
+	if acc != nil {
+		incarnation = acc.Incarnation
+	}
+	wr.incarnations[addressHash(address)] = incarnation
+	return acc, nil
+}
+
+func (wr *witnessRecorder) ReadAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash) ([]byte, error) {
+	var storageKey [length.Hash + length.Incarnation + length.Hash]byte
+	addrHash := addressHash(address)
+	copy(storageKey[:], addrHash[:])
+	binary.BigEndian.PutUint64(storageKey[length.Hash:], incarnation)
+	copy(storageKey[length.Hash+length.Incarnation:], crypto.Keccak256(key[:]))
+	wr.storageKeys[storageKey] = struct{}{}
+	return wr.r.ReadAccountStorage(address, incarnation, key)
+}
+
+func (wr *witnessRecorder) ReadAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) ([]byte, error) {
+	code, err := wr.r.ReadAccountCode(address, incarnation, codeHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(code) > 0 {
+		wr.codes[addressHash(address)] = code
+	}
+	return code, nil
+}
+
+// ReadAccountCodeSize records the whole code, because the witness has no other way to prove its size
+func (wr *witnessRecorder) ReadAccountCodeSize(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash) (int, error) {
+	code, err := wr.ReadAccountCode(address, incarnation, codeHash)
+	return len(code), err
+}
+
+func (wr *witnessRecorder) ReadAccountIncarnation(address libcommon.Address) (uint64, error) {
+	return wr.r.ReadAccountIncarnation(address)
+}
+
+func (wr *witnessRecorder) UpdateAccountData(address libcommon.Address, original, account *accounts.Account) error {
+	// The account is recreated after being deleted
+	delete(wr.deletedAccount, addressHash(address))
+	return nil
+}
+
+func (wr *witnessRecorder) UpdateAccountCode(address libcommon.Address, incarnation uint64, codeHash libcommon.Hash, code []byte) error {
+	return nil
+}
+
+func (wr *witnessRecorder) DeleteAccount(address libcommon.Address, original *accounts.Account) error {
+	wr.deletedAccount[addressHash(address)] = struct{}{}
+	return nil
+}
+
+func (wr *witnessRecorder) WriteAccountStorage(address libcommon.Address, incarnation uint64, key *libcommon.Hash, original, value *uint256.Int) error {
+	if !value.IsZero() {
+		return nil
+	}
+	var storageKey [2 * length.Hash]byte
+	addrHash := addressHash(address)
+	copy(storageKey[:], addrHash[:])
+	copy(storageKey[length.Hash:], crypto.Keccak256(key[:]))
+	wr.deletedStorage[storageKey] = struct{}{}
+	return nil
+}
+
+func (wr *witnessRecorder) CreateContract(address libcommon.Address) error {
+	return nil
+}
+
+func (wr *witnessRecorder) WriteChangeSets() error {
+	return nil
+}
+
+func (wr *witnessRecorder) WriteHistory() error {
+	return nil
+}
+
+// deletedKeys returns the keys of the trie deleted by the block
+func (wr *witnessRecorder) deletedKeys() [][]byte {
+	keys := make([][]byte, 0, len(wr.deletedAccount)+len(wr.deletedStorage))
+	for addrHash := range wr.deletedAccount {
+		keys = append(keys, libcommon.Copy(addrHash[:]))
+	}
+	for storageKey := range wr.deletedStorage {
+		keys = append(keys, libcommon.Copy(storageKey[:]))
+	}
+	return keys
+}
+
+// retainedTrie loads the part of the state trie with the given root which contains the recorded keys and the nodes
+// at the given paths of the trie (in HEX encoding, as returned by trie.DeletionSiblings)
+func (wr *witnessRecorder) retainedTrie(root libcommon.Hash, loadTrie WitnessTrieLoader, paths [][]byte) (*trie.Trie, error) {
+	rl := trie.NewRetainList(0)
+	for addrHash := range wr.incarnations {
+		rl.AddKey(addrHash[:])
+	}
+	for storageKey := range wr.storageKeys {
+		rl.AddKey(storageKey[:])
+	}
+	for _, path := range paths {
+		if len(path) <= 2*length.Hash {
+			rl.AddHex(path)
+			continue
+		}
+		// The paths to the storage items of the loader contain the incarnation of the account
+		var incarnation [length.Incarnation]byte
+		binary.BigEndian.PutUint64(incarnation[:], wr.incarnations[libcommon.BytesToHash(hexToKey(path[:2*length.Hash]))])
+		hex := append(libcommon.Copy(path[:2*length.Hash]), keyToHex(incarnation[:])...)
+		rl.AddHex(append(hex, path[2*length.Hash:]...))
+	}
+
+	loader, tx, release, err := loadTrie(rl)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	wt := trie.NewWitnessRetainer(rl)
+	loader.SetWitnessRetainer(wt)
+	if loaded, err := loader.CalcTrieRoot(tx, nil); err != nil {
+		return nil, err
+	} else if loaded != root {
+		return nil, fmt.Errorf("loaded state root %x, expected %x", loaded, root)
+	}
+	return wt.Trie(root)
+}
+
+func keyToHex(key []byte) []byte {
+	hex := make([]byte, 2*len(key))
+	for i, b := range key {
+		hex[2*i], hex[2*i+1] = b/16, b%16
+	}
+	return hex
+}
+
+func hexToKey(hex []byte) []byte {
+	key := make([]byte, len(hex)/2)
+	for i := range key {
+		key[i] = hex[2*i]<<4 | hex[2*i+1]
+	}
+	return key
+}
@@ -263,14 +263,18 @@ func (rl *RetainList) AddKeyWithMarker(key []byte, marker bool) []byte {
 		nibbles[i*2] = b / 16
 		nibbles[i*2+1] = b % 16
 	}
-	rl.AddHex(nibbles)
-	rl.markers = append(rl.markers, marker)
+	rl.addHexWithMarker(nibbles, marker)
 	return nibbles
 }
 
 // AddHex adds a new key (in HEX encoding) to the list
 func (rl *RetainList) AddHex(hex []byte) {
+	rl.addHexWithMarker(hex, false)
+}
+
+func (rl *RetainList) addHexWithMarker(hex []byte, marker bool) {
 	rl.hexes = append(rl.hexes, hex)
+	rl.markers = append(rl.markers, marker)
 }
 
 // AddCodeTouch adds a new code touch into the resolve set
@@ -126,8 +126,8 @@ type RootHashAggregator struct {
 	leafData       GenStructStepLeafData
 	accData        GenStructStepAccountData
 
-	// Used to construct an Account proof while calculating the tree root.
-	proofRetainer *ProofRetainer
+	// Used to construct an Account proof or a witness while calculating the tree root.
+	proofRetainer proofElementRetainer
 	cutoff        bool
 }
 
@@ -165,6 +165,10 @@ func (l *FlatDBTrieLoader) SetProofRetainer(pr *ProofRetainer) {
 	l.receiver.proofRetainer = pr
 }
 
+func (l *FlatDBTrieLoader) SetWitnessRetainer(wr *WitnessRetainer) {
+	l.receiver.proofRetainer = wr
+}
+
 // CalcTrieRoot algo:
 //
 //		for iterateIHOfAccounts {
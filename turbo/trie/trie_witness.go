@@ -1,6 +1,9 @@
 package trie
 
-import "errors"
+import (
+	"bytes"
+	"errors"
+)
 
 func (t *Trie) ExtractWitness(trace bool, rl RetainDecider) (*Witness, error) {
 	var rd RetainDecider
@@ -53,3 +56,84 @@ func extractWitnessFromRootNode(root node, trace bool, retainDec RetainDecider)
 	}
 	return builder.Build(limiter)
 }
+
+// DeletionSiblings returns the paths (in HEX encoding, without the terminator) to the hash nodes which may be merged
+// into their parent when the given keys (in KEY encoding) are deleted. Deleting the keys can leave a branch node
+// with a single child, and if that child is a short node, the keys of both nodes are concatenated, so the child
+// has to be resolved for the deletion to produce the correct root. The returned paths may contain some nodes
+// which do not end up being merged.
+func (t *Trie) DeletionSiblings(keys [][]byte) [][]byte {
+	hexes := make([][]byte, len(keys))
+	for i, key := range keys {
+		hex := keybytesToHex(key)
+		hexes[i] = hex[:len(hex)-1]
+	}
+	var siblings [][]byte
+	deletionSiblings(t.root, nil, hexes, &siblings)
+	return siblings
+}
+
+// deletionSiblings collects the siblings for the keys (suffixes after the path) which go through the node n
+func deletionSiblings(n node, path []byte, keys [][]byte, siblings *[][]byte) {
+	switch n := n.(type) {
+	case *shortNode:
+		nKey := n.Key
+		if hasTerm(nKey) {
+			nKey = nKey[:len(nKey)-1]
+		}
+		var matching [][]byte
+		for _, key := range keys {
+			if bytes.HasPrefix(key, nKey) {
+				matching = append(matching, key[len(nKey):])
+			}
+		}
+		if len(matching) > 0 {
+			deletionSiblings(n.Val, concat(path, nKey...), matching, siblings)
+		}
+	case *accountNode:
+		var storageKeys [][]byte
+		for _, key := range keys {
+			if len(key) > 0 {
+				storageKeys = append(storageKeys, key)
+			}
+		}
+		if n.storage != nil && len(storageKeys) > 0 {
+			deletionSiblings(n.storage, path, storageKeys, siblings)
+		}
+	case *duoNode:
+		var children [16]node
+		i1, i2 := n.childrenIdx()
+		children[i1], children[i2] = n.child1, n.child2
+		branchDeletionSiblings(children, path, keys, siblings)
+	case *fullNode:
+		var children [16]node
+		copy(children[:], n.Children[:16])
+		branchDeletionSiblings(children, path, keys, siblings)
+	}
+}
+
+func branchDeletionSiblings(children [16]node, path []byte, keys [][]byte, siblings *[][]byte) {
+	var byNibble [16][][]byte
+	for _, key := range keys {
+		if len(key) > 0 && children[key[0]] != nil {
+			byNibble[key[0]] = append(byNibble[key[0]], key[1:])
+		}
+	}
+	// Every child containing deleted keys may disappear, the branch is reduced if at most one child remains
+	remaining := 0
+	for i, child := range children {
+		if child != nil && byNibble[i] == nil {
+			remaining++
+		}
+	}
+	for i, child := range children {
+		if child == nil {
+			continue
+		}
+		if byNibble[i] != nil {
+			deletionSiblings(child, concat(path, byte(i)), byNibble[i], siblings)
+		} else if _, ok := child.(hashNode); ok && remaining <= 1 {
+			*siblings = append(*siblings, concat(path, byte(i)))
+		}
+	}
+}
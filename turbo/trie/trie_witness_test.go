@@ -0,0 +1,31 @@
+package trie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeletionSiblings(t *testing.T) {
+	key := func(first byte) []byte {
+		k := make([]byte, 32)
+		k[0] = first
+		return k
+	}
+	tr := New(EmptyRoot)
+	for _, k := range [][]byte{key(0x10), key(0x20), key(0x21)} {
+		tr.Update(k, []byte{1})
+	}
+	// The leaf under the nibble 1 is only known by its hash
+	root, ok := tr.root.(*duoNode)
+	require.True(t, ok)
+	root.child1 = hashNode{hash: make([]byte, 32)}
+
+	// Deleting both keys under the nibble 2 leaves the hash node alone in the root
+	require.Equal(t, [][]byte{{1}}, tr.DeletionSiblings([][]byte{key(0x20), key(0x21)}))
+	// The sibling of the deleted leaf under the nibble 2 is resolved
+	require.Nil(t, tr.DeletionSiblings([][]byte{key(0x10)}))
+
+	tr.Update(key(0x30), []byte{1})
+	require.Nil(t, tr.DeletionSiblings([][]byte{key(0x20), key(0x21)}))
+}
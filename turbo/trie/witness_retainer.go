@@ -0,0 +1,127 @@
+package trie
+
+import (
+	"fmt"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// proofElementRetainer decides which nodes have their RLP encoding retained while the FlatDBTrieLoader
+// computes the state root. It is implemented by ProofRetainer and WitnessRetainer.
+type proofElementRetainer interface {
+	ProofElement(prefix []byte) *proofElement
+}
+
+// WitnessRetainer collects the nodes on the paths to all the keys of the retain decider (accounts and storage
+// items in the same encoding as for the FlatDBTrieLoader), so that the part of the trie which is needed to read and
+// update these keys can be rebuilt, e.g. to extract a block witness from it. It should be set onto the
+// FlatDBTrieLoader via SetWitnessRetainer before calling CalcTrieRoot.
+type WitnessRetainer struct {
+	rd       RetainDecider
+	elements []*proofElement
+}
+
+func NewWitnessRetainer(rd RetainDecider) *WitnessRetainer {
+	return &WitnessRetainer{rd: rd}
+}
+
+// ProofElement requests a new proof element for every node on the paths to the retained keys
+func (wr *WitnessRetainer) ProofElement(prefix []byte) *proofElement {
+	if !wr.rd.Retain(prefix) {
+		return nil
+	}
+	pe := &proofElement{hexKey: append([]byte{}, prefix...)}
+	wr.elements = append(wr.elements, pe)
+	return pe
+}
+
+// Trie may be invoked only after CalcTrieRoot of the FlatDBTrieLoader has successfully executed. It rebuilds the
+// trie with the given root from the collected nodes, the subtries which were not collected are hash nodes.
+// Incarnations of the accounts are not part of the trie and are set to 0.
+func (wr *WitnessRetainer) Trie(root libcommon.Hash) (*Trie, error) {
+	nodes := make(map[libcommon.Hash][]byte, len(wr.elements))
+	for _, pe := range wr.elements {
+		enc := pe.proof.Bytes()
+		nodes[crypto.Keccak256Hash(enc)] = enc
+	}
+	t := New(root)
+	if t.root == nil {
+		return t, nil
+	}
+	resolved, err := resolveRetainedNode(t.root, nodes, false /* storage */)
+	if err != nil {
+		return nil, err
+	}
+	t.root = resolved
+	if t.Hash() != root {
+		return nil, fmt.Errorf("rebuilt trie has root %x, expected %x", t.Hash(), root)
+	}
+	return t, nil
+}
+
+// resolveRetainedNode replaces the hash nodes by the collected nodes and the leaves of the accounts by account nodes
+func resolveRetainedNode(n node, nodes map[libcommon.Hash][]byte, storage bool) (node, error) {
+	switch n := n.(type) {
+	case hashNode:
+		enc, ok := nodes[libcommon.BytesToHash(n.hash)]
+		if !ok {
+			return n, nil
+		}
+		decoded, err := decodeNode(enc)
+		if err != nil {
+			return nil, err
+		}
+		return resolveRetainedNode(decoded, nodes, storage)
+	case *fullNode:
+		for i, child := range n.Children {
+			if child == nil {
+				continue
+			}
+			resolved, err := resolveRetainedNode(child, nodes, storage)
+			if err != nil {
+				return nil, err
+			}
+			n.Children[i] = resolved
+		}
+		return n, nil
+	case *shortNode:
+		value, ok := n.Val.(valueNode)
+		if !ok {
+			resolved, err := resolveRetainedNode(n.Val, nodes, storage)
+			if err != nil {
+				return nil, err
+			}
+			n.Val = resolved
+			return n, nil
+		}
+		if storage {
+			// The trie keeps the storage values without their RLP encoding
+			v, _, err := rlp.SplitString(value)
+			if err != nil {
+				return nil, err
+			}
+			n.Val = valueNode(v)
+			return n, nil
+		}
+		var acc accounts.Account
+		if err := acc.DecodeForHashing(value); err != nil {
+			return nil, err
+		}
+		accNode := &accountNode{Account: acc, rootCorrect: true, codeSize: codeSizeUncached}
+		if acc.Root != EmptyRoot {
+			storageRoot, err := resolveRetainedNode(hashNode{hash: libcommon.Copy(acc.Root[:])}, nodes, true /* storage */)
+			if err != nil {
+				return nil, err
+			}
+			accNode.storage = storageRoot
+		}
+		n.Val = accNode
+		return n, nil
+	default:
+		return nil, fmt.Errorf("unexpected node type: %T", n)
+	}
+}
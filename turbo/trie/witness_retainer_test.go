@@ -0,0 +1,88 @@
+package trie_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	libcommon "github.com/ledgerwatch/erigon-lib/common"
+	"github.com/ledgerwatch/erigon-lib/kv/memdb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+	"github.com/ledgerwatch/erigon/crypto"
+	"github.com/ledgerwatch/erigon/turbo/trie"
+)
+
+func TestWitnessRetainer(t *testing.T) {
+	db := memdb.NewTestDB(t)
+	var accountHashes, storageHashes []libcommon.Hash
+	for i := 0; i < 64; i++ {
+		accountHashes = append(accountHashes, crypto.Keccak256Hash([]byte{byte(i)}))
+		storageHashes = append(storageHashes, crypto.Keccak256Hash([]byte{byte(i), 1}))
+	}
+	seedInitialAccounts(t, db, accountHashes)
+	storageKeys := seedInitialStorage(t, db, storageHashes)
+	root := initialFlatDBTrieBuild(t, db)
+
+	// Retain an account, a missing account, the contract and one of its storage items
+	rl := trie.NewRetainList(0)
+	rl.AddKey(accountHashes[3][:])
+	rl.AddKey(missingHash[:])
+	rl.AddKey(storageAccountHash[:])
+	rl.AddKey(storageKeys[5])
+	loader := trie.NewFlatDBTrieLoader("test", rl, nil, nil, false)
+	wr := trie.NewWitnessRetainer(rl)
+	loader.SetWitnessRetainer(wr)
+	tx, err := db.BeginRo(context.Background())
+	require.NoError(t, err)
+	defer tx.Rollback()
+	hash, err := loader.CalcTrieRoot(tx, nil)
+	require.NoError(t, err)
+	require.Equal(t, root, hash)
+	tx.Rollback()
+
+	tr, err := wr.Trie(root)
+	require.NoError(t, err)
+	require.Equal(t, root, tr.Hash())
+
+	acc, ok := tr.GetAccount(accountHashes[3][:])
+	require.True(t, ok)
+	require.Equal(t, uint64(1), acc.Nonce)
+	acc, ok = tr.GetAccount(missingHash[:])
+	require.True(t, ok)
+	require.Nil(t, acc)
+	contract, ok := tr.GetAccount(storageAccountHash[:])
+	require.True(t, ok)
+	require.Equal(t, storageAccountCodeHash, contract.CodeHash)
+	storageTrieKey := append(append([]byte{}, storageAccountHash[:]...), storageHashes[5][:]...)
+	value, ok := tr.Get(storageTrieKey)
+	require.True(t, ok)
+	require.Equal(t, storageInitialValue[:], value)
+
+	// The witness of the trie rebuilds the same trie
+	witness, err := tr.ExtractWitness(false, nil)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	_, err = witness.WriteInto(&buf)
+	require.NoError(t, err)
+	witness, err = trie.NewWitnessFromReader(&buf, false)
+	require.NoError(t, err)
+	fromWitness, err := trie.BuildTrieFromWitness(witness, false)
+	require.NoError(t, err)
+	require.Equal(t, root, fromWitness.Hash())
+
+	// The updates of the retained keys produce the same root as the updates of the state
+	modifiedRl := trie.NewRetainList(0)
+	for _, key := range seedModifiedAccounts(t, db, accountHashes[3:4]) {
+		modifiedRl.AddKey(key)
+	}
+	for _, key := range seedModifiedStorage(t, db, storageHashes[5:6]) {
+		modifiedRl.AddKey(key)
+	}
+	modified := accounts.NewAccount()
+	modified.Nonce = 2
+	fromWitness.UpdateAccount(accountHashes[3][:], &modified)
+	fromWitness.Update(storageTrieKey, storageModifiedValue[:])
+	require.Equal(t, rebuildFlatDBTrieHash(t, modifiedRl, db), fromWitness.Hash())
+}